@@ -0,0 +1,533 @@
+//-----------------------------------------------------------------------------
+/*
+
+Banked Memory
+
+A pool is physical memory divided into equal sized banks of RAM or ROM.
+A window is a device mapped onto the bus that shows one bank of a pool.
+A latch is a bank select register: a write to it selects the bank of each
+window it controls.
+
+A latch can be mapped as a device (e.g. the bank register of an SBC) or take
+the writes to windows showing ROM (e.g. the NES UxROM mapper).
+
+*/
+//-----------------------------------------------------------------------------
+
+package bus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+// Pool is physical memory divided into banks.
+type Pool struct {
+	size  int // bank size
+	banks [][]uint8
+	rom   []bool // read only banks
+}
+
+// NewPool returns a pool of n RAM banks of size bytes.
+func NewPool(n, size int) *Pool {
+	p := &Pool{
+		size:  size,
+		banks: make([][]uint8, n),
+		rom:   make([]bool, n),
+	}
+	for i := range p.banks {
+		p.banks[i] = make([]uint8, size)
+	}
+	return p
+}
+
+// Banks returns the number of banks.
+func (p *Pool) Banks() int {
+	return len(p.banks)
+}
+
+// Size returns the bank size.
+func (p *Pool) Size() int {
+	return p.size
+}
+
+// Bank returns the contents of a bank.
+func (p *Pool) Bank(n int) []uint8 {
+	return p.banks[n]
+}
+
+// LoadROM copies data into a bank and makes it read only.
+func (p *Pool) LoadROM(n int, data []uint8) error {
+	if n < 0 || n >= len(p.banks) {
+		return fmt.Errorf("no bank %d", n)
+	}
+	if len(data) > p.size {
+		return fmt.Errorf("rom is larger than the bank size (%d bytes)", p.size)
+	}
+	copy(p.banks[n], data)
+	p.rom[n] = true
+	return nil
+}
+
+// ROM returns true if a bank is read only.
+func (p *Pool) ROM(n int) bool {
+	return p.rom[n]
+}
+
+// Window returns a window onto the pool showing a bank.
+func (p *Pool) Window(bank int) (*Window, error) {
+	if len(p.banks) == 0 || p.size == 0 {
+		return nil, errors.New("a window needs a pool with banks")
+	}
+	w := &Window{
+		pool: p,
+	}
+	w.Select(bank)
+	return w, nil
+}
+
+//-----------------------------------------------------------------------------
+
+// Window is a device showing a bank of a pool.
+// A window larger than the bank size shows repeats of the bank.
+type Window struct {
+	pool  *Pool
+	bank  int
+	latch *Latch   // writes to a rom bank go to the latch (if any)
+	fns   []func() // called when another bank is selected
+}
+
+// Select selects the bank shown by the window.
+// Bank numbers wrap at the pool size (the unused latch bits are ignored).
+func (w *Window) Select(bank int) {
+	n := len(w.pool.banks)
+	bank = ((bank % n) + n) % n
+	if bank == w.bank {
+		return
+	}
+	w.bank = bank
+	for _, fn := range w.fns {
+		fn()
+	}
+}
+
+// onSelect adds a function called when another bank is selected.
+func (w *Window) onSelect(fn func()) {
+	w.fns = append(w.fns, fn)
+}
+
+// Bank returns the bank shown by the window.
+func (w *Window) Bank() int {
+	return w.bank
+}
+
+// WriteLatch sends the writes to a window showing ROM to a latch.
+func (w *Window) WriteLatch(l *Latch) {
+	w.latch = l
+}
+
+// Read8 reads a byte from the bank.
+func (w *Window) Read8(ofs uint16) uint8 {
+	return w.pool.banks[w.bank][int(ofs)%w.pool.size]
+}
+
+// Write8 writes a byte to the bank.
+func (w *Window) Write8(ofs uint16, val uint8) {
+	if w.pool.rom[w.bank] {
+		if w.latch != nil {
+			w.latch.Write8(ofs, val)
+		}
+		return
+	}
+	w.pool.banks[w.bank][int(ofs)%w.pool.size] = val
+}
+
+// Peek8 reads a byte from the bank.
+func (w *Window) Peek8(ofs uint16) uint8 {
+	return w.Read8(ofs)
+}
+
+// Poke8 writes a byte to the bank (ROM included).
+func (w *Window) Poke8(ofs uint16, val uint8) {
+	w.pool.banks[w.bank][int(ofs)%w.pool.size] = val
+}
+
+func (w *Window) String() string {
+	kind := "ram"
+	if w.pool.rom[w.bank] {
+		kind = "rom"
+	}
+	return fmt.Sprintf("bank %d/%d %s", w.bank, len(w.pool.banks), kind)
+}
+
+//-----------------------------------------------------------------------------
+
+// latchCtrl is a bank select field of a latch.
+type latchCtrl struct {
+	w     *Window
+	shift uint
+	mask  uint8
+	base  int
+}
+
+// Latch is a bank select register.
+type Latch struct {
+	val  uint8
+	ctrl []latchCtrl
+	fn   func(val uint8)
+}
+
+// NewLatch returns a bank select latch.
+func NewLatch() *Latch {
+	return &Latch{}
+}
+
+// Control has the latch select the bank of a window.
+// The bank is base + ((value >> shift) & mask).
+func (l *Latch) Control(w *Window, shift uint, mask uint8, base int) {
+	l.ctrl = append(l.ctrl, latchCtrl{w, shift, mask, base})
+	l.update()
+}
+
+// OnWrite sets a function called with the latch value after a write.
+// It can decode the value where bit fields aren't enough (e.g. the C64 PLA).
+func (l *Latch) OnWrite(fn func(val uint8)) {
+	l.fn = fn
+}
+
+// Value returns the latch value.
+func (l *Latch) Value() uint8 {
+	return l.val
+}
+
+// update selects the banks for the latch value.
+func (l *Latch) update() {
+	for _, c := range l.ctrl {
+		c.w.Select(c.base + int((l.val>>c.shift)&c.mask))
+	}
+	if l.fn != nil {
+		l.fn(l.val)
+	}
+}
+
+// Read8 reads the latch.
+func (l *Latch) Read8(ofs uint16) uint8 {
+	return l.val
+}
+
+// Write8 writes the latch.
+func (l *Latch) Write8(ofs uint16, val uint8) {
+	l.val = val
+	l.update()
+}
+
+//-----------------------------------------------------------------------------
+// state
+
+// Selects is the bank shown by each window and the latch values on a bus.
+type Selects struct {
+	windows []int
+	latches []uint8
+}
+
+// State is the saved state of the banked memory on a bus: the bank selects
+// and the contents of the RAM banks.
+type State struct {
+	last uint8
+	Selects
+	pools [][][]uint8 // ram banks (nil for rom)
+}
+
+// banked returns the windows, latches and pools on the bus in address order.
+func (b *Bus) banked() ([]*Window, []*Latch, []*Pool) {
+	var ws []*Window
+	var ls []*Latch
+	var ps []*Pool
+	addLatch := func(l *Latch) {
+		for _, x := range ls {
+			if x == l {
+				return
+			}
+		}
+		ls = append(ls, l)
+	}
+	addPool := func(p *Pool) {
+		for _, x := range ps {
+			if x == p {
+				return
+			}
+		}
+		ps = append(ps, p)
+	}
+	var last *region
+	add := func(r *region) {
+		if r == nil || r == last || r.kind != kindDevice {
+			return
+		}
+		last = r
+		switch d := r.dev.(type) {
+		case *Window:
+			ws = append(ws, d)
+			if d.latch != nil {
+				addLatch(d.latch)
+			}
+			addPool(d.pool)
+		case *Latch:
+			addLatch(d)
+		}
+	}
+	for i := range b.pages {
+		p := &b.pages[i]
+		if p.sub == nil {
+			add(p.r)
+			continue
+		}
+		for _, r := range p.sub {
+			add(r)
+		}
+	}
+	return ws, ls, ps
+}
+
+// saveSelects returns the selects of the windows and latches.
+func saveSelects(ws []*Window, ls []*Latch) Selects {
+	var s Selects
+	for _, w := range ws {
+		s.windows = append(s.windows, w.bank)
+	}
+	for _, l := range ls {
+		s.latches = append(s.latches, l.val)
+	}
+	return s
+}
+
+// restore sets the selects of the windows and latches.
+func (s *Selects) restore(ws []*Window, ls []*Latch) error {
+	if len(ws) != len(s.windows) || len(ls) != len(s.latches) {
+		return errors.New("bus layout has changed")
+	}
+	for i, l := range ls {
+		l.val = s.latches[i]
+		l.update()
+	}
+	// a window may have been selected without its latch
+	for i, w := range ws {
+		w.Select(s.windows[i])
+	}
+	return nil
+}
+
+// SaveSelects returns the bank selects (without the bank contents).
+func (b *Bus) SaveSelects() *Selects {
+	ws, ls, _ := b.banked()
+	s := saveSelects(ws, ls)
+	return &s
+}
+
+// RestoreSelects sets the bank selects.
+// The bus must have the layout it had when they were saved.
+func (b *Bus) RestoreSelects(s *Selects) error {
+	ws, ls, _ := b.banked()
+	return s.restore(ws, ls)
+}
+
+// Save returns the state of the banked memory.
+func (b *Bus) Save() *State {
+	ws, ls, ps := b.banked()
+	s := &State{
+		last:    b.last,
+		Selects: saveSelects(ws, ls),
+	}
+	for _, p := range ps {
+		banks := make([][]uint8, len(p.banks))
+		for i := range p.banks {
+			if !p.rom[i] {
+				banks[i] = append([]uint8(nil), p.banks[i]...)
+			}
+		}
+		s.pools = append(s.pools, banks)
+	}
+	return s
+}
+
+// Restore sets the state of the banked memory.
+// The bus must have the layout it had when the state was saved.
+func (b *Bus) Restore(s *State) error {
+	ws, ls, ps := b.banked()
+	if len(ps) != len(s.pools) {
+		return errors.New("bus layout has changed")
+	}
+	for i, p := range ps {
+		if len(s.pools[i]) != len(p.banks) {
+			return errors.New("bus layout has changed")
+		}
+	}
+	if err := s.restore(ws, ls); err != nil {
+		return err
+	}
+	b.last = s.last
+	for i, p := range ps {
+		for j, bank := range s.pools[i] {
+			if bank != nil {
+				copy(p.banks[j], bank)
+			}
+		}
+	}
+	// the bank contents have changed
+	for _, w := range ws {
+		for _, fn := range w.fns {
+			fn()
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the state.
+func (s *State) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	w := func(v interface{}) {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	w(s.last)
+	w(uint16(len(s.windows)))
+	for _, bank := range s.windows {
+		w(int32(bank))
+	}
+	w(uint16(len(s.latches)))
+	w(s.latches)
+	w(uint16(len(s.pools)))
+	for _, banks := range s.pools {
+		w(uint16(len(banks)))
+		for _, bank := range banks {
+			// rom banks aren't saved
+			w(uint32(len(bank)))
+			w(bank)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the state.
+func (s *State) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	var err error
+	rd := func(v interface{}) {
+		if err == nil {
+			err = binary.Read(r, binary.LittleEndian, v)
+		}
+	}
+	var n uint16
+	x := State{}
+	rd(&x.last)
+	rd(&n)
+	for i := 0; i < int(n) && err == nil; i++ {
+		var bank int32
+		rd(&bank)
+		x.windows = append(x.windows, int(bank))
+	}
+	rd(&n)
+	if err == nil {
+		x.latches = make([]uint8, n)
+		rd(x.latches)
+	}
+	rd(&n)
+	for i := 0; i < int(n) && err == nil; i++ {
+		var nbanks uint16
+		rd(&nbanks)
+		banks := make([][]uint8, nbanks)
+		for j := range banks {
+			var size uint32
+			rd(&size)
+			if err != nil || int64(size) > int64(r.Len()) {
+				err = errors.New("bad bus state")
+				break
+			}
+			if size != 0 {
+				banks[j] = make([]uint8, size)
+				rd(banks[j])
+			}
+		}
+		x.pools = append(x.pools, banks)
+	}
+	if err != nil || r.Len() != 0 {
+		return errors.New("bad bus state")
+	}
+	*s = x
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// debug views
+
+// window returns the window (and its offset) for an address.
+func (b *Bus) window(adr uint16) (*Window, uint16) {
+	r, adr := b.resolve(adr)
+	if r == nil || r.kind != kindDevice {
+		return nil, 0
+	}
+	w, ok := r.dev.(*Window)
+	if !ok {
+		return nil, 0
+	}
+	return w, adr - r.start
+}
+
+// PokeRAM writes a byte to the RAM at an address (a RAM region or a window
+// showing a RAM bank) without side effects. ROM, devices and unmapped
+// addresses aren't written and it returns false.
+func (b *Bus) PokeRAM(adr uint16, val uint8) bool {
+	r, adr := b.resolve(adr)
+	if r == nil {
+		return false
+	}
+	switch r.kind {
+	case kindRAM:
+		r.mem[adr-r.start] = val
+		return true
+	case kindDevice:
+		if w, ok := r.dev.(*Window); ok && !w.pool.rom[w.bank] {
+			w.Poke8(adr-r.start, val)
+			return true
+		}
+	}
+	return false
+}
+
+// Banks returns the number of banks that can be shown at an address (0 if it isn't banked).
+func (b *Bus) Banks(adr uint16) int {
+	if w, _ := b.window(adr); w != nil {
+		return len(w.pool.banks)
+	}
+	return 0
+}
+
+// bankView is a debugger view of the bus with a bank shown in every window.
+type bankView struct {
+	b    *Bus
+	bank int
+}
+
+func (v *bankView) Read8(adr uint16) uint8 {
+	if w, ofs := v.b.window(adr); w != nil && v.bank >= 0 && v.bank < len(w.pool.banks) {
+		return w.pool.banks[v.bank][int(ofs)%w.pool.size]
+	}
+	return v.b.Peek8(adr)
+}
+
+func (v *bankView) Write8(adr uint16, val uint8) {
+	// read only
+}
+
+// BankView returns a read only view of the bus with a bank shown in every
+// window, e.g. to look at code that isn't currently switched in.
+func (b *Bus) BankView(bank int) cpu.Memory {
+	return &bankView{b, bank}
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,219 @@
+//-----------------------------------------------------------------------------
+/*
+
+Banked Memory Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package bus
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+// testWindow returns a window onto a pool.
+func testWindow(t *testing.T, p *Pool, bank int) *Window {
+	t.Helper()
+	w, err := p.Window(bank)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+//-----------------------------------------------------------------------------
+
+func TestBankROM(t *testing.T) {
+	// NES UxROM: switched bank at $8000, last bank fixed at $c000
+	b := New()
+	prg := NewPool(8, 0x4000)
+	for i := 0; i < prg.Banks(); i++ {
+		prg.LoadROM(i, []uint8{uint8(i)})
+	}
+	lo, hi := testWindow(t, prg, 0), testWindow(t, prg, 7)
+	l := NewLatch()
+	l.Control(lo, 0, 0x07, 0)
+	lo.WriteLatch(l)
+	hi.WriteLatch(l)
+	b.Device("prg", 0x8000, 0xbfff, lo)
+	b.Device("prg", 0xc000, 0xffff, hi)
+
+	if b.Read8(0x8000) != 0 || b.Read8(0xc000) != 7 {
+		t.Fatalf("initial banks")
+	}
+	b.Write8(0xc123, 0x0b) // high bits ignored
+	if b.Read8(0x8000) != 3 || b.Read8(0xc000) != 7 || lo.Bank() != 3 {
+		t.Errorf("bank switch: %d", lo.Bank())
+	}
+	if s := b.Map()[1].String(); s != "8000-bfff device   prg (bank 3/8 rom)" {
+		t.Errorf("map: %q", s)
+	}
+
+	// the debug view of another bank
+	if b.Banks(0x8000) != 8 || b.Banks(0x0000) != 0 {
+		t.Errorf("banks")
+	}
+	v := b.BankView(5)
+	if v.Read8(0x8000) != 5 || v.Read8(0xc000) != 5 || lo.Bank() != 3 {
+		t.Errorf("bank view")
+	}
+}
+
+func TestBankRAM(t *testing.T) {
+	// an SBC with a bank register at $fe00 selecting 16K of RAM at $4000
+	b := New()
+	ram := NewPool(4, 0x4000)
+	w := testWindow(t, ram, 0)
+	l := NewLatch()
+	l.Control(w, 4, 0x03, 0)
+	b.Device("bank", 0x4000, 0x7fff, w)
+	b.Device("latch", 0xfe00, 0xfe00, l)
+
+	for i := 0; i < 4; i++ {
+		b.Write8(0xfe00, uint8(i<<4))
+		b.Write8(0x4000, uint8(0x10+i))
+	}
+	for i := 0; i < 4; i++ {
+		if ram.Bank(i)[0] != uint8(0x10+i) {
+			t.Errorf("bank %d: %02x", i, ram.Bank(i)[0])
+		}
+	}
+	if b.Read8(0xfe00) != 0x30 || w.Bank() != 3 {
+		t.Errorf("latch")
+	}
+}
+
+func TestBankState(t *testing.T) {
+	b := New()
+	ram := NewPool(4, 0x100)
+	rom := NewPool(2, 0x100)
+	rom.LoadROM(1, []uint8{0x42})
+	w := testWindow(t, ram, 0)
+	r := testWindow(t, rom, 0)
+	l := NewLatch()
+	l.Control(w, 0, 0x03, 0)
+	l.Control(r, 2, 0x01, 0)
+	b.Device("ram", 0x4000, 0x40ff, w)
+	b.Device("rom", 0x5000, 0x50ff, r)
+	b.Device("latch", 0xfe00, 0xfe00, l)
+
+	b.Write8(0xfe00, 0x06)
+	b.Write8(0x4000, 0x11)
+	s := b.Save()
+	b.Write8(0x4000, 0x22)
+	b.Write8(0xfe00, 0x01)
+	b.Write8(0x4000, 0x33)
+	if err := b.Restore(s); err != nil {
+		t.Fatal(err)
+	}
+	if l.Value() != 0x06 || w.Bank() != 2 || r.Bank() != 1 {
+		t.Errorf("latch %02x banks %d %d", l.Value(), w.Bank(), r.Bank())
+	}
+	if b.Read8(0x4000) != 0x11 || ram.Bank(1)[0] != 0 || b.Read8(0x5000) != 0x42 {
+		t.Errorf("bank contents")
+	}
+	// the layout must match
+	b.Unmap(0x5000, 0x50ff)
+	if err := b.Restore(s); err == nil {
+		t.Errorf("restored into another layout")
+	}
+}
+
+func TestBankStateBinary(t *testing.T) {
+	b := New()
+	ram := NewPool(3, 0x100)
+	rom := NewPool(2, 0x100)
+	rom.LoadROM(1, []uint8{0x42})
+	w, r := testWindow(t, ram, 0), testWindow(t, rom, 0)
+	l := NewLatch()
+	l.Control(w, 0, 0x03, 0)
+	l.Control(r, 2, 0x01, 0)
+	b.Device("ram", 0x4000, 0x40ff, w)
+	b.Device("rom", 0x5000, 0x50ff, r)
+	b.Device("latch", 0xfe00, 0xfe00, l)
+	b.Write8(0xfe00, 0x06)
+	b.Write8(0x4000, 0x11)
+	s := b.Save()
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s2 State
+	if err := s2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s, &s2) {
+		t.Fatalf("decoded state\n%+v\nwant\n%+v", s2, *s)
+	}
+	b.Write8(0xfe00, 0x00)
+	b.Write8(0x4000, 0x22)
+	if err := b.Restore(&s2); err != nil {
+		t.Fatal(err)
+	}
+	if l.Value() != 0x06 || b.Read8(0x4000) != 0x11 || b.Read8(0x5000) != 0x42 {
+		t.Errorf("latch %02x banks %d %d", l.Value(), w.Bank(), r.Bank())
+	}
+	// truncated and padded
+	if err := s2.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("short state decoded")
+	}
+	if err := s2.UnmarshalBinary(append(data, 0)); err == nil {
+		t.Errorf("long state decoded")
+	}
+}
+
+func TestBankPredecode(t *testing.T) {
+	// a subroutine at $8000 in two banks selected by a latch at $fe00
+	b := New()
+	ram := make([]uint8, 0x1000)
+	b.RAM("ram", 0, ram)
+	rom := NewPool(2, 0x100)
+	rom.LoadROM(0, []uint8{0xa9, 0x11, 0x60}) // lda #$11, rts
+	rom.LoadROM(1, []uint8{0xa9, 0x22, 0x60}) // lda #$22, rts
+	w := testWindow(t, rom, 0)
+	l := NewLatch()
+	l.Control(w, 0, 0x01, 0)
+	b.Device("rom", 0x8000, 0x80ff, w)
+	b.Device("latch", 0xfe00, 0xfe00, l)
+	b.Mirror("rom", 0x9000, 0x90ff, 0x8000, 0x100)
+	copy(ram[0x0400:], []uint8{
+		0x20, 0x00, 0x80, // jsr $8000
+		0x20, 0x00, 0x90, // jsr $9000
+		0x85, 0x10, // sta $10
+		0xa2, 0x01, // ldx #1
+		0x8e, 0x00, 0xfe, // stx $fe00
+		0x20, 0x00, 0x80, // jsr $8000
+		0x85, 0x11, // sta $11
+		0x20, 0x00, 0x90, // jsr $9000
+		0x85, 0x12, // sta $12
+		0x4c, 0x17, 0x04, // jmp *
+	})
+	m := cpu.New6502(b, cpu.WithPredecode())
+	b.OnRemap(m.Invalidate)
+	m.PC = 0x0400
+	for i := 0; i < 17; i++ {
+		if err := m.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the code switched in runs, at the window and its mirror
+	if ram[0x10] != 0x11 || ram[0x11] != 0x22 || ram[0x12] != 0x22 {
+		t.Errorf("ran %02x %02x %02x, want 11 22 22", ram[0x10], ram[0x11], ram[0x12])
+	}
+}
+
+func TestBankEmptyPool(t *testing.T) {
+	for _, p := range []*Pool{NewPool(0, 0x100), NewPool(2, 0)} {
+		if w, err := p.Window(0); w != nil || err == nil {
+			t.Errorf("window onto %d banks of %d bytes", p.Banks(), p.Size())
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
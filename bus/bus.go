@@ -0,0 +1,445 @@
+//-----------------------------------------------------------------------------
+/*
+
+System Bus
+
+The bus decodes the 64K address space of a 6502 system onto regions of RAM,
+ROM, mirrors of other regions and memory mapped devices. It implements
+cpu.Memory (and cpu.DebugMemory) so it can be used as the CPU memory.
+
+Dispatch is through a table of 256 byte pages. A page that is split between
+regions has a table with an entry per address.
+
+Reads from unmapped addresses return the last value on the data bus (open bus)
+and writes to them are ignored. Writes to ROM are ignored, or reported to the
+fault handler if the ROM was mapped as faulting.
+
+Later mappings replace earlier mappings where they overlap.
+
+A mirror can't target another mirror, so an access through a mirror reaches
+the backing region in one step. Mapping a mirror chain (or cycle) is an error.
+
+A change to what the CPU sees at an address range (a mapping or a window
+showing another bank) is reported to the remap function, e.g. so the CPU
+can drop the instructions it has predecoded there.
+
+*/
+//-----------------------------------------------------------------------------
+
+package bus
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+// Device is a memory mapped device.
+// The address passed to a device is the offset within its region.
+// A device may implement cpu.DebugMemory for side effect free debugger accesses.
+type Device interface {
+	Read8(ofs uint16) uint8
+	Write8(ofs uint16, val uint8)
+}
+
+// FaultFunc is called with the bus address of a write to a faulting ROM.
+type FaultFunc func(adr uint16, val uint8)
+
+// RemapFunc is called with an address range (inclusive) that has been remapped.
+type RemapFunc func(start, end uint16)
+
+type regionKind int
+
+const (
+	kindRAM regionKind = iota
+	kindROM
+	kindMirror
+	kindDevice
+)
+
+var kindName = map[regionKind]string{
+	kindRAM:    "ram",
+	kindROM:    "rom",
+	kindMirror: "mirror",
+	kindDevice: "device",
+}
+
+// region is a mapped address range.
+type region struct {
+	name   string
+	kind   regionKind
+	start  uint16
+	end    uint16  // inclusive
+	mem    []uint8 // ram/rom contents
+	fault  bool    // rom writes fault
+	target uint16  // mirror: start of the mirrored range
+	size   uint    // mirror: size of the mirrored range
+	dev    Device
+}
+
+// page is a 256 byte page of the address space.
+type page struct {
+	r   *region       // region for the whole page (nil if split)
+	sub *[256]*region // split page: region for each address
+}
+
+// Bus is a 6502 system bus.
+type Bus struct {
+	pages   [256]page
+	last    uint8 // last value on the data bus
+	fault   FaultFunc
+	remap   RemapFunc
+	mirrors []*region // mirrors that have been mapped
+}
+
+// New returns an empty bus.
+func New() *Bus {
+	return &Bus{}
+}
+
+//-----------------------------------------------------------------------------
+// mapping
+
+// rangeCheck checks that a region fits in the address space.
+func rangeCheck(start uint16, size int) error {
+	if size <= 0 {
+		return errors.New("empty region")
+	}
+	if int(start)+size > 1<<16 {
+		return fmt.Errorf("region %04x+%x is outside the address space", start, size)
+	}
+	return nil
+}
+
+// add maps a region onto the page table.
+func (b *Bus) add(r *region) {
+	b.set(r.start, r.end, r)
+	if r.kind == kindMirror {
+		b.mirrors = append(b.mirrors, r)
+	}
+	b.remapped(r.start, r.end)
+}
+
+// remapped reports a remapped address range and the mirrors of it.
+func (b *Bus) remapped(start, end uint16) {
+	if b.remap == nil {
+		return
+	}
+	b.remap(start, end)
+	for _, r := range b.mirrors {
+		if int(r.target) <= int(end) && int(r.target)+int(r.size) > int(start) {
+			b.remap(r.start, r.end)
+		}
+	}
+}
+
+// set sets the region for an address range.
+func (b *Bus) set(start, end uint16, r *region) {
+	for pn := int(start >> 8); pn <= int(end>>8); pn++ {
+		p := &b.pages[pn]
+		lo, hi := 0, 255
+		if pn == int(start>>8) {
+			lo = int(start & 0xff)
+		}
+		if pn == int(end>>8) {
+			hi = int(end & 0xff)
+		}
+		if lo == 0 && hi == 255 {
+			p.r, p.sub = r, nil
+			continue
+		}
+		if p.sub == nil {
+			p.sub = new([256]*region)
+			for i := range p.sub {
+				p.sub[i] = p.r
+			}
+			p.r = nil
+		}
+		for i := lo; i <= hi; i++ {
+			p.sub[i] = r
+		}
+		// collapse a page that is no longer split
+		whole := true
+		for i := range p.sub {
+			if p.sub[i] != p.sub[0] {
+				whole = false
+				break
+			}
+		}
+		if whole {
+			p.r, p.sub = p.sub[0], nil
+		}
+	}
+}
+
+// RAM maps RAM at an address. The slice holds the contents.
+func (b *Bus) RAM(name string, start uint16, mem []uint8) error {
+	if err := rangeCheck(start, len(mem)); err != nil {
+		return err
+	}
+	b.add(&region{
+		name:  name,
+		kind:  kindRAM,
+		start: start,
+		end:   start + uint16(len(mem)-1),
+		mem:   mem,
+	})
+	return nil
+}
+
+// ROM maps ROM at an address. The slice holds the contents.
+// Writes are ignored, or reported to the fault handler if fault is set.
+func (b *Bus) ROM(name string, start uint16, mem []uint8, fault bool) error {
+	if err := rangeCheck(start, len(mem)); err != nil {
+		return err
+	}
+	b.add(&region{
+		name:  name,
+		kind:  kindROM,
+		start: start,
+		end:   start + uint16(len(mem)-1),
+		mem:   mem,
+		fault: fault,
+	})
+	return nil
+}
+
+// Mirror maps start..end onto repeats of the size bytes at target.
+// The target can't hold a mirror and start..end can't hold the target of a mirror.
+func (b *Bus) Mirror(name string, start, end, target uint16, size int) error {
+	if end < start {
+		return errors.New("empty region")
+	}
+	if err := rangeCheck(target, size); err != nil {
+		return err
+	}
+	if int(target) <= int(end) && int(target)+size > int(start) {
+		return errors.New("a mirror can't overlap its target")
+	}
+	tend := uint16(int(target) + size - 1)
+	for adr := uint(target); adr <= uint(tend); adr++ {
+		if r := b.lookup(uint16(adr)); r != nil && r.kind == kindMirror {
+			return fmt.Errorf("mirror target %04x is mirrored from %04x", adr, r.target)
+		}
+	}
+	for _, r := range b.mirrors {
+		if int(r.target) <= int(end) && int(r.target)+int(r.size) > int(start) && b.mapped(r) {
+			return fmt.Errorf("mirror %s targets %04x-%04x", r.name, r.target, int(r.target)+int(r.size)-1)
+		}
+	}
+	b.add(&region{
+		name:   name,
+		kind:   kindMirror,
+		start:  start,
+		end:    end,
+		target: target,
+		size:   uint(size),
+	})
+	return nil
+}
+
+// Device maps a device onto start..end.
+func (b *Bus) Device(name string, start, end uint16, dev Device) error {
+	if end < start {
+		return errors.New("empty region")
+	}
+	b.add(&region{
+		name:  name,
+		kind:  kindDevice,
+		start: start,
+		end:   end,
+		dev:   dev,
+	})
+	if w, ok := dev.(*Window); ok {
+		w.onSelect(func() { b.remapped(start, end) })
+	}
+	return nil
+}
+
+// Unmap removes the mapping of start..end.
+func (b *Bus) Unmap(start, end uint16) {
+	if end >= start {
+		b.set(start, end, nil)
+		b.remapped(start, end)
+	}
+}
+
+// SetFaultHandler sets the function called for writes to a faulting ROM.
+func (b *Bus) SetFaultHandler(fn FaultFunc) {
+	b.fault = fn
+}
+
+// OnRemap sets the function called when an address range is remapped.
+func (b *Bus) OnRemap(fn RemapFunc) {
+	b.remap = fn
+}
+
+//-----------------------------------------------------------------------------
+// access
+
+// lookup returns the region for an address (nil if unmapped).
+func (b *Bus) lookup(adr uint16) *region {
+	p := &b.pages[adr>>8]
+	if p.sub != nil {
+		return p.sub[adr&0xff]
+	}
+	return p.r
+}
+
+// mapped returns true if some of the region is still mapped.
+func (b *Bus) mapped(r *region) bool {
+	for adr := uint(r.start); adr <= uint(r.end); adr++ {
+		if b.lookup(uint16(adr)) == r {
+			return true
+		}
+	}
+	return false
+}
+
+// mirror returns the mirrored address.
+func (r *region) mirror(adr uint16) uint16 {
+	return r.target + uint16(uint(adr-r.start)%r.size)
+}
+
+// resolve returns the region and address for an address (following a mirror).
+func (b *Bus) resolve(adr uint16) (*region, uint16) {
+	r := b.lookup(adr)
+	if r != nil && r.kind == kindMirror {
+		adr = r.mirror(adr)
+		r = b.lookup(adr)
+	}
+	return r, adr
+}
+
+// Read8 reads a byte from the bus.
+func (b *Bus) Read8(adr uint16) uint8 {
+	r, adr := b.resolve(adr)
+	if r == nil {
+		return b.last
+	}
+	switch r.kind {
+	case kindRAM, kindROM:
+		b.last = r.mem[adr-r.start]
+	case kindDevice:
+		b.last = r.dev.Read8(adr - r.start)
+	}
+	return b.last
+}
+
+// Write8 writes a byte to the bus.
+func (b *Bus) Write8(adr uint16, val uint8) {
+	b.last = val
+	// a fault reports the address on the bus, not the mirrored one
+	r, at := b.resolve(adr)
+	if r == nil {
+		return
+	}
+	switch r.kind {
+	case kindRAM:
+		r.mem[at-r.start] = val
+	case kindROM:
+		if r.fault && b.fault != nil {
+			b.fault(adr, val)
+		}
+	case kindDevice:
+		r.dev.Write8(at-r.start, val)
+	}
+}
+
+// Peek8 reads a byte from the bus without side effects.
+func (b *Bus) Peek8(adr uint16) uint8 {
+	r, adr := b.resolve(adr)
+	if r == nil {
+		return b.last
+	}
+	switch r.kind {
+	case kindDevice:
+		return cpu.Peek8(r.dev, adr-r.start)
+	}
+	return r.mem[adr-r.start]
+}
+
+// Poke8 writes a byte to the bus without side effects.
+// ROM contents are changed (e.g. to patch code).
+func (b *Bus) Poke8(adr uint16, val uint8) {
+	r, adr := b.resolve(adr)
+	if r == nil {
+		return
+	}
+	switch r.kind {
+	case kindDevice:
+		cpu.Poke8(r.dev, adr-r.start, val)
+	default:
+		r.mem[adr-r.start] = val
+	}
+}
+
+//-----------------------------------------------------------------------------
+// layout
+
+// Range is a range of addresses mapped to a region.
+type Range struct {
+	Start, End uint16 // inclusive
+	Name       string // region name ("" if unmapped)
+	Kind       string // ram, rom, mirror, device or unmapped
+	Target     uint16 // mirror: start of the mirrored range
+	Size       int    // mirror: size of the mirrored range
+	Detail     string // device: state (e.g. the bank shown by a window)
+}
+
+func (x *Range) String() string {
+	s := fmt.Sprintf("%04x-%04x %-8s %s", x.Start, x.End, x.Kind, x.Name)
+	if x.Kind == kindName[kindMirror] {
+		s += fmt.Sprintf(" (%04x-%04x)", x.Target, int(x.Target)+x.Size-1)
+	}
+	if x.Detail != "" {
+		s += fmt.Sprintf(" (%s)", x.Detail)
+	}
+	return strings.TrimRight(s, " ")
+}
+
+// Map returns the current layout of the address space.
+func (b *Bus) Map() []Range {
+	var m []Range
+	var cur *region
+	start := 0
+	emit := func(end int) {
+		x := Range{Start: uint16(start), End: uint16(end), Kind: "unmapped"}
+		if cur != nil {
+			x.Name = cur.name
+			x.Kind = kindName[cur.kind]
+			x.Target = cur.target
+			x.Size = int(cur.size)
+			if d, ok := cur.dev.(fmt.Stringer); ok {
+				x.Detail = d.String()
+			}
+		}
+		m = append(m, x)
+	}
+	for adr := 0; adr < 1<<16; adr++ {
+		r := b.lookup(uint16(adr))
+		if r != cur {
+			if adr != 0 {
+				emit(adr - 1)
+			}
+			cur, start = r, adr
+		}
+	}
+	emit(0xffff)
+	return m
+}
+
+func (b *Bus) String() string {
+	m := b.Map()
+	s := make([]string, len(m))
+	for i := range m {
+		s[i] = m[i].String()
+	}
+	return strings.Join(s, "\n")
+}
+
+//-----------------------------------------------------------------------------
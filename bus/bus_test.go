@@ -0,0 +1,184 @@
+//-----------------------------------------------------------------------------
+/*
+
+System Bus Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package bus
+
+import (
+	"testing"
+
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+// testDev is a device with a status register cleared by a read.
+type testDev struct {
+	regs   [16]uint8
+	writes int
+}
+
+func (d *testDev) Read8(ofs uint16) uint8 {
+	v := d.regs[ofs]
+	if ofs == 0 {
+		d.regs[0] = 0
+	}
+	return v
+}
+
+func (d *testDev) Write8(ofs uint16, val uint8) {
+	d.regs[ofs] = val
+	d.writes++
+}
+
+func (d *testDev) Peek8(ofs uint16) uint8 {
+	return d.regs[ofs]
+}
+
+func (d *testDev) Poke8(ofs uint16, val uint8) {
+	d.regs[ofs] = val
+}
+
+// testBus is a NES like layout.
+func testBus(t *testing.T) (*Bus, []uint8, []uint8, *testDev) {
+	b := New()
+	ram := make([]uint8, 0x800)
+	rom := make([]uint8, 0x4000)
+	dev := &testDev{}
+	for _, err := range []error{
+		b.RAM("ram", 0x0000, ram),
+		b.Mirror("ram", 0x0800, 0x1fff, 0x0000, 0x800),
+		b.Device("io", 0x2000, 0x200f, dev),
+		b.ROM("prg", 0x8000, rom, true),
+		b.Mirror("prg", 0xc000, 0xffff, 0x8000, 0x4000),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b, ram, rom, dev
+}
+
+//-----------------------------------------------------------------------------
+
+var _ cpu.Memory = &Bus{}
+var _ cpu.DebugMemory = &Bus{}
+
+func TestBus(t *testing.T) {
+	b, ram, rom, dev := testBus(t)
+
+	// ram and its mirrors
+	b.Write8(0x1834, 0x12)
+	if ram[0x034] != 0x12 || b.Read8(0x0034) != 0x12 || b.Read8(0x0834) != 0x12 {
+		t.Errorf("ram mirror")
+	}
+
+	// rom writes are ignored (and fault)
+	rom[0x0010] = 0x34
+	var fault uint16
+	b.SetFaultHandler(func(adr uint16, val uint8) { fault = adr })
+	b.Write8(0xc010, 0x56)
+	if b.Read8(0xc010) != 0x34 || rom[0x0010] != 0x34 || fault != 0xc010 {
+		t.Errorf("rom write: %02x fault %04x", b.Read8(0xc010), fault)
+	}
+
+	// devices get the offset, peek doesn't have side effects
+	b.Write8(0x2000, 0x80)
+	if dev.writes != 1 || b.Peek8(0x2000) != 0x80 || b.Read8(0x2000) != 0x80 || b.Read8(0x2000) != 0 {
+		t.Errorf("device")
+	}
+
+	// open bus
+	b.Read8(0xc010)
+	if b.Read8(0x5000) != 0x34 {
+		t.Errorf("open bus")
+	}
+	b.Write8(0x5000, 0x77)
+	if b.Read8(0x5000) != 0x77 {
+		t.Errorf("open bus after write")
+	}
+
+	// poke patches rom
+	b.Poke8(0xc011, 0x99)
+	if rom[0x0011] != 0x99 {
+		t.Errorf("poke rom")
+	}
+}
+
+func TestBusMap(t *testing.T) {
+	b, _, _, _ := testBus(t)
+	b.Unmap(0x2008, 0x200f)
+	want := []Range{
+		{0x0000, 0x07ff, "ram", "ram", 0, 0, ""},
+		{0x0800, 0x1fff, "ram", "mirror", 0x0000, 0x800, ""},
+		{0x2000, 0x2007, "io", "device", 0, 0, ""},
+		{0x2008, 0x7fff, "", "unmapped", 0, 0, ""},
+		{0x8000, 0xbfff, "prg", "rom", 0, 0, ""},
+		{0xc000, 0xffff, "prg", "mirror", 0x8000, 0x4000, ""},
+	}
+	got := b.Map()
+	if len(got) != len(want) {
+		t.Fatalf("got\n%s", b)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %s, want %s", &got[i], &want[i])
+		}
+	}
+	// split pages collapse
+	b.Device("io", 0x2008, 0x20ff, &testDev{})
+	b.RAM("ram", 0x2000, make([]uint8, 0x100))
+	if b.pages[0x20].sub != nil {
+		t.Errorf("page not collapsed")
+	}
+}
+
+func TestBusErrors(t *testing.T) {
+	b := New()
+	if b.RAM("ram", 0xff00, make([]uint8, 0x200)) == nil {
+		t.Errorf("ram outside the address space")
+	}
+	if b.ROM("rom", 0, nil, false) == nil {
+		t.Errorf("empty rom")
+	}
+	if b.Mirror("m", 0x0000, 0x1fff, 0x0800, 0x800) == nil {
+		t.Errorf("mirror overlaps its target")
+	}
+}
+
+func TestBusMirrorChain(t *testing.T) {
+	b := New()
+	ram := make([]uint8, 0x100)
+	b.RAM("ram", 0x0000, ram)
+	if err := b.Mirror("a", 0x1000, 0x10ff, 0x0000, 0x100); err != nil {
+		t.Fatal(err)
+	}
+	// a mirror of a mirror
+	if b.Mirror("b", 0x2000, 0x20ff, 0x1000, 0x100) == nil {
+		t.Errorf("mirror chain mapped")
+	}
+	// a mirror under the target of a mirror
+	if b.Mirror("c", 0x0000, 0x00ff, 0x3000, 0x100) == nil {
+		t.Errorf("mirror chain mapped below a mirror")
+	}
+	// a cycle
+	if b.Mirror("d", 0x0000, 0x00ff, 0x1000, 0x100) == nil {
+		t.Errorf("mirror cycle mapped")
+	}
+	// the rejected mirrors weren't mapped
+	b.Write8(0x1010, 0x55)
+	if ram[0x10] != 0x55 || b.Read8(0x0010) != 0x55 || b.lookup(0x2010) != nil {
+		t.Errorf("mirror wrote %02x", ram[0x10])
+	}
+	// a replaced mirror doesn't stop a mirror of its target
+	b.RAM("ram2", 0x1000, make([]uint8, 0x100))
+	if err := b.Mirror("e", 0x0000, 0x00ff, 0x3000, 0x100); err != nil {
+		t.Errorf("mirror onto a replaced mirror target: %v", err)
+	}
+}
+
+//-----------------------------------------------------------------------------
@@ -11,6 +11,7 @@ package main
 import (
 	"fmt"
 
+	"github.com/deadsy/bender/bus"
 	"github.com/deadsy/bender/cpu"
 )
 
@@ -345,30 +346,13 @@ var code = []uint8{
 
 //-----------------------------------------------------------------------------
 
-type memory struct {
-	base uint16
-	mem  []uint8
-}
-
-func (m *memory) Read8(adr uint16) uint8 {
-	return m.mem[adr-m.base]
-}
-
-func (m *memory) Write8(adr uint16, val uint8) {
-	// nop
-}
-
-//-----------------------------------------------------------------------------
-
 func main() {
 
 	adr := uint16(0x200)
 	size := len(code)
 
-	m := &memory{
-		base: adr,
-		mem:  code,
-	}
+	m := bus.New()
+	m.ROM("code", adr, code, false)
 
 	for size > 0 {
 		da := cpu.Disassemble(m, adr, symtab)
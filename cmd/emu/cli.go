@@ -9,9 +9,12 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/deadsy/bender/cpu"
 	cli "github.com/deadsy/go-cli"
 )
 
@@ -67,32 +70,63 @@ func memArgs(args []string) (uint16, uint, error) {
 	return uint16(adr), uint(size), nil
 }
 
+// bankArg splits a bank:adr argument into the bank and the address.
+func bankArg(arg string) (int, string, bool, error) {
+	i := strings.Index(arg, ":")
+	if i < 0 {
+		return 0, arg, false, nil
+	}
+	bank, err := cli.IntArg(arg[:i], [2]int{0, 0xff}, 16)
+	return bank, arg[i+1:], true, err
+}
+
 var helpMemDisplay = []cli.Help{
-	{"<adr> [len]", "address (hex)"},
+	{"<adr> [len]", "address (hex) - bank:adr for a bank"},
 	{"", "length (hex) - default is 0x40"},
 }
 
 var cmdMemDisplay = cli.Leaf{
 	Descr: "display memory",
 	F: func(c *cli.CLI, args []string) {
+		u := c.User.(*userApp)
+		var bank int
+		var banked bool
+		var err error
+		if len(args) >= 1 {
+			bank, args[0], banked, err = bankArg(args[0])
+			if err != nil {
+				c.User.Put(fmt.Sprintf("%s\n", err))
+				return
+			}
+		}
 		adr, size, err := memArgs(args)
 		if err != nil {
 			c.User.Put(fmt.Sprintf("%s\n", err))
 			return
 		}
+		var mem cpu.Memory = u.mem
+		prefix := ""
+		if banked {
+			mem, err = u.bankView(bank, adr)
+			if err != nil {
+				c.User.Put(fmt.Sprintf("%s\n", err))
+				return
+			}
+			prefix = fmt.Sprintf("%02x:", bank)
+		}
 		// round down address to 16 byte boundary
 		adr &= ^uint16(15)
 		// round up n to an integral multiple of 16 bytes
 		size = (size + 15) & ^uint(15)
 		// print the header
-		c.User.Put("addr  0  1  2  3  4  5  6  7  8  9  A  B  C  D  E  F\n")
+		c.User.Put(fmt.Sprintf("addr%*s  0  1  2  3  4  5  6  7  8  9  A  B  C  D  E  F\n", len(prefix), ""))
 		// read and print the data
 		for i := 0; i < int(size>>4); i++ {
 			// read 16 bytes per line
 			var data [16]string
 			var ascii [16]string
 			for j := 0; j < 16; j++ {
-				x := c.User.(*userApp).mem.Read8(adr + uint16(j))
+				x := cpu.Peek8(mem, adr+uint16(j))
 				data[j] = fmt.Sprintf("%02x", x)
 				if x >= 32 && x <= 126 {
 					ascii[j] = fmt.Sprintf("%c", x)
@@ -102,12 +136,19 @@ var cmdMemDisplay = cli.Leaf{
 			}
 			dataStr := strings.Join(data[:], " ")
 			asciiStr := strings.Join(ascii[:], "")
-			c.User.Put(fmt.Sprintf("%04x  %s  %s\n", adr, dataStr, asciiStr))
+			c.User.Put(fmt.Sprintf("%s%04x  %s  %s\n", prefix, adr, dataStr, asciiStr))
 			adr += 16
 		}
 	},
 }
 
+var cmdMap = cli.Leaf{
+	Descr: "show the memory map",
+	F: func(c *cli.CLI, args []string) {
+		c.User.Put(fmt.Sprintf("%s\n", c.User.(*userApp).mem.bus))
+	},
+}
+
 var cmdZeroPage = cli.Leaf{
 	Descr: "show the zero page memory",
 	F: func(c *cli.CLI, args []string) {
@@ -125,101 +166,203 @@ var cmdStack = cli.Leaf{
 //-----------------------------------------------------------------------------
 
 // goArgs converts go arguments to an address value.
-func goArgs(pc uint16, args []string) (uint16, error) {
+func goArgs(u *userApp, args []string) (uint32, error) {
 	err := cli.CheckArgc(args, []int{0, 1})
 	if err != nil {
 		return 0, err
 	}
 	// address
-	adr := int(pc)
+	adr := int(u.pc())
 	if len(args) >= 1 {
-		adr, err = cli.IntArg(args[0], [2]int{0, 0xffff}, 16)
+		adr, err = cli.IntArg(args[0], [2]int{0, u.maxAdr()}, 16)
 		if err != nil {
 			return 0, err
 		}
 	}
-	return uint16(adr), nil
+	return uint32(adr), nil
 }
 
 var helpGo = []cli.Help{
 	{"<adr>", "address (hex) - default is PC"},
 }
 
+// goLoop runs the emulation until an error, a halt or a ctrl-c.
+func goLoop(c *cli.CLI, trace bool) {
+	u := c.User.(*userApp)
+	ctx, stop := interruptible()
+	defer stop()
+	if trace {
+		c.User.Put(fmt.Sprintf("%s\n", u.traceLine()))
+	}
+	err := u.runUntil(ctx, func() bool {
+		if u.haltState() != "" {
+			return true
+		}
+		if trace {
+			c.User.Put(fmt.Sprintf("%s\n", u.traceLine()))
+		}
+		return false
+	})
+	switch {
+	case errors.Is(err, context.Canceled):
+		c.User.Put("interrupted\n")
+	case err != nil:
+		c.User.Put(fmt.Sprintf("%s\n", err))
+	default:
+		c.User.Put(fmt.Sprintf("%s\n", u.haltState()))
+	}
+}
+
 var cmdGo = cli.Leaf{
 	Descr: "run the emulation (no tracing)",
 	F: func(c *cli.CLI, args []string) {
-		m := c.User.(*userApp).cpu
-		adr, err := goArgs(m.PC, args)
+		u := c.User.(*userApp)
+		adr, err := goArgs(u, args)
 		if err != nil {
 			c.User.Put(fmt.Sprintf("%s\n", err))
 			return
 		}
-		m.PC = adr
-		for true {
-			err := m.Run()
-			if err != nil {
-				c.User.Put(fmt.Sprintf("%s\n", err))
-				break
-			}
-		}
+		u.setPC(adr)
+		goLoop(c, false)
 	},
 }
 
 var cmdTrace = cli.Leaf{
 	Descr: "run the emulation (with tracing)",
 	F: func(c *cli.CLI, args []string) {
-		m := c.User.(*userApp).cpu
-		adr, err := goArgs(m.PC, args)
+		u := c.User.(*userApp)
+		adr, err := goArgs(u, args)
+		if err != nil {
+			c.User.Put(fmt.Sprintf("%s\n", err))
+			return
+		}
+		u.setPC(adr)
+		goLoop(c, true)
+	},
+}
+
+var cmdStep = cli.Leaf{
+	Descr: "single step the emulation",
+	F: func(c *cli.CLI, args []string) {
+		u := c.User.(*userApp)
+		adr, err := goArgs(u, args)
 		if err != nil {
 			c.User.Put(fmt.Sprintf("%s\n", err))
 			return
 		}
-		m.PC = adr
-		for true {
-			s := m.Disassemble(m.PC, 1)
-			err := m.Run()
+		u.setPC(adr)
+		s := u.traceLine()
+		err = u.run()
+		c.User.Put(fmt.Sprintf("%s\n", s))
+		if err != nil {
+			c.User.Put(fmt.Sprintf("%s\n", err))
+		}
+		if s := u.haltState(); s != "" {
 			c.User.Put(fmt.Sprintf("%s\n", s))
+		}
+	},
+}
+
+//-----------------------------------------------------------------------------
+// reverse execution
+
+var helpBack = []cli.Help{
+	{"[n]", "number of steps (decimal) - default is 1"},
+}
+
+var cmdBack = cli.Leaf{
+	Descr: "rewind the emulation",
+	F: func(c *cli.CLI, args []string) {
+		u := c.User.(*userApp)
+		err := cli.CheckArgc(args, []int{0, 1})
+		if err != nil {
+			c.User.Put(fmt.Sprintf("%s\n", err))
+			return
+		}
+		n := 1
+		if len(args) >= 1 {
+			n, err = cli.IntArg(args[0], [2]int{1, histSize}, 10)
 			if err != nil {
 				c.User.Put(fmt.Sprintf("%s\n", err))
-				break
+				return
 			}
 		}
+		err = u.back(uint64(n))
+		if err != nil {
+			c.User.Put(fmt.Sprintf("%s\n", err))
+			return
+		}
+		cmdRegisters.F(c, nil)
 	},
 }
 
-var cmdStep = cli.Leaf{
-	Descr: "single step the emulation",
+var cmdReverseStep = cli.Leaf{
+	Descr: "single step the emulation backwards",
 	F: func(c *cli.CLI, args []string) {
-		m := c.User.(*userApp).cpu
-		adr, err := goArgs(m.PC, args)
+		u := c.User.(*userApp)
+		err := u.back(1)
 		if err != nil {
 			c.User.Put(fmt.Sprintf("%s\n", err))
 			return
 		}
-		m.PC = adr
-		s := m.Disassemble(m.PC, 1)
-		err = m.Run()
-		c.User.Put(fmt.Sprintf("%s\n", s))
+		c.User.Put(fmt.Sprintf("%s\n", u.traceLine()))
+	},
+}
+
+var helpReverseGo = []cli.Help{
+	{"<adr>", "address (hex) - rewind to the last write"},
+}
+
+var cmdReverseGo = cli.Leaf{
+	Descr: "rewind to the last write of an address",
+	F: func(c *cli.CLI, args []string) {
+		u := c.User.(*userApp)
+		adr, _, err := memArgs(args)
+		if err == nil && len(args) != 1 {
+			err = fmt.Errorf("bad number of arguments, need 1")
+		}
+		if err == nil {
+			err = u.backWrite(adr)
+		}
 		if err != nil {
 			c.User.Put(fmt.Sprintf("%s\n", err))
+			return
 		}
+		c.User.Put(fmt.Sprintf("%s\n", u.traceLine()))
 	},
 }
 
 //-----------------------------------------------------------------------------
 
 // daArgs converts disassembly arguments to an (address, size) tuple.
-func daArgs(pc uint16, args []string) (uint16, uint, error) {
+// A 6502 bank:adr address returns a view of the bank (nil for the cpu view).
+func daArgs(u *userApp, args []string) (cpu.Memory, uint32, uint, error) {
 	err := cli.CheckArgc(args, []int{0, 1, 2})
 	if err != nil {
-		return 0, 0, err
+		return nil, 0, 0, err
 	}
 	// address
-	adr := int(pc) // default address
+	var mem cpu.Memory
+	adr := int(u.pc()) // default address
 	if len(args) >= 1 {
-		adr, err = cli.IntArg(args[0], [2]int{0, 0xffff}, 16)
+		bank, arg, banked, err := bankArg(args[0])
 		if err != nil {
-			return 0, 0, err
+			return nil, 0, 0, err
+		}
+		max := u.maxAdr()
+		if banked {
+			max = 0xffff
+		}
+		adr, err = cli.IntArg(arg, [2]int{0, max}, 16)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if banked {
+			if u.cpu816 != nil {
+				adr |= bank << 16
+			} else if mem, err = u.bankView(bank, uint16(adr)); err != nil {
+				return nil, 0, 0, err
+			}
 		}
 	}
 	// size
@@ -227,27 +370,31 @@ func daArgs(pc uint16, args []string) (uint16, uint, error) {
 	if len(args) >= 2 {
 		size, err = cli.IntArg(args[1], [2]int{1, 2048}, 16)
 		if err != nil {
-			return 0, 0, err
+			return nil, 0, 0, err
 		}
 	}
-	return uint16(adr), uint(size), nil
+	return mem, uint32(adr), uint(size), nil
 }
 
 var helpDisassemble = []cli.Help{
-	{"[adr] [len]", "address (hex) - default is current pc"},
+	{"[adr] [len]", "address (hex) - default is current pc, bank:adr for a bank"},
 	{"", "length (hex) - default is 0x10"},
 }
 
 var cmdDisassemble = cli.Leaf{
 	Descr: "disassemble memory",
 	F: func(c *cli.CLI, args []string) {
-		m := c.User.(*userApp).cpu
-		adr, size, err := daArgs(m.ReadPC(), args)
+		u := c.User.(*userApp)
+		mem, adr, size, err := daArgs(u, args)
 		if err != nil {
 			c.User.Put(fmt.Sprintf("%s\n", err))
 			return
 		}
-		c.User.Put(fmt.Sprintf("%s\n", m.Disassemble(adr, int(size))))
+		if mem != nil {
+			c.User.Put(fmt.Sprintf("%s\n", u.disassembleView(mem, uint16(adr), int(size))))
+			return
+		}
+		c.User.Put(fmt.Sprintf("%s\n", u.disassemble(adr, int(size))))
 	},
 }
 
@@ -256,8 +403,11 @@ var cmdDisassemble = cli.Leaf{
 var cmdRegisters = cli.Leaf{
 	Descr: "display cpu registers",
 	F: func(c *cli.CLI, args []string) {
-		m := c.User.(*userApp).cpu
-		c.User.Put(fmt.Sprintf("%s\n", m.Dump()))
+		u := c.User.(*userApp)
+		c.User.Put(fmt.Sprintf("%s\n", u.dump()))
+		if s := u.haltState(); s != "" {
+			c.User.Put(fmt.Sprintf("%s\n", s))
+		}
 	},
 }
 
@@ -266,10 +416,95 @@ var cmdRegisters = cli.Leaf{
 var cmdReset = cli.Leaf{
 	Descr: "reset the cpu",
 	F: func(c *cli.CLI, args []string) {
-		m := c.User.(*userApp).cpu
-		m.Power(false)
-		m.Power(true)
-		m.Reset()
+		c.User.(*userApp).reset()
+	},
+}
+
+//-----------------------------------------------------------------------------
+
+var helpIrq = []cli.Help{
+	{"[source] [on|off]", "interrupt source - default is irq (user)"},
+	{"", "user sources: irq, nmi"},
+}
+
+var cmdIrq = cli.Leaf{
+	Descr: "display/drive interrupt sources",
+	F: func(c *cli.CLI, args []string) {
+		err := cli.CheckArgc(args, []int{0, 1, 2})
+		if err != nil {
+			c.User.Put(fmt.Sprintf("%s\n", err))
+			return
+		}
+		ints := c.User.(*userApp).interrupts()
+		// sources for driving the lines by hand
+		if _, err := ints.IRQ("irq"); err != nil {
+			c.User.Put(fmt.Sprintf("%s\n", err))
+			return
+		}
+		if _, err := ints.NMI("nmi"); err != nil {
+			c.User.Put(fmt.Sprintf("%s\n", err))
+			return
+		}
+		if len(args) == 0 {
+			for _, s := range ints.Sources() {
+				c.User.Put(fmt.Sprintf("%s\n", s))
+			}
+			c.User.Put(fmt.Sprintf("irq line %t, nmi line %t\n", ints.IRQLine(), ints.NMILine()))
+			return
+		}
+		name, state := "irq", args[0]
+		if len(args) == 2 {
+			name, state = args[0], args[1]
+		}
+		s := ints.Source(name)
+		if s == nil {
+			c.User.Put(fmt.Sprintf("unknown interrupt source \"%s\"\n", name))
+			return
+		}
+		switch state {
+		case "on":
+			s.Assert()
+		case "off":
+			s.Clear()
+		default:
+			c.User.Put(fmt.Sprintf("bad state \"%s\", must be on or off\n", state))
+			return
+		}
+		c.User.Put(fmt.Sprintf("%s\n", s))
+	},
+}
+
+//-----------------------------------------------------------------------------
+
+var helpState = []cli.Help{
+	{"<file>", "machine state file"},
+}
+
+var cmdSave = cli.Leaf{
+	Descr: "save the machine state",
+	F: func(c *cli.CLI, args []string) {
+		err := cli.CheckArgc(args, []int{1})
+		if err == nil {
+			err = c.User.(*userApp).saveState(args[0])
+		}
+		if err != nil {
+			c.User.Put(fmt.Sprintf("%s\n", err))
+		}
+	},
+}
+
+var cmdLoad = cli.Leaf{
+	Descr: "load the machine state",
+	F: func(c *cli.CLI, args []string) {
+		err := cli.CheckArgc(args, []int{1})
+		if err == nil {
+			err = c.User.(*userApp).loadState(args[0])
+		}
+		if err != nil {
+			c.User.Put(fmt.Sprintf("%s\n", err))
+			return
+		}
+		cmdRegisters.F(c, nil)
 	},
 }
 
@@ -277,14 +512,21 @@ var cmdReset = cli.Leaf{
 
 // root menu
 var menuRoot = cli.Menu{
+	{"back", cmdBack, helpBack},
 	{"da", cmdDisassemble, helpDisassemble},
 	{"exit", cmdExit},
 	{"go", cmdGo, helpGo},
 	{"help", cmdHelp},
 	{"history", cmdHistory, cli.HistoryHelp},
+	{"irq", cmdIrq, helpIrq},
+	{"load", cmdLoad, helpState},
+	{"map", cmdMap},
 	{"md", cmdMemDisplay, helpMemDisplay},
 	{"regs", cmdRegisters},
 	{"reset", cmdReset},
+	{"rgo", cmdReverseGo, helpReverseGo},
+	{"rstep", cmdReverseStep},
+	{"save", cmdSave, helpState},
 	{"stack", cmdStack},
 	{"step", cmdStep, helpGo},
 	{"trace", cmdTrace, helpGo},
@@ -0,0 +1,253 @@
+//-----------------------------------------------------------------------------
+/*
+
+Execution History
+
+The history records the cpu registers before each step and the memory writes
+done by the step in a bounded ring, with the bank selects after a step changes
+them. Full snapshots of the cpu, RAM and banked memory are taken periodically.
+Rewinding restores the last snapshot before the target and replays the
+recorded writes to RAM and the bank selects up to it. Writes to devices are
+not repeated and no code is re-executed.
+
+*/
+//-----------------------------------------------------------------------------
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/deadsy/bender/bus"
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+const histSize = 1 << 16      // steps in the history ring
+const histSnapInterval = 4096 // steps between full snapshots
+
+// histWrite is a memory write on the bus, or a change of the bank selects.
+type histWrite struct {
+	adr uint16
+	val uint8
+	sel *bus.Selects // bank selects (the write is a marker if set)
+}
+
+// histEntry is the state before a step and the writes done by it.
+type histEntry struct {
+	pc      uint16
+	a, x, y uint8
+	s, p    uint8
+	cycles  uint
+	writes  []histWrite // new values
+}
+
+// histSnap is a full snapshot taken before a step.
+type histSnap struct {
+	step uint64
+	cpu  *cpu.Snapshot
+	ram  []uint8
+	bus  *bus.State // banked memory
+}
+
+type history struct {
+	bus     *bus.Bus // bus with the banked memory
+	entries []histEntry
+	snaps   []*histSnap // oldest first
+	oldest  uint64      // oldest step in the ring
+	next    uint64      // next step to be recorded
+	cur     *histEntry  // step being recorded
+	remap   bool        // the step being recorded has changed the bank selects
+}
+
+func newHistory(b *bus.Bus) *history {
+	return &history{
+		bus:     b,
+		entries: make([]histEntry, histSize),
+	}
+}
+
+// clear discards the history (e.g. when memory is changed by hand).
+func (h *history) clear() {
+	h.snaps = nil
+	h.oldest = h.next
+	h.cur = nil
+	h.remap = false
+}
+
+// entry returns the entry for a step.
+func (h *history) entry(step uint64) *histEntry {
+	return &h.entries[step%histSize]
+}
+
+// begin records the state before a step.
+func (h *history) begin(m *cpu.M6502, mem *memory) {
+	step := h.next
+	if step%histSnapInterval == 0 || len(h.snaps) == 0 {
+		s := &histSnap{
+			step: step,
+			cpu:  m.Snapshot(),
+			ram:  append([]uint8(nil), mem.ram[:]...),
+			bus:  mem.bus.Save(),
+		}
+		h.snaps = append(h.snaps, s)
+	}
+	e := h.entry(step)
+	e.pc, e.a, e.x, e.y, e.s, e.p = m.PC, m.A, m.X, m.Y, m.S, m.P
+	e.cycles = m.Cycles()
+	e.writes = e.writes[:0]
+	h.cur = e
+	h.next++
+	// the ring is full: drop the oldest step and any snapshot that can't be replayed
+	if h.next-h.oldest > histSize {
+		h.oldest = h.next - histSize
+		for len(h.snaps) > 0 && h.snaps[0].step < h.oldest {
+			h.snaps = h.snaps[1:]
+		}
+	}
+}
+
+// remapped records a change of the memory map by the current step.
+func (h *history) remapped() {
+	if h.cur != nil {
+		h.remap = true
+	}
+}
+
+// selects records the bank selects if the current step has changed them.
+func (h *history) selects() {
+	if h.remap {
+		h.cur.writes = append(h.cur.writes, histWrite{sel: h.bus.SaveSelects()})
+		h.remap = false
+	}
+}
+
+// write records a memory write by the current step.
+func (h *history) write(adr uint16, val uint8) {
+	if h.cur != nil {
+		h.selects()
+		h.cur.writes = append(h.cur.writes, histWrite{adr: adr, val: val})
+	}
+}
+
+// end ends the recording of a step.
+func (h *history) end() {
+	if h.cur != nil {
+		h.selects()
+	}
+	h.cur = nil
+}
+
+// cancel ends the recording of a step, dropping it if it didn't run.
+func (h *history) cancel(m *cpu.M6502) {
+	if e := h.cur; e != nil && m.Cycles() == e.cycles && len(e.writes) == 0 {
+		h.next--
+		if n := len(h.snaps); n > 0 && h.snaps[n-1].step == h.next {
+			h.snaps = h.snaps[:n-1]
+		}
+	}
+	h.cur = nil
+	h.remap = false
+}
+
+// first returns the oldest step that can be rewound to.
+func (h *history) first() uint64 {
+	if len(h.snaps) == 0 {
+		return h.next
+	}
+	if h.snaps[0].step > h.oldest {
+		return h.snaps[0].step
+	}
+	return h.oldest
+}
+
+// lastWrite returns the most recent step that wrote to the address.
+func (h *history) lastWrite(adr uint16) (uint64, bool) {
+	for step := h.next; step > h.first(); {
+		step--
+		for _, w := range h.entry(step).writes {
+			if w.sel == nil && w.adr == adr {
+				return step, true
+			}
+		}
+	}
+	return 0, false
+}
+
+//-----------------------------------------------------------------------------
+
+// rewind returns the machine to the state before a recorded step.
+// The steps after it are discarded.
+func (u *userApp) rewind(step uint64) error {
+	h := u.hist
+	if step < h.first() || step >= h.next {
+		return errors.New("step is not in the history")
+	}
+	// the last snapshot at or before the step
+	var snap *histSnap
+	for _, s := range h.snaps {
+		if s.step <= step {
+			snap = s
+		}
+	}
+	copy(u.mem.ram[:], snap.ram)
+	if err := u.mem.bus.Restore(snap.bus); err != nil {
+		return err
+	}
+	// replay the writes to RAM and the bank selects
+	for i := snap.step; i < step; i++ {
+		for _, w := range h.entry(i).writes {
+			if w.sel != nil {
+				if err := u.mem.bus.RestoreSelects(w.sel); err != nil {
+					return err
+				}
+				continue
+			}
+			u.mem.bus.PokeRAM(w.adr, w.val)
+		}
+	}
+	// the cpu state
+	e := h.entry(step)
+	s := *snap.cpu
+	s.PC, s.A, s.X, s.Y, s.S, s.P = e.pc, e.a, e.x, e.y, e.s, e.p
+	s.Cycles = e.cycles
+	s.Illegal, s.Jam, s.Exit = false, false, false
+	s.LastPC, s.StuckPC = 0, 0
+	if err := u.cpu.Restore(&s); err != nil {
+		return err
+	}
+	// discard the future
+	h.next = step
+	for len(h.snaps) > 0 && h.snaps[len(h.snaps)-1].step > step {
+		h.snaps = h.snaps[:len(h.snaps)-1]
+	}
+	return nil
+}
+
+// back rewinds n steps.
+func (u *userApp) back(n uint64) error {
+	if u.hist == nil {
+		return errors.New("no history for the 65816")
+	}
+	h := u.hist
+	if n > h.next-h.first() {
+		return fmt.Errorf("only %d steps of history", h.next-h.first())
+	}
+	return u.rewind(h.next - n)
+}
+
+// backWrite rewinds to the last step that wrote to an address.
+func (u *userApp) backWrite(adr uint16) error {
+	if u.hist == nil {
+		return errors.New("no history for the 65816")
+	}
+	step, ok := u.hist.lastWrite(adr)
+	if !ok {
+		return fmt.Errorf("no write to %04x in the history", adr)
+	}
+	return u.rewind(step)
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,260 @@
+//-----------------------------------------------------------------------------
+/*
+
+Execution History Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deadsy/bender/bus"
+)
+
+//-----------------------------------------------------------------------------
+
+// testApp returns a 6502 user application running code at $0400.
+func testApp(code ...uint8) *userApp {
+	u := newUserApp(nil)
+	u.cpu.Power(true)
+	for i, v := range code {
+		u.mem.ram[0x0400+i] = v
+	}
+	u.cpu.PC = 0x0400
+	return u
+}
+
+// histState is the state compared after a rewind.
+type histState struct {
+	pc     uint16
+	a, x   uint8
+	cycles uint
+	mem    [4]uint8 // $10, $20, $8000, $8001
+	bank   int
+}
+
+func (u *userApp) histState() histState {
+	return histState{
+		pc:     u.cpu.PC,
+		a:      u.cpu.A,
+		x:      u.cpu.X,
+		cycles: u.cpu.Cycles(),
+		mem:    [4]uint8{u.mem.Peek8(0x10), u.mem.Peek8(0x20), u.mem.Peek8(0x8000), u.mem.Peek8(0x8001)},
+	}
+}
+
+// testSteps runs n steps and returns the state before each of them.
+func testSteps(t *testing.T, u *userApp, n int) []histState {
+	t.Helper()
+	states := make([]histState, n)
+	for i := range states {
+		states[i] = u.histState()
+		if err := u.run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return states
+}
+
+// counter counts in $10 and X, and stores to $20 once.
+var counter = []uint8{
+	0xa9, 0x55, // lda #$55
+	0x85, 0x20, // sta $20
+	0x18,       // loop: clc
+	0x65, 0x10, // adc $10
+	0xe6, 0x10, // inc $10
+	0xe8,             // inx
+	0x4c, 0x04, 0x04, // jmp loop
+}
+
+//-----------------------------------------------------------------------------
+
+func TestHistoryRewind(t *testing.T) {
+	u := testApp(counter...)
+	// cross several snapshots
+	n := 3*histSnapInterval + 100
+	states := testSteps(t, u, n)
+	end := u.histState()
+	for _, back := range []int{1, 100, histSnapInterval, n - 1, n} {
+		u := testApp(counter...)
+		testSteps(t, u, n)
+		if err := u.back(uint64(back)); err != nil {
+			t.Fatalf("back %d: %v", back, err)
+		}
+		if s := u.histState(); s != states[n-back] {
+			t.Fatalf("back %d: state %+v, want %+v", back, s, states[n-back])
+		}
+		// replay to the end
+		testSteps(t, u, back)
+		if s := u.histState(); s != end {
+			t.Errorf("back %d: replayed to %+v, want %+v", back, s, end)
+		}
+	}
+}
+
+func TestHistoryWrap(t *testing.T) {
+	u := testApp(counter...)
+	testSteps(t, u, histSize+histSnapInterval+10)
+	h := u.hist
+	if h.next-h.oldest != histSize {
+		t.Fatalf("%d steps in the ring", h.next-h.oldest)
+	}
+	// the oldest snapshot that can be replayed bounds the history
+	if h.first() != 2*histSnapInterval {
+		t.Errorf("first step %d, want %d", h.first(), 2*histSnapInterval)
+	}
+	n := h.next - h.first()
+	if err := u.back(n + 1); err == nil {
+		t.Errorf("rewound past the history")
+	}
+	if err := u.back(n); err != nil {
+		t.Fatal(err)
+	}
+	// the state at the first step: 5 steps per loop after the two setup steps
+	loops := (2*histSnapInterval - 2) / 5
+	if u.cpu.X != uint8(loops) {
+		t.Errorf("x %02x, want %02x", u.cpu.X, uint8(loops))
+	}
+}
+
+func TestHistoryLastWrite(t *testing.T) {
+	u := testApp(counter...)
+	testSteps(t, u, 1000)
+	if err := u.backWrite(0x20); err != nil {
+		t.Fatal(err)
+	}
+	// the step before the sta $20
+	if u.cpu.PC != 0x0402 || u.mem.Peek8(0x20) != 0xff || u.hist.next != 1 {
+		t.Errorf("rewound to %04x, $20 = %02x", u.cpu.PC, u.mem.Peek8(0x20))
+	}
+	if err := u.backWrite(0x30); err == nil {
+		t.Errorf("rewound to a write that didn't happen")
+	}
+}
+
+func TestHistoryBanks(t *testing.T) {
+	u := testApp(
+		0xa9, 0x01, // lda #1
+		0x8d, 0x00, 0x70, // sta $7000
+		0xa9, 0xaa, // lda #$aa
+		0x8d, 0x00, 0x80, // sta $8000
+		0xa9, 0x02, // lda #2
+		0x8d, 0x00, 0x70, // sta $7000
+		0xa9, 0xbb, // lda #$bb
+		0x8d, 0x01, 0x80, // sta $8001
+		0x4c, 0x14, 0x04, // jmp *
+	)
+	// 4 banks of RAM at $8000 selected by a latch at $7000
+	pool := bus.NewPool(4, 0x1000)
+	w, err := pool.Window(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := bus.NewLatch()
+	l.Control(w, 0, 0x03, 0)
+	u.mem.bus.Device("bank", 0x8000, 0x8fff, w)
+	u.mem.bus.Device("latch", 0x7000, 0x7000, l)
+	u.hist.clear()
+
+	states := testSteps(t, u, 8)
+	for i := range states {
+		states[i].bank = []int{0, 0, 1, 1, 1, 1, 2, 2}[i]
+	}
+	end := u.histState()
+	for back := 1; back <= len(states); back++ {
+		if err := u.back(1); err != nil {
+			t.Fatal(err)
+		}
+		s := u.histState()
+		s.bank = w.Bank()
+		if want := states[len(states)-back]; s != want || l.Value() != uint8(want.bank) {
+			t.Errorf("back %d: state %+v, want %+v", back, s, want)
+		}
+	}
+	// bank 1 is empty again
+	if pool.Bank(1)[0] != 0 {
+		t.Errorf("bank 1 holds %02x", pool.Bank(1)[0])
+	}
+	testSteps(t, u, 8)
+	if s := u.histState(); s != end || pool.Bank(1)[0] != 0xaa || pool.Bank(2)[1] != 0xbb {
+		t.Errorf("replayed to %+v, want %+v", s, end)
+	}
+}
+
+// testDevice counts the accesses to it.
+type testDevice struct {
+	reads, writes int
+}
+
+func (d *testDevice) Read8(ofs uint16) uint8 {
+	d.reads++
+	return 0
+}
+
+func (d *testDevice) Write8(ofs uint16, val uint8) {
+	d.writes++
+}
+
+func TestHistoryDevice(t *testing.T) {
+	u := testApp(
+		0xa9, 0x01, // lda #1
+		0x8d, 0x00, 0x60, // sta $6000
+		0x85, 0x10, // sta $10
+		0x8d, 0x00, 0x60, // sta $6000
+		0xe6, 0x10, // inc $10
+		0x8d, 0x00, 0x60, // sta $6000
+	)
+	d := &testDevice{}
+	u.mem.bus.Device("uart", 0x6000, 0x6000, d)
+	u.hist.clear()
+
+	states := testSteps(t, u, 6)
+	if d.writes != 3 {
+		t.Fatalf("%d device writes", d.writes)
+	}
+	// rewinding restores the RAM without writing to the device again
+	for _, n := range []uint64{1, 2, 3} {
+		if err := u.back(n); err != nil {
+			t.Fatal(err)
+		}
+		if s, want := u.histState(), states[len(states)-int(n)]; s != want {
+			t.Errorf("back %d: state %+v, want %+v", n, s, want)
+		}
+		testSteps(t, u, int(n))
+	}
+	if d.writes != 3+1+1+2 || d.reads != 0 {
+		t.Errorf("%d device writes, %d reads after the rewinds", d.writes, d.reads)
+	}
+}
+
+func TestHistoryRunUntil(t *testing.T) {
+	// runUntil records the same history as stepping
+	u := testApp(counter...)
+	states := testSteps(t, u, 200)
+	end := u.histState()
+	u = testApp(counter...)
+	err := u.runUntil(context.Background(), func() bool {
+		return u.hist.next == 200
+	})
+	if err != nil || u.histState() != end {
+		t.Fatalf("ran to %+v, want %+v (%v)", u.histState(), end, err)
+	}
+	if err := u.back(150); err != nil || u.histState() != states[50] {
+		t.Fatalf("back to %+v, want %+v (%v)", u.histState(), states[50], err)
+	}
+	// a step that doesn't run isn't recorded
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := u.runUntil(ctx, func() bool { return false }); err != context.Canceled {
+		t.Fatalf("cancelled run: %v", err)
+	}
+	if u.hist.next != 50 || u.histState() != states[50] {
+		t.Errorf("cancelled run recorded %d steps", u.hist.next)
+	}
+}
+
+//-----------------------------------------------------------------------------
@@ -9,11 +9,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
 
+	"github.com/deadsy/bender/bus"
 	"github.com/deadsy/bender/cpu"
 	cli "github.com/deadsy/go-cli"
 )
@@ -27,17 +33,79 @@ const historyPath = "history.txt"
 
 type memory struct {
 	ram   [64 << 10]uint8
-	spAdr uint8 // sim6502: zero page stack pointer address
+	bus   *bus.Bus              // 64K address space (mapped onto ram)
+	banks [256]*[64 << 10]uint8 // 65816: banks 1..255 (allocated on write)
+	spAdr uint8                 // sim6502: zero page stack pointer address
+	hist  *history              // execution history (if any)
 }
 
 // Read8 reads a byte from memory.
 func (m *memory) Read8(adr uint16) uint8 {
-	return m.ram[adr]
+	return m.bus.Read8(adr)
 }
 
 // Write8 writes a byte to memory.
 func (m *memory) Write8(adr uint16, val uint8) {
-	m.ram[adr] = val
+	m.bus.Write8(adr, val)
+	if m.hist != nil {
+		m.hist.write(adr, val)
+	}
+}
+
+// Peek8 reads a byte from memory for the debugger.
+func (m *memory) Peek8(adr uint16) uint8 {
+	return m.bus.Peek8(adr)
+}
+
+// Poke8 writes a byte to memory for the debugger.
+// The execution history can't replay the change, so it is discarded.
+func (m *memory) Poke8(adr uint16, val uint8) {
+	if m.hist != nil {
+		m.hist.clear()
+	}
+	m.bus.Poke8(adr, val)
+}
+
+// ReadLong reads a byte from a 24-bit address.
+func (m *memory) ReadLong(adr uint32) uint8 {
+	bank := adr >> 16
+	if bank == 0 {
+		return m.Read8(uint16(adr))
+	}
+	if m.banks[bank] == nil {
+		return 0xff
+	}
+	return m.banks[bank][uint16(adr)]
+}
+
+// WriteLong writes a byte to a 24-bit address.
+func (m *memory) WriteLong(adr uint32, val uint8) {
+	bank := adr >> 16
+	if bank == 0 {
+		m.Write8(uint16(adr), val)
+		return
+	}
+	if m.banks[bank] == nil {
+		m.banks[bank] = new([64 << 10]uint8)
+		for i := range m.banks[bank] {
+			m.banks[bank][i] = 0xff
+		}
+	}
+	m.banks[bank][uint16(adr)] = val
+}
+
+// longView is a read only view of a 64K bank of 65816 memory.
+type longView struct {
+	m    *memory
+	base uint32
+}
+
+func (v *longView) Read8(adr uint16) uint8 {
+	return v.m.ReadLong(v.base | uint32(adr))
+}
+
+func (v *longView) Write8(adr uint16, val uint8) {
+	// read only
 }
 
 func (m *memory) read16(adr uint16) uint16 {
@@ -63,27 +131,222 @@ func newMemory() *memory {
 	for i := range m.ram {
 		m.ram[i] = 0xff
 	}
+	m.bus = bus.New()
+	m.bus.RAM("ram", 0, m.ram[:])
 	return &m
 }
 
 //-----------------------------------------------------------------------------
 
+// cpuTypes maps cpu names onto cpu variants.
+var cpuTypes = map[string]cpu.Variant{
+	"6502":    cpu.Variant6502,
+	"65c02":   cpu.Variant65C02,
+	"r65c02":  cpu.VariantR65C02,
+	"w65c02s": cpu.VariantW65C02S,
+	"2a03":    cpu.Variant2A03,
+	"6510":    cpu.Variant6510,
+}
+
 // userApp is state associated with the user application.
 type userApp struct {
-	mem *memory
-	cpu *cpu.M6502
+	mem     *memory
+	cpu     *cpu.M6502
+	cpu816  *cpu.M65816 // 65816 cpu (replaces cpu)
+	hist    *history    // execution history (6502 only)
+	opts    []cpu.Option
+	nestest bool // trace in the nestest.log format
 }
 
 // newUserApp returns a user application.
-func newUserApp() *userApp {
+func newUserApp(opts []cpu.Option) *userApp {
+	mem := newMemory()
+	cpu := cpu.New6502(mem, opts...)
+	mem.hist = newHistory(mem.bus)
+	u := &userApp{
+		mem:  mem,
+		cpu:  cpu,
+		opts: opts,
+		hist: mem.hist,
+	}
+	// bank switches drop the predecoded instructions and are recorded in the history
+	mem.bus.OnRemap(func(start, end uint16) {
+		u.cpu.Invalidate(start, end)
+		u.hist.remapped()
+	})
+	return u
+}
+
+// newUserApp816 returns a user application with a 65816 cpu.
+func newUserApp816() *userApp {
 	mem := newMemory()
-	cpu := cpu.New6502(mem)
 	return &userApp{
-		mem: mem,
-		cpu: cpu,
+		mem:    mem,
+		cpu816: cpu.New65816(mem),
 	}
 }
 
+//-----------------------------------------------------------------------------
+// cpu access (6502 or 65816)
+
+// maxAdr returns the maximum cpu address.
+func (u *userApp) maxAdr() int {
+	if u.cpu816 != nil {
+		return 0xffffff
+	}
+	return 0xffff
+}
+
+// pc returns the program counter.
+func (u *userApp) pc() uint32 {
+	if u.cpu816 != nil {
+		return u.cpu816.ReadPC()
+	}
+	return uint32(u.cpu.PC)
+}
+
+// setPC sets the program counter.
+func (u *userApp) setPC(adr uint32) {
+	if u.cpu816 != nil {
+		u.cpu816.PBR = uint8(adr >> 16)
+		u.cpu816.PC = uint16(adr)
+		return
+	}
+	u.cpu.PC = uint16(adr)
+}
+
+// run runs the cpu for a single instruction.
+func (u *userApp) run() error {
+	if u.cpu816 != nil {
+		return u.cpu816.Run()
+	}
+	u.hist.begin(u.cpu, u.mem)
+	defer u.hist.end()
+	return u.cpu.Run()
+}
+
+// runUntil runs the cpu until the predicate (called after each step) is true,
+// there is an error or the context is done.
+func (u *userApp) runUntil(ctx context.Context, until func() bool) error {
+	if u.cpu816 != nil {
+		return u.cpu816.RunUntil(ctx, func(m *cpu.M65816) bool {
+			return until()
+		})
+	}
+	// record each step in the history
+	u.hist.begin(u.cpu, u.mem)
+	defer u.hist.cancel(u.cpu)
+	return u.cpu.RunUntil(ctx, func(m *cpu.M6502) bool {
+		u.hist.end()
+		if until() {
+			return true
+		}
+		u.hist.begin(m, u.mem)
+		return false
+	})
+}
+
+// dump returns a display string for the cpu registers.
+func (u *userApp) dump() string {
+	if u.cpu816 != nil {
+		return u.cpu816.Dump()
+	}
+	return u.cpu.Dump()
+}
+
+// disassemble returns the disassembly for a region of memory.
+func (u *userApp) disassemble(adr uint32, size int) string {
+	if u.cpu816 != nil {
+		return u.cpu816.Disassemble(adr, size)
+	}
+	return u.cpu.Disassemble(uint16(adr), size)
+}
+
+// disassembleView returns the disassembly for a region of a memory view.
+func (u *userApp) disassembleView(mem cpu.Memory, adr uint16, size int) string {
+	s := make([]string, 0, 16)
+	for size > 0 {
+		da := cpu.DisassembleVariant(u.cpu.Variant(), mem, adr, nil)
+		s = append(s, da.String())
+		n := len(da.Bytes)
+		size -= n
+		adr += uint16(n)
+	}
+	return strings.Join(s, "\n")
+}
+
+// bankView returns a view of memory with a bank selected.
+// The 6502 sees the bank in the banked window at the address, the 65816 sees
+// the 64K bank.
+func (u *userApp) bankView(bank int, adr uint16) (cpu.Memory, error) {
+	if u.cpu816 != nil {
+		return &longView{u.mem, uint32(bank) << 16}, nil
+	}
+	n := u.mem.bus.Banks(adr)
+	if n == 0 {
+		return nil, fmt.Errorf("%04x is not banked", adr)
+	}
+	if bank >= n {
+		return nil, fmt.Errorf("%04x has %d banks", adr, n)
+	}
+	return u.mem.bus.BankView(bank), nil
+}
+
+// traceLine returns the trace line for the instruction at the PC.
+func (u *userApp) traceLine() string {
+	if u.nestest && u.cpu != nil {
+		return u.cpu.NestestTrace()
+	}
+	return u.disassemble(u.pc(), 1)
+}
+
+// reset powers up and resets the cpu.
+func (u *userApp) reset() {
+	if u.cpu816 != nil {
+		u.cpu816.Power(false)
+		u.cpu816.Power(true)
+		u.cpu816.Reset()
+		return
+	}
+	u.cpu.Power(false)
+	u.cpu.Power(true)
+	u.cpu.Reset()
+	u.hist.clear()
+}
+
+// interrupts returns the interrupt sources of the cpu.
+func (u *userApp) interrupts() *cpu.Interrupts {
+	if u.cpu816 != nil {
+		return u.cpu816.Interrupts()
+	}
+	return u.cpu.Interrupts()
+}
+
+// haltState returns a description of a halted (waiting/stopped) cpu, or "".
+func (u *userApp) haltState() string {
+	var waiting, stopped bool
+	if u.cpu816 != nil {
+		waiting, stopped = u.cpu816.Waiting(), u.cpu816.Stopped()
+	} else {
+		waiting, stopped = u.cpu.Waiting(), u.cpu.Stopped()
+	}
+	if waiting {
+		return fmt.Sprintf("waiting for interrupt at %s", u.pcString())
+	}
+	if stopped {
+		return fmt.Sprintf("stopped at %s", u.pcString())
+	}
+	return ""
+}
+
+// pcString returns the program counter as a display string.
+func (u *userApp) pcString() string {
+	if u.cpu816 != nil {
+		return fmt.Sprintf("%02x:%04x", u.cpu816.PBR, u.cpu816.PC)
+	}
+	return fmt.Sprintf("%04x", u.cpu.PC)
+}
+
 //-----------------------------------------------------------------------------
 // file loading
 
@@ -97,7 +360,16 @@ func (u *userApp) loadSim6502(filename string, x []uint8) (string, error) {
 	if x[5] != 2 {
 		return "", fmt.Errorf("%s: bad version", filename)
 	}
-	if x[6] != 0 {
+	switch x[6] {
+	case 0:
+		// 6502
+	case 1:
+		// 65c02: upgrade a 6502
+		if u.cpu != nil && u.cpu.Variant() == cpu.Variant6502 {
+			u.opts = append(u.opts, cpu.WithVariant(cpu.Variant65C02))
+			u.cpu = cpu.New6502(u.mem, u.opts...)
+		}
+	default:
 		return "", fmt.Errorf("%s: bad cpu type", filename)
 	}
 
@@ -116,6 +388,15 @@ func (u *userApp) loadSim6502(filename string, x []uint8) (string, error) {
 	u.mem.write16(cpu.RstAddress, rstAdr)
 
 	// Add the sim6502 VSRs
+	if u.cpu816 != nil {
+		u.cpu816.AddVSR(0xfff4, vsrOpen816)
+		u.cpu816.AddVSR(0xfff5, vsrClose816)
+		u.cpu816.AddVSR(0xfff6, vsrRead816)
+		u.cpu816.AddVSR(0xfff7, vsrWrite816)
+		u.cpu816.AddVSR(0xfff8, vsrArgs816)
+		u.cpu816.AddVSR(0xfff9, vsrExit816)
+		return fmt.Sprintf("%s code %04x-%04x reset %04x sp %02x", filename, loadAdr, endAdr, rstAdr, u.mem.spAdr), nil
+	}
 	u.cpu.AddVSR(0xfff4, vsrOpen)
 	u.cpu.AddVSR(0xfff5, vsrClose)
 	u.cpu.AddVSR(0xfff6, vsrRead)
@@ -126,6 +407,66 @@ func (u *userApp) loadSim6502(filename string, x []uint8) (string, error) {
 	return fmt.Sprintf("%s code %04x-%04x reset %04x sp %02x", filename, loadAdr, endAdr, rstAdr, u.mem.spAdr), nil
 }
 
+// loadNES loads an iNES file (NROM and UxROM mappers only).
+func (u *userApp) loadNES(filename string, x []uint8) (string, error) {
+
+	if len(x) < 16 {
+		return "", fmt.Errorf("%s: short header", filename)
+	}
+	mapper := (x[6] >> 4) | (x[7] & 0xf0)
+	if mapper != 0 && mapper != 2 {
+		return "", fmt.Errorf("%s: mapper %d not supported", filename, mapper)
+	}
+
+	// skip the header and any trainer
+	ofs := 16
+	if x[6]&4 != 0 {
+		ofs += 512
+	}
+	prgSize := int(x[4]) * (16 << 10)
+	if mapper == 0 && prgSize != 16<<10 && prgSize != 32<<10 || prgSize == 0 {
+		return "", fmt.Errorf("%s: bad prg rom size %d", filename, prgSize)
+	}
+	if len(x) < ofs+prgSize {
+		return "", fmt.Errorf("%s: short prg rom", filename)
+	}
+	prg := x[ofs : ofs+prgSize]
+
+	if mapper == 2 {
+		// UxROM: a write to the rom selects the 16K bank at $8000, the last bank is fixed at $c000
+		pool := bus.NewPool(int(x[4]), 16<<10)
+		for i := 0; i < pool.Banks(); i++ {
+			pool.LoadROM(i, prg[i*(16<<10):(i+1)*(16<<10)])
+		}
+		lo, err := pool.Window(0)
+		if err != nil {
+			return "", fmt.Errorf("%s: %s", filename, err)
+		}
+		hi, err := pool.Window(pool.Banks() - 1)
+		if err != nil {
+			return "", fmt.Errorf("%s: %s", filename, err)
+		}
+		latch := bus.NewLatch()
+		latch.Control(lo, 0, 0xff, 0)
+		lo.WriteLatch(latch)
+		hi.WriteLatch(latch)
+		u.mem.bus.Device("prg", 0x8000, 0xbfff, lo)
+		u.mem.bus.Device("prg", 0xc000, 0xffff, hi)
+	} else {
+		// NROM-128 is mirrored at $8000 and $c000, NROM-256 fills $8000-$ffff
+		copy(u.mem.ram[0x8000:], prg)
+		u.mem.bus.ROM("prg", 0x8000, u.mem.ram[0x8000:0x8000+prgSize], false)
+		if prgSize == 16<<10 {
+			u.mem.bus.Mirror("prg", 0xc000, 0xffff, 0x8000, prgSize)
+		}
+	}
+	if u.hist != nil {
+		u.hist.clear()
+	}
+
+	return fmt.Sprintf("%s mapper %d prg rom %dk reset %04x", filename, mapper, prgSize>>10, u.mem.read16(cpu.RstAddress)), nil
+}
+
 // loadRaw loads a raw binary file.
 func (u *userApp) loadRaw(filename string, x []uint8) (string, error) {
 
@@ -151,6 +492,9 @@ func (u *userApp) loadFile(filename string) (string, error) {
 	if string(x[0:5]) == "sim65" {
 		return u.loadSim6502(filename, x)
 	}
+	if string(x[0:4]) == "NES\x1a" {
+		return u.loadNES(filename, x)
+	}
 
 	return u.loadRaw(filename, x)
 }
@@ -164,13 +508,70 @@ func (u *userApp) Put(s string) {
 
 //-----------------------------------------------------------------------------
 
+// interruptible returns a context that is cancelled by a SIGINT (ctrl-c),
+// and a function to call when the context is no longer needed.
+func interruptible() (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sig)
+		cancel()
+	}
+}
+
+// exitCode maps a run error onto a process exit code.
+// A program exit passes on its status, a stuck PC (the trap loops of a test
+// program) is 2 and anything else is 1.
+func exitCode(err error) int {
+	var exit *cpu.ExitError
+	switch {
+	case errors.As(err, &exit):
+		return int(exit.Status)
+	case errors.Is(err, cpu.ErrStuck):
+		return 2
+	}
+	return 1
+}
+
+//-----------------------------------------------------------------------------
+
 func main() {
 	// command line flags
-	fname := flag.String("f", "out.bin", "file to load (sim6502 or raw)")
+	fname := flag.String("f", "out.bin", "file to load (sim6502, iNES or raw)")
+	strict := flag.Bool("strict", false, "treat undocumented opcodes as illegal")
+	cpuType := flag.String("cpu", "6502", "cpu type (6502, 6510, 65c02, r65c02, w65c02s, 2a03, 65816)")
+	nestest := flag.Bool("nestest", false, "trace in the nestest.log format")
+	startPC := flag.String("pc", "", "start address (hex) - default is the reset vector")
+	batch := flag.Int("n", 0, "trace n instructions without the cli and exit")
 	flag.Parse()
 
+	// cpu options
+	var opts []cpu.Option
+	if *strict {
+		opts = append(opts, cpu.Strict())
+	}
+
 	// create the application
-	app := newUserApp()
+	var app *userApp
+	if *cpuType == "65816" {
+		app = newUserApp816()
+	} else {
+		v, ok := cpuTypes[*cpuType]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown cpu type \"%s\"\n", *cpuType)
+			os.Exit(1)
+		}
+		app = newUserApp(append(opts, cpu.WithVariant(v)))
+	}
+	app.nestest = *nestest
 
 	// load the file
 	status, err := app.loadFile(*fname)
@@ -182,17 +583,36 @@ func main() {
 		fmt.Fprintf(os.Stderr, "%s\n", status)
 	}
 
+	// reset the cpu
+	app.reset()
+	if *startPC != "" {
+		adr, err := strconv.ParseUint(*startPC, 16, 32)
+		if err != nil || int(adr) > app.maxAdr() {
+			fmt.Fprintf(os.Stderr, "bad start address \"%s\"\n", *startPC)
+			os.Exit(1)
+		}
+		app.setPC(uint32(adr))
+	}
+
+	// headless tracing
+	if *batch > 0 {
+		for i := 0; i < *batch; i++ {
+			fmt.Printf("%s\n", app.traceLine())
+			err := app.run()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", err)
+				os.Exit(exitCode(err))
+			}
+		}
+		os.Exit(0)
+	}
+
 	// create the cli
 	c := cli.NewCLI(app)
 	c.HistoryLoad(historyPath)
 	c.SetRoot(menuRoot)
 	c.SetPrompt("emu> ")
 
-	// reset the cpu
-	app.cpu.Power(false)
-	app.cpu.Power(true)
-	app.cpu.Reset()
-
 	// run the cli
 	for c.Running() {
 		c.Run()
@@ -0,0 +1,54 @@
+//-----------------------------------------------------------------------------
+/*
+
+Emulator Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+// testApp816 returns a 65816 user application running code at $0400.
+func testApp816(code ...uint8) *userApp {
+	u := newUserApp816()
+	u.reset()
+	for i, v := range code {
+		u.mem.ram[0x0400+i] = v
+	}
+	u.setPC(0x0400)
+	return u
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		code []uint8
+		exit int
+	}{
+		{"exit", []uint8{0xa9, 0x03, 0x20, 0x00, 0x05}, 3}, // lda #3, jsr exit
+		{"stuck", []uint8{0x4c, 0x00, 0x04}, 2},            // jmp *
+	}
+	for _, tt := range tests {
+		u := testApp(tt.code...)
+		u.cpu.AddVSR(0x0500, func(m *cpu.M6502) { m.Exit(m.A) })
+		u816 := testApp816(tt.code...)
+		u816.cpu816.AddVSR(0x0500, func(m *cpu.M65816) { m.Exit(uint8(m.A)) })
+		for _, u := range []*userApp{u, u816} {
+			err := u.runUntil(context.Background(), func() bool { return false })
+			if code := exitCode(err); code != tt.exit {
+				t.Errorf("%s (65816 %t): exit code %d for %v", tt.name, u.cpu816 != nil, code, err)
+			}
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,116 @@
+//-----------------------------------------------------------------------------
+/*
+
+Machine State Files
+
+A state file holds the cpu snapshot, the banked memory and the 64K RAM so a
+long run can be checkpointed and restarted.
+
+	header (magic, version, cpu snapshot and bus state lengths, sim6502 stack pointer address)
+	cpu snapshot
+	bus state (bank selects, latches and RAM banks)
+	64K RAM
+
+*/
+//-----------------------------------------------------------------------------
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/deadsy/bender/bus"
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+const stateMagic = "bender\x00\x00"
+const stateVersion = 2
+
+type stateHeader struct {
+	Magic   [8]byte
+	Version uint16
+	CPULen  uint32
+	BusLen  uint32
+	SpAdr   uint8
+}
+
+// saveState writes the machine state to a file.
+func (u *userApp) saveState(filename string) error {
+	if u.cpu816 != nil {
+		return errors.New("state files are not supported for the 65816")
+	}
+	snap, err := u.cpu.Snapshot().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	banks, err := u.mem.bus.Save().MarshalBinary()
+	if err != nil {
+		return err
+	}
+	hdr := stateHeader{
+		Version: stateVersion,
+		CPULen:  uint32(len(snap)),
+		BusLen:  uint32(len(banks)),
+		SpAdr:   u.mem.spAdr,
+	}
+	copy(hdr.Magic[:], stateMagic)
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, &hdr)
+	buf.Write(snap)
+	buf.Write(banks)
+	buf.Write(u.mem.ram[:])
+	return ioutil.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// loadState reads the machine state from a file.
+func (u *userApp) loadState(filename string) error {
+	if u.cpu816 != nil {
+		return errors.New("state files are not supported for the 65816")
+	}
+	x, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var hdr stateHeader
+	r := bytes.NewReader(x)
+	if err := binary.Read(r, binary.LittleEndian, &hdr); err != nil || string(hdr.Magic[:]) != stateMagic {
+		return fmt.Errorf("%s is not a state file", filename)
+	}
+	if hdr.Version != stateVersion {
+		return fmt.Errorf("%s: unsupported state version %d", filename, hdr.Version)
+	}
+	n := binary.Size(&hdr)
+	if len(x) != n+int(hdr.CPULen)+int(hdr.BusLen)+len(u.mem.ram) {
+		return fmt.Errorf("%s: bad length", filename)
+	}
+	x = x[n:]
+	var snap cpu.Snapshot
+	if err := snap.UnmarshalBinary(x[:hdr.CPULen]); err != nil {
+		return fmt.Errorf("%s: %s", filename, err)
+	}
+	x = x[hdr.CPULen:]
+	var banks bus.State
+	if err := banks.UnmarshalBinary(x[:hdr.BusLen]); err != nil {
+		return fmt.Errorf("%s: %s", filename, err)
+	}
+	x = x[hdr.BusLen:]
+	if err := u.mem.bus.Restore(&banks); err != nil {
+		return fmt.Errorf("%s: %s", filename, err)
+	}
+	// the memory goes first, the cpu drops its predecoded code when restored
+	copy(u.mem.ram[:], x)
+	if err := u.cpu.Restore(&snap); err != nil {
+		return fmt.Errorf("%s: %s", filename, err)
+	}
+	u.mem.spAdr = hdr.SpAdr
+	u.hist.clear()
+	return nil
+}
+
+//-----------------------------------------------------------------------------
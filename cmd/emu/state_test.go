@@ -0,0 +1,79 @@
+//-----------------------------------------------------------------------------
+/*
+
+Machine State File Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deadsy/bender/bus"
+)
+
+//-----------------------------------------------------------------------------
+
+func TestStateBanks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "emu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fname := filepath.Join(dir, "state")
+
+	code := []uint8{
+		0xa9, 0x01, // lda #1
+		0x8d, 0x00, 0x70, // sta $7000
+		0xa9, 0xaa, // lda #$aa
+		0x8d, 0x00, 0x80, // sta $8000
+		0xa9, 0x02, // lda #2
+		0x8d, 0x00, 0x70, // sta $7000
+		0xa9, 0xbb, // lda #$bb
+		0x8d, 0x00, 0x80, // sta $8000
+	}
+	// 4 banks of RAM at $8000 selected by a latch at $7000
+	banked := func(u *userApp) (*bus.Pool, *bus.Window) {
+		pool := bus.NewPool(4, 0x1000)
+		w, err := pool.Window(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l := bus.NewLatch()
+		l.Control(w, 0, 0x03, 0)
+		u.mem.bus.Device("bank", 0x8000, 0x8fff, w)
+		u.mem.bus.Device("latch", 0x7000, 0x7000, l)
+		return pool, w
+	}
+	u := testApp(code...)
+	banked(u)
+	testSteps(t, u, 4)
+	if err := u.saveState(fname); err != nil {
+		t.Fatal(err)
+	}
+	want := u.histState()
+
+	// load into a machine with the same map, in another bank
+	u = testApp(code...)
+	pool, w := banked(u)
+	testSteps(t, u, 8)
+	if err := u.loadState(fname); err != nil {
+		t.Fatal(err)
+	}
+	if s := u.histState(); s != want || w.Bank() != 1 || pool.Bank(1)[0] != 0xaa || pool.Bank(2)[0] != 0 {
+		t.Errorf("loaded %+v bank %d, want %+v bank 1", s, w.Bank(), want)
+	}
+
+	// a machine without the banked memory can't load it
+	u = testApp(code...)
+	if err := u.loadState(fname); err == nil {
+		t.Errorf("loaded into another memory map")
+	}
+}
+
+//-----------------------------------------------------------------------------
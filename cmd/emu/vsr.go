@@ -27,8 +27,7 @@ func setAX(m *cpu.M6502, val uint16) {
 
 const spAddress = 0 // TODO should be u.mem.spAdr
 
-func popParam(m *cpu.M6502, inc uint16) uint16 {
-	mem := m.Mem.(*memory)
+func popParam(mem *memory, inc uint16) uint16 {
 	sp := mem.read16zp(spAddress)
 	val := mem.read16(sp)
 	mem.write16(spAddress, sp+inc)
@@ -48,8 +47,8 @@ func vsrRead(m *cpu.M6502) {
 }
 func vsrWrite(m *cpu.M6502) {
 	n := getAX(m)
-	buf := popParam(m, 2)
-	fd := popParam(m, 2)
+	buf := popParam(m.Mem.(*memory), 2)
+	fd := popParam(m.Mem.(*memory), 2)
 
 	//fmt.Printf("vsrWrite ($%04X, $%04X, $%04X)\n", fd, buf, n)
 	_ = fd
@@ -71,3 +70,45 @@ func vsrExit(m *cpu.M6502) {
 }
 
 //-----------------------------------------------------------------------------
+// 65C816 virtual subroutines (emulation mode abi)
+
+func getAX816(m *cpu.M65816) uint16 {
+	return uint16(uint8(m.A)) + (uint16(uint8(m.X)) << 8)
+}
+
+func setAX816(m *cpu.M65816, val uint16) {
+	m.A = (m.A & 0xff00) | (val & 0xff)
+	m.X = val >> 8
+}
+
+func vsrOpen816(m *cpu.M65816) {
+	fmt.Printf("*** vsrOpen ***\n")
+}
+func vsrClose816(m *cpu.M65816) {
+	fmt.Printf("*** vsrClose ***\n")
+}
+func vsrRead816(m *cpu.M65816) {
+	fmt.Printf("*** vsrRead ***\n")
+}
+func vsrWrite816(m *cpu.M65816) {
+	n := getAX816(m)
+	buf := popParam(m.Mem.(*memory), 2)
+	popParam(m.Mem.(*memory), 2) // fd
+
+	s := make([]uint8, n)
+	for i := range s {
+		s[i] = m.Mem.Read8(buf + uint16(i))
+	}
+	fmt.Printf("%s", string(s))
+
+	setAX816(m, 0)
+}
+func vsrArgs816(m *cpu.M65816) {
+	fmt.Printf("*** vsrArgs ***\n")
+}
+
+func vsrExit816(m *cpu.M65816) {
+	m.Exit(0)
+}
+
+//-----------------------------------------------------------------------------
@@ -3,13 +3,24 @@
 
 Code Generator
 
+Generate the opcode tables for all CPU variants:
+
+go run ./cmd/gen > cpu/opcodes.go
+
+Generate the operations of the cycle stepped core:
+
+go run ./cmd/gen -tick > cpu/tickops.go
+
 */
 //-----------------------------------------------------------------------------
 
 package main
 
 import (
+	"flag"
 	"fmt"
+	"go/format"
+	"os"
 
 	"github.com/deadsy/bender/cpu"
 )
@@ -17,7 +28,25 @@ import (
 //-----------------------------------------------------------------------------
 
 func main() {
-	fmt.Printf("%s\n", cpu.GenOpcodeFunctions())
+	funcs := flag.Bool("funcs", false, "generate template opcode functions")
+	tick := flag.Bool("tick", false, "generate the cycle stepped operations")
+	flag.Parse()
+
+	src := cpu.GenOpcodeTables()
+	if *funcs {
+		src = cpu.GenOpcodeFunctions()
+	}
+	if *tick {
+		src = cpu.GenTickOps()
+	}
+
+	// gofmt the output
+	out, err := format.Source([]byte(src))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s", out)
 }
 
 //-----------------------------------------------------------------------------
@@ -0,0 +1,110 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Emulator Benchmarks
+
+The benchmarks report the emulated clock rate in MHz.
+Run them with: go test -bench . -run XXX
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+//-----------------------------------------------------------------------------
+
+// benchCycles is the number of cycles run per benchmark iteration.
+const benchCycles = 1000000
+
+// functionalTest is the Klaus Dormann 6502 functional test.
+const functionalTest = "../test/test2/6502_functional_test.bin"
+
+// benchMHz reports the emulated clock rate.
+func benchMHz(b *testing.B, cycles uint, d time.Duration) {
+	b.ReportMetric(float64(cycles)/d.Seconds()/1e6, "MHz")
+}
+
+// benchFunctional runs the functional test with a run function.
+func benchFunctional(b *testing.B, v Variant, run func(m *M6502) error) {
+	img, err := ioutil.ReadFile(functionalTest)
+	if err != nil {
+		b.Skip(err)
+	}
+	var r testRAM
+	m := newCPU(&r, v, nil)
+	b.ResetTimer()
+	var cycles uint
+	var elapsed time.Duration
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(r[:], img)
+		m.Invalidate(0, 0xffff)
+		m.Reset()
+		m.PC = 0x0400
+		start := m.Cycles()
+		b.StartTimer()
+		t := time.Now()
+		if err := run(m); err != nil {
+			b.Fatal(err)
+		}
+		elapsed += time.Since(t)
+		cycles += m.Cycles() - start
+	}
+	benchMHz(b, cycles, elapsed)
+}
+
+// runSteps runs the benchmark cycles with a call to Run per instruction.
+func runSteps(m *M6502) error {
+	for start := m.Cycles(); m.Cycles()-start < benchCycles; {
+		if err := m.Run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runBatch runs the benchmark cycles as a batch.
+func runBatch(m *M6502) error {
+	_, err := m.RunCycles(benchCycles)
+	return err
+}
+
+//-----------------------------------------------------------------------------
+
+func BenchmarkRun6502(b *testing.B) {
+	benchFunctional(b, Variant6502, runSteps)
+}
+
+func BenchmarkRunCycles6502(b *testing.B) {
+	benchFunctional(b, Variant6502, runBatch)
+}
+
+func BenchmarkRunCycles65C02(b *testing.B) {
+	benchFunctional(b, Variant65C02, runBatch)
+}
+
+func BenchmarkPredecode6502(b *testing.B) {
+	benchFunctional(b, Variant6502, func(m *M6502) error {
+		m.SetPredecode(true)
+		return runBatch(m)
+	})
+}
+
+func BenchmarkTick6502(b *testing.B) {
+	benchFunctional(b, Variant6502, func(m *M6502) error {
+		for start := m.Cycles(); m.Cycles()-start < benchCycles; {
+			if err := m.Tick(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,83 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Subroutine Calls
+
+Call runs a 6502 subroutine from Go (the reverse of a VSR). It pushes a
+sentinel return address and runs until the RTS of the subroutine returns to the
+sentinel with the stack at the depth of the call.
+
+Call can be used from a VSR: the caller's registers, stack pointer, cycle
+stepping and stuck PC detection are restored afterwards, whether or not the
+subroutine returns. The registers set by the subroutine are only returned in
+the Regs result.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+//-----------------------------------------------------------------------------
+
+// CallReturn is the sentinel return address of a called subroutine.
+const CallReturn = 0xffff
+
+// DefaultCallLimit is the default cycle limit for a called subroutine.
+const DefaultCallLimit = 100000000
+
+// Regs are the registers passed to and returned by a called subroutine.
+type Regs struct {
+	A, X, Y uint8
+	P       uint8 // processor status flags
+}
+
+// regs returns the CPU registers.
+func (m *M6502) regs() Regs {
+	return Regs{m.A, m.X, m.Y, m.P}
+}
+
+// SetCallLimit sets the cycle limit for a called subroutine (0 for the default).
+func (m *M6502) SetCallLimit(n uint) {
+	m.callLimit = n
+}
+
+// Call calls the subroutine at adr with the registers set to regs.
+// It returns the registers and cycles used when the subroutine returns, or
+// the registers at the point it failed.
+func (m *M6502) Call(adr uint16, regs Regs) (Regs, uint, error) {
+	limit := m.callLimit
+	if limit == 0 {
+		limit = DefaultCallLimit
+	}
+
+	// restore the caller's state
+	pc, s, caller := m.PC, m.S, m.regs()
+	tick, lastPC, stuckPC := m.tick, m.lastPC, m.stuckPC
+	defer func() {
+		m.PC, m.S = pc, s
+		m.A, m.X, m.Y, m.P = caller.A, caller.X, caller.Y, caller.P
+		m.tick, m.lastPC, m.stuckPC = tick, lastPC, stuckPC
+	}()
+	m.tick = tickState{}
+
+	// jsr from the sentinel
+	m.push16(CallReturn - 1)
+	m.PC = adr
+	m.A, m.X, m.Y = regs.A, regs.X, regs.Y
+	m.P = regs.P | flagB | flagU
+
+	start := m.cycles
+	for {
+		if m.cycles-start >= limit || m.stop {
+			return m.regs(), m.cycles - start, &CallError{Adr: adr, PC: m.PC, Cycles: m.cycles - start}
+		}
+		if err := m.Run(); err != nil {
+			return m.regs(), m.cycles - start, err
+		}
+		if m.PC == CallReturn && m.S == s {
+			return m.regs(), m.cycles - start, nil
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,172 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Subroutine Call Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+func TestCall(t *testing.T) {
+	var r testRAM
+	m := New6502(&r)
+	copy(r[0x0400:], []uint8{
+		0x86, 0x10, // 0400 stx $10
+		0x18,       // 0402 clc
+		0x65, 0x10, // 0403 adc $10
+		0x60, // 0405 rts
+	})
+	m.PC = 0x1234
+	s := m.S
+	regs, n, err := m.Call(0x0400, Regs{A: 0x12, X: 0x34})
+	if err != nil || regs.A != 0x46 || n != 14 {
+		t.Errorf("a %02x %d cycles %v", regs.A, n, err)
+	}
+	if m.PC != 0x1234 || m.S != s {
+		t.Errorf("pc %04x s %02x", m.PC, m.S)
+	}
+}
+
+func TestCallLimit(t *testing.T) {
+	var r testRAM
+	m := New6502(&r)
+	copy(r[0x0400:], []uint8{
+		0xe8,             // 0400 inx
+		0x4c, 0x00, 0x04, // 0401 jmp $0400
+	})
+	m.SetCallLimit(1000)
+	_, n, err := m.Call(0x0400, Regs{})
+	var e *CallError
+	if !errors.Is(err, ErrNoReturn) || !errors.As(err, &e) || e.Adr != 0x0400 || n < 1000 {
+		t.Errorf("%d cycles %v", n, err)
+	}
+}
+
+func TestCallVSR(t *testing.T) {
+	var r testRAM
+	m := New6502(&r)
+	copy(r[0x0400:], []uint8{
+		0x20, 0x00, 0x05, // 0400 jsr $0500 (vsr)
+		0xe8, // 0403 inx
+		0x60, // 0404 rts
+		0x0a, // 0405 asl a
+		0x60, // 0406 rts
+	})
+	// the vsr doubles the accumulator with a call to 0405
+	m.AddVSR(0x0500, func(m *M6502) {
+		regs, _, err := m.Call(0x0405, Regs{A: m.A, X: m.X})
+		if err != nil {
+			t.Error(err)
+		}
+		m.A, m.X = regs.A, regs.X
+	})
+	regs, _, err := m.Call(0x0400, Regs{A: 0x21, X: 1})
+	if err != nil || regs.A != 0x42 || regs.X != 2 {
+		t.Errorf("a %02x x %02x %v", regs.A, regs.X, err)
+	}
+}
+
+func TestCallRestore(t *testing.T) {
+	tests := []struct {
+		name string
+		code []uint8
+		err  error
+	}{
+		{"return", []uint8{0xa9, 0x80, 0xa2, 0x00, 0xa0, 0x7f, 0x60}, nil},
+		{"limit", []uint8{0xa9, 0x80, 0xa2, 0x00, 0xa0, 0x7f, 0x48, 0x4c, 0x06, 0x04}, ErrNoReturn},
+		{"jam", []uint8{0xa9, 0x80, 0xa2, 0x00, 0xa0, 0x7f, 0x48, 0x02}, ErrJam},
+	}
+	for _, tt := range tests {
+		var r testRAM
+		m := New6502(&r)
+		// lda #$80, ldx #$00, ldy #$7f, then rts, pha forever or pha and jam
+		copy(r[0x0400:], tt.code)
+		m.SetCallLimit(1000)
+		m.PC, m.A, m.X, m.Y, m.S, m.P = 0x1234, 0x11, 0x22, 0x33, 0xf0, flagU|flagB|flagC
+		regs, _, err := m.Call(0x0400, Regs{})
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		// the callee's registers are returned
+		if regs.A != 0x80 || regs.X != 0x00 || regs.Y != 0x7f || regs.P&(flagN|flagZ) != 0 {
+			t.Errorf("%s: returned %+v", tt.name, regs)
+		}
+		// the caller's registers are unchanged
+		if m.PC != 0x1234 || m.A != 0x11 || m.X != 0x22 || m.Y != 0x33 || m.S != 0xf0 || m.P != flagU|flagB|flagC {
+			t.Errorf("%s: caller's registers\n%s", tt.name, m.Dump())
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+// floating point package (code/code.txt)
+
+// loadCode loads a hex dump ("adr byte byte ...") into memory.
+func loadCode(t *testing.T, r *testRAM, name string) {
+	f, err := os.Open(name)
+	if err != nil {
+		t.Skip(err)
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		x := strings.Fields(s.Text())
+		if len(x) == 0 {
+			continue
+		}
+		adr, err := strconv.ParseUint(x[0], 16, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, b := range x[1:] {
+			v, err := strconv.ParseUint(b, 16, 8)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r[int(adr)+i] = uint8(v)
+		}
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallFloat(t *testing.T) {
+	var r testRAM
+	loadCode(t, &r, "../code/code.txt")
+	m := New6502(&r)
+
+	// fpmult: fpacc = fpacc * fpop (lsw, nsw, msw, exponent)
+	copy(r[0x08:], []uint8{0x00, 0x00, 0x60, 0x02}) // 3.0
+	copy(r[0x10:], []uint8{0x00, 0x00, 0x40, 0x02}) // 2.0
+	if _, _, err := m.Call(0x0337, Regs{}); err != nil {
+		t.Fatal(err)
+	}
+	if x := r[0x08:0x0c]; !bytes.Equal(x, []uint8{0x00, 0x00, 0x60, 0x03}) {
+		t.Errorf("fpmult: fpacc % x, want 00 00 60 03 (6.0)", x)
+	}
+
+	// decbin: iostr = iostr * 10
+	copy(r[0x23:], []uint8{0x40, 0xe2, 0x01}) // 123456
+	if _, _, err := m.Call(0x05ab, Regs{}); err != nil {
+		t.Fatal(err)
+	}
+	if x := r[0x23:0x27]; !bytes.Equal(x, []uint8{0x80, 0xd6, 0x12, 0x00}) {
+		t.Errorf("decbin: iostr % x, want 80 d6 12 00 (1234560)", x)
+	}
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,218 @@
+//-----------------------------------------------------------------------------
+/*
+
+65C02 Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import "testing"
+
+//-----------------------------------------------------------------------------
+
+// testCycles runs one instruction and returns the cycles it used.
+func testCycles(t *testing.T, m *M6502, tick bool) uint {
+	t.Helper()
+	n := m.cycles
+	testStep(t, m, tick)
+	return m.cycles - n
+}
+
+//-----------------------------------------------------------------------------
+// undefined opcodes
+
+func TestCMOSNop(t *testing.T) {
+	tests := []struct {
+		code   []uint8
+		length uint16
+		cycles uint
+	}{
+		{[]uint8{0x03}, 1, 1},
+		{[]uint8{0x0b}, 1, 1},
+		{[]uint8{0xfb}, 1, 1},
+		{[]uint8{0x02, 0xff}, 2, 2},
+		{[]uint8{0xe2, 0xff}, 2, 2},
+		{[]uint8{0x44, 0x10}, 2, 3},
+		{[]uint8{0x54, 0x10}, 2, 4},
+		{[]uint8{0xf4, 0x10}, 2, 4},
+		{[]uint8{0xdc, 0x34, 0x12}, 3, 4},
+		{[]uint8{0xfc, 0x34, 0x12}, 3, 4},
+		{[]uint8{0x5c, 0x34, 0x12}, 3, 8},
+	}
+	for _, tt := range tests {
+		testCPUs(t, cmosVariants, func(m *M6502, r *testRAM, tick bool) {
+			m.PC = 0x0400
+			copy(r[0x0400:], tt.code)
+			m.A, m.X, m.Y, m.S, m.P = 0x11, 0x22, 0x33, 0xf0, flagU|flagB
+			n := testCycles(t, m, tick)
+			if m.PC != 0x0400+tt.length || n != tt.cycles {
+				t.Errorf("%s: %02x ran to %04x in %d cycles, want %04x in %d",
+					m.variant, tt.code[0], m.PC, n, 0x0400+tt.length, tt.cycles)
+			}
+			if m.A != 0x11 || m.X != 0x22 || m.Y != 0x33 || m.S != 0xf0 || m.P != flagU|flagB {
+				t.Errorf("%s: %02x changed the registers\n%s", m.variant, tt.code[0], m.Dump())
+			}
+		})
+	}
+}
+
+func TestCMOSOpcodesDefined(t *testing.T) {
+	for _, v := range cmosVariants {
+		for code := range variants[v].table {
+			if x := &variants[v].table[code]; x.ins == "ill" {
+				t.Errorf("%s: opcode %02x is illegal", v, code)
+			}
+		}
+	}
+	// the bit and low power opcodes replace the 1 cycle nops
+	for _, tt := range []struct {
+		v    Variant
+		code uint8
+		ins  string
+	}{
+		{Variant65C02, 0x07, "nop"},
+		{Variant65C02, 0xcb, "nop"},
+		{VariantR65C02, 0x07, "rmb0"},
+		{VariantR65C02, 0xcb, "nop"},
+		{VariantW65C02S, 0x0f, "bbr0"},
+		{VariantW65C02S, 0xcb, "wai"},
+	} {
+		if ins := variants[tt.v].table[tt.code].ins; ins != tt.ins {
+			t.Errorf("%s: opcode %02x is %s, want %s", tt.v, tt.code, ins, tt.ins)
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+// bit manipulation
+
+var bitVariants = []Variant{VariantR65C02, VariantW65C02S}
+
+func TestBitBranch(t *testing.T) {
+	tests := []struct {
+		name   string
+		pc     uint16
+		code   []uint8
+		m      uint8  // value at $10
+		want   uint16 // pc after
+		cycles uint
+	}{
+		{"bbr0 not taken", 0x0400, []uint8{0x0f, 0x10, 0x10}, 0x01, 0x0403, 5},
+		{"bbr0 taken", 0x0400, []uint8{0x0f, 0x10, 0x10}, 0xfe, 0x0413, 6},
+		{"bbs7 taken", 0x0400, []uint8{0xff, 0x10, 0x10}, 0x80, 0x0413, 6},
+		{"bbs7 not taken", 0x0400, []uint8{0xff, 0x10, 0x10}, 0x7f, 0x0403, 5},
+		{"bbr1 backwards", 0x0410, []uint8{0x1f, 0x10, 0xf0}, 0x00, 0x0403, 6},
+		{"bbs3 backwards", 0x0410, []uint8{0xbf, 0x10, 0xfd}, 0x08, 0x0410, 6},
+		{"bbs0 page cross", 0x04fc, []uint8{0x8f, 0x10, 0x10}, 0x01, 0x050f, 7},
+		{"bbr2 page cross backwards", 0x0500, []uint8{0x2f, 0x10, 0x80}, 0x00, 0x0483, 7},
+	}
+	for _, tt := range tests {
+		testCPUs(t, bitVariants, func(m *M6502, r *testRAM, tick bool) {
+			m.PC = tt.pc
+			copy(r[tt.pc:], tt.code)
+			r[0x10] = tt.m
+			m.P = flagU | flagB
+			n := testCycles(t, m, tick)
+			if m.PC != tt.want || n != tt.cycles {
+				t.Errorf("%s: %s: ran to %04x in %d cycles, want %04x in %d",
+					m.variant, tt.name, m.PC, n, tt.want, tt.cycles)
+			}
+			if r[0x10] != tt.m || m.P != flagU|flagB {
+				t.Errorf("%s: %s: changed the operand or flags", m.variant, tt.name)
+			}
+		})
+	}
+}
+
+func TestBitSetReset(t *testing.T) {
+	tests := []struct {
+		code uint8
+		m    uint8
+		want uint8
+	}{
+		{0x07, 0xff, 0xfe}, // rmb0
+		{0x77, 0xff, 0x7f}, // rmb7
+		{0x87, 0x00, 0x01}, // smb0
+		{0xd7, 0x00, 0x20}, // smb5
+		{0xf7, 0x80, 0x80}, // smb7
+		{0x37, 0x00, 0x00}, // rmb3
+	}
+	for _, tt := range tests {
+		testCPUs(t, bitVariants, func(m *M6502, r *testRAM, tick bool) {
+			m.PC = 0x0400
+			copy(r[0x0400:], []uint8{tt.code, 0x10})
+			r[0x10] = tt.m
+			m.P = flagU | flagB
+			n := testCycles(t, m, tick)
+			if r[0x10] != tt.want || m.PC != 0x0402 || n != 5 || m.P != flagU|flagB {
+				t.Errorf("%s: %02x: $10 = %02x pc %04x in %d cycles, want %02x at 0402 in 5",
+					m.variant, tt.code, r[0x10], m.PC, n, tt.want)
+			}
+		})
+	}
+}
+
+//-----------------------------------------------------------------------------
+// JMP indirect and decimal mode
+
+func TestJmpIndirectCycles(t *testing.T) {
+	for _, vs := range []struct {
+		vs     []Variant
+		cycles uint
+	}{
+		{nmosVariants, 5},
+		{cmosVariants, 6},
+	} {
+		testCPUs(t, vs.vs, func(m *M6502, r *testRAM, tick bool) {
+			m.PC = 0x0400
+			copy(r[0x0400:], []uint8{0x6c, 0xff, 0x10}) // jmp ($10ff)
+			if n := testCycles(t, m, tick); n != vs.cycles {
+				t.Errorf("%s: jmp ($10ff) took %d cycles, want %d", m.variant, n, vs.cycles)
+			}
+		})
+	}
+}
+
+func TestDecimalMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   []uint8
+		a, p   uint8 // a and carry before
+		want   uint8 // a after
+		nmosP  uint8 // flags after (nv..dizc)
+		cmosP  uint8
+		cycles uint // nmos cycles (+1 on cmos)
+	}{
+		// the NMOS N and Z flags are from the binary sum (0x9a)
+		{"adc #$01", []uint8{0x69, 0x01}, 0x99, 0, 0x00, flagN | flagC, flagZ | flagC, 2},
+		{"adc #$01", []uint8{0x69, 0x01}, 0x09, 0, 0x10, 0, 0, 2},
+		{"adc #$50", []uint8{0x69, 0x50}, 0x50, 0, 0x00, flagN | flagV | flagC, flagV | flagZ | flagC, 2},
+		{"adc $10", []uint8{0x65, 0x10}, 0x25, flagC, 0x74, 0, 0, 3},
+		{"sbc #$01", []uint8{0xe9, 0x01}, 0x00, flagC, 0x99, flagN, flagN, 2},
+		{"sbc #$01", []uint8{0xe9, 0x01}, 0x10, flagC, 0x09, flagC, flagC, 2},
+		{"sbc $10", []uint8{0xe5, 0x10}, 0x73, 0, 0x24, flagC, flagC, 3},
+	}
+	for _, tt := range tests {
+		testCPUs(t, append([]Variant{Variant6502}, cmosVariants...), func(m *M6502, r *testRAM, tick bool) {
+			m.PC = 0x0400
+			copy(r[0x0400:], tt.code)
+			r[0x10] = 0x48
+			m.A = tt.a
+			m.P = tt.p | flagD | flagU | flagB
+			n := testCycles(t, m, tick)
+			p, cycles := tt.nmosP, tt.cycles
+			if m.cmos {
+				p, cycles = tt.cmosP, tt.cycles+1
+			}
+			p |= flagD | flagU | flagB
+			if m.A != tt.want || m.P != p || n != cycles {
+				t.Errorf("%s: %s with a %02x: a %02x p %02x %d cycles, want a %02x p %02x %d cycles",
+					m.variant, tt.name, tt.a, m.A, m.P, n, tt.want, p, cycles)
+			}
+		})
+	}
+}
+
+//-----------------------------------------------------------------------------
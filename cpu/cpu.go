@@ -24,6 +24,32 @@ type Memory interface {
 	Write8(adr uint16, val uint8)
 }
 
+// DebugMemory is an optional interface for target memory with accesses that
+// have no side effects (e.g. reading a device status register doesn't clear it).
+// Debuggers and the disassembler use it when the Memory implements it,
+// otherwise they use Read8 and Write8.
+type DebugMemory interface {
+	Peek8(adr uint16) uint8
+	Poke8(adr uint16, val uint8)
+}
+
+// Peek8 reads a byte from memory without side effects (if possible).
+func Peek8(m Memory, adr uint16) uint8 {
+	if dm, ok := m.(DebugMemory); ok {
+		return dm.Peek8(adr)
+	}
+	return m.Read8(adr)
+}
+
+// Poke8 writes a byte to memory without side effects (if possible).
+func Poke8(m Memory, adr uint16, val uint8) {
+	if dm, ok := m.(DebugMemory); ok {
+		dm.Poke8(adr, val)
+		return
+	}
+	m.Write8(adr, val)
+}
+
 //-----------------------------------------------------------------------------
 
 // VSRFunc is the type for a virtual subroutine handler function.
@@ -31,22 +57,59 @@ type VSRFunc func(m *M6502)
 
 // M6502 is the state for the 6502 CPU.
 type M6502 struct {
-	PC      uint16             // program counter
-	S       uint8              // stack pointer
-	P       uint8              // processor status flags
-	A       uint8              // accumulator
-	X       uint8              // x index
-	Y       uint8              // y index
-	Mem     Memory             // memory of the target system
-	cycles  uint               // number of cpu cycles
-	nmi     bool               // nmi state
-	irq     bool               // irq state
-	illegal bool               // illegal instruction state
-	exit    bool               // exit from emulation
-	lastPC  uint16             // PC stuck detection
-	stuckPC uint               // PC stuck detection
-	vsr     map[uint16]VSRFunc // virtual subroutines
-	usage   map[uint8]uint     // opcode usage
+	PC        uint16             // program counter
+	S         uint8              // stack pointer
+	P         uint8              // processor status flags
+	A         uint8              // accumulator
+	X         uint8              // x index
+	Y         uint8              // y index
+	Mem       Memory             // memory of the target system
+	variant   Variant            // cpu variant
+	table     *[256]opcode       // opcode dispatch table
+	cmos      bool               // cmos behaviour
+	decimal   bool               // decimal mode arithmetic (D flag honoured by ADC/SBC)
+	port      *ioPort            // 6510 on-chip I/O port (if any)
+	cycles    uint               // number of cpu cycles
+	nmi       bool               // nmi latched by an edge on the nmi line
+	nmiLine   bool               // nmi line state
+	irq       bool               // irq line state
+	illegal   bool               // illegal instruction state
+	jam       bool               // jammed (halted) by a JAM instruction
+	strict    bool               // undocumented opcodes are illegal
+	wait      bool               // waiting for an interrupt (WAI)
+	stop      bool               // clock stopped until reset (STP)
+	exit      bool               // exit from emulation
+	lastPC    uint16             // PC stuck detection
+	stuckPC   uint               // PC stuck detection
+	vsr       map[uint16]VSRFunc // virtual subroutines
+	usage     [256]uint          // opcode usage
+	ints      *Interrupts        // interrupt sources
+	ticks     *[256]tickOp       // cycle stepped operations
+	tick      tickState          // cycle stepped instruction state
+	bus       BusFunc            // bus cycle callback
+	hooks     *hooks             // execution hooks (nil if none)
+	halted    error              // halt requested by a hook (or a panic)
+	obs       Observer           // memory access observer (nil if none)
+	opPC      uint16             // PC of the instruction being observed
+	pre       *predecode         // predecode cache (nil if disabled)
+	callLimit uint               // cycle limit for a called subroutine (0 for the default)
+}
+
+// Option is a functional option for CPU creation.
+type Option func(m *M6502)
+
+// WithVariant selects the CPU variant.
+func WithVariant(v Variant) Option {
+	return func(m *M6502) {
+		m.variant = v
+	}
+}
+
+// Strict treats the undocumented NMOS opcodes as illegal instructions.
+func Strict() Option {
+	return func(m *M6502) {
+		m.strict = true
+	}
 }
 
 // NmiAddress is the non-maskable interrupt address
@@ -67,15 +130,45 @@ const stkAddress = 0x0100
 const initialPC = 0x0000
 const initialS = 0xFD
 const initialP = 0x36
+const initialP2A03 = 0x34 // 2A03 power up state
 const initialA = 0x00
 const initialX = 0x00
 const initialY = 0x00
 
+//-----------------------------------------------------------------------------
+// cpu variants
+
+// Variant is a member of the 6502 CPU family.
+type Variant int
+
+// Supported CPU variants.
+const (
+	Variant6502    Variant = iota // MOS 6502 (NMOS)
+	Variant65C02                  // 65C02 (CMOS)
+	VariantR65C02                 // Rockwell R65C02 (CMOS + bit manipulation)
+	VariantW65C02S                // WDC W65C02S (CMOS + bit manipulation + WAI/STP)
+	Variant2A03                   // Ricoh 2A03 (NES: NMOS without decimal mode)
+	Variant6510                   // MOS 6510 (C64: NMOS with an I/O port at $0000/$0001)
+)
+
+// variantInfo describes a CPU variant (see opcodes.go).
+type variantInfo struct {
+	name    string       // variant name
+	table   *[256]opcode // opcode dispatch and metadata table
+	cmos    bool         // CMOS: decimal flags, JMP indirect fix, D cleared on interrupt
+	decimal bool         // decimal mode is implemented
+}
+
+func (v Variant) String() string {
+	return variants[v].name
+}
+
 //-----------------------------------------------------------------------------
 // status flags
 
 const flagN = uint8(1 << 7) // Negative
 const flagV = uint8(1 << 6) // Overflow
+const flagU = uint8(1 << 5) // Unused (always 1 when pushed)
 const flagB = uint8(1 << 4) // Break
 const flagD = uint8(1 << 3) // Decimal
 const flagI = uint8(1 << 2) // Interrupt Disable
@@ -85,6 +178,8 @@ const flagC = uint8(1 << 0) // Carry
 const flagNZ = (flagN | flagZ)
 const flagNVZ = (flagN | flagV | flagZ)
 const flagNVZC = (flagN | flagV | flagZ | flagC)
+const flagNZC = (flagN | flagZ | flagC)
+const flagAll = (flagN | flagV | flagB | flagD | flagI | flagZ | flagC)
 
 //-----------------------------------------------------------------------------
 // address modes
@@ -92,20 +187,34 @@ const flagNVZC = (flagN | flagV | flagZ | flagC)
 type adrMode int
 
 const (
-	amNone adrMode = iota
-	amAcc          // accumulator
-	amAbs          // absolute
-	amAbsX         // absolute, X-indexed
-	amAbsY         // absolute, Y-indexed
-	amImm          // immediate
-	amImpl         // implied
-	amInd          // indirect
-	amXInd         // X-indexed, indirect
-	amIndY         // indirect, Y-indexed
-	amRel          // relative
-	amZpg          // zeropage
-	amZpgX         // zeropage, X-indexed
-	amZpgY         // zeropage, Y-indexed
+	amNone        adrMode = iota
+	amAcc                 // accumulator
+	amAbs                 // absolute
+	amAbsX                // absolute, X-indexed
+	amAbsY                // absolute, Y-indexed
+	amImm                 // immediate
+	amImpl                // implied
+	amInd                 // indirect
+	amXInd                // X-indexed, indirect
+	amIndY                // indirect, Y-indexed
+	amRel                 // relative
+	amZpg                 // zeropage
+	amZpgX                // zeropage, X-indexed
+	amZpgY                // zeropage, Y-indexed
+	amZpgInd              // zeropage indirect (65c02)
+	amAbsXInd             // absolute X-indexed indirect (65c02)
+	amZpgRel              // zeropage, relative (bbr/bbs)
+	amImmM                // immediate, accumulator width (65816)
+	amImmX                // immediate, index width (65816)
+	amLong                // absolute long (65816)
+	amLongX               // absolute long, X-indexed (65816)
+	amZpgIndLong          // zeropage indirect long (65816)
+	amZpgIndLongY         // zeropage indirect long, Y-indexed (65816)
+	amSR                  // stack relative (65816)
+	amSRIndY              // stack relative indirect, Y-indexed (65816)
+	amRelLong             // relative long (65816)
+	amAbsIndLong          // absolute indirect long (65816)
+	amBlock               // block move (65816)
 )
 
 type adrModeInfo struct {
@@ -114,20 +223,34 @@ type adrModeInfo struct {
 }
 
 var modeDescr = map[adrMode]adrModeInfo{
-	amNone: {"", ""},
-	amAcc:  {"acc", "accumulator"},
-	amAbs:  {"abs", "absolute"},
-	amAbsX: {"absx", "absolute X-indexed"},
-	amAbsY: {"absy", "absolute Y-indexed"},
-	amImm:  {"imm", "immediate"},
-	amImpl: {"impl", "implied"},
-	amInd:  {"ind", "indirect"},
-	amXInd: {"xind", "X-indexed indirect"},
-	amIndY: {"indy", "indirect Y-indexed"},
-	amRel:  {"rel", "relative"},
-	amZpg:  {"z", "zeropage"},
-	amZpgX: {"zx", "zeropage X-indexed"},
-	amZpgY: {"zy", "zeropage Y-indexed"},
+	amNone:        {"", ""},
+	amAcc:         {"acc", "accumulator"},
+	amAbs:         {"abs", "absolute"},
+	amAbsX:        {"absx", "absolute X-indexed"},
+	amAbsY:        {"absy", "absolute Y-indexed"},
+	amImm:         {"imm", "immediate"},
+	amImpl:        {"impl", "implied"},
+	amInd:         {"ind", "indirect"},
+	amXInd:        {"xind", "X-indexed indirect"},
+	amIndY:        {"indy", "indirect Y-indexed"},
+	amRel:         {"rel", "relative"},
+	amZpg:         {"z", "zeropage"},
+	amZpgX:        {"zx", "zeropage X-indexed"},
+	amZpgY:        {"zy", "zeropage Y-indexed"},
+	amZpgInd:      {"zind", "zeropage indirect"},
+	amAbsXInd:     {"absxind", "absolute X-indexed indirect"},
+	amZpgRel:      {"zrel", "zeropage relative"},
+	amImmM:        {"immm", "immediate"},
+	amImmX:        {"immx", "immediate"},
+	amLong:        {"long", "absolute long"},
+	amLongX:       {"longx", "absolute long X-indexed"},
+	amZpgIndLong:  {"zindl", "zeropage indirect long"},
+	amZpgIndLongY: {"zindly", "zeropage indirect long Y-indexed"},
+	amSR:          {"sr", "stack relative"},
+	amSRIndY:      {"srindy", "stack relative indirect Y-indexed"},
+	amRelLong:     {"rell", "relative long"},
+	amAbsIndLong:  {"absindl", "absolute indirect long"},
+	amBlock:       {"blk", "block move"},
 }
 
 var insLengthByMode = []int{
@@ -145,10 +268,24 @@ var insLengthByMode = []int{
 	1 + 1, // amZpg
 	1 + 1, // amZpgX
 	1 + 1, // amZpgY
+	1 + 1, // amZpgInd
+	1 + 2, // amAbsXInd
+	1 + 2, // amZpgRel
+	1 + 1, // amImmM (+1 with a 16-bit accumulator)
+	1 + 1, // amImmX (+1 with 16-bit index registers)
+	1 + 3, // amLong
+	1 + 3, // amLongX
+	1 + 1, // amZpgIndLong
+	1 + 1, // amZpgIndLongY
+	1 + 1, // amSR
+	1 + 1, // amSRIndY
+	1 + 2, // amRelLong
+	1 + 2, // amAbsIndLong
+	1 + 2, // amBlock
 }
 
-func insLength(code uint8) int {
-	return insLengthByMode[opcodeLookup(code).mode]
+func insLength(v Variant, code uint8) int {
+	return int(opcodeLookup(v, code).length)
 }
 
 //-----------------------------------------------------------------------------
@@ -160,266 +297,148 @@ type insInfo struct {
 	mode adrMode // address mode
 }
 
-// opcodeInfo is the opcode table as a map, unspecified opcodes are illegal instructions
-var opcodeInfo = map[uint8]insInfo{
-
-	0x00: insInfo{"brk", amImpl},
-	0x10: insInfo{"bpl", amRel},
-	0x20: insInfo{"jsr", amAbs},
-	0x30: insInfo{"bmi", amRel},
-	0x40: insInfo{"rti", amImpl},
-	0x50: insInfo{"bvc", amRel},
-	0x60: insInfo{"rts", amImpl},
-	0x70: insInfo{"bvs", amRel},
-	0x80: insInfo{"ill", amNone},
-	0x90: insInfo{"bcc", amRel},
-	0xa0: insInfo{"ldy", amImm},
-	0xb0: insInfo{"bcs", amRel},
-	0xc0: insInfo{"cpy", amImm},
-	0xd0: insInfo{"bne", amRel},
-	0xe0: insInfo{"cpx", amImm},
-	0xf0: insInfo{"beq", amRel},
-
-	0x01: insInfo{"ora", amXInd},
-	0x11: insInfo{"ora", amIndY},
-	0x21: insInfo{"and", amXInd},
-	0x31: insInfo{"and", amIndY},
-	0x41: insInfo{"eor", amXInd},
-	0x51: insInfo{"eor", amIndY},
-	0x61: insInfo{"adc", amXInd},
-	0x71: insInfo{"adc", amIndY},
-	0x81: insInfo{"sta", amXInd},
-	0x91: insInfo{"sta", amIndY},
-	0xa1: insInfo{"lda", amXInd},
-	0xb1: insInfo{"lda", amIndY},
-	0xc1: insInfo{"cmp", amXInd},
-	0xd1: insInfo{"cmp", amIndY},
-	0xe1: insInfo{"sbc", amXInd},
-	0xf1: insInfo{"sbc", amIndY},
-
-	0xa2: insInfo{"ldx", amImm},
-
-	0x04: insInfo{"ill", amNone},
-	0x14: insInfo{"ill", amNone},
-	0x24: insInfo{"bit", amZpg},
-	0x34: insInfo{"ill", amNone},
-	0x44: insInfo{"ill", amNone},
-	0x54: insInfo{"ill", amNone},
-	0x64: insInfo{"ill", amNone},
-	0x74: insInfo{"ill", amNone},
-	0x84: insInfo{"sty", amZpg},
-	0x94: insInfo{"sty", amZpgX},
-	0xa4: insInfo{"ldy", amZpg},
-	0xb4: insInfo{"ldy", amZpgX},
-	0xc4: insInfo{"cpy", amZpg},
-	0xd4: insInfo{"ill", amNone},
-	0xe4: insInfo{"cpx", amZpg},
-	0xf4: insInfo{"ill", amNone},
-
-	0x05: insInfo{"ora", amZpg},
-	0x15: insInfo{"ora", amZpgX},
-	0x25: insInfo{"and", amZpg},
-	0x35: insInfo{"and", amZpgX},
-	0x45: insInfo{"eor", amZpg},
-	0x55: insInfo{"eor", amZpgX},
-	0x65: insInfo{"adc", amZpg},
-	0x75: insInfo{"adc", amZpgX},
-	0x85: insInfo{"sta", amZpg},
-	0x95: insInfo{"sta", amZpgX},
-	0xa5: insInfo{"lda", amZpg},
-	0xb5: insInfo{"lda", amZpgX},
-	0xc5: insInfo{"cmp", amZpg},
-	0xd5: insInfo{"cmp", amZpgX},
-	0xe5: insInfo{"sbc", amZpg},
-	0xf5: insInfo{"sbc", amZpgX},
-
-	0x06: insInfo{"asl", amZpg},
-	0x16: insInfo{"asl", amZpgX},
-	0x26: insInfo{"rol", amZpg},
-	0x36: insInfo{"rol", amZpgX},
-	0x46: insInfo{"lsr", amZpg},
-	0x56: insInfo{"lsr", amZpgX},
-	0x66: insInfo{"ror", amZpg},
-	0x76: insInfo{"ror", amZpgX},
-	0x86: insInfo{"stx", amZpg},
-	0x96: insInfo{"stx", amZpgY},
-	0xa6: insInfo{"ldx", amZpg},
-	0xb6: insInfo{"ldx", amZpgY},
-	0xc6: insInfo{"dec", amZpg},
-	0xd6: insInfo{"dec", amZpgX},
-	0xe6: insInfo{"inc", amZpg},
-	0xf6: insInfo{"inc", amZpgX},
-
-	0x08: insInfo{"php", amImpl},
-	0x18: insInfo{"clc", amImpl},
-	0x28: insInfo{"plp", amImpl},
-	0x38: insInfo{"sec", amImpl},
-	0x48: insInfo{"pha", amImpl},
-	0x58: insInfo{"cli", amImpl},
-	0x68: insInfo{"pla", amImpl},
-	0x78: insInfo{"sei", amImpl},
-	0x88: insInfo{"dey", amImpl},
-	0x98: insInfo{"tya", amImpl},
-	0xa8: insInfo{"tay", amImpl},
-	0xb8: insInfo{"clv", amImpl},
-	0xc8: insInfo{"iny", amImpl},
-	0xd8: insInfo{"cld", amImpl},
-	0xe8: insInfo{"inx", amImpl},
-	0xf8: insInfo{"sed", amImpl},
-
-	0x09: insInfo{"ora", amImm},
-	0x19: insInfo{"ora", amAbsY},
-	0x29: insInfo{"and", amImm},
-	0x39: insInfo{"and", amAbsY},
-	0x49: insInfo{"eor", amImm},
-	0x59: insInfo{"eor", amAbsY},
-	0x69: insInfo{"adc", amImm},
-	0x79: insInfo{"adc", amAbsY},
-	0x89: insInfo{"ill", amNone},
-	0x99: insInfo{"sta", amAbsY},
-	0xa9: insInfo{"lda", amImm},
-	0xb9: insInfo{"lda", amAbsY},
-	0xc9: insInfo{"cmp", amImm},
-	0xd9: insInfo{"cmp", amAbsY},
-	0xe9: insInfo{"sbc", amImm},
-	0xf9: insInfo{"sbc", amAbsY},
-
-	0x0a: insInfo{"asl", amAcc},
-	0x1a: insInfo{"ill", amNone},
-	0x2a: insInfo{"rol", amAcc},
-	0x3a: insInfo{"ill", amNone},
-	0x4a: insInfo{"lsr", amAcc},
-	0x5a: insInfo{"ill", amNone},
-	0x6a: insInfo{"ror", amAcc},
-	0x7a: insInfo{"ill", amNone},
-	0x8a: insInfo{"txa", amImpl},
-	0x9a: insInfo{"txs", amImpl},
-	0xaa: insInfo{"tax", amImpl},
-	0xba: insInfo{"tsx", amImpl},
-	0xca: insInfo{"dex", amImpl},
-	0xda: insInfo{"ill", amNone},
-	0xea: insInfo{"nop", amImpl},
-	0xfa: insInfo{"ill", amNone},
-
-	0x0c: insInfo{"ill", amNone},
-	0x1c: insInfo{"ill", amNone},
-	0x2c: insInfo{"bit", amAbs},
-	0x3c: insInfo{"ill", amNone},
-	0x4c: insInfo{"jmp", amAbs},
-	0x5c: insInfo{"ill", amNone},
-	0x6c: insInfo{"jmp", amInd},
-	0x7c: insInfo{"ill", amNone},
-	0x8c: insInfo{"sty", amAbs},
-	0x9c: insInfo{"ill", amNone},
-	0xac: insInfo{"ldy", amAbs},
-	0xbc: insInfo{"ldy", amAbsX},
-	0xcc: insInfo{"cpy", amAbs},
-	0xdc: insInfo{"ill", amNone},
-	0xec: insInfo{"cpx", amAbs},
-	0xfc: insInfo{"ill", amNone},
-
-	0x0d: insInfo{"ora", amAbs},
-	0x1d: insInfo{"ora", amAbsX},
-	0x2d: insInfo{"and", amAbs},
-	0x3d: insInfo{"and", amAbsX},
-	0x4d: insInfo{"eor", amAbs},
-	0x5d: insInfo{"eor", amAbsX},
-	0x6d: insInfo{"adc", amAbs},
-	0x7d: insInfo{"adc", amAbsX},
-	0x8d: insInfo{"sta", amAbs},
-	0x9d: insInfo{"sta", amAbsX},
-	0xad: insInfo{"lda", amAbs},
-	0xbd: insInfo{"lda", amAbsX},
-	0xcd: insInfo{"cmp", amAbs},
-	0xdd: insInfo{"cmp", amAbsX},
-	0xed: insInfo{"sbc", amAbs},
-	0xfd: insInfo{"sbc", amAbsX},
-
-	0x0e: insInfo{"asl", amAbs},
-	0x1e: insInfo{"asl", amAbsX},
-	0x2e: insInfo{"rol", amAbs},
-	0x3e: insInfo{"rol", amAbsX},
-	0x4e: insInfo{"lsr", amAbs},
-	0x5e: insInfo{"lsr", amAbsX},
-	0x6e: insInfo{"ror", amAbs},
-	0x7e: insInfo{"ror", amAbsX},
-	0x8e: insInfo{"stx", amAbs},
-	0x9e: insInfo{"ill", amNone},
-	0xae: insInfo{"ldx", amAbs},
-	0xbe: insInfo{"ldx", amAbsY},
-	0xce: insInfo{"dec", amAbs},
-	0xde: insInfo{"dec", amAbsX},
-	0xee: insInfo{"inc", amAbs},
-	0xfe: insInfo{"inc", amAbsX},
+// opcode is the dispatch and metadata entry for an opcode of a CPU variant.
+// The tables are generated by cmd/gen from the instruction sets in isa.go.
+type opcode struct {
+	fn      opFunc  // handler
+	ins     string  // mneumonic
+	mode    adrMode // address mode
+	length  uint8   // instruction length
+	cycles  uint    // base cycles
+	penalty bool    // +1 cycle when indexing crosses a page
+	flagsR  uint8   // status flags read
+	flagsW  uint8   // status flags written
+	undoc   bool    // undocumented opcode
 }
 
+// opcodeIllegal is the table entry for an illegal opcode.
+var opcodeIllegal = opcode{opXX, "ill", amNone, 1, 0, false, 0, 0, false}
+
 // opcodeLookup returns the instruction information for this opcode.
-func opcodeLookup(code uint8) *insInfo {
-	if info, ok := opcodeInfo[code]; ok {
-		return &info
-	}
-	return &insInfo{"ill", amNone}
+func opcodeLookup(v Variant, code uint8) *opcode {
+	return &variants[v].table[code]
 }
 
 // insDescr maps the instruction mneumonic onto a full description.
 var insDescr = map[string]string{
-	"adc": "add with carry",
-	"and": "and (with accumulator)",
-	"asl": "arithmetic shift left",
-	"bcc": "branch on carry clear",
-	"bcs": "branch on carry set",
-	"beq": "branch on equal (zero set)",
-	"bit": "bit test",
-	"bmi": "branch on minus (negative set)",
-	"bne": "branch on not equal (zero clear)",
-	"bpl": "branch on plus (negative clear)",
-	"brk": "break/interrupt",
-	"bvc": "branch on overflow clear",
-	"bvs": "branch on overflow set",
-	"clc": "clear carry",
-	"cld": "clear decimal",
-	"cli": "clear interrupt disable",
-	"clv": "clear overflow",
-	"cmp": "compare (with accumulator)",
-	"cpx": "compare with X",
-	"cpy": "compare with Y",
-	"dec": "decrement",
-	"dex": "decrement X",
-	"dey": "decrement Y",
-	"eor": "exclusive or (with accumulator)",
-	"ill": "illegal",
-	"inc": "increment",
-	"inx": "increment X",
-	"iny": "increment Y",
-	"jmp": "jump",
-	"jsr": "jump subroutine",
-	"lda": "load accumulator",
-	"ldx": "load X",
-	"ldy": "load Y",
-	"lsr": "logical shift right",
-	"nop": "no operation",
-	"ora": "or with accumulator",
-	"pha": "push accumulator",
-	"php": "push processor status (SR)",
-	"pla": "pull accumulator",
-	"plp": "pull processor status (SR)",
-	"rol": "rotate left",
-	"ror": "rotate right",
-	"rti": "return from interrupt",
-	"rts": "return from subroutine",
-	"sbc": "subtract with carry",
-	"sec": "set carry",
-	"sed": "set decimal",
-	"sei": "set interrupt disable",
-	"sta": "store accumulator",
-	"stx": "store X",
-	"sty": "store Y",
-	"tax": "transfer accumulator to X",
-	"tay": "transfer accumulator to Y",
-	"tsx": "transfer stack pointer to X",
-	"txa": "transfer X to accumulator",
-	"txs": "transfer X to stack pointer",
-	"tya": "transfer Y to accumulator",
+	"adc":  "add with carry",
+	"alr":  "and then logical shift right",
+	"anc":  "and then copy N to carry",
+	"and":  "and (with accumulator)",
+	"arr":  "and then rotate right",
+	"asl":  "arithmetic shift left",
+	"bbr0": "branch on bit 0 reset",
+	"bbr1": "branch on bit 1 reset",
+	"bbr2": "branch on bit 2 reset",
+	"bbr3": "branch on bit 3 reset",
+	"bbr4": "branch on bit 4 reset",
+	"bbr5": "branch on bit 5 reset",
+	"bbr6": "branch on bit 6 reset",
+	"bbr7": "branch on bit 7 reset",
+	"bbs0": "branch on bit 0 set",
+	"bbs1": "branch on bit 1 set",
+	"bbs2": "branch on bit 2 set",
+	"bbs3": "branch on bit 3 set",
+	"bbs4": "branch on bit 4 set",
+	"bbs5": "branch on bit 5 set",
+	"bbs6": "branch on bit 6 set",
+	"bbs7": "branch on bit 7 set",
+	"bcc":  "branch on carry clear",
+	"bcs":  "branch on carry set",
+	"beq":  "branch on equal (zero set)",
+	"bit":  "bit test",
+	"bmi":  "branch on minus (negative set)",
+	"bne":  "branch on not equal (zero clear)",
+	"bpl":  "branch on plus (negative clear)",
+	"bra":  "branch always",
+	"brk":  "break/interrupt",
+	"bvc":  "branch on overflow clear",
+	"bvs":  "branch on overflow set",
+	"clc":  "clear carry",
+	"cld":  "clear decimal",
+	"cli":  "clear interrupt disable",
+	"clv":  "clear overflow",
+	"cmp":  "compare (with accumulator)",
+	"cpx":  "compare with X",
+	"cpy":  "compare with Y",
+	"dcp":  "decrement then compare (with accumulator)",
+	"dec":  "decrement",
+	"dex":  "decrement X",
+	"dey":  "decrement Y",
+	"eor":  "exclusive or (with accumulator)",
+	"ill":  "illegal",
+	"inc":  "increment",
+	"inx":  "increment X",
+	"iny":  "increment Y",
+	"isc":  "increment then subtract with carry",
+	"jam":  "jam (halt the cpu)",
+	"jmp":  "jump",
+	"jsr":  "jump subroutine",
+	"las":  "load accumulator, X and stack pointer (unstable)",
+	"lax":  "load accumulator and X",
+	"lda":  "load accumulator",
+	"ldx":  "load X",
+	"ldy":  "load Y",
+	"lsr":  "logical shift right",
+	"nop":  "no operation",
+	"ora":  "or with accumulator",
+	"pha":  "push accumulator",
+	"php":  "push processor status (SR)",
+	"phx":  "push X",
+	"phy":  "push Y",
+	"pla":  "pull accumulator",
+	"plp":  "pull processor status (SR)",
+	"plx":  "pull X",
+	"ply":  "pull Y",
+	"rla":  "rotate left then and (with accumulator)",
+	"rmb0": "reset memory bit 0",
+	"rmb1": "reset memory bit 1",
+	"rmb2": "reset memory bit 2",
+	"rmb3": "reset memory bit 3",
+	"rmb4": "reset memory bit 4",
+	"rmb5": "reset memory bit 5",
+	"rmb6": "reset memory bit 6",
+	"rmb7": "reset memory bit 7",
+	"rol":  "rotate left",
+	"ror":  "rotate right",
+	"rra":  "rotate right then add with carry",
+	"rti":  "return from interrupt",
+	"rts":  "return from subroutine",
+	"sax":  "store accumulator and X",
+	"sbc":  "subtract with carry",
+	"sbx":  "subtract from accumulator and X",
+	"sec":  "set carry",
+	"sed":  "set decimal",
+	"sei":  "set interrupt disable",
+	"sha":  "store accumulator and X and high (unstable)",
+	"shx":  "store X and high (unstable)",
+	"shy":  "store Y and high (unstable)",
+	"slo":  "shift left then or (with accumulator)",
+	"smb0": "set memory bit 0",
+	"smb1": "set memory bit 1",
+	"smb2": "set memory bit 2",
+	"smb3": "set memory bit 3",
+	"smb4": "set memory bit 4",
+	"smb5": "set memory bit 5",
+	"smb6": "set memory bit 6",
+	"smb7": "set memory bit 7",
+	"sre":  "shift right then exclusive or (with accumulator)",
+	"sta":  "store accumulator",
+	"stp":  "stop the clock",
+	"stx":  "store X",
+	"sty":  "store Y",
+	"stz":  "store zero",
+	"tas":  "transfer to stack pointer then store (unstable)",
+	"tax":  "transfer accumulator to X",
+	"tay":  "transfer accumulator to Y",
+	"trb":  "test and reset bits",
+	"tsb":  "test and set bits",
+	"tsx":  "transfer stack pointer to X",
+	"txa":  "transfer X to accumulator",
+	"txs":  "transfer X to stack pointer",
+	"tya":  "transfer Y to accumulator",
+	"wai":  "wait for interrupt",
+	"xaa":  "transfer X then and (unstable)",
 }
 
 //-----------------------------------------------------------------------------
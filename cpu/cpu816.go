@@ -0,0 +1,374 @@
+//-----------------------------------------------------------------------------
+/*
+
+65C816 CPU Definitions
+
+http://www.westerndesigncenter.com/wdc/documentation/w65c816s.pdf
+http://6502.org/tutorials/65c816opcodes.html
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"fmt"
+	"strings"
+)
+
+//-----------------------------------------------------------------------------
+
+// LongMemory is an optional interface for target memory with 24-bit addresses.
+// The 65C816 uses it when the Memory implements it, otherwise the bank byte
+// of an address is ignored and all accesses go to the 64K of bank 0.
+type LongMemory interface {
+	ReadLong(adr uint32) uint8
+	WriteLong(adr uint32, val uint8)
+}
+
+//-----------------------------------------------------------------------------
+
+// VSR816Func is the type for a 65C816 virtual subroutine handler function.
+type VSR816Func func(m *M65816)
+
+// M65816 is the state for the 65C816 CPU.
+type M65816 struct {
+	PC      uint16                // program counter
+	PBR     uint8                 // program bank register
+	DBR     uint8                 // data bank register
+	D       uint16                // direct page register
+	S       uint16                // stack pointer
+	P       uint8                 // processor status flags
+	E       bool                  // emulation mode
+	A       uint16                // accumulator (B:A)
+	X       uint16                // x index
+	Y       uint16                // y index
+	Mem     Memory                // memory of the target system
+	long    LongMemory            // 24-bit memory of the target system (if any)
+	bank0   bool                  // effective address wraps within bank 0
+	cycles  uint                  // number of cpu cycles
+	nmi     bool                  // nmi latched (on the assertion of the line)
+	nmiLine bool                  // nmi line state
+	irq     bool                  // irq line state
+	ints    *Interrupts           // interrupt sources (nil until used)
+	wait    bool                  // waiting for an interrupt (WAI)
+	stop    bool                  // clock stopped until reset (STP)
+	exit    bool                  // exit from emulation
+	lastPC  uint32                // PC stuck detection
+	stuckPC uint                  // PC stuck detection
+	vsr     map[uint32]VSR816Func // virtual subroutines
+}
+
+// native mode vectors (emulation mode uses the 6502 vectors)
+const copNative816 = 0xFFE4
+const brkNative816 = 0xFFE6
+const nmiNative816 = 0xFFEA
+const irqNative816 = 0xFFEE
+
+// emulation mode COP vector
+const copEmulation816 = 0xFFF4
+
+//-----------------------------------------------------------------------------
+// status flags (native mode)
+
+const flagM = uint8(1 << 5) // Accumulator/Memory width (1 = 8 bits)
+const flagX = uint8(1 << 4) // Index register width (1 = 8 bits)
+
+//-----------------------------------------------------------------------------
+// opcodes
+
+// opcodeInfo816 is the 65C816 opcode table, all opcodes are defined
+var opcodeInfo816 = [256]insInfo{
+
+	0x00: insInfo{"brk", amImm},
+	0x10: insInfo{"bpl", amRel},
+	0x20: insInfo{"jsr", amAbs},
+	0x30: insInfo{"bmi", amRel},
+	0x40: insInfo{"rti", amImpl},
+	0x50: insInfo{"bvc", amRel},
+	0x60: insInfo{"rts", amImpl},
+	0x70: insInfo{"bvs", amRel},
+	0x80: insInfo{"bra", amRel},
+	0x90: insInfo{"bcc", amRel},
+	0xa0: insInfo{"ldy", amImmX},
+	0xb0: insInfo{"bcs", amRel},
+	0xc0: insInfo{"cpy", amImmX},
+	0xd0: insInfo{"bne", amRel},
+	0xe0: insInfo{"cpx", amImmX},
+	0xf0: insInfo{"beq", amRel},
+
+	0x01: insInfo{"ora", amXInd},
+	0x11: insInfo{"ora", amIndY},
+	0x21: insInfo{"and", amXInd},
+	0x31: insInfo{"and", amIndY},
+	0x41: insInfo{"eor", amXInd},
+	0x51: insInfo{"eor", amIndY},
+	0x61: insInfo{"adc", amXInd},
+	0x71: insInfo{"adc", amIndY},
+	0x81: insInfo{"sta", amXInd},
+	0x91: insInfo{"sta", amIndY},
+	0xa1: insInfo{"lda", amXInd},
+	0xb1: insInfo{"lda", amIndY},
+	0xc1: insInfo{"cmp", amXInd},
+	0xd1: insInfo{"cmp", amIndY},
+	0xe1: insInfo{"sbc", amXInd},
+	0xf1: insInfo{"sbc", amIndY},
+
+	0x02: insInfo{"cop", amImm},
+	0x12: insInfo{"ora", amZpgInd},
+	0x22: insInfo{"jsl", amLong},
+	0x32: insInfo{"and", amZpgInd},
+	0x42: insInfo{"wdm", amImm},
+	0x52: insInfo{"eor", amZpgInd},
+	0x62: insInfo{"per", amRelLong},
+	0x72: insInfo{"adc", amZpgInd},
+	0x82: insInfo{"brl", amRelLong},
+	0x92: insInfo{"sta", amZpgInd},
+	0xa2: insInfo{"ldx", amImmX},
+	0xb2: insInfo{"lda", amZpgInd},
+	0xc2: insInfo{"rep", amImm},
+	0xd2: insInfo{"cmp", amZpgInd},
+	0xe2: insInfo{"sep", amImm},
+	0xf2: insInfo{"sbc", amZpgInd},
+
+	0x03: insInfo{"ora", amSR},
+	0x13: insInfo{"ora", amSRIndY},
+	0x23: insInfo{"and", amSR},
+	0x33: insInfo{"and", amSRIndY},
+	0x43: insInfo{"eor", amSR},
+	0x53: insInfo{"eor", amSRIndY},
+	0x63: insInfo{"adc", amSR},
+	0x73: insInfo{"adc", amSRIndY},
+	0x83: insInfo{"sta", amSR},
+	0x93: insInfo{"sta", amSRIndY},
+	0xa3: insInfo{"lda", amSR},
+	0xb3: insInfo{"lda", amSRIndY},
+	0xc3: insInfo{"cmp", amSR},
+	0xd3: insInfo{"cmp", amSRIndY},
+	0xe3: insInfo{"sbc", amSR},
+	0xf3: insInfo{"sbc", amSRIndY},
+
+	0x04: insInfo{"tsb", amZpg},
+	0x14: insInfo{"trb", amZpg},
+	0x24: insInfo{"bit", amZpg},
+	0x34: insInfo{"bit", amZpgX},
+	0x44: insInfo{"mvp", amBlock},
+	0x54: insInfo{"mvn", amBlock},
+	0x64: insInfo{"stz", amZpg},
+	0x74: insInfo{"stz", amZpgX},
+	0x84: insInfo{"sty", amZpg},
+	0x94: insInfo{"sty", amZpgX},
+	0xa4: insInfo{"ldy", amZpg},
+	0xb4: insInfo{"ldy", amZpgX},
+	0xc4: insInfo{"cpy", amZpg},
+	0xd4: insInfo{"pei", amZpgInd},
+	0xe4: insInfo{"cpx", amZpg},
+	0xf4: insInfo{"pea", amAbs},
+
+	0x05: insInfo{"ora", amZpg},
+	0x15: insInfo{"ora", amZpgX},
+	0x25: insInfo{"and", amZpg},
+	0x35: insInfo{"and", amZpgX},
+	0x45: insInfo{"eor", amZpg},
+	0x55: insInfo{"eor", amZpgX},
+	0x65: insInfo{"adc", amZpg},
+	0x75: insInfo{"adc", amZpgX},
+	0x85: insInfo{"sta", amZpg},
+	0x95: insInfo{"sta", amZpgX},
+	0xa5: insInfo{"lda", amZpg},
+	0xb5: insInfo{"lda", amZpgX},
+	0xc5: insInfo{"cmp", amZpg},
+	0xd5: insInfo{"cmp", amZpgX},
+	0xe5: insInfo{"sbc", amZpg},
+	0xf5: insInfo{"sbc", amZpgX},
+
+	0x06: insInfo{"asl", amZpg},
+	0x16: insInfo{"asl", amZpgX},
+	0x26: insInfo{"rol", amZpg},
+	0x36: insInfo{"rol", amZpgX},
+	0x46: insInfo{"lsr", amZpg},
+	0x56: insInfo{"lsr", amZpgX},
+	0x66: insInfo{"ror", amZpg},
+	0x76: insInfo{"ror", amZpgX},
+	0x86: insInfo{"stx", amZpg},
+	0x96: insInfo{"stx", amZpgY},
+	0xa6: insInfo{"ldx", amZpg},
+	0xb6: insInfo{"ldx", amZpgY},
+	0xc6: insInfo{"dec", amZpg},
+	0xd6: insInfo{"dec", amZpgX},
+	0xe6: insInfo{"inc", amZpg},
+	0xf6: insInfo{"inc", amZpgX},
+
+	0x07: insInfo{"ora", amZpgIndLong},
+	0x17: insInfo{"ora", amZpgIndLongY},
+	0x27: insInfo{"and", amZpgIndLong},
+	0x37: insInfo{"and", amZpgIndLongY},
+	0x47: insInfo{"eor", amZpgIndLong},
+	0x57: insInfo{"eor", amZpgIndLongY},
+	0x67: insInfo{"adc", amZpgIndLong},
+	0x77: insInfo{"adc", amZpgIndLongY},
+	0x87: insInfo{"sta", amZpgIndLong},
+	0x97: insInfo{"sta", amZpgIndLongY},
+	0xa7: insInfo{"lda", amZpgIndLong},
+	0xb7: insInfo{"lda", amZpgIndLongY},
+	0xc7: insInfo{"cmp", amZpgIndLong},
+	0xd7: insInfo{"cmp", amZpgIndLongY},
+	0xe7: insInfo{"sbc", amZpgIndLong},
+	0xf7: insInfo{"sbc", amZpgIndLongY},
+
+	0x08: insInfo{"php", amImpl},
+	0x18: insInfo{"clc", amImpl},
+	0x28: insInfo{"plp", amImpl},
+	0x38: insInfo{"sec", amImpl},
+	0x48: insInfo{"pha", amImpl},
+	0x58: insInfo{"cli", amImpl},
+	0x68: insInfo{"pla", amImpl},
+	0x78: insInfo{"sei", amImpl},
+	0x88: insInfo{"dey", amImpl},
+	0x98: insInfo{"tya", amImpl},
+	0xa8: insInfo{"tay", amImpl},
+	0xb8: insInfo{"clv", amImpl},
+	0xc8: insInfo{"iny", amImpl},
+	0xd8: insInfo{"cld", amImpl},
+	0xe8: insInfo{"inx", amImpl},
+	0xf8: insInfo{"sed", amImpl},
+
+	0x09: insInfo{"ora", amImmM},
+	0x19: insInfo{"ora", amAbsY},
+	0x29: insInfo{"and", amImmM},
+	0x39: insInfo{"and", amAbsY},
+	0x49: insInfo{"eor", amImmM},
+	0x59: insInfo{"eor", amAbsY},
+	0x69: insInfo{"adc", amImmM},
+	0x79: insInfo{"adc", amAbsY},
+	0x89: insInfo{"bit", amImmM},
+	0x99: insInfo{"sta", amAbsY},
+	0xa9: insInfo{"lda", amImmM},
+	0xb9: insInfo{"lda", amAbsY},
+	0xc9: insInfo{"cmp", amImmM},
+	0xd9: insInfo{"cmp", amAbsY},
+	0xe9: insInfo{"sbc", amImmM},
+	0xf9: insInfo{"sbc", amAbsY},
+
+	0x0a: insInfo{"asl", amAcc},
+	0x1a: insInfo{"inc", amAcc},
+	0x2a: insInfo{"rol", amAcc},
+	0x3a: insInfo{"dec", amAcc},
+	0x4a: insInfo{"lsr", amAcc},
+	0x5a: insInfo{"phy", amImpl},
+	0x6a: insInfo{"ror", amAcc},
+	0x7a: insInfo{"ply", amImpl},
+	0x8a: insInfo{"txa", amImpl},
+	0x9a: insInfo{"txs", amImpl},
+	0xaa: insInfo{"tax", amImpl},
+	0xba: insInfo{"tsx", amImpl},
+	0xca: insInfo{"dex", amImpl},
+	0xda: insInfo{"phx", amImpl},
+	0xea: insInfo{"nop", amImpl},
+	0xfa: insInfo{"plx", amImpl},
+
+	0x0b: insInfo{"phd", amImpl},
+	0x1b: insInfo{"tcs", amImpl},
+	0x2b: insInfo{"pld", amImpl},
+	0x3b: insInfo{"tsc", amImpl},
+	0x4b: insInfo{"phk", amImpl},
+	0x5b: insInfo{"tcd", amImpl},
+	0x6b: insInfo{"rtl", amImpl},
+	0x7b: insInfo{"tdc", amImpl},
+	0x8b: insInfo{"phb", amImpl},
+	0x9b: insInfo{"txy", amImpl},
+	0xab: insInfo{"plb", amImpl},
+	0xbb: insInfo{"tyx", amImpl},
+	0xcb: insInfo{"wai", amImpl},
+	0xdb: insInfo{"stp", amImpl},
+	0xeb: insInfo{"xba", amImpl},
+	0xfb: insInfo{"xce", amImpl},
+
+	0x0c: insInfo{"tsb", amAbs},
+	0x1c: insInfo{"trb", amAbs},
+	0x2c: insInfo{"bit", amAbs},
+	0x3c: insInfo{"bit", amAbsX},
+	0x4c: insInfo{"jmp", amAbs},
+	0x5c: insInfo{"jml", amLong},
+	0x6c: insInfo{"jmp", amInd},
+	0x7c: insInfo{"jmp", amAbsXInd},
+	0x8c: insInfo{"sty", amAbs},
+	0x9c: insInfo{"stz", amAbs},
+	0xac: insInfo{"ldy", amAbs},
+	0xbc: insInfo{"ldy", amAbsX},
+	0xcc: insInfo{"cpy", amAbs},
+	0xdc: insInfo{"jml", amAbsIndLong},
+	0xec: insInfo{"cpx", amAbs},
+	0xfc: insInfo{"jsr", amAbsXInd},
+
+	0x0d: insInfo{"ora", amAbs},
+	0x1d: insInfo{"ora", amAbsX},
+	0x2d: insInfo{"and", amAbs},
+	0x3d: insInfo{"and", amAbsX},
+	0x4d: insInfo{"eor", amAbs},
+	0x5d: insInfo{"eor", amAbsX},
+	0x6d: insInfo{"adc", amAbs},
+	0x7d: insInfo{"adc", amAbsX},
+	0x8d: insInfo{"sta", amAbs},
+	0x9d: insInfo{"sta", amAbsX},
+	0xad: insInfo{"lda", amAbs},
+	0xbd: insInfo{"lda", amAbsX},
+	0xcd: insInfo{"cmp", amAbs},
+	0xdd: insInfo{"cmp", amAbsX},
+	0xed: insInfo{"sbc", amAbs},
+	0xfd: insInfo{"sbc", amAbsX},
+
+	0x0e: insInfo{"asl", amAbs},
+	0x1e: insInfo{"asl", amAbsX},
+	0x2e: insInfo{"rol", amAbs},
+	0x3e: insInfo{"rol", amAbsX},
+	0x4e: insInfo{"lsr", amAbs},
+	0x5e: insInfo{"lsr", amAbsX},
+	0x6e: insInfo{"ror", amAbs},
+	0x7e: insInfo{"ror", amAbsX},
+	0x8e: insInfo{"stx", amAbs},
+	0x9e: insInfo{"stz", amAbsX},
+	0xae: insInfo{"ldx", amAbs},
+	0xbe: insInfo{"ldx", amAbsY},
+	0xce: insInfo{"dec", amAbs},
+	0xde: insInfo{"dec", amAbsX},
+	0xee: insInfo{"inc", amAbs},
+	0xfe: insInfo{"inc", amAbsX},
+
+	0x0f: insInfo{"ora", amLong},
+	0x1f: insInfo{"ora", amLongX},
+	0x2f: insInfo{"and", amLong},
+	0x3f: insInfo{"and", amLongX},
+	0x4f: insInfo{"eor", amLong},
+	0x5f: insInfo{"eor", amLongX},
+	0x6f: insInfo{"adc", amLong},
+	0x7f: insInfo{"adc", amLongX},
+	0x8f: insInfo{"sta", amLong},
+	0x9f: insInfo{"sta", amLongX},
+	0xaf: insInfo{"lda", amLong},
+	0xbf: insInfo{"lda", amLongX},
+	0xcf: insInfo{"cmp", amLong},
+	0xdf: insInfo{"cmp", amLongX},
+	0xef: insInfo{"sbc", amLong},
+	0xff: insInfo{"sbc", amLongX},
+}
+
+//-----------------------------------------------------------------------------
+
+// Dump returns a display string for the CPU registers.
+func (m *M65816) Dump() string {
+	s := make([]string, 2)
+	if m.E {
+		s[0] = "pc      a    x    y    s    d    db p  e   n v - b d i z c"
+	} else {
+		s[0] = "pc      a    x    y    s    d    db p  e   n v m x d i z c"
+	}
+	e := 0
+	if m.E {
+		e = 1
+	}
+	s[1] = fmt.Sprintf("%02x:%04x %04x %04x %04x %04x %04x %02x %02x %d   %s", m.PBR, m.PC, m.A, m.X, m.Y, m.S, m.D, m.DBR, m.P, e, toBits(m.P))
+	return strings.Join(s, "\n")
+}
+
+//-----------------------------------------------------------------------------
@@ -8,10 +8,7 @@
 
 package cpu
 
-import (
-	"fmt"
-	"strings"
-)
+import "strings"
 
 //-----------------------------------------------------------------------------
 
@@ -28,22 +25,48 @@ type Disassembly struct {
 }
 
 func (da *Disassembly) String() string {
-	s := make([]string, 2)
-	s[0] = fmt.Sprintf("%-16s %8s %-13s", da.Dump, da.Symbol, da.Instruction)
+	b := make([]byte, 0, 64)
+	b = appendPad(append(b, da.Dump...), 16-len(da.Dump))
+	b = append(appendPad(append(b, ' '), 8-len(da.Symbol)), da.Symbol...)
+	b = appendPad(append(append(b, ' '), da.Instruction...), 13-len(da.Instruction))
 	if da.Comment != "" {
-		s[1] = fmt.Sprintf(" ; %s", da.Comment)
+		b = append(append(b, " ; "...), da.Comment...)
 	}
-	return strings.Join(s, "")
+	return string(b)
+}
+
+//-----------------------------------------------------------------------------
+// formatting without fmt (the tracer disassembles every instruction)
+
+const hexDigits = "0123456789abcdef"
+
+// appendHex8 appends a byte as 2 hex digits.
+func appendHex8(b []byte, v uint8) []byte {
+	return append(b, hexDigits[v>>4], hexDigits[v&15])
+}
+
+// appendHex16 appends a word as 4 hex digits.
+func appendHex16(b []byte, v uint16) []byte {
+	return appendHex8(appendHex8(b, uint8(v>>8)), uint8(v))
+}
+
+// appendPad appends n spaces.
+func appendPad(b []byte, n int) []byte {
+	for ; n > 0; n-- {
+		b = append(b, ' ')
+	}
+	return b
 }
 
 //-----------------------------------------------------------------------------
 
 func daDump(adr uint16, mem []byte) string {
-	s := make([]string, len(mem))
-	for i, v := range mem {
-		s[i] = fmt.Sprintf("%02x", v)
+	b := make([]byte, 0, 16)
+	b = append(appendHex16(b, adr), ':')
+	for _, v := range mem {
+		b = appendHex8(append(b, ' '), v)
 	}
-	return fmt.Sprintf("%04x: %s", adr, strings.Join(s, " "))
+	return string(b)
 }
 
 func daSymbol(adr uint16, st SymbolTable) string {
@@ -53,89 +76,103 @@ func daSymbol(adr uint16, st SymbolTable) string {
 	return ""
 }
 
-func daInstruction(adr uint16, mem []uint8) (string, string) {
+// daOperand16 returns the 2 byte operand of an instruction.
+func daOperand16(mem []uint8) uint16 {
+	return uint16(mem[1]) | uint16(mem[2])<<8
+}
+
+func daInstruction(v Variant, adr uint16, mem []uint8) (string, string) {
 
-	var s []string
+	b := make([]byte, 0, 16)
 	var comment string
 
-	info := opcodeLookup(mem[0])
+	info := opcodeLookup(v, mem[0])
 
 	// instruction mneumonic
-	if info.ins == "ill" {
-		info.ins = "?"
+	ins := info.ins
+	if ins == "ill" {
+		ins = "?"
 	}
-	s = append(s, info.ins)
+	b = append(b, ins...)
 
 	switch info.mode {
 	case amNone:
 		// illegal - no operands
 	case amAcc:
 		// accumulator - no operands
-		s = append(s, "a")
+		b = append(b, " a"...)
 	case amAbs:
 		// absolute - 2 byte operand
-		operand := int(mem[1]) + (int(mem[2]) << 8)
-		s = append(s, fmt.Sprintf("$%04x", operand))
+		b = appendHex16(append(b, " $"...), daOperand16(mem))
 	case amAbsX:
 		// absolute, X-indexed - 2 byte operand
-		operand := int(mem[1]) + (int(mem[2]) << 8)
-		s = append(s, fmt.Sprintf("$%04x,x", operand))
+		b = append(appendHex16(append(b, " $"...), daOperand16(mem)), ",x"...)
 	case amAbsY:
 		// absolute, Y-indexed - 2 byte operand
-		operand := int(mem[1]) + (int(mem[2]) << 8)
-		s = append(s, fmt.Sprintf("$%04x,y", operand))
+		b = append(appendHex16(append(b, " $"...), daOperand16(mem)), ",y"...)
 	case amImm:
 		// immediate - 1 byte operand
-		operand := mem[1]
-		s = append(s, fmt.Sprintf("#$%02x", operand))
+		b = appendHex8(append(b, " #$"...), mem[1])
 	case amImpl:
 		// implied - no operands
 	case amInd:
 		// indirect - 2 byte operand
-		operand := int(mem[1]) + (int(mem[2]) << 8)
-		s = append(s, fmt.Sprintf("($%04x)", operand))
+		b = append(appendHex16(append(b, " ($"...), daOperand16(mem)), ')')
 	case amXInd:
 		// X-indexed, indirect - 1 byte operand
-		operand := mem[1]
-		s = append(s, fmt.Sprintf("($%02x,x)", operand))
+		b = append(appendHex8(append(b, " ($"...), mem[1]), ",x)"...)
 	case amIndY:
 		// indirect, Y-indexed - 1 byte operand
-		operand := mem[1]
-		s = append(s, fmt.Sprintf("($%02x),y", operand))
+		b = append(appendHex8(append(b, " ($"...), mem[1]), "),y"...)
 	case amRel:
 		// relative - 1 byte operand
 		operand := mem[1]
-		s = append(s, fmt.Sprintf("$%02x", operand))
+		b = appendHex8(append(b, " $"...), operand)
 		dst := uint16(int(adr) + int(int8(operand)) + 2)
-		comment = fmt.Sprintf("$%04x", dst)
+		comment = string(appendHex16([]byte{'$'}, dst))
 	case amZpg:
 		// zeropage - 1 byte operand
-		operand := mem[1]
-		s = append(s, fmt.Sprintf("$%02x", operand))
+		b = appendHex8(append(b, " $"...), mem[1])
 	case amZpgX:
 		// zeropage, X-indexed - 1 byte operand
-		operand := mem[1]
-		s = append(s, fmt.Sprintf("$%02x,x", operand))
+		b = append(appendHex8(append(b, " $"...), mem[1]), ",x"...)
 	case amZpgY:
 		// zeropage, Y-indexed - 1 byte operand
-		operand := mem[1]
-		s = append(s, fmt.Sprintf("$%02x,y", operand))
+		b = append(appendHex8(append(b, " $"...), mem[1]), ",y"...)
+	case amZpgInd:
+		// zeropage indirect - 1 byte operand
+		b = append(appendHex8(append(b, " ($"...), mem[1]), ')')
+	case amAbsXInd:
+		// absolute X-indexed, indirect - 2 byte operand
+		b = append(appendHex16(append(b, " ($"...), daOperand16(mem)), ",x)"...)
+	case amZpgRel:
+		// zeropage, relative - 2 byte operand
+		zp := mem[1]
+		operand := mem[2]
+		b = appendHex8(append(appendHex8(append(b, " $"...), zp), ",$"...), operand)
+		dst := uint16(int(adr) + int(int8(operand)) + 3)
+		comment = string(appendHex16([]byte{'$'}, dst))
 	default:
 		panic("bad address mode")
 	}
 
-	return strings.Join(s, " "), comment
+	return string(b), comment
 }
 
 // Disassemble a 6502 instruction from the memory at the address.
 func Disassemble(m Memory, adr uint16, st SymbolTable) *Disassembly {
+	return DisassembleVariant(Variant6502, m, adr, st)
+}
+
+// DisassembleVariant disassembles an instruction for a given CPU variant.
+func DisassembleVariant(v Variant, m Memory, adr uint16, st SymbolTable) *Disassembly {
 	// get the instruction bytes
-	mem := make([]uint8, insLength(m.Read8(adr)))
+	mem := make([]uint8, insLength(v, Peek8(m, adr)))
 	for i := range mem {
-		mem[i] = m.Read8(adr + uint16(i))
+		mem[i] = Peek8(m, adr+uint16(i))
 	}
 
-	instruction, comment := daInstruction(adr, mem)
+	instruction, comment := daInstruction(v, adr, mem)
 
 	return &Disassembly{
 		Dump:        daDump(adr, mem),
@@ -152,7 +189,7 @@ func Disassemble(m Memory, adr uint16, st SymbolTable) *Disassembly {
 func (m *M6502) Disassemble(adr uint16, size int) string {
 	s := make([]string, 0, 16)
 	for size > 0 {
-		da := Disassemble(m.Mem, adr, nil)
+		da := DisassembleVariant(m.variant, m.Mem, adr, nil)
 		s = append(s, da.String())
 		n := len(da.Bytes)
 		size -= n
@@ -0,0 +1,208 @@
+//-----------------------------------------------------------------------------
+/*
+
+65C816 CPU Disassembler
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"fmt"
+	"strings"
+)
+
+//-----------------------------------------------------------------------------
+
+func daDump816(adr uint32, mem []byte) string {
+	s := make([]string, len(mem))
+	for i, v := range mem {
+		s[i] = fmt.Sprintf("%02x", v)
+	}
+	// pad to the longest (4 byte) instruction so listings line up
+	return fmt.Sprintf("%-20s", fmt.Sprintf("%02x:%04x: %s", uint8(adr>>16), uint16(adr), strings.Join(s, " ")))
+}
+
+func daSymbol816(adr uint32, st SymbolTable) string {
+	if adr>>16 == 0 {
+		return daSymbol(uint16(adr), st)
+	}
+	return ""
+}
+
+// insLength816 returns the instruction length for the M/X widths in the status flags.
+func insLength816(code uint8, p uint8, e bool) int {
+	mode := opcodeInfo816[code].mode
+	n := insLengthByMode[mode]
+	if !e && ((mode == amImmM && p&flagM == 0) || (mode == amImmX && p&flagX == 0)) {
+		n++
+	}
+	return n
+}
+
+func daInstruction816(adr uint32, mem []uint8) (string, string) {
+
+	var s []string
+	var comment string
+
+	info := opcodeInfo816[mem[0]]
+	s = append(s, info.ins)
+
+	// operand values
+	var op8 uint8
+	var op16, op24 int
+	if len(mem) >= 2 {
+		op8 = mem[1]
+	}
+	if len(mem) >= 3 {
+		op16 = int(mem[1]) + (int(mem[2]) << 8)
+	}
+	if len(mem) >= 4 {
+		op24 = op16 + (int(mem[3]) << 16)
+	}
+	bank := adr & 0xff0000
+
+	switch info.mode {
+	case amAcc:
+		// accumulator - no operands
+		s = append(s, "a")
+	case amAbs:
+		// absolute - 2 byte operand
+		s = append(s, fmt.Sprintf("$%04x", op16))
+	case amAbsX:
+		// absolute, X-indexed - 2 byte operand
+		s = append(s, fmt.Sprintf("$%04x,x", op16))
+	case amAbsY:
+		// absolute, Y-indexed - 2 byte operand
+		s = append(s, fmt.Sprintf("$%04x,y", op16))
+	case amImm:
+		// immediate - 1 byte operand
+		s = append(s, fmt.Sprintf("#$%02x", op8))
+	case amImmM, amImmX:
+		// immediate - 1 or 2 byte operand
+		if len(mem) == 3 {
+			s = append(s, fmt.Sprintf("#$%04x", op16))
+		} else {
+			s = append(s, fmt.Sprintf("#$%02x", op8))
+		}
+	case amImpl:
+		// implied - no operands
+	case amInd:
+		// indirect - 2 byte operand
+		s = append(s, fmt.Sprintf("($%04x)", op16))
+	case amXInd:
+		// X-indexed, indirect - 1 byte operand
+		s = append(s, fmt.Sprintf("($%02x,x)", op8))
+	case amIndY:
+		// indirect, Y-indexed - 1 byte operand
+		s = append(s, fmt.Sprintf("($%02x),y", op8))
+	case amRel:
+		// relative - 1 byte operand
+		s = append(s, fmt.Sprintf("$%02x", op8))
+		dst := uint16(int(adr) + int(int8(op8)) + 2)
+		comment = fmt.Sprintf("$%04x", dst)
+	case amRelLong:
+		// long relative - 2 byte operand
+		s = append(s, fmt.Sprintf("$%04x", op16))
+		dst := uint16(int(adr) + int(int16(op16)) + 3)
+		comment = fmt.Sprintf("$%02x:%04x", bank>>16, dst)
+	case amZpg:
+		// direct page - 1 byte operand
+		s = append(s, fmt.Sprintf("$%02x", op8))
+	case amZpgX:
+		// direct page, X-indexed - 1 byte operand
+		s = append(s, fmt.Sprintf("$%02x,x", op8))
+	case amZpgY:
+		// direct page, Y-indexed - 1 byte operand
+		s = append(s, fmt.Sprintf("$%02x,y", op8))
+	case amZpgInd:
+		// direct page indirect - 1 byte operand
+		s = append(s, fmt.Sprintf("($%02x)", op8))
+	case amZpgIndLong:
+		// direct page indirect long - 1 byte operand
+		s = append(s, fmt.Sprintf("[$%02x]", op8))
+	case amZpgIndLongY:
+		// direct page indirect long, Y-indexed - 1 byte operand
+		s = append(s, fmt.Sprintf("[$%02x],y", op8))
+	case amAbsXInd:
+		// absolute X-indexed, indirect - 2 byte operand
+		s = append(s, fmt.Sprintf("($%04x,x)", op16))
+	case amAbsIndLong:
+		// absolute indirect long - 2 byte operand
+		s = append(s, fmt.Sprintf("[$%04x]", op16))
+	case amLong:
+		// absolute long - 3 byte operand
+		s = append(s, fmt.Sprintf("$%06x", op24))
+	case amLongX:
+		// absolute long, X-indexed - 3 byte operand
+		s = append(s, fmt.Sprintf("$%06x,x", op24))
+	case amSR:
+		// stack relative - 1 byte operand
+		s = append(s, fmt.Sprintf("$%02x,s", op8))
+	case amSRIndY:
+		// stack relative indirect, Y-indexed - 1 byte operand
+		s = append(s, fmt.Sprintf("($%02x,s),y", op8))
+	case amBlock:
+		// block move - destination bank, source bank
+		s = append(s, fmt.Sprintf("$%02x,$%02x", mem[1], mem[2]))
+	default:
+		panic("bad address mode")
+	}
+
+	return strings.Join(s, " "), comment
+}
+
+// Disassemble816 disassembles a 65C816 instruction from the memory at a 24-bit address.
+// The status flags and emulation mode determine the length of immediate operands.
+func Disassemble816(m Memory, adr uint32, p uint8, e bool, st SymbolTable) *Disassembly {
+	read := func(adr uint32) uint8 {
+		return Peek8(m, uint16(adr))
+	}
+	if lm, ok := m.(LongMemory); ok {
+		read = func(adr uint32) uint8 {
+			return lm.ReadLong(adr)
+		}
+	}
+	// get the instruction bytes (the PC wraps within the bank)
+	mem := make([]uint8, insLength816(read(adr), p, e))
+	for i := range mem {
+		mem[i] = read((adr & 0xff0000) | uint32(uint16(adr)+uint16(i)))
+	}
+
+	instruction, comment := daInstruction816(adr, mem)
+
+	return &Disassembly{
+		Dump:        daDump816(adr, mem),
+		Symbol:      daSymbol816(adr, st),
+		Instruction: instruction,
+		Comment:     comment,
+		Bytes:       mem,
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// Disassemble returns the disassembly for a region of the CPU memory.
+// The M/X widths start from the current status flags and follow any REP/SEP
+// instructions in the listing.
+func (m *M65816) Disassemble(adr uint32, size int) string {
+	s := make([]string, 0, 16)
+	p, e := m.P, m.E
+	for size > 0 {
+		da := Disassemble816(m.Mem, adr, p, e, nil)
+		s = append(s, da.String())
+		switch da.Bytes[0] {
+		case 0xc2: // rep
+			p &= ^da.Bytes[1]
+		case 0xe2: // sep
+			p |= da.Bytes[1]
+		}
+		n := len(da.Bytes)
+		size -= n
+		adr = (adr & 0xff0000) | uint32(uint16(adr)+uint16(n))
+	}
+	return strings.Join(s, "\n")
+}
+
+//-----------------------------------------------------------------------------
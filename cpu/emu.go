@@ -8,8 +8,6 @@
 
 package cpu
 
-import "fmt"
-
 //-----------------------------------------------------------------------------
 
 func (m *M6502) setN(cond bool) {
@@ -47,20 +45,104 @@ func (m *M6502) setNZ(val uint8) {
 
 //-----------------------------------------------------------------------------
 
+// read8 reads a byte from the target memory.
+func (m *M6502) read8(adr uint16) uint8 {
+	return m.load(AccessRead, adr)
+}
+
+// write8 writes a byte to the target memory.
+func (m *M6502) write8(adr uint16, val uint8) {
+	m.store(AccessWrite, adr, val)
+}
+
+// mem8 reads a byte from the target memory without observing it.
+func (m *M6502) mem8(adr uint16) uint8 {
+	if m.port != nil && adr <= 1 {
+		return m.port.read(adr)
+	}
+	return m.Mem.Read8(adr)
+}
+
+// Peek8 reads a byte from the CPU memory map without side effects.
+func (m *M6502) Peek8(adr uint16) uint8 {
+	if m.port != nil && adr <= 1 {
+		return m.port.read(adr)
+	}
+	return Peek8(m.Mem, adr)
+}
+
+// Poke8 writes a byte to the CPU memory map without side effects.
+func (m *M6502) Poke8(adr uint16, val uint8) {
+	if m.pre != nil {
+		m.pre.invalidate(adr)
+	}
+	if m.port != nil && adr <= 1 {
+		m.port.write(adr, val)
+	}
+	Poke8(m.Mem, adr, val)
+}
+
+// peek16 reads a 16-bit value from the low and high byte addresses.
+func (m *M6502) peek16(lo, hi uint16) uint16 {
+	return uint16(m.Peek8(hi))<<8 | uint16(m.Peek8(lo))
+}
+
 func (m *M6502) read16(adr uint16) uint16 {
-	l := uint16(m.Mem.Read8(adr))
-	h := uint16(m.Mem.Read8(adr + 1))
+	l := uint16(m.read8(adr))
+	h := uint16(m.read8(adr + 1))
+	return (h << 8) | l
+}
+
+// operand8 reads an instruction operand byte.
+func (m *M6502) operand8(adr uint16) uint8 {
+	if m.pre != nil {
+		if v, ok := m.pre.operand(m.PC, adr); ok {
+			return v
+		}
+	}
+	return m.load(AccessOperand, adr)
+}
+
+// operand16 reads an instruction operand word.
+func (m *M6502) operand16(adr uint16) uint16 {
+	l := uint16(m.operand8(adr))
+	h := uint16(m.operand8(adr + 1))
+	return (h << 8) | l
+}
+
+// vector16 reads an interrupt/reset vector.
+func (m *M6502) vector16(adr uint16) uint16 {
+	l := uint16(m.load(AccessVector, adr))
+	h := uint16(m.load(AccessVector, adr+1))
+	return (h << 8) | l
+}
+
+// read16ind reads the JMP indirect pointer. The NMOS parts don't carry into the
+// high byte of the pointer, so a pointer at $xxFF wraps within the page.
+func (m *M6502) read16ind(adr uint16) uint16 {
+	if m.cmos {
+		return m.read16(adr)
+	}
+	l := uint16(m.read8(adr))
+	h := uint16(m.read8((adr & 0xff00) | uint16(uint8(adr)+1)))
+	return (h << 8) | l
+}
+
+// read16zp reads a 16-bit pointer from the zero page (wrapping within the page).
+func (m *M6502) read16zp(adr uint8) uint16 {
+	l := uint16(m.read8(uint16(adr)))
+	h := uint16(m.read8(uint16(adr + 1)))
 	return (h << 8) | l
 }
 
 func (m *M6502) push8(val uint8) {
-	m.Mem.Write8(stkAddress+uint16(m.S), val)
+	m.store(AccessPush, stkAddress+uint16(m.S), val)
 	m.S--
 }
 
 func (m *M6502) pop8() uint8 {
 	m.S++
-	return m.Mem.Read8(stkAddress + uint16(m.S))
+	return m.load(AccessPull, stkAddress+uint16(m.S))
 }
 
 func (m *M6502) push16(val uint16) {
@@ -78,130 +160,140 @@ func (m *M6502) pop16() uint16 {
 // modal write functions
 
 func (m *M6502) writeZeroPage(val uint8) {
-	ea := m.Mem.Read8(m.PC + 1)
-	m.Mem.Write8(uint16(ea), val)
+	ea := m.operand8(m.PC + 1)
+	m.write8(uint16(ea), val)
 }
 
 func (m *M6502) writeZeroPageX(val uint8) {
-	ea := m.Mem.Read8(m.PC+1) + m.X
-	m.Mem.Write8(uint16(ea), val)
+	ea := m.operand8(m.PC+1) + m.X
+	m.write8(uint16(ea), val)
 }
 
 func (m *M6502) writeZeroPageY(val uint8) {
-	ea := m.Mem.Read8(m.PC+1) + m.Y
-	m.Mem.Write8(uint16(ea), val)
+	ea := m.operand8(m.PC+1) + m.Y
+	m.write8(uint16(ea), val)
 }
 
 func (m *M6502) writeAbsolute(val uint8) {
-	ea := m.read16(m.PC + 1)
-	m.Mem.Write8(ea, val)
+	ea := m.operand16(m.PC + 1)
+	m.write8(ea, val)
 }
 
 func (m *M6502) writeAbsoluteX(val uint8) {
-	ea := m.read16(m.PC+1) + uint16(m.X)
-	m.Mem.Write8(ea, val)
+	ea := m.operand16(m.PC+1) + uint16(m.X)
+	m.write8(ea, val)
 }
 
 func (m *M6502) writeAbsoluteY(val uint8) {
-	ea := m.read16(m.PC+1) + uint16(m.Y)
-	m.Mem.Write8(ea, val)
+	ea := m.operand16(m.PC+1) + uint16(m.Y)
+	m.write8(ea, val)
 }
 
 func (m *M6502) writeIndirectX(val uint8) {
-	ea := m.read16(uint16(m.Mem.Read8(m.PC+1) + m.X))
-	m.Mem.Write8(ea, val)
+	ea := m.read16zp(m.operand8(m.PC+1) + m.X)
+	m.write8(ea, val)
 }
 
 func (m *M6502) writeIndirectY(val uint8) {
-	ea := m.read16(uint16(m.Mem.Read8(m.PC+1))) + uint16(m.Y)
-	m.Mem.Write8(ea, val)
+	ea := m.read16zp(m.operand8(m.PC+1)) + uint16(m.Y)
+	m.write8(ea, val)
+}
+
+func (m *M6502) writeZeroPageIndirect(val uint8) {
+	ea := m.read16zp(m.operand8(m.PC + 1))
+	m.write8(ea, val)
 }
 
 //-----------------------------------------------------------------------------
 // modal read functions
 
 func (m *M6502) readImmediate() uint8 {
-	return m.Mem.Read8(m.PC + 1)
+	return m.operand8(m.PC + 1)
 }
 
 func (m *M6502) readZeroPage() (uint8, uint16) {
-	ea := uint16(m.Mem.Read8(m.PC + 1))
-	return m.Mem.Read8(ea), ea
+	ea := uint16(m.operand8(m.PC + 1))
+	return m.read8(ea), ea
 }
 
 func (m *M6502) readZeroPageX() (uint8, uint16) {
-	ea := uint16(m.Mem.Read8(m.PC+1) + m.X)
-	return m.Mem.Read8(ea), ea
+	ea := uint16(m.operand8(m.PC+1) + m.X)
+	return m.read8(ea), ea
 }
 
 func (m *M6502) readZeroPageY() (uint8, uint16) {
-	ea := uint16(m.Mem.Read8(m.PC+1) + m.Y)
-	return m.Mem.Read8(ea), ea
+	ea := uint16(m.operand8(m.PC+1) + m.Y)
+	return m.read8(ea), ea
 }
 
 func (m *M6502) readAbsolute() (uint8, uint16) {
-	ea := m.read16(m.PC + 1)
-	return m.Mem.Read8(ea), ea
+	ea := m.operand16(m.PC + 1)
+	return m.read8(ea), ea
 }
 
 func (m *M6502) readAbsoluteX() (uint8, uint16) {
-	ea := m.read16(m.PC+1) + uint16(m.X)
-	return m.Mem.Read8(ea), ea
+	ea := m.operand16(m.PC+1) + uint16(m.X)
+	return m.read8(ea), ea
 }
 
 func (m *M6502) readAbsoluteY() (uint8, uint16) {
-	ea := m.read16(m.PC+1) + uint16(m.Y)
-	return m.Mem.Read8(ea), ea
+	ea := m.operand16(m.PC+1) + uint16(m.Y)
+	return m.read8(ea), ea
 }
 
 func (m *M6502) readIndirectX() (uint8, uint16) {
-	ea := m.read16(uint16(m.Mem.Read8(m.PC+1) + m.X))
-	return m.Mem.Read8(ea), ea
+	ea := m.read16zp(m.operand8(m.PC+1) + m.X)
+	return m.read8(ea), ea
 }
 
 func (m *M6502) readIndirectY() (uint8, uint16) {
-	ea := m.read16(uint16(m.Mem.Read8(m.PC+1))) + uint16(m.Y)
-	return m.Mem.Read8(ea), ea
+	ea := m.read16zp(m.operand8(m.PC+1)) + uint16(m.Y)
+	return m.read8(ea), ea
+}
+
+func (m *M6502) readZeroPageIndirect() (uint8, uint16) {
+	ea := m.read16zp(m.operand8(m.PC + 1))
+	return m.read8(ea), ea
 }
 
 func (m *M6502) readAbsoluteXPenalized() (uint8, uint, uint16) {
-	ea := m.read16(m.PC + 1)
+	ea := m.operand16(m.PC + 1)
 	var n uint
 	if (ea&0xff)+uint16(m.X) > 0xff {
 		n = 1
 	}
 	ea += uint16(m.X)
-	return m.Mem.Read8(ea), n, ea
+	return m.read8(ea), n, ea
 }
 
 func (m *M6502) readAbsoluteYPenalized() (uint8, uint, uint16) {
-	ea := m.read16(m.PC + 1)
+	ea := m.operand16(m.PC + 1)
 	var n uint
 	if (ea&0xff)+uint16(m.Y) > 0xff {
 		n = 1
 	}
 	ea += uint16(m.Y)
-	return m.Mem.Read8(ea), n, ea
+	return m.read8(ea), n, ea
 }
 
 func (m *M6502) readIndirectYPenalized() (uint8, uint, uint16) {
-	ea := m.read16(uint16(m.Mem.Read8(m.PC + 1)))
+	ea := m.read16zp(m.operand8(m.PC + 1))
 	var n uint
 	if (ea&0xff)+uint16(m.Y) > 0xff {
 		n = 1
 	}
 	ea += uint16(m.Y)
-	return m.Mem.Read8(ea), n, ea
+	return m.read8(ea), n, ea
 }
 
 //-----------------------------------------------------------------------------
 
-// opBranch does a relative branch if a condition is true.
+// opBranch does a relative branch if a condition is true, returns any extra cycles.
 func (m *M6502) opBranch(cond bool) uint {
-	cycles := 2
+	cycles := 0
 	if cond {
 		pc := uint16(m.PC + 2)
-		ofs := int8(m.Mem.Read8(m.PC + 1))
+		ofs := int8(m.operand8(m.PC + 1))
 		tgt := uint16(int(pc) + int(ofs))
 		if (tgt >> 8) == (pc >> 8) {
 			// same page: +1 cycle
@@ -217,6 +309,44 @@ func (m *M6502) opBranch(cond bool) uint {
 	return uint(cycles)
 }
 
+// opRMB resets a zero page memory bit.
+func (m *M6502) opRMB(bit uint) uint {
+	v, ea := m.readZeroPage()
+	m.write8(ea, v&^(1<<bit))
+	m.PC += 2
+	return 0
+}
+
+// opSMB sets a zero page memory bit.
+func (m *M6502) opSMB(bit uint) uint {
+	v, ea := m.readZeroPage()
+	m.write8(ea, v|(1<<bit))
+	m.PC += 2
+	return 0
+}
+
+// opBBx does a relative branch if a zero page memory bit has the given state, returns any extra cycles.
+func (m *M6502) opBBx(bit uint, set bool) uint {
+	v, _ := m.readZeroPage()
+	cycles := 0
+	pc := uint16(m.PC + 3)
+	if (v&(1<<bit) != 0) == set {
+		ofs := int8(m.operand8(m.PC + 2))
+		tgt := uint16(int(pc) + int(ofs))
+		if (tgt >> 8) == (pc >> 8) {
+			// same page: +1 cycle
+			cycles++
+		} else {
+			// different page: +2 cycles
+			cycles += 2
+		}
+		m.PC = tgt
+	} else {
+		m.PC = pc
+	}
+	return uint(cycles)
+}
+
 // opCompare sets the NZC values for the register/value compare operation.
 func (m *M6502) opCompare(reg, val uint8) {
 	m.setNZ(reg - val)
@@ -227,12 +357,12 @@ func (m *M6502) opCompare(reg, val uint8) {
 	}
 }
 
-// opADC add with carry
-func (m *M6502) opADC(v uint8) {
+// opADC add with carry, returns any extra cycles
+func (m *M6502) opADC(v uint8) uint {
 	c := uint(m.P & flagC)
 	m.P &= ^flagNVZC
 
-	if m.P&flagD != 0 {
+	if m.P&flagD != 0 && m.decimal {
 		al := (m.A & 15) + (v & 15) + uint8(c)
 		if al > 9 {
 			al += 6
@@ -250,6 +380,11 @@ func (m *M6502) opADC(v uint8) {
 		// carry
 		m.setC(ah > 15)
 		m.A = (ah << 4) | (al & 15)
+		if m.cmos {
+			// valid N and Z flags, +1 cycle
+			m.setNZ(m.A)
+			return 1
+		}
 	} else {
 		a := uint(m.A) + uint(v) + c
 		m.setN(a&0x80 != 0)
@@ -258,10 +393,11 @@ func (m *M6502) opADC(v uint8) {
 		m.setV(^(uint(m.A)^uint(v))&(uint(m.A)^a)&0x80 != 0)
 		m.A = uint8(a)
 	}
+	return 0
 }
 
-// opSBC subtract with cary
-func (m *M6502) opSBC(v uint8) {
+// opSBC subtract with cary, returns any extra cycles
+func (m *M6502) opSBC(v uint8) uint {
 	c := uint(^m.P & flagC)
 	m.P &= ^flagNVZC
 
@@ -271,7 +407,21 @@ func (m *M6502) opSBC(v uint8) {
 	m.setC(a <= 0xff)
 	m.setV((m.A^v)&(m.A^uint8(a))&0x80 != 0)
 
-	if m.P&flagD != 0 {
+	if m.P&flagD != 0 && m.decimal {
+		if m.cmos {
+			// different decimal adjust, valid N and Z flags, +1 cycle
+			al := int(m.A&15) - int(v&15) - int(c)
+			x := int(m.A) - int(v) - int(c)
+			if x < 0 {
+				x -= 0x60
+			}
+			if al < 0 {
+				x -= 0x06
+			}
+			m.A = uint8(x)
+			m.setNZ(m.A)
+			return 1
+		}
 		al := (m.A & 15) - (v & 15) - uint8(c)
 		if int8(al) < 0 {
 			al -= 6
@@ -287,6 +437,7 @@ func (m *M6502) opSBC(v uint8) {
 	} else {
 		m.A = uint8(a)
 	}
+	return 0
 }
 
 // opBit
@@ -349,71 +500,182 @@ func (m *M6502) opLSR(v uint8) uint8 {
 	return v
 }
 
+// opSLO shift left then or (with accumulator)
+func (m *M6502) opSLO(v uint8) uint8 {
+	v = m.opASL(v)
+	m.A |= v
+	m.setNZ(m.A)
+	return v
+}
+
+// opRLA rotate left then and (with accumulator)
+func (m *M6502) opRLA(v uint8) uint8 {
+	v = m.opROL(v)
+	m.A &= v
+	m.setNZ(m.A)
+	return v
+}
+
+// opSRE shift right then exclusive or (with accumulator)
+func (m *M6502) opSRE(v uint8) uint8 {
+	v = m.opLSR(v)
+	m.A ^= v
+	m.setNZ(m.A)
+	return v
+}
+
+// opRRA rotate right then add with carry
+func (m *M6502) opRRA(v uint8) uint8 {
+	v = m.opROR(v)
+	m.opADC(v)
+	return v
+}
+
+// opDCP decrement then compare (with accumulator)
+func (m *M6502) opDCP(v uint8) uint8 {
+	v--
+	m.opCompare(m.A, v)
+	return v
+}
+
+// opISC increment then subtract with carry
+func (m *M6502) opISC(v uint8) uint8 {
+	v++
+	m.opSBC(v)
+	return v
+}
+
+// opARR and then rotate right
+func (m *M6502) opARR(v uint8) {
+	t := m.A & v
+	c := m.P & flagC
+	m.P &= ^flagNVZC
+	m.A = (t >> 1) | (c << 7)
+	if m.P&flagD != 0 && m.decimal {
+		m.setN(c != 0)
+		m.setZ(m.A == 0)
+		m.setV((t^m.A)&0x40 != 0)
+		if (t&0x0f)+(t&0x01) > 5 {
+			m.A = (m.A & 0xf0) | ((m.A + 6) & 0x0f)
+		}
+		if uint(t&0xf0)+uint(t&0x10) > 0x50 {
+			m.A += 0x60
+			m.P |= flagC
+		}
+	} else {
+		m.setN(m.A&0x80 != 0)
+		m.setZ(m.A == 0)
+		m.setC(m.A&0x40 != 0)
+		m.setV(((m.A>>6)^(m.A>>5))&1 != 0)
+	}
+}
+
+// unstableMagic is the commonly accepted constant for the unstable XAA/LAX #imm opcodes.
+const unstableMagic = 0xee
+
+// writeUnstable does the SHA/SHX/SHY/TAS store of val & (high byte of base + 1).
+// When indexing crosses a page the stored value replaces the address high byte.
+func (m *M6502) writeUnstable(base uint16, idx uint8, val uint8) {
+	ea := base + uint16(idx)
+	v := val & (uint8(base>>8) + 1)
+	if (ea & 0xff00) != (base & 0xff00) {
+		ea = (uint16(v) << 8) | (ea & 0xff)
+	}
+	m.write8(ea, v)
+}
+
 //-----------------------------------------------------------------------------
 // instructions
 
 // op61, ADC add with carry, X-indexed indirect
 func op61(m *M6502) uint {
 	v, _ := m.readIndirectX()
-	m.opADC(v)
+	d := m.opADC(v)
 	m.PC += 2
-	return 6
+	return d
 }
 
 // op65, ADC add with carry, zeropage
 func op65(m *M6502) uint {
 	v, _ := m.readZeroPage()
-	m.opADC(v)
+	d := m.opADC(v)
 	m.PC += 2
-	return 3
+	return d
 }
 
 // op69, ADC add with carry, immediate
 func op69(m *M6502) uint {
 	v := m.readImmediate()
-	m.opADC(v)
+	d := m.opADC(v)
 	m.PC += 2
-	return 2
+	return d
 }
 
 // op6D, ADC add with carry, absolute
 func op6D(m *M6502) uint {
 	v, _ := m.readAbsolute()
-	m.opADC(v)
+	d := m.opADC(v)
 	m.PC += 3
-	return 4
+	return d
 }
 
 // op71, ADC add with carry, indirect Y-indexed
 func op71(m *M6502) uint {
 	v, n, _ := m.readIndirectYPenalized()
-	m.opADC(v)
+	d := m.opADC(v)
 	m.PC += 2
-	return 5 + n
+	return n + d
 }
 
 // op75, ADC add with carry, zeropage X-indexed
 func op75(m *M6502) uint {
 	v, _ := m.readZeroPageX()
-	m.opADC(v)
+	d := m.opADC(v)
 	m.PC += 2
-	return 4
+	return d
 }
 
 // op79, ADC add with carry, absolute Y-indexed
 func op79(m *M6502) uint {
 	v, n, _ := m.readAbsoluteYPenalized()
-	m.opADC(v)
+	d := m.opADC(v)
 	m.PC += 3
-	return 4 + n
+	return n + d
 }
 
 // op7D, ADC add with carry, absolute X-indexed
 func op7D(m *M6502) uint {
 	v, n, _ := m.readAbsoluteXPenalized()
-	m.opADC(v)
+	d := m.opADC(v)
 	m.PC += 3
-	return 4 + n
+	return n + d
+}
+
+// op4B, ALR and then logical shift right, immediate
+func op4B(m *M6502) uint {
+	m.A = m.opLSR(m.A & m.readImmediate())
+	m.PC += 2
+	return 0
+}
+
+// op0B, ANC and then copy N to carry, immediate
+func op0B(m *M6502) uint {
+	m.P &= ^flagC
+	m.A &= m.readImmediate()
+	m.setNZ(m.A)
+	m.setC(m.A&0x80 != 0)
+	m.PC += 2
+	return 0
+}
+
+// op2B, ANC and then copy N to carry, immediate
+func op2B(m *M6502) uint {
+	m.P &= ^flagC
+	m.A &= m.readImmediate()
+	m.setNZ(m.A)
+	m.setC(m.A&0x80 != 0)
+	m.PC += 2
+	return 0
 }
 
 // op21, AND and (with accumulator), X-indexed indirect
@@ -422,7 +684,7 @@ func op21(m *M6502) uint {
 	m.A &= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // op25, AND and (with accumulator), zeropage
@@ -431,7 +693,7 @@ func op25(m *M6502) uint {
 	m.A &= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // op29, AND and (with accumulator), immediate
@@ -440,7 +702,7 @@ func op29(m *M6502) uint {
 	m.A &= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // op2D, AND and (with accumulator), absolute
@@ -449,7 +711,7 @@ func op2D(m *M6502) uint {
 	m.A &= v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // op31, AND and (with accumulator), indirect Y-indexed
@@ -458,7 +720,7 @@ func op31(m *M6502) uint {
 	m.A &= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 5 + n
+	return n
 }
 
 // op35, AND and (with accumulator), zeropage X-indexed
@@ -467,7 +729,7 @@ func op35(m *M6502) uint {
 	m.A &= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 4
+	return 0
 }
 
 // op39, AND and (with accumulator), absolute Y-indexed
@@ -476,7 +738,7 @@ func op39(m *M6502) uint {
 	m.A &= v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // op3D, AND and (with accumulator), absolute X-indexed
@@ -485,50 +747,57 @@ func op3D(m *M6502) uint {
 	m.A &= v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4 + n
+	return n
+}
+
+// op6B, ARR and then rotate right, immediate
+func op6B(m *M6502) uint {
+	m.opARR(m.readImmediate())
+	m.PC += 2
+	return 0
 }
 
 // op06, ASL arithmetic shift left, zeropage
 func op06(m *M6502) uint {
 	v, ea := m.readZeroPage()
 	v = m.opASL(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 5
+	return 0
 }
 
 // op0A, ASL arithmetic shift left, accumulator
 func op0A(m *M6502) uint {
 	m.A = m.opASL(m.A)
 	m.PC++
-	return 2
+	return 0
 }
 
 // op0E, ASL arithmetic shift left, absolute
 func op0E(m *M6502) uint {
 	v, ea := m.readAbsolute()
 	v = m.opASL(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 6
+	return 0
 }
 
 // op16, ASL arithmetic shift left, zeropage X-indexed
 func op16(m *M6502) uint {
 	v, ea := m.readZeroPageX()
 	v = m.opASL(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // op1E, ASL arithmetic shift left, absolute X-indexed
 func op1E(m *M6502) uint {
 	v, ea := m.readAbsoluteX()
 	v = m.opASL(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 7
+	return 0
 }
 
 // op90, BCC branch on carry clear, relative
@@ -551,7 +820,7 @@ func op24(m *M6502) uint {
 	v, _ := m.readZeroPage()
 	m.opBit(v)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // op2C, BIT bit test, absolute
@@ -559,7 +828,7 @@ func op2C(m *M6502) uint {
 	v, _ := m.readAbsolute()
 	m.opBit(v)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // op30, BMI branch on minus (negative set), relative
@@ -580,10 +849,10 @@ func op10(m *M6502) uint {
 // op00, BRK break/interrupt
 func op00(m *M6502) uint {
 	m.push16(m.PC + 2)
-	m.push8(m.P)
+	m.push8(m.P | flagB | flagU)
 	m.P |= flagI
-	m.PC = m.read16(BrkAddress)
-	return 7
+	m.PC = m.vector16(m.hijack(BrkAddress))
+	return 0
 }
 
 // op50, BVC branch on overflow clear, relative
@@ -600,28 +869,28 @@ func op70(m *M6502) uint {
 func op18(m *M6502) uint {
 	m.P &= ^flagC
 	m.PC++
-	return 2
+	return 0
 }
 
 // opD8, CLD clear decimal
 func opD8(m *M6502) uint {
 	m.P &= ^flagD
 	m.PC++
-	return 2
+	return 0
 }
 
 // op58, CLI clear interrupt disable
 func op58(m *M6502) uint {
 	m.P &= ^flagI
 	m.PC++
-	return 2
+	return 0
 }
 
 // opB8, CLV clear overflow
 func opB8(m *M6502) uint {
 	m.P &= ^flagV
 	m.PC++
-	return 2
+	return 0
 }
 
 // opC1, CMP compare (with accumulator), X-indexed indirect
@@ -629,7 +898,7 @@ func opC1(m *M6502) uint {
 	v, _ := m.readIndirectX()
 	m.opCompare(m.A, v)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // opC5, CMP compare (with accumulator), zeropage
@@ -637,7 +906,7 @@ func opC5(m *M6502) uint {
 	v, _ := m.readZeroPage()
 	m.opCompare(m.A, v)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // opC9, CMP compare (with accumulator), immediate
@@ -645,7 +914,7 @@ func opC9(m *M6502) uint {
 	v := m.readImmediate()
 	m.opCompare(m.A, v)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // opCD, CMP compare (with accumulator), absolute
@@ -653,7 +922,7 @@ func opCD(m *M6502) uint {
 	v, _ := m.readAbsolute()
 	m.opCompare(m.A, v)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // opD1, CMP compare (with accumulator), indirect Y-indexed
@@ -661,7 +930,7 @@ func opD1(m *M6502) uint {
 	v, n, _ := m.readIndirectYPenalized()
 	m.opCompare(m.A, v)
 	m.PC += 2
-	return 5 + n
+	return n
 }
 
 // opD5, CMP compare (with accumulator), zeropage X-indexed
@@ -669,7 +938,7 @@ func opD5(m *M6502) uint {
 	v, _ := m.readZeroPageX()
 	m.opCompare(m.A, v)
 	m.PC += 2
-	return 4
+	return 0
 }
 
 // opD9, CMP compare (with accumulator), absolute Y-indexed
@@ -677,7 +946,7 @@ func opD9(m *M6502) uint {
 	v, n, _ := m.readAbsoluteYPenalized()
 	m.opCompare(m.A, v)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // opDD, CMP compare (with accumulator), absolute X-indexed
@@ -685,7 +954,7 @@ func opDD(m *M6502) uint {
 	v, n, _ := m.readAbsoluteXPenalized()
 	m.opCompare(m.A, v)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // opE0, CPX compare with X, immediate
@@ -693,7 +962,7 @@ func opE0(m *M6502) uint {
 	v := m.readImmediate()
 	m.opCompare(m.X, v)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // opE4, CPX compare with X, zeropage
@@ -701,7 +970,7 @@ func opE4(m *M6502) uint {
 	v, _ := m.readZeroPage()
 	m.opCompare(m.X, v)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // opEC, CPX compare with X, absolute
@@ -709,7 +978,7 @@ func opEC(m *M6502) uint {
 	v, _ := m.readAbsolute()
 	m.opCompare(m.X, v)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // opC0, CPY compare with Y, immediate
@@ -717,7 +986,7 @@ func opC0(m *M6502) uint {
 	v := m.readImmediate()
 	m.opCompare(m.Y, v)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // opC4, CPY compare with Y, zeropage
@@ -725,7 +994,7 @@ func opC4(m *M6502) uint {
 	v, _ := m.readZeroPage()
 	m.opCompare(m.Y, v)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // opCC, CPY compare with Y, absolute
@@ -733,7 +1002,70 @@ func opCC(m *M6502) uint {
 	v, _ := m.readAbsolute()
 	m.opCompare(m.Y, v)
 	m.PC += 3
-	return 4
+	return 0
+}
+
+// opC3, DCP decrement then compare (with accumulator), X-indexed indirect
+func opC3(m *M6502) uint {
+	v, ea := m.readIndirectX()
+	v = m.opDCP(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// opC7, DCP decrement then compare (with accumulator), zeropage
+func opC7(m *M6502) uint {
+	v, ea := m.readZeroPage()
+	v = m.opDCP(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// opCF, DCP decrement then compare (with accumulator), absolute
+func opCF(m *M6502) uint {
+	v, ea := m.readAbsolute()
+	v = m.opDCP(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// opD3, DCP decrement then compare (with accumulator), indirect Y-indexed
+func opD3(m *M6502) uint {
+	v, ea := m.readIndirectY()
+	v = m.opDCP(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// opD7, DCP decrement then compare (with accumulator), zeropage X-indexed
+func opD7(m *M6502) uint {
+	v, ea := m.readZeroPageX()
+	v = m.opDCP(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// opDB, DCP decrement then compare (with accumulator), absolute Y-indexed
+func opDB(m *M6502) uint {
+	v, ea := m.readAbsoluteY()
+	v = m.opDCP(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// opDF, DCP decrement then compare (with accumulator), absolute X-indexed
+func opDF(m *M6502) uint {
+	v, ea := m.readAbsoluteX()
+	v = m.opDCP(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
 }
 
 // opC6, DEC decrement, zeropage
@@ -741,9 +1073,9 @@ func opC6(m *M6502) uint {
 	v, ea := m.readZeroPage()
 	v--
 	m.setNZ(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 5
+	return 0
 }
 
 // opCE, DEC decrement, absolute
@@ -751,9 +1083,9 @@ func opCE(m *M6502) uint {
 	v, ea := m.readAbsolute()
 	v--
 	m.setNZ(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 6
+	return 0
 }
 
 // opD6, DEC decrement, zeropage X-indexed
@@ -761,9 +1093,9 @@ func opD6(m *M6502) uint {
 	v, ea := m.readZeroPageX()
 	v--
 	m.setNZ(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // opDE, DEC decrement, absolute X-indexed
@@ -771,9 +1103,9 @@ func opDE(m *M6502) uint {
 	v, ea := m.readAbsoluteX()
 	v--
 	m.setNZ(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 7
+	return 0
 }
 
 // opCA, DEX decrement X
@@ -781,7 +1113,7 @@ func opCA(m *M6502) uint {
 	m.X--
 	m.setNZ(m.X)
 	m.PC++
-	return 2
+	return 0
 }
 
 // op88, DEY decrement Y
@@ -789,7 +1121,7 @@ func op88(m *M6502) uint {
 	m.Y--
 	m.setNZ(m.Y)
 	m.PC++
-	return 2
+	return 0
 }
 
 // op41, EOR exclusive or (with accumulator), X-indexed indirect
@@ -798,7 +1130,7 @@ func op41(m *M6502) uint {
 	m.A ^= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // op45, EOR exclusive or (with accumulator), zeropage
@@ -807,7 +1139,7 @@ func op45(m *M6502) uint {
 	m.A ^= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // op49, EOR exclusive or (with accumulator), immediate
@@ -816,7 +1148,7 @@ func op49(m *M6502) uint {
 	m.A ^= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // op4D, EOR exclusive or (with accumulator), absolute
@@ -825,7 +1157,7 @@ func op4D(m *M6502) uint {
 	m.A ^= v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // op51, EOR exclusive or (with accumulator), indirect Y-indexed
@@ -834,7 +1166,7 @@ func op51(m *M6502) uint {
 	m.A ^= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 5 + n
+	return n
 }
 
 // op55, EOR exclusive or (with accumulator), zeropage X-indexed
@@ -843,7 +1175,7 @@ func op55(m *M6502) uint {
 	m.A ^= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 4
+	return 0
 }
 
 // op59, EOR exclusive or (with accumulator), absolute Y-indexed
@@ -852,7 +1184,7 @@ func op59(m *M6502) uint {
 	m.A ^= v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // op5D, EOR exclusive or (with accumulator), absolute X-indexed
@@ -861,7 +1193,7 @@ func op5D(m *M6502) uint {
 	m.A ^= v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // opXX, ILL illegal
@@ -875,9 +1207,9 @@ func opE6(m *M6502) uint {
 	v, ea := m.readZeroPage()
 	v++
 	m.setNZ(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 5
+	return 0
 }
 
 // opEE, INC increment, absolute
@@ -885,9 +1217,9 @@ func opEE(m *M6502) uint {
 	v, ea := m.readAbsolute()
 	v++
 	m.setNZ(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 6
+	return 0
 }
 
 // opF6, INC increment, zeropage X-indexed
@@ -895,9 +1227,9 @@ func opF6(m *M6502) uint {
 	v, ea := m.readZeroPageX()
 	v++
 	m.setNZ(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // opFE, INC increment, absolute X-indexed
@@ -905,9 +1237,9 @@ func opFE(m *M6502) uint {
 	v, ea := m.readAbsoluteX()
 	v++
 	m.setNZ(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 7
+	return 0
 }
 
 // opE8, INX increment X
@@ -915,7 +1247,7 @@ func opE8(m *M6502) uint {
 	m.X++
 	m.setNZ(m.X)
 	m.PC++
-	return 2
+	return 0
 }
 
 // opC8, INY increment Y
@@ -923,37 +1255,183 @@ func opC8(m *M6502) uint {
 	m.Y++
 	m.setNZ(m.Y)
 	m.PC++
-	return 2
+	return 0
+}
+
+// opE3, ISC increment then subtract with carry, X-indexed indirect
+func opE3(m *M6502) uint {
+	v, ea := m.readIndirectX()
+	v = m.opISC(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// opE7, ISC increment then subtract with carry, zeropage
+func opE7(m *M6502) uint {
+	v, ea := m.readZeroPage()
+	v = m.opISC(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// opEF, ISC increment then subtract with carry, absolute
+func opEF(m *M6502) uint {
+	v, ea := m.readAbsolute()
+	v = m.opISC(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// opF3, ISC increment then subtract with carry, indirect Y-indexed
+func opF3(m *M6502) uint {
+	v, ea := m.readIndirectY()
+	v = m.opISC(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// opF7, ISC increment then subtract with carry, zeropage X-indexed
+func opF7(m *M6502) uint {
+	v, ea := m.readZeroPageX()
+	v = m.opISC(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// opFB, ISC increment then subtract with carry, absolute Y-indexed
+func opFB(m *M6502) uint {
+	v, ea := m.readAbsoluteY()
+	v = m.opISC(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// opFF, ISC increment then subtract with carry, absolute X-indexed
+func opFF(m *M6502) uint {
+	v, ea := m.readAbsoluteX()
+	v = m.opISC(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// opJAM, JAM jam (halt the cpu)
+func opJAM(m *M6502) uint {
+	m.jam = true
+	return 0
 }
 
 // op4C, JMP jump, absolute
 func op4C(m *M6502) uint {
-	m.PC = m.read16(m.PC + 1)
+	m.PC = m.operand16(m.PC + 1)
 	m.jmpVSR()
-	return 3
+	return 0
 }
 
 // op6C, JMP jump, indirect
 func op6C(m *M6502) uint {
-	m.PC = m.read16(m.read16(m.PC + 1))
+	m.PC = m.read16ind(m.operand16(m.PC + 1))
 	m.jmpVSR()
-	return 5
+	return 0
 }
 
 // op20, JSR jump subroutine, absolute
 func op20(m *M6502) uint {
 	m.push16(m.PC + 2)
-	m.PC = m.read16(m.PC + 1)
+	m.PC = m.operand16(m.PC + 1)
 	m.jsrVSR()
-	return 6
+	return 0
 }
 
-// opA1, LDA load accumulator, X-indexed indirect
-func opA1(m *M6502) uint {
+// opBB, LAS load accumulator, X and stack pointer (unstable), absolute Y-indexed
+func opBB(m *M6502) uint {
+	v, n, _ := m.readAbsoluteYPenalized()
+	v &= m.S
+	m.A = v
+	m.X = v
+	m.S = v
+	m.setNZ(v)
+	m.PC += 3
+	return n
+}
+
+// opA3, LAX load accumulator and X, X-indexed indirect
+func opA3(m *M6502) uint {
+	m.A, _ = m.readIndirectX()
+	m.X = m.A
+	m.setNZ(m.A)
+	m.PC += 2
+	return 0
+}
+
+// opA7, LAX load accumulator and X, zeropage
+func opA7(m *M6502) uint {
+	m.A, _ = m.readZeroPage()
+	m.X = m.A
+	m.setNZ(m.A)
+	m.PC += 2
+	return 0
+}
+
+// opAB, LAX load accumulator and X, immediate
+func opAB(m *M6502) uint {
+	m.A = (m.A | unstableMagic) & m.readImmediate()
+	m.X = m.A
+	m.setNZ(m.A)
+	m.PC += 2
+	return 0
+}
+
+// opAF, LAX load accumulator and X, absolute
+func opAF(m *M6502) uint {
+	m.A, _ = m.readAbsolute()
+	m.X = m.A
+	m.setNZ(m.A)
+	m.PC += 3
+	return 0
+}
+
+// opB3, LAX load accumulator and X, indirect Y-indexed
+func opB3(m *M6502) uint {
+	v, n, _ := m.readIndirectYPenalized()
+	m.A = v
+	m.X = v
+	m.setNZ(v)
+	m.PC += 2
+	return n
+}
+
+// opB7, LAX load accumulator and X, zeropage Y-indexed
+func opB7(m *M6502) uint {
+	m.A, _ = m.readZeroPageY()
+	m.X = m.A
+	m.setNZ(m.A)
+	m.PC += 2
+	return 0
+}
+
+// opBF, LAX load accumulator and X, absolute Y-indexed
+func opBF(m *M6502) uint {
+	v, n, _ := m.readAbsoluteYPenalized()
+	m.A = v
+	m.X = v
+	m.setNZ(v)
+	m.PC += 3
+	return n
+}
+
+// opA1, LDA load accumulator, X-indexed indirect
+func opA1(m *M6502) uint {
 	m.A, _ = m.readIndirectX()
 	m.setNZ(m.A)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // opA5, LDA load accumulator, zeropage
@@ -961,7 +1439,7 @@ func opA5(m *M6502) uint {
 	m.A, _ = m.readZeroPage()
 	m.setNZ(m.A)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // opA9, LDA load accumulator, immediate
@@ -969,7 +1447,7 @@ func opA9(m *M6502) uint {
 	m.A = m.readImmediate()
 	m.setNZ(m.A)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // opAD, LDA load accumulator, absolute
@@ -977,7 +1455,7 @@ func opAD(m *M6502) uint {
 	m.A, _ = m.readAbsolute()
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // opB1, LDA load accumulator, indirect Y-indexed
@@ -986,7 +1464,7 @@ func opB1(m *M6502) uint {
 	m.A = v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 5 + n
+	return n
 }
 
 // opB5, LDA load accumulator, zeropage X-indexed
@@ -994,7 +1472,7 @@ func opB5(m *M6502) uint {
 	m.A, _ = m.readZeroPageX()
 	m.setNZ(m.A)
 	m.PC += 2
-	return 4
+	return 0
 }
 
 // opB9, LDA load accumulator, absolute Y-indexed
@@ -1003,7 +1481,7 @@ func opB9(m *M6502) uint {
 	m.A = v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // opBD, LDA load accumulator, absolute X-indexed
@@ -1012,7 +1490,7 @@ func opBD(m *M6502) uint {
 	m.A = v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // opA2, LDX load X, immediate
@@ -1020,7 +1498,7 @@ func opA2(m *M6502) uint {
 	m.X = m.readImmediate()
 	m.setNZ(m.X)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // opA6, LDX load X, zeropage
@@ -1028,7 +1506,7 @@ func opA6(m *M6502) uint {
 	m.X, _ = m.readZeroPage()
 	m.setNZ(m.X)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // opAE, LDX load X, absolute
@@ -1036,7 +1514,7 @@ func opAE(m *M6502) uint {
 	m.X, _ = m.readAbsolute()
 	m.setNZ(m.X)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // opB6, LDX load X, zeropage Y-indexed
@@ -1044,7 +1522,7 @@ func opB6(m *M6502) uint {
 	m.X, _ = m.readZeroPageY()
 	m.setNZ(m.X)
 	m.PC += 2
-	return 4
+	return 0
 }
 
 // opBE, LDX load X, absolute Y-indexed
@@ -1053,7 +1531,7 @@ func opBE(m *M6502) uint {
 	m.X = v
 	m.setNZ(m.X)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // opA0, LDY load Y, immediate
@@ -1062,7 +1540,7 @@ func opA0(m *M6502) uint {
 	m.Y = v
 	m.setNZ(m.Y)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // opA4, LDY load Y, zeropage
@@ -1070,7 +1548,7 @@ func opA4(m *M6502) uint {
 	m.Y, _ = m.readZeroPage()
 	m.setNZ(m.Y)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // opAC, LDY load Y, absolute
@@ -1078,7 +1556,7 @@ func opAC(m *M6502) uint {
 	m.Y, _ = m.readAbsolute()
 	m.setNZ(m.Y)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // opB4, LDY load Y, zeropage X-indexed
@@ -1086,7 +1564,7 @@ func opB4(m *M6502) uint {
 	m.Y, _ = m.readZeroPageX()
 	m.setNZ(m.Y)
 	m.PC += 2
-	return 4
+	return 0
 }
 
 // opBC, LDY load Y, absolute X-indexed
@@ -1095,56 +1573,234 @@ func opBC(m *M6502) uint {
 	m.Y = v
 	m.setNZ(m.Y)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // op46, LSR logical shift right, zeropage
 func op46(m *M6502) uint {
 	v, ea := m.readZeroPage()
 	v = m.opLSR(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 5
+	return 0
 }
 
 // op4A, LSR logical shift right, accumulator
 func op4A(m *M6502) uint {
 	m.A = m.opLSR(m.A)
 	m.PC++
-	return 2
+	return 0
 }
 
 // op4E, LSR logical shift right, absolute
 func op4E(m *M6502) uint {
 	v, ea := m.readAbsolute()
 	v = m.opLSR(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 6
+	return 0
 }
 
 // op56, LSR logical shift right, zeropage X-indexed
 func op56(m *M6502) uint {
 	v, ea := m.readZeroPageX()
 	v = m.opLSR(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // op5E, LSR logical shift right, absolute X-indexed
 func op5E(m *M6502) uint {
 	v, ea := m.readAbsoluteX()
 	v = m.opLSR(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op04, NOP no operation, zeropage
+func op04(m *M6502) uint {
+	m.readZeroPage()
+	m.PC += 2
+	return 0
+}
+
+// op0C, NOP no operation, absolute
+func op0C(m *M6502) uint {
+	m.readAbsolute()
+	m.PC += 3
+	return 0
+}
+
+// op14, NOP no operation, zeropage X-indexed
+func op14(m *M6502) uint {
+	m.readZeroPageX()
+	m.PC += 2
+	return 0
+}
+
+// op1A, NOP no operation
+func op1A(m *M6502) uint {
+	m.PC++
+	return 0
+}
+
+// op1C, NOP no operation, absolute X-indexed
+func op1C(m *M6502) uint {
+	_, n, _ := m.readAbsoluteXPenalized()
+	m.PC += 3
+	return n
+}
+
+// op34, NOP no operation, zeropage X-indexed
+func op34(m *M6502) uint {
+	m.readZeroPageX()
+	m.PC += 2
+	return 0
+}
+
+// op3A, NOP no operation
+func op3A(m *M6502) uint {
+	m.PC++
+	return 0
+}
+
+// op3C, NOP no operation, absolute X-indexed
+func op3C(m *M6502) uint {
+	_, n, _ := m.readAbsoluteXPenalized()
+	m.PC += 3
+	return n
+}
+
+// op44, NOP no operation, zeropage
+func op44(m *M6502) uint {
+	m.readZeroPage()
+	m.PC += 2
+	return 0
+}
+
+// op54, NOP no operation, zeropage X-indexed
+func op54(m *M6502) uint {
+	m.readZeroPageX()
+	m.PC += 2
+	return 0
+}
+
+// op5A, NOP no operation
+func op5A(m *M6502) uint {
+	m.PC++
+	return 0
+}
+
+// op5C, NOP no operation, absolute X-indexed
+func op5C(m *M6502) uint {
+	_, n, _ := m.readAbsoluteXPenalized()
+	m.PC += 3
+	return n
+}
+
+// op64, NOP no operation, zeropage
+func op64(m *M6502) uint {
+	m.readZeroPage()
+	m.PC += 2
+	return 0
+}
+
+// op74, NOP no operation, zeropage X-indexed
+func op74(m *M6502) uint {
+	m.readZeroPageX()
+	m.PC += 2
+	return 0
+}
+
+// op7A, NOP no operation
+func op7A(m *M6502) uint {
+	m.PC++
+	return 0
+}
+
+// op7C, NOP no operation, absolute X-indexed
+func op7C(m *M6502) uint {
+	_, n, _ := m.readAbsoluteXPenalized()
+	m.PC += 3
+	return n
+}
+
+// op80, NOP no operation, immediate
+func op80(m *M6502) uint {
+	m.PC += 2
+	return 0
+}
+
+// op82, NOP no operation, immediate
+func op82(m *M6502) uint {
+	m.PC += 2
+	return 0
+}
+
+// op89, NOP no operation, immediate
+func op89(m *M6502) uint {
+	m.PC += 2
+	return 0
+}
+
+// opC2, NOP no operation, immediate
+func opC2(m *M6502) uint {
+	m.PC += 2
+	return 0
+}
+
+// opD4, NOP no operation, zeropage X-indexed
+func opD4(m *M6502) uint {
+	m.readZeroPageX()
+	m.PC += 2
+	return 0
+}
+
+// opDA, NOP no operation
+func opDA(m *M6502) uint {
+	m.PC++
+	return 0
+}
+
+// opDC, NOP no operation, absolute X-indexed
+func opDC(m *M6502) uint {
+	_, n, _ := m.readAbsoluteXPenalized()
 	m.PC += 3
-	return 7
+	return n
+}
+
+// opE2, NOP no operation, immediate
+func opE2(m *M6502) uint {
+	m.PC += 2
+	return 0
 }
 
 // opEA, NOP no operation
 func opEA(m *M6502) uint {
 	m.PC++
-	return 2
+	return 0
+}
+
+// opF4, NOP no operation, zeropage X-indexed
+func opF4(m *M6502) uint {
+	m.readZeroPageX()
+	m.PC += 2
+	return 0
+}
+
+// opFA, NOP no operation
+func opFA(m *M6502) uint {
+	m.PC++
+	return 0
+}
+
+// opFC, NOP no operation, absolute X-indexed
+func opFC(m *M6502) uint {
+	_, n, _ := m.readAbsoluteXPenalized()
+	m.PC += 3
+	return n
 }
 
 // op01, ORA or with accumulator, X-indexed indirect
@@ -1153,7 +1809,7 @@ func op01(m *M6502) uint {
 	m.A |= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // op05, ORA or with accumulator, zeropage
@@ -1162,7 +1818,7 @@ func op05(m *M6502) uint {
 	m.A |= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // op09, ORA or with accumulator, immediate
@@ -1171,7 +1827,7 @@ func op09(m *M6502) uint {
 	m.A |= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // op0D, ORA or with accumulator, absolute
@@ -1180,7 +1836,7 @@ func op0D(m *M6502) uint {
 	m.A |= v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // op11, ORA or with accumulator, indirect Y-indexed
@@ -1189,7 +1845,7 @@ func op11(m *M6502) uint {
 	m.A |= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 5 + n
+	return n
 }
 
 // op15, ORA or with accumulator, zeropage X-indexed
@@ -1198,7 +1854,7 @@ func op15(m *M6502) uint {
 	m.A |= v
 	m.setNZ(m.A)
 	m.PC += 2
-	return 4
+	return 0
 }
 
 // op19, ORA or with accumulator, absolute Y-indexed
@@ -1207,7 +1863,7 @@ func op19(m *M6502) uint {
 	m.A |= v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // op1D, ORA or with accumulator, absolute X-indexed
@@ -1216,21 +1872,21 @@ func op1D(m *M6502) uint {
 	m.A |= v
 	m.setNZ(m.A)
 	m.PC += 3
-	return 4 + n
+	return n
 }
 
 // op48, PHA push accumulator
 func op48(m *M6502) uint {
 	m.push8(m.A)
 	m.PC++
-	return 3
+	return 0
 }
 
 // op08, PHP push processor status (SR)
 func op08(m *M6502) uint {
-	m.push8(m.P)
+	m.push8(m.P | flagB | flagU)
 	m.PC++
-	return 3
+	return 0
 }
 
 // op68, PLA pull accumulator
@@ -1238,289 +1894,623 @@ func op68(m *M6502) uint {
 	m.A = m.pop8()
 	m.setNZ(m.A)
 	m.PC++
-	return 4
+	return 0
 }
 
 // op28, PLP pull processor status (SR)
 func op28(m *M6502) uint {
-	m.P = m.pop8() | flagB | (1 << 5)
+	m.P = m.pop8() | flagB | flagU
 	m.PC++
-	return 4
+	return 0
+}
+
+// op23, RLA rotate left then and (with accumulator), X-indexed indirect
+func op23(m *M6502) uint {
+	v, ea := m.readIndirectX()
+	v = m.opRLA(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op27, RLA rotate left then and (with accumulator), zeropage
+func op27(m *M6502) uint {
+	v, ea := m.readZeroPage()
+	v = m.opRLA(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op2F, RLA rotate left then and (with accumulator), absolute
+func op2F(m *M6502) uint {
+	v, ea := m.readAbsolute()
+	v = m.opRLA(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op33, RLA rotate left then and (with accumulator), indirect Y-indexed
+func op33(m *M6502) uint {
+	v, ea := m.readIndirectY()
+	v = m.opRLA(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op37, RLA rotate left then and (with accumulator), zeropage X-indexed
+func op37(m *M6502) uint {
+	v, ea := m.readZeroPageX()
+	v = m.opRLA(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op3B, RLA rotate left then and (with accumulator), absolute Y-indexed
+func op3B(m *M6502) uint {
+	v, ea := m.readAbsoluteY()
+	v = m.opRLA(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op3F, RLA rotate left then and (with accumulator), absolute X-indexed
+func op3F(m *M6502) uint {
+	v, ea := m.readAbsoluteX()
+	v = m.opRLA(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
 }
 
 // op26, ROL rotate left, zeropage
 func op26(m *M6502) uint {
 	v, ea := m.readZeroPage()
 	v = m.opROL(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 5
+	return 0
 }
 
 // op2A, ROL rotate left, accumulator
 func op2A(m *M6502) uint {
 	m.A = m.opROL(m.A)
 	m.PC++
-	return 2
+	return 0
 }
 
 // op2E, ROL rotate left, absolute
 func op2E(m *M6502) uint {
 	v, ea := m.readAbsolute()
 	v = m.opROL(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 6
+	return 0
 }
 
 // op36, ROL rotate left, zeropage X-indexed
 func op36(m *M6502) uint {
 	v, ea := m.readZeroPageX()
 	v = m.opROL(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // op3E, ROL rotate left, absolute X-indexed
 func op3E(m *M6502) uint {
 	v, ea := m.readAbsoluteX()
 	v = m.opROL(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 7
+	return 0
 }
 
 // op66, ROR rotate right, zeropage
 func op66(m *M6502) uint {
 	v, ea := m.readZeroPage()
 	v = m.opROR(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 5
+	return 0
 }
 
 // op6A, ROR rotate right, accumulator
 func op6A(m *M6502) uint {
 	m.A = m.opROR(m.A)
 	m.PC++
-	return 2
+	return 0
 }
 
 // op6E, ROR rotate right, absolute
 func op6E(m *M6502) uint {
 	v, ea := m.readAbsolute()
 	v = m.opROR(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 6
+	return 0
 }
 
 // op76, ROR rotate right, zeropage X-indexed
 func op76(m *M6502) uint {
 	v, ea := m.readZeroPageX()
 	v = m.opROR(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // op7E, ROR rotate right, absolute X-indexed
 func op7E(m *M6502) uint {
 	v, ea := m.readAbsoluteX()
 	v = m.opROR(v)
-	m.Mem.Write8(ea, v)
+	m.write8(ea, v)
 	m.PC += 3
-	return 7
+	return 0
 }
 
-// op40, RTI return from interrupt
-func op40(m *M6502) uint {
-	m.P = m.pop8()
-	m.PC = m.pop16()
-	return 6
+// op63, RRA rotate right then add with carry, X-indexed indirect
+func op63(m *M6502) uint {
+	v, ea := m.readIndirectX()
+	v = m.opRRA(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
 }
 
-// op60, RTS return from subroutine
-func op60(m *M6502) uint {
-	m.PC = m.pop16() + 1
-	return 6
+// op67, RRA rotate right then add with carry, zeropage
+func op67(m *M6502) uint {
+	v, ea := m.readZeroPage()
+	v = m.opRRA(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
 }
 
-// opE1, SBC subtract with carry, X-indexed indirect
-func opE1(m *M6502) uint {
-	v, _ := m.readIndirectX()
-	m.opSBC(v)
+// op6F, RRA rotate right then add with carry, absolute
+func op6F(m *M6502) uint {
+	v, ea := m.readAbsolute()
+	v = m.opRRA(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op73, RRA rotate right then add with carry, indirect Y-indexed
+func op73(m *M6502) uint {
+	v, ea := m.readIndirectY()
+	v = m.opRRA(v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 6
+	return 0
 }
 
-// opE5, SBC subtract with carry, zeropage
-func opE5(m *M6502) uint {
-	v, _ := m.readZeroPage()
-	m.opSBC(v)
+// op77, RRA rotate right then add with carry, zeropage X-indexed
+func op77(m *M6502) uint {
+	v, ea := m.readZeroPageX()
+	v = m.opRRA(v)
+	m.write8(ea, v)
 	m.PC += 2
-	return 3
+	return 0
 }
 
-// opE9, SBC subtract with carry, immediate
+// op7B, RRA rotate right then add with carry, absolute Y-indexed
+func op7B(m *M6502) uint {
+	v, ea := m.readAbsoluteY()
+	v = m.opRRA(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op7F, RRA rotate right then add with carry, absolute X-indexed
+func op7F(m *M6502) uint {
+	v, ea := m.readAbsoluteX()
+	v = m.opRRA(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op40, RTI return from interrupt
+func op40(m *M6502) uint {
+	m.P = m.pop8() | flagB | flagU
+	m.PC = m.pop16()
+	return 0
+}
+
+// op60, RTS return from subroutine
+func op60(m *M6502) uint {
+	m.PC = m.pop16() + 1
+	return 0
+}
+
+// op83, SAX store accumulator and X, X-indexed indirect
+func op83(m *M6502) uint {
+	m.writeIndirectX(m.A & m.X)
+	m.PC += 2
+	return 0
+}
+
+// op87, SAX store accumulator and X, zeropage
+func op87(m *M6502) uint {
+	m.writeZeroPage(m.A & m.X)
+	m.PC += 2
+	return 0
+}
+
+// op8F, SAX store accumulator and X, absolute
+func op8F(m *M6502) uint {
+	m.writeAbsolute(m.A & m.X)
+	m.PC += 3
+	return 0
+}
+
+// op97, SAX store accumulator and X, zeropage Y-indexed
+func op97(m *M6502) uint {
+	m.writeZeroPageY(m.A & m.X)
+	m.PC += 2
+	return 0
+}
+
+// opE1, SBC subtract with carry, X-indexed indirect
+func opE1(m *M6502) uint {
+	v, _ := m.readIndirectX()
+	d := m.opSBC(v)
+	m.PC += 2
+	return d
+}
+
+// opE5, SBC subtract with carry, zeropage
+func opE5(m *M6502) uint {
+	v, _ := m.readZeroPage()
+	d := m.opSBC(v)
+	m.PC += 2
+	return d
+}
+
+// opE9, SBC subtract with carry, immediate
 func opE9(m *M6502) uint {
+	v := m.readImmediate()
+	d := m.opSBC(v)
+	m.PC += 2
+	return d
+}
+
+// opEB, SBC subtract with carry, immediate
+func opEB(m *M6502) uint {
 	v := m.readImmediate()
 	m.opSBC(v)
 	m.PC += 2
-	return 2
+	return 0
 }
 
 // opED, SBC subtract with carry, absolute
 func opED(m *M6502) uint {
 	v, _ := m.readAbsolute()
-	m.opSBC(v)
+	d := m.opSBC(v)
 	m.PC += 3
-	return 4
+	return d
 }
 
 // opF1, SBC subtract with carry, indirect Y-indexed
 func opF1(m *M6502) uint {
 	v, n, _ := m.readIndirectYPenalized()
-	m.opSBC(v)
+	d := m.opSBC(v)
 	m.PC += 2
-	return 5 + n
+	return n + d
 }
 
 // opF5, SBC subtract with carry, zeropage X-indexed
 func opF5(m *M6502) uint {
 	v, _ := m.readZeroPageX()
-	m.opSBC(v)
+	d := m.opSBC(v)
 	m.PC += 2
-	return 4
+	return d
 }
 
 // opF9, SBC subtract with carry, absolute Y-indexed
 func opF9(m *M6502) uint {
 	v, n, _ := m.readAbsoluteYPenalized()
-	m.opSBC(v)
+	d := m.opSBC(v)
 	m.PC += 3
-	return 4 + n
+	return n + d
 }
 
 // opFD, SBC subtract with carry, absolute X-indexed
 func opFD(m *M6502) uint {
 	v, n, _ := m.readAbsoluteXPenalized()
-	m.opSBC(v)
+	d := m.opSBC(v)
 	m.PC += 3
-	return 4 + n
+	return n + d
+}
+
+// opCB, SBX subtract from accumulator and X, immediate
+func opCB(m *M6502) uint {
+	v := m.readImmediate()
+	x := m.A & m.X
+	m.opCompare(x, v)
+	m.X = x - v
+	m.PC += 2
+	return 0
 }
 
 // op38, SEC set carry
 func op38(m *M6502) uint {
 	m.PC++
 	m.P |= flagC
-	return 2
+	return 0
 }
 
 // opF8, SED set decimal
 func opF8(m *M6502) uint {
 	m.PC++
 	m.P |= flagD
-	return 2
+	return 0
 }
 
 // op78, SEI set interrupt disable
 func op78(m *M6502) uint {
 	m.PC++
 	m.P |= flagI
-	return 2
+	return 0
+}
+
+// op93, SHA store accumulator and X and high (unstable), indirect Y-indexed
+func op93(m *M6502) uint {
+	m.writeUnstable(m.read16zp(m.operand8(m.PC+1)), m.Y, m.A&m.X)
+	m.PC += 2
+	return 0
+}
+
+// op9F, SHA store accumulator and X and high (unstable), absolute Y-indexed
+func op9F(m *M6502) uint {
+	m.writeUnstable(m.operand16(m.PC+1), m.Y, m.A&m.X)
+	m.PC += 3
+	return 0
+}
+
+// op9E, SHX store X and high (unstable), absolute Y-indexed
+func op9E(m *M6502) uint {
+	m.writeUnstable(m.operand16(m.PC+1), m.Y, m.X)
+	m.PC += 3
+	return 0
+}
+
+// op9C, SHY store Y and high (unstable), absolute X-indexed
+func op9C(m *M6502) uint {
+	m.writeUnstable(m.operand16(m.PC+1), m.X, m.Y)
+	m.PC += 3
+	return 0
+}
+
+// op03, SLO shift left then or (with accumulator), X-indexed indirect
+func op03(m *M6502) uint {
+	v, ea := m.readIndirectX()
+	v = m.opSLO(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op07, SLO shift left then or (with accumulator), zeropage
+func op07(m *M6502) uint {
+	v, ea := m.readZeroPage()
+	v = m.opSLO(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op0F, SLO shift left then or (with accumulator), absolute
+func op0F(m *M6502) uint {
+	v, ea := m.readAbsolute()
+	v = m.opSLO(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op13, SLO shift left then or (with accumulator), indirect Y-indexed
+func op13(m *M6502) uint {
+	v, ea := m.readIndirectY()
+	v = m.opSLO(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op17, SLO shift left then or (with accumulator), zeropage X-indexed
+func op17(m *M6502) uint {
+	v, ea := m.readZeroPageX()
+	v = m.opSLO(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op1B, SLO shift left then or (with accumulator), absolute Y-indexed
+func op1B(m *M6502) uint {
+	v, ea := m.readAbsoluteY()
+	v = m.opSLO(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op1F, SLO shift left then or (with accumulator), absolute X-indexed
+func op1F(m *M6502) uint {
+	v, ea := m.readAbsoluteX()
+	v = m.opSLO(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op43, SRE shift right then exclusive or (with accumulator), X-indexed indirect
+func op43(m *M6502) uint {
+	v, ea := m.readIndirectX()
+	v = m.opSRE(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op47, SRE shift right then exclusive or (with accumulator), zeropage
+func op47(m *M6502) uint {
+	v, ea := m.readZeroPage()
+	v = m.opSRE(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op4F, SRE shift right then exclusive or (with accumulator), absolute
+func op4F(m *M6502) uint {
+	v, ea := m.readAbsolute()
+	v = m.opSRE(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op53, SRE shift right then exclusive or (with accumulator), indirect Y-indexed
+func op53(m *M6502) uint {
+	v, ea := m.readIndirectY()
+	v = m.opSRE(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op57, SRE shift right then exclusive or (with accumulator), zeropage X-indexed
+func op57(m *M6502) uint {
+	v, ea := m.readZeroPageX()
+	v = m.opSRE(v)
+	m.write8(ea, v)
+	m.PC += 2
+	return 0
+}
+
+// op5B, SRE shift right then exclusive or (with accumulator), absolute Y-indexed
+func op5B(m *M6502) uint {
+	v, ea := m.readAbsoluteY()
+	v = m.opSRE(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
+}
+
+// op5F, SRE shift right then exclusive or (with accumulator), absolute X-indexed
+func op5F(m *M6502) uint {
+	v, ea := m.readAbsoluteX()
+	v = m.opSRE(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return 0
 }
 
 // op81, STA store accumulator, X-indexed indirect
 func op81(m *M6502) uint {
 	m.writeIndirectX(m.A)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // op85, STA store accumulator, zeropage
 func op85(m *M6502) uint {
 	m.writeZeroPage(m.A)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // op8D, STA store accumulator, absolute
 func op8D(m *M6502) uint {
 	m.writeAbsolute(m.A)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // op91, STA store accumulator, indirect Y-indexed
 func op91(m *M6502) uint {
 	m.writeIndirectY(m.A)
 	m.PC += 2
-	return 6
+	return 0
 }
 
 // op95, STA store accumulator, zeropage X-indexed
 func op95(m *M6502) uint {
 	m.writeZeroPageX(m.A)
 	m.PC += 2
-	return 4
+	return 0
 }
 
 // op99, STA store accumulator, absolute Y-indexed
 func op99(m *M6502) uint {
 	m.writeAbsoluteY(m.A)
 	m.PC += 3
-	return 5
+	return 0
 }
 
 // op9D, STA store accumulator, absolute X-indexed
 func op9D(m *M6502) uint {
 	m.writeAbsoluteX(m.A)
 	m.PC += 3
-	return 5
+	return 0
 }
 
 // op86, STX store X, zeropage
 func op86(m *M6502) uint {
 	m.writeZeroPage(m.X)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // op8E, STX store X, absolute
 func op8E(m *M6502) uint {
 	m.writeAbsolute(m.X)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // op96, STX store X, zeropage Y-indexed
 func op96(m *M6502) uint {
 	m.writeZeroPageY(m.X)
 	m.PC += 2
-	return 4
+	return 0
 }
 
 // op84, STY store Y, zeropage
 func op84(m *M6502) uint {
 	m.writeZeroPage(m.Y)
 	m.PC += 2
-	return 3
+	return 0
 }
 
 // op8C, STY store Y, absolute
 func op8C(m *M6502) uint {
 	m.writeAbsolute(m.Y)
 	m.PC += 3
-	return 4
+	return 0
 }
 
 // op94, STY store Y, zeropage X-indexed
 func op94(m *M6502) uint {
 	m.writeZeroPageX(m.Y)
 	m.PC += 2
-	return 4
+	return 0
+}
+
+// op9B, TAS transfer to stack pointer then store (unstable), absolute Y-indexed
+func op9B(m *M6502) uint {
+	m.S = m.A & m.X
+	m.writeUnstable(m.operand16(m.PC+1), m.Y, m.S)
+	m.PC += 3
+	return 0
 }
 
 // opAA, TAX transfer accumulator to X
@@ -1528,7 +2518,7 @@ func opAA(m *M6502) uint {
 	m.PC++
 	m.X = m.A
 	m.setNZ(m.X)
-	return 2
+	return 0
 }
 
 // opA8, TAY transfer accumulator to Y
@@ -1536,7 +2526,7 @@ func opA8(m *M6502) uint {
 	m.PC++
 	m.Y = m.A
 	m.setNZ(m.Y)
-	return 2
+	return 0
 }
 
 // opBA, TSX transfer stack pointer to X
@@ -1544,7 +2534,7 @@ func opBA(m *M6502) uint {
 	m.PC++
 	m.X = m.S
 	m.setNZ(m.X)
-	return 2
+	return 0
 }
 
 // op8A, TXA transfer X to accumulator
@@ -1552,14 +2542,14 @@ func op8A(m *M6502) uint {
 	m.PC++
 	m.A = m.X
 	m.setNZ(m.A)
-	return 2
+	return 0
 }
 
 // op9A, TXS transfer X to stack pointer
 func op9A(m *M6502) uint {
 	m.PC++
 	m.S = m.X
-	return 2
+	return 0
 }
 
 // op98, TYA transfer Y to accumulator
@@ -1567,55 +2557,558 @@ func op98(m *M6502) uint {
 	m.PC++
 	m.A = m.Y
 	m.setNZ(m.A)
-	return 2
+	return 0
 }
 
-type opFunc func(m *M6502) uint
+// op8B, XAA transfer X then and (unstable), immediate
+func op8B(m *M6502) uint {
+	m.A = (m.A | unstableMagic) & m.X & m.readImmediate()
+	m.setNZ(m.A)
+	m.PC += 2
+	return 0
+}
+
+//-----------------------------------------------------------------------------
+// 65C02 instructions
+
+// cmos00, BRK break/interrupt
+func cmos00(m *M6502) uint {
+	m.push16(m.PC + 2)
+	m.push8(m.P | flagB | flagU)
+	m.P |= flagI
+	m.P &= ^flagD
+	m.PC = m.vector16(BrkAddress)
+	return 0
+}
+
+// cmos04, TSB test and set bits, zeropage
+func cmos04(m *M6502) uint {
+	v, ea := m.readZeroPage()
+	m.P &= ^flagZ
+	m.setZ(v&m.A == 0)
+	m.write8(ea, v|m.A)
+	m.PC += 2
+	return 0
+}
+
+// cmos07, RMB0 reset memory bit 0, zeropage
+func cmos07(m *M6502) uint {
+	return m.opRMB(0)
+}
+
+// cmos0C, TSB test and set bits, absolute
+func cmos0C(m *M6502) uint {
+	v, ea := m.readAbsolute()
+	m.P &= ^flagZ
+	m.setZ(v&m.A == 0)
+	m.write8(ea, v|m.A)
+	m.PC += 3
+	return 0
+}
+
+// cmos0F, BBR0 branch on bit 0 reset, zeropage relative
+func cmos0F(m *M6502) uint {
+	return m.opBBx(0, false)
+}
+
+// cmos12, ORA or with accumulator, zeropage indirect
+func cmos12(m *M6502) uint {
+	v, _ := m.readZeroPageIndirect()
+	m.A |= v
+	m.setNZ(m.A)
+	m.PC += 2
+	return 0
+}
+
+// cmos14, TRB test and reset bits, zeropage
+func cmos14(m *M6502) uint {
+	v, ea := m.readZeroPage()
+	m.P &= ^flagZ
+	m.setZ(v&m.A == 0)
+	m.write8(ea, v&^m.A)
+	m.PC += 2
+	return 0
+}
+
+// cmos17, RMB1 reset memory bit 1, zeropage
+func cmos17(m *M6502) uint {
+	return m.opRMB(1)
+}
+
+// cmos1A, INC increment, accumulator
+func cmos1A(m *M6502) uint {
+	m.A++
+	m.setNZ(m.A)
+	m.PC++
+	return 0
+}
+
+// cmos1C, TRB test and reset bits, absolute
+func cmos1C(m *M6502) uint {
+	v, ea := m.readAbsolute()
+	m.P &= ^flagZ
+	m.setZ(v&m.A == 0)
+	m.write8(ea, v&^m.A)
+	m.PC += 3
+	return 0
+}
+
+// cmos1E, ASL arithmetic shift left, absolute X-indexed
+func cmos1E(m *M6502) uint {
+	v, n, ea := m.readAbsoluteXPenalized()
+	v = m.opASL(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return n
+}
+
+// cmos1F, BBR1 branch on bit 1 reset, zeropage relative
+func cmos1F(m *M6502) uint {
+	return m.opBBx(1, false)
+}
+
+// cmos27, RMB2 reset memory bit 2, zeropage
+func cmos27(m *M6502) uint {
+	return m.opRMB(2)
+}
+
+// cmos2F, BBR2 branch on bit 2 reset, zeropage relative
+func cmos2F(m *M6502) uint {
+	return m.opBBx(2, false)
+}
+
+// cmos32, AND and (with accumulator), zeropage indirect
+func cmos32(m *M6502) uint {
+	v, _ := m.readZeroPageIndirect()
+	m.A &= v
+	m.setNZ(m.A)
+	m.PC += 2
+	return 0
+}
+
+// cmos34, BIT bit test, zeropage X-indexed
+func cmos34(m *M6502) uint {
+	v, _ := m.readZeroPageX()
+	m.opBit(v)
+	m.PC += 2
+	return 0
+}
+
+// cmos37, RMB3 reset memory bit 3, zeropage
+func cmos37(m *M6502) uint {
+	return m.opRMB(3)
+}
+
+// cmos3A, DEC decrement, accumulator
+func cmos3A(m *M6502) uint {
+	m.A--
+	m.setNZ(m.A)
+	m.PC++
+	return 0
+}
+
+// cmos3C, BIT bit test, absolute X-indexed
+func cmos3C(m *M6502) uint {
+	v, n, _ := m.readAbsoluteXPenalized()
+	m.opBit(v)
+	m.PC += 3
+	return n
+}
+
+// cmos3E, ROL rotate left, absolute X-indexed
+func cmos3E(m *M6502) uint {
+	v, n, ea := m.readAbsoluteXPenalized()
+	v = m.opROL(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return n
+}
+
+// cmos3F, BBR3 branch on bit 3 reset, zeropage relative
+func cmos3F(m *M6502) uint {
+	return m.opBBx(3, false)
+}
+
+// cmos47, RMB4 reset memory bit 4, zeropage
+func cmos47(m *M6502) uint {
+	return m.opRMB(4)
+}
+
+// cmos4F, BBR4 branch on bit 4 reset, zeropage relative
+func cmos4F(m *M6502) uint {
+	return m.opBBx(4, false)
+}
+
+// cmos52, EOR exclusive or (with accumulator), zeropage indirect
+func cmos52(m *M6502) uint {
+	v, _ := m.readZeroPageIndirect()
+	m.A ^= v
+	m.setNZ(m.A)
+	m.PC += 2
+	return 0
+}
+
+// cmos57, RMB5 reset memory bit 5, zeropage
+func cmos57(m *M6502) uint {
+	return m.opRMB(5)
+}
+
+// cmos5A, PHY push Y
+func cmos5A(m *M6502) uint {
+	m.push8(m.Y)
+	m.PC++
+	return 0
+}
+
+// cmos5E, LSR logical shift right, absolute X-indexed
+func cmos5E(m *M6502) uint {
+	v, n, ea := m.readAbsoluteXPenalized()
+	v = m.opLSR(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return n
+}
+
+// cmos5F, BBR5 branch on bit 5 reset, zeropage relative
+func cmos5F(m *M6502) uint {
+	return m.opBBx(5, false)
+}
+
+// cmos64, STZ store zero, zeropage
+func cmos64(m *M6502) uint {
+	m.writeZeroPage(0)
+	m.PC += 2
+	return 0
+}
+
+// cmos67, RMB6 reset memory bit 6, zeropage
+func cmos67(m *M6502) uint {
+	return m.opRMB(6)
+}
+
+// cmos6C, JMP jump, indirect
+func cmos6C(m *M6502) uint {
+	m.PC = m.read16ind(m.operand16(m.PC + 1))
+	m.jmpVSR()
+	return 0
+}
+
+// cmos6F, BBR6 branch on bit 6 reset, zeropage relative
+func cmos6F(m *M6502) uint {
+	return m.opBBx(6, false)
+}
 
-var opcodeTable = [256]opFunc{
-	op00, op01, opXX, opXX, opXX, op05, op06, opXX, op08, op09, op0A, opXX, opXX, op0D, op0E, opXX,
-	op10, op11, opXX, opXX, opXX, op15, op16, opXX, op18, op19, opXX, opXX, opXX, op1D, op1E, opXX,
-	op20, op21, opXX, opXX, op24, op25, op26, opXX, op28, op29, op2A, opXX, op2C, op2D, op2E, opXX,
-	op30, op31, opXX, opXX, opXX, op35, op36, opXX, op38, op39, opXX, opXX, opXX, op3D, op3E, opXX,
-	op40, op41, opXX, opXX, opXX, op45, op46, opXX, op48, op49, op4A, opXX, op4C, op4D, op4E, opXX,
-	op50, op51, opXX, opXX, opXX, op55, op56, opXX, op58, op59, opXX, opXX, opXX, op5D, op5E, opXX,
-	op60, op61, opXX, opXX, opXX, op65, op66, opXX, op68, op69, op6A, opXX, op6C, op6D, op6E, opXX,
-	op70, op71, opXX, opXX, opXX, op75, op76, opXX, op78, op79, opXX, opXX, opXX, op7D, op7E, opXX,
-	opXX, op81, opXX, opXX, op84, op85, op86, opXX, op88, opXX, op8A, opXX, op8C, op8D, op8E, opXX,
-	op90, op91, opXX, opXX, op94, op95, op96, opXX, op98, op99, op9A, opXX, opXX, op9D, opXX, opXX,
-	opA0, opA1, opA2, opXX, opA4, opA5, opA6, opXX, opA8, opA9, opAA, opXX, opAC, opAD, opAE, opXX,
-	opB0, opB1, opXX, opXX, opB4, opB5, opB6, opXX, opB8, opB9, opBA, opXX, opBC, opBD, opBE, opXX,
-	opC0, opC1, opXX, opXX, opC4, opC5, opC6, opXX, opC8, opC9, opCA, opXX, opCC, opCD, opCE, opXX,
-	opD0, opD1, opXX, opXX, opXX, opD5, opD6, opXX, opD8, opD9, opXX, opXX, opXX, opDD, opDE, opXX,
-	opE0, opE1, opXX, opXX, opE4, opE5, opE6, opXX, opE8, opE9, opEA, opXX, opEC, opED, opEE, opXX,
-	opF0, opF1, opXX, opXX, opXX, opF5, opF6, opXX, opF8, opF9, opXX, opXX, opXX, opFD, opFE, opXX,
+// cmos72, ADC add with carry, zeropage indirect
+func cmos72(m *M6502) uint {
+	v, _ := m.readZeroPageIndirect()
+	d := m.opADC(v)
+	m.PC += 2
+	return d
+}
+
+// cmos74, STZ store zero, zeropage X-indexed
+func cmos74(m *M6502) uint {
+	m.writeZeroPageX(0)
+	m.PC += 2
+	return 0
+}
+
+// cmos77, RMB7 reset memory bit 7, zeropage
+func cmos77(m *M6502) uint {
+	return m.opRMB(7)
+}
+
+// cmos7A, PLY pull Y
+func cmos7A(m *M6502) uint {
+	m.Y = m.pop8()
+	m.setNZ(m.Y)
+	m.PC++
+	return 0
+}
+
+// cmos7C, JMP jump, absolute X-indexed indirect
+func cmos7C(m *M6502) uint {
+	m.PC = m.read16(m.operand16(m.PC+1) + uint16(m.X))
+	m.jmpVSR()
+	return 0
+}
+
+// cmos7E, ROR rotate right, absolute X-indexed
+func cmos7E(m *M6502) uint {
+	v, n, ea := m.readAbsoluteXPenalized()
+	v = m.opROR(v)
+	m.write8(ea, v)
+	m.PC += 3
+	return n
+}
+
+// cmos7F, BBR7 branch on bit 7 reset, zeropage relative
+func cmos7F(m *M6502) uint {
+	return m.opBBx(7, false)
+}
+
+// cmos80, BRA branch always, relative
+func cmos80(m *M6502) uint {
+	return m.opBranch(true)
+}
+
+// cmos87, SMB0 set memory bit 0, zeropage
+func cmos87(m *M6502) uint {
+	return m.opSMB(0)
+}
+
+// cmos89, BIT bit test, immediate
+func cmos89(m *M6502) uint {
+	v := m.readImmediate()
+	// immediate mode only affects the Z flag
+	m.P &= ^flagZ
+	m.setZ(v&m.A == 0)
+	m.PC += 2
+	return 0
+}
+
+// cmos8F, BBS0 branch on bit 0 set, zeropage relative
+func cmos8F(m *M6502) uint {
+	return m.opBBx(0, true)
+}
+
+// cmos92, STA store accumulator, zeropage indirect
+func cmos92(m *M6502) uint {
+	m.writeZeroPageIndirect(m.A)
+	m.PC += 2
+	return 0
+}
+
+// cmos97, SMB1 set memory bit 1, zeropage
+func cmos97(m *M6502) uint {
+	return m.opSMB(1)
+}
+
+// cmos9C, STZ store zero, absolute
+func cmos9C(m *M6502) uint {
+	m.writeAbsolute(0)
+	m.PC += 3
+	return 0
+}
+
+// cmos9E, STZ store zero, absolute X-indexed
+func cmos9E(m *M6502) uint {
+	m.writeAbsoluteX(0)
+	m.PC += 3
+	return 0
+}
+
+// cmos9F, BBS1 branch on bit 1 set, zeropage relative
+func cmos9F(m *M6502) uint {
+	return m.opBBx(1, true)
+}
+
+// cmosA7, SMB2 set memory bit 2, zeropage
+func cmosA7(m *M6502) uint {
+	return m.opSMB(2)
+}
+
+// cmosAF, BBS2 branch on bit 2 set, zeropage relative
+func cmosAF(m *M6502) uint {
+	return m.opBBx(2, true)
+}
+
+// cmosB2, LDA load accumulator, zeropage indirect
+func cmosB2(m *M6502) uint {
+	m.A, _ = m.readZeroPageIndirect()
+	m.setNZ(m.A)
+	m.PC += 2
+	return 0
+}
+
+// cmosB7, SMB3 set memory bit 3, zeropage
+func cmosB7(m *M6502) uint {
+	return m.opSMB(3)
+}
+
+// cmosBF, BBS3 branch on bit 3 set, zeropage relative
+func cmosBF(m *M6502) uint {
+	return m.opBBx(3, true)
+}
+
+// cmosC7, SMB4 set memory bit 4, zeropage
+func cmosC7(m *M6502) uint {
+	return m.opSMB(4)
+}
+
+// cmosCB, WAI wait for interrupt
+func cmosCB(m *M6502) uint {
+	m.wait = true
+	m.PC++
+	return 0
+}
+
+// cmosCF, BBS4 branch on bit 4 set, zeropage relative
+func cmosCF(m *M6502) uint {
+	return m.opBBx(4, true)
+}
+
+// cmosD2, CMP compare (with accumulator), zeropage indirect
+func cmosD2(m *M6502) uint {
+	v, _ := m.readZeroPageIndirect()
+	m.opCompare(m.A, v)
+	m.PC += 2
+	return 0
+}
+
+// cmosD7, SMB5 set memory bit 5, zeropage
+func cmosD7(m *M6502) uint {
+	return m.opSMB(5)
+}
+
+// cmosDA, PHX push X
+func cmosDA(m *M6502) uint {
+	m.push8(m.X)
+	m.PC++
+	return 0
+}
+
+// cmosDB, STP stop the clock
+func cmosDB(m *M6502) uint {
+	m.stop = true
+	m.PC++
+	return 0
+}
+
+// cmosDF, BBS5 branch on bit 5 set, zeropage relative
+func cmosDF(m *M6502) uint {
+	return m.opBBx(5, true)
+}
+
+// cmosE7, SMB6 set memory bit 6, zeropage
+func cmosE7(m *M6502) uint {
+	return m.opSMB(6)
+}
+
+// cmosEF, BBS6 branch on bit 6 set, zeropage relative
+func cmosEF(m *M6502) uint {
+	return m.opBBx(6, true)
+}
+
+// cmosF2, SBC subtract with carry, zeropage indirect
+func cmosF2(m *M6502) uint {
+	v, _ := m.readZeroPageIndirect()
+	d := m.opSBC(v)
+	m.PC += 2
+	return d
+}
+
+// cmosF7, SMB7 set memory bit 7, zeropage
+func cmosF7(m *M6502) uint {
+	return m.opSMB(7)
+}
+
+// cmosFA, PLX pull X
+func cmosFA(m *M6502) uint {
+	m.X = m.pop8()
+	m.setNZ(m.X)
+	m.PC++
+	return 0
+}
+
+// cmosFF, BBS7 branch on bit 7 set, zeropage relative
+func cmosFF(m *M6502) uint {
+	return m.opBBx(7, true)
 }
 
 //-----------------------------------------------------------------------------
 
-// New6502 returns a 6502 CPU in the powered-on and reset state.
-func New6502(mem Memory) *M6502 {
+// opFunc is an opcode handler, it returns the cycles used beyond the base cycles.
+type opFunc func(m *M6502) uint
+
+//-----------------------------------------------------------------------------
+
+// newCPU returns a CPU of the given variant.
+func newCPU(mem Memory, v Variant, opts []Option) *M6502 {
 	var m M6502
 	m.Mem = mem
+	m.variant = v
+	// the port options need a port
+	m.port = newIOPort(&m)
+
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	v = m.variant
+	m.table = variants[v].table
+	m.cmos = variants[v].cmos
+	m.decimal = variants[v].decimal
+	if v != Variant6510 {
+		m.port = nil
+	}
 
-	// initialise the opcode usage map
-	m.usage = make(map[uint8]uint)
-	for i := 0; i < 256; i++ {
-		x := opcodeLookup(uint8(i))
-		if x.ins != "ill" {
-			m.usage[uint8(i)] = 0
+	if m.strict {
+		// undocumented opcodes are illegal
+		table := *m.table
+		for code := range table {
+			if table[code].undoc {
+				table[code] = opcodeIllegal
+			}
 		}
+		m.table = &table
 	}
 
+	m.ticks = newTickTable(m.table, m.cmos)
+
 	return &m
 }
 
+// New6502 returns a 6502 CPU in the powered-on and reset state.
+// Use the WithVariant option to select another member of the 6502 family.
+func New6502(mem Memory, opts ...Option) *M6502 {
+	return newCPU(mem, Variant6502, opts)
+}
+
+// New65C02 returns a 65C02 CPU in the powered-on and reset state.
+func New65C02(mem Memory, opts ...Option) *M6502 {
+	return newCPU(mem, Variant65C02, opts)
+}
+
+// NewR65C02 returns a Rockwell R65C02 CPU in the powered-on and reset state.
+func NewR65C02(mem Memory, opts ...Option) *M6502 {
+	return newCPU(mem, VariantR65C02, opts)
+}
+
+// New6510 returns a 6510 CPU in the powered-on and reset state.
+func New6510(mem Memory, opts ...Option) *M6502 {
+	return newCPU(mem, Variant6510, opts)
+}
+
+// New2A03 returns a Ricoh 2A03 (NES) CPU in the powered-on and reset state.
+func New2A03(mem Memory, opts ...Option) *M6502 {
+	return newCPU(mem, Variant2A03, opts)
+}
+
+// NewW65C02S returns a WDC W65C02S CPU in the powered-on and reset state.
+func NewW65C02S(mem Memory, opts ...Option) *M6502 {
+	return newCPU(mem, VariantW65C02S, opts)
+}
+
+// Variant returns the CPU variant.
+func (m *M6502) Variant() Variant {
+	return m.variant
+}
+
+// initialP returns the processor status after power up/reset.
+func (m *M6502) initialP() uint8 {
+	if m.variant == Variant2A03 {
+		return initialP2A03
+	}
+	return initialP
+}
+
 // Power on/off the 6502 CPU.
 func (m *M6502) Power(state bool) {
 	if state {
 		m.PC = initialPC
 		m.S = initialS
-		m.P = initialP
+		m.P = m.initialP()
 		m.A = initialA
 		m.X = initialX
 		m.Y = initialY
@@ -1629,64 +3122,172 @@ func (m *M6502) Power(state bool) {
 	}
 	m.irq = false
 	m.nmi = false
+	m.nmiLine = false
+	if m.ints != nil {
+		// the lines are held by the interrupt sources
+		m.irq = m.ints.irqLine
+		m.nmiLine = m.ints.nmiLine
+	}
 	m.illegal = false
+	m.jam = false
+	m.wait = false
+	m.stop = false
 	m.exit = false
 	m.cycles = 0
 	m.lastPC = 0
 	m.stuckPC = 0
+	m.tick = tickState{}
 }
 
 // Reset the 6502 CPU.
 func (m *M6502) Reset() {
-	m.PC = m.read16(RstAddress)
+	m.PC = m.vector16(RstAddress)
 	m.S = initialS
-	m.P = initialP
-	m.irq = false
+	m.P = m.initialP()
+	// the interrupt lines are held by the devices
 	m.nmi = false
+	m.jam = false
+	m.wait = false
+	m.stop = false
+	if m.port != nil {
+		m.port.reset()
+	}
+	m.tick = tickState{}
+	// the reset sequence takes 7 cycles
+	m.cycles += 7
 }
 
-// NMI generates a non-maskable-interrupt.
+// NMI generates a non-maskable-interrupt (a pulse on the NMI line).
 func (m *M6502) NMI() {
 	m.nmi = true
 }
 
-// IRQ generates an interrupt request.
+// NMILine sets the state of the NMI line (true = asserted).
+// The NMI is edge triggered: it is latched when the line is asserted.
+func (m *M6502) NMILine(state bool) {
+	if state && !m.nmiLine {
+		m.nmi = true
+	}
+	m.nmiLine = state
+}
+
+// IRQ sets the state of the IRQ line (true = asserted).
+// The IRQ is level triggered: it is serviced while the line is asserted and
+// the I flag is clear, so a device holds it until it is acknowledged.
 func (m *M6502) IRQ(state bool) {
 	m.irq = state
 }
 
+// interrupt services an IRQ or NMI.
+func (m *M6502) interrupt(vector uint16) {
+	m.push16(m.PC)
+	m.push8((m.P & ^flagB) | flagU)
+	m.P |= flagI
+	if m.cmos {
+		m.P &= ^flagD
+	}
+	m.PC = m.vector16(m.hijack(vector))
+	m.cycles += 7
+}
+
+// hijack returns the vector for BRK and IRQ. On the NMOS parts an NMI that
+// arrives while the status is pushed takes over the vector fetch.
+func (m *M6502) hijack(vector uint16) uint16 {
+	if m.nmi && !m.cmos {
+		m.nmi = false
+		return NmiAddress
+	}
+	return vector
+}
+
 // Run the 6502 CPU for a single instruction.
+// RunCycles and RunInstructions run batches of instructions more quickly.
 func (m *M6502) Run() error {
-	// nmi handling
+	// finish an instruction started by Tick
+	if m.tick.n != 0 {
+		return m.tickFinish()
+	}
+	m.halted = nil
+	// a jammed cpu needs a reset
+	if m.jam {
+		return m.jamError()
+	}
+	// a stopped cpu needs a reset
+	if m.stop {
+		return nil
+	}
+	// wait for an interrupt
+	if m.wait {
+		if !m.nmi && !m.irq {
+			m.cycles++
+			return nil
+		}
+		// an irq with interrupts disabled continues with the next instruction
+		m.wait = false
+	}
+	if m.obs != nil {
+		m.opPC = m.PC
+	}
+	// nmi handling (latched on an edge)
 	if m.nmi {
 		m.nmi = false
-		m.push16(m.PC)
-		m.push8(m.P & ^flagB)
-		m.P |= flagI
-		m.PC = m.read16(NmiAddress)
-		m.cycles += 7
+		m.interrupt(NmiAddress)
 		return nil
 	}
-	// irq handling
+	// irq handling (level triggered)
 	if m.irq && (m.P&flagI == 0) {
-		m.irq = false
-		m.push16(m.PC)
-		m.push8(m.P & ^flagB)
-		m.P |= flagI
-		m.PC = m.read16(IrqAddress)
-		m.cycles += 7
+		m.interrupt(IrqAddress)
 		return nil
 	}
 	// normal instructions
-	op := m.Mem.Read8(m.PC)
-	m.cycles += opcodeTable[op](m)
+	if m.pre != nil && m.hooks == nil && m.obs == nil {
+		op, n := m.predecoded()
+		m.cycles += n
+		return m.retire(op)
+	}
+	if m.hooks != nil {
+		// the hooks may halt the instruction, so they don't see the fetch
+		if err := m.hookBefore(m.Peek8(m.PC)); err != nil {
+			return err
+		}
+	}
+	op := m.load(AccessFetch, m.PC)
+	x := &m.table[op]
+	n := x.cycles + x.fn(m)
+	m.cycles += n
+	if err := m.retire(op); err != nil {
+		return err
+	}
+	if m.hooks != nil {
+		return m.hookAfter(n)
+	}
+	return nil
+}
+
+// stuckLimit is the number of times the PC can repeat before it is stuck.
+const stuckLimit = 4
+
+// jamError returns the error for a jammed cpu.
+func (m *M6502) jamError() error {
+	return &JamError{PC: m.PC, Opcode: m.Peek8(m.PC)}
+}
+
+// retire does the end of instruction checks.
+func (m *M6502) retire(op uint8) error {
+	if m.halted != nil {
+		return m.halted
+	}
 
 	if m.illegal {
-		return fmt.Errorf("illegal instruction at %04x", m.PC)
+		return &IllegalOpcodeError{PC: m.PC, Opcode: op}
+	}
+
+	if m.jam {
+		return m.jamError()
 	}
 
 	if m.exit {
-		return fmt.Errorf("exit at %04x, status %02x, %d cpu cycles, %.2f coverage", m.PC, m.A, m.cycles, m.Coverage())
+		return &ExitError{PC: m.PC, Status: m.A, Cycles: m.cycles, Coverage: m.Coverage()}
 	}
 
 	// accumulate opcode usage
@@ -1695,8 +3296,8 @@ func (m *M6502) Run() error {
 	// stuck PC detection
 	if m.PC == m.lastPC {
 		m.stuckPC++
-		if m.stuckPC >= 4 {
-			return fmt.Errorf("PC is stuck at %04x, %d cpu cycles, %.2f coverage", m.PC, m.cycles, m.Coverage())
+		if m.stuckPC >= stuckLimit {
+			return &StuckError{PC: m.PC, Cycles: m.cycles, Coverage: m.Coverage()}
 		}
 	} else {
 		m.stuckPC = 0
@@ -1706,15 +3307,40 @@ func (m *M6502) Run() error {
 	return nil
 }
 
-// Coverage returns the fraction of valid opcodes that have run.
+// Coverage returns the fraction of valid (documented) opcodes that have run.
 func (m *M6502) Coverage() float32 {
-	run := 0
-	for _, v := range m.usage {
-		if v != 0 {
+	run, valid := 0, 0
+	for i := range m.table {
+		x := &m.table[i]
+		if x.ins == "ill" || x.undoc {
+			continue
+		}
+		valid++
+		if m.usage[i] != 0 {
 			run++
 		}
 	}
-	return float32(run) / float32(len(m.usage))
+	return float32(run) / float32(valid)
+}
+
+// Jammed returns true if the CPU has been halted by a JAM instruction.
+func (m *M6502) Jammed() bool {
+	return m.jam
+}
+
+// Waiting returns true if the CPU is waiting for an interrupt (WAI).
+func (m *M6502) Waiting() bool {
+	return m.wait
+}
+
+// Stopped returns true if the CPU clock has been stopped until reset (STP).
+func (m *M6502) Stopped() bool {
+	return m.stop
+}
+
+// Cycles returns the number of CPU cycles.
+func (m *M6502) Cycles() uint {
+	return m.cycles
 }
 
 // ReadPC returns the 6502 program counter.
@@ -1735,7 +3361,10 @@ func (m *M6502) jsrVSR() {
 	if m.vsr != nil {
 		if fn, ok := m.vsr[m.PC]; ok {
 			// call the hook
-			fn(m)
+			if m.callVSR(fn) != nil {
+				// leave the PC at the VSR
+				return
+			}
 			// simulate RTS
 			m.PC = m.pop16() + 1
 		}
@@ -1746,12 +3375,20 @@ func (m *M6502) jmpVSR() {
 	if m.vsr != nil {
 		if fn, ok := m.vsr[m.PC]; ok {
 			// call the hook
-			fn(m)
+			m.callVSR(fn)
 			// called by a jump: we don't have anywhere to go...
 		}
 	}
 }
 
+// callVSR calls a virtual subroutine. A panic halts the CPU.
+func (m *M6502) callVSR(fn VSRFunc) error {
+	return m.halt(m.protect(func() error {
+		fn(m)
+		return nil
+	}))
+}
+
 // AddVSR adds a virtual subroutine handler at the call address.
 func (m *M6502) AddVSR(adr uint16, fn VSRFunc) {
 	if m.vsr == nil {
@@ -0,0 +1,1646 @@
+//-----------------------------------------------------------------------------
+/*
+
+65C816 CPU Emulator
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import "context"
+
+//-----------------------------------------------------------------------------
+// memory access
+
+func (m *M65816) read8(adr uint32) uint8 {
+	if m.long != nil {
+		return m.long.ReadLong(adr & 0xffffff)
+	}
+	return m.Mem.Read8(uint16(adr))
+}
+
+func (m *M65816) write8(adr uint32, val uint8) {
+	if m.long != nil {
+		m.long.WriteLong(adr&0xffffff, val)
+		return
+	}
+	m.Mem.Write8(uint16(adr), val)
+}
+
+// next returns the address of the next byte of a multi-byte value.
+func (m *M65816) next(adr uint32) uint32 {
+	if m.bank0 {
+		// direct page and stack accesses wrap within bank 0
+		return uint32(uint16(adr + 1))
+	}
+	return (adr + 1) & 0xffffff
+}
+
+// read16 reads a 16-bit value from bank 0.
+func (m *M65816) read16(adr uint16) uint16 {
+	l := uint16(m.read8(uint32(adr)))
+	h := uint16(m.read8(uint32(adr + 1)))
+	return (h << 8) | l
+}
+
+// read24 reads a 24-bit value from bank 0.
+func (m *M65816) read24(adr uint16) uint32 {
+	l := uint32(m.read16(adr))
+	h := uint32(m.read8(uint32(adr + 2)))
+	return (h << 16) | l
+}
+
+// readData reads an 8 or 16-bit value from the effective address.
+func (m *M65816) readData(ea uint32, wide bool) uint16 {
+	v := uint16(m.read8(ea))
+	if wide {
+		v |= uint16(m.read8(m.next(ea))) << 8
+	}
+	return v
+}
+
+// writeData writes an 8 or 16-bit value to the effective address.
+func (m *M65816) writeData(ea uint32, val uint16, wide bool) {
+	m.write8(ea, uint8(val))
+	if wide {
+		m.write8(m.next(ea), uint8(val>>8))
+	}
+}
+
+// fetch8 reads the next byte of the instruction stream.
+func (m *M65816) fetch8() uint8 {
+	v := m.read8(uint32(m.PBR)<<16 | uint32(m.PC))
+	m.PC++
+	return v
+}
+
+// fetch16 reads the next 2 bytes of the instruction stream.
+func (m *M65816) fetch16() uint16 {
+	l := uint16(m.fetch8())
+	h := uint16(m.fetch8())
+	return (h << 8) | l
+}
+
+//-----------------------------------------------------------------------------
+// stack
+
+func (m *M65816) push8(val uint8) {
+	m.write8(uint32(m.S), val)
+	m.S--
+	if m.E {
+		m.S = 0x100 | (m.S & 0xff)
+	}
+}
+
+func (m *M65816) pop8() uint8 {
+	m.S++
+	if m.E {
+		m.S = 0x100 | (m.S & 0xff)
+	}
+	return m.read8(uint32(m.S))
+}
+
+func (m *M65816) push16(val uint16) {
+	m.push8(uint8(val >> 8))
+	m.push8(uint8(val))
+}
+
+func (m *M65816) pop16() uint16 {
+	l := m.pop8()
+	h := m.pop8()
+	return (uint16(h) << 8) | uint16(l)
+}
+
+//-----------------------------------------------------------------------------
+// register widths and flags
+
+// wideM returns true for a 16-bit accumulator/memory.
+func (m *M65816) wideM() bool {
+	return m.P&flagM == 0
+}
+
+// wideX returns true for 16-bit index registers.
+func (m *M65816) wideX() bool {
+	return m.P&flagX == 0
+}
+
+// setNZ sets the N and Z flags for an 8 or 16-bit value.
+func (m *M65816) setNZ(val uint16, wide bool) {
+	m.P &= ^flagNZ
+	if !wide {
+		val &= 0xff
+		if val&0x80 != 0 {
+			m.P |= flagN
+		}
+	} else if val&0x8000 != 0 {
+		m.P |= flagN
+	}
+	if val == 0 {
+		m.P |= flagZ
+	}
+}
+
+func (m *M65816) setFlag(flag uint8, cond bool) {
+	if cond {
+		m.P |= flag
+	} else {
+		m.P &= ^flag
+	}
+}
+
+// setA sets the accumulator, a narrow write leaves B unchanged.
+func (m *M65816) setA(val uint16) {
+	if m.wideM() {
+		m.A = val
+	} else {
+		m.A = (m.A & 0xff00) | (val & 0xff)
+	}
+	m.setNZ(val, m.wideM())
+}
+
+// setIndex returns an index register value, masked to the index width.
+func (m *M65816) setIndex(val uint16) uint16 {
+	if !m.wideX() {
+		val &= 0xff
+	}
+	m.setNZ(val, m.wideX())
+	return val
+}
+
+// setP sets the processor status, applying the emulation mode and index width rules.
+func (m *M65816) setP(val uint8) {
+	m.P = val
+	if m.E {
+		m.P |= flagM | flagX
+	}
+	if m.P&flagX != 0 {
+		m.X &= 0xff
+		m.Y &= 0xff
+	}
+}
+
+//-----------------------------------------------------------------------------
+// address modes
+
+// dpPenalty returns the extra cycle for a direct page register not aligned to a page.
+func (m *M65816) dpPenalty() uint {
+	if m.D&0xff != 0 {
+		return 1
+	}
+	return 0
+}
+
+// direct returns the direct page address for an offset and index.
+func (m *M65816) direct(ofs uint8, idx uint16) uint16 {
+	if m.E && m.D&0xff == 0 {
+		// emulation mode: indexing wraps within the direct page
+		return m.D | uint16(ofs+uint8(idx))
+	}
+	return m.D + uint16(ofs) + idx
+}
+
+// indexPenalty returns the extra cycle for indexing across a page or with 16-bit index registers.
+func (m *M65816) indexPenalty(base, ea uint32) uint {
+	if m.wideX() || (base&0xffff00) != (ea&0xffff00) {
+		return 1
+	}
+	return 0
+}
+
+// resolve fetches the operand for an address mode and returns the effective
+// address and the number of extra cycles for the address calculation.
+// For jumps and branches the effective address is the target address.
+func (m *M65816) resolve(mode adrMode, index bool) (uint32, uint) {
+	m.bank0 = false
+	dbr := uint32(m.DBR) << 16
+	pbr := uint32(m.PBR) << 16
+	switch mode {
+	case amImpl, amAcc:
+		return 0, 0
+	case amImm:
+		ea := pbr | uint32(m.PC)
+		m.PC++
+		return ea, 0
+	case amImmM:
+		ea := pbr | uint32(m.PC)
+		m.PC++
+		if m.wideM() {
+			m.PC++
+		}
+		return ea, 0
+	case amImmX:
+		ea := pbr | uint32(m.PC)
+		m.PC++
+		if m.wideX() {
+			m.PC++
+		}
+		return ea, 0
+	case amZpg:
+		m.bank0 = true
+		return uint32(m.direct(m.fetch8(), 0)), m.dpPenalty()
+	case amZpgX:
+		m.bank0 = true
+		return uint32(m.direct(m.fetch8(), m.X)), m.dpPenalty()
+	case amZpgY:
+		m.bank0 = true
+		return uint32(m.direct(m.fetch8(), m.Y)), m.dpPenalty()
+	case amZpgInd:
+		ptr := m.direct(m.fetch8(), 0)
+		return dbr + uint32(m.read16(ptr)), m.dpPenalty()
+	case amXInd:
+		ptr := m.direct(m.fetch8(), m.X)
+		return dbr + uint32(m.read16(ptr)), m.dpPenalty()
+	case amIndY:
+		ptr := m.direct(m.fetch8(), 0)
+		base := dbr + uint32(m.read16(ptr))
+		ea := (base + uint32(m.Y)) & 0xffffff
+		n := m.dpPenalty()
+		if index {
+			n += m.indexPenalty(base, ea)
+		}
+		return ea, n
+	case amZpgIndLong:
+		ptr := m.direct(m.fetch8(), 0)
+		return m.read24(ptr), m.dpPenalty()
+	case amZpgIndLongY:
+		ptr := m.direct(m.fetch8(), 0)
+		return (m.read24(ptr) + uint32(m.Y)) & 0xffffff, m.dpPenalty()
+	case amAbs:
+		return dbr | uint32(m.fetch16()), 0
+	case amAbsX, amAbsY:
+		base := dbr | uint32(m.fetch16())
+		idx := m.X
+		if mode == amAbsY {
+			idx = m.Y
+		}
+		ea := (base + uint32(idx)) & 0xffffff
+		var n uint
+		if index {
+			n = m.indexPenalty(base, ea)
+		}
+		return ea, n
+	case amLong:
+		l := uint32(m.fetch16())
+		h := uint32(m.fetch8())
+		return (h << 16) | l, 0
+	case amLongX:
+		l := uint32(m.fetch16())
+		h := uint32(m.fetch8())
+		return (((h << 16) | l) + uint32(m.X)) & 0xffffff, 0
+	case amSR:
+		m.bank0 = true
+		return uint32(m.S + uint16(m.fetch8())), 0
+	case amSRIndY:
+		ptr := m.S + uint16(m.fetch8())
+		return (dbr + uint32(m.read16(ptr)) + uint32(m.Y)) & 0xffffff, 0
+	case amRel:
+		ofs := int8(m.fetch8())
+		return pbr | uint32(uint16(int(m.PC)+int(ofs))), 0
+	case amRelLong:
+		ofs := m.fetch16()
+		return pbr | uint32(m.PC+ofs), 0
+	case amInd:
+		// jmp (abs): the pointer is in bank 0
+		ptr := m.fetch16()
+		return pbr | uint32(m.read16(ptr)), 0
+	case amAbsXInd:
+		// jmp/jsr (abs,x): the pointer is in the program bank
+		ptr := pbr | uint32(m.fetch16()+m.X)
+		l := uint32(m.read8(ptr))
+		h := uint32(m.read8(pbr | uint32(uint16(ptr+1))))
+		return pbr | (h << 8) | l, 0
+	case amAbsIndLong:
+		return m.read24(m.fetch16()), 0
+	case amBlock:
+		// destination bank, source bank
+		dst := uint32(m.fetch8())
+		src := uint32(m.fetch8())
+		return (dst << 8) | src, 0
+	}
+	panic("bad address mode")
+}
+
+//-----------------------------------------------------------------------------
+// arithmetic
+
+// opADC add with carry (8 or 16 bits, binary or decimal)
+func (m *M65816) opADC(val uint16) {
+	wide := m.wideM()
+	bits, mask := uint(8), 0xff
+	if wide {
+		bits, mask = 16, 0xffff
+	}
+	a := int(m.A) & mask
+	d := int(val) & mask
+	c := int(m.P & flagC)
+	var r int
+	if m.P&flagD == 0 {
+		r = a + d + c
+	} else {
+		// one nibble at a time
+		for shift := uint(0); shift < bits; shift += 4 {
+			nib := 0xf << shift
+			low := (1 << shift) - 1
+			r = (a & nib) + (d & nib) + (c << shift) + (r & low)
+			if shift == bits-4 {
+				break
+			}
+			if r > (0xa<<shift)-1 {
+				r += 6 << shift
+			}
+			c = 0
+			if r > (0x10<<shift)-1 {
+				c = 1
+			}
+		}
+	}
+	sign := 1 << (bits - 1)
+	m.setFlag(flagV, ^(a^d)&(a^r)&sign != 0)
+	if m.P&flagD != 0 && r > (0xa<<(bits-4))-1 {
+		r += 6 << (bits - 4)
+	}
+	m.setFlag(flagC, r > mask)
+	m.setA(uint16(r))
+}
+
+// opSBC subtract with carry (8 or 16 bits, binary or decimal)
+func (m *M65816) opSBC(val uint16) {
+	wide := m.wideM()
+	bits, mask := uint(8), 0xff
+	if wide {
+		bits, mask = 16, 0xffff
+	}
+	a := int(m.A) & mask
+	d := ^int(val) & mask
+	c := int(m.P & flagC)
+	var r int
+	if m.P&flagD == 0 {
+		r = a + d + c
+	} else {
+		// one nibble at a time
+		for shift := uint(0); shift < bits; shift += 4 {
+			nib := 0xf << shift
+			low := (1 << shift) - 1
+			r = (a & nib) + (d & nib) + (c << shift) + (r & low)
+			if shift == bits-4 {
+				break
+			}
+			if r <= (0x10<<shift)-1 {
+				r -= 6 << shift
+			}
+			c = 0
+			if r > (0x10<<shift)-1 {
+				c = 1
+			}
+		}
+	}
+	sign := 1 << (bits - 1)
+	m.setFlag(flagV, ^(a^d)&(a^r)&sign != 0)
+	if m.P&flagD != 0 && r <= mask {
+		r -= 6 << (bits - 4)
+	}
+	m.setFlag(flagC, r > mask)
+	m.setA(uint16(r))
+}
+
+// opCompare sets the NZC values for the register/value compare operation.
+func (m *M65816) opCompare(reg, val uint16, wide bool) {
+	if !wide {
+		reg &= 0xff
+		val &= 0xff
+	}
+	m.setNZ(reg-val, wide)
+	m.setFlag(flagC, reg >= val)
+}
+
+// signBit returns the sign bit for the accumulator width.
+func (m *M65816) signBit() uint16 {
+	if m.wideM() {
+		return 0x8000
+	}
+	return 0x80
+}
+
+//-----------------------------------------------------------------------------
+// read-modify-write operations
+
+func (m *M65816) opASL(v uint16) uint16 {
+	m.setFlag(flagC, v&m.signBit() != 0)
+	v <<= 1
+	m.setNZ(v, m.wideM())
+	return v
+}
+
+func (m *M65816) opLSR(v uint16) uint16 {
+	if !m.wideM() {
+		v &= 0xff
+	}
+	m.setFlag(flagC, v&1 != 0)
+	v >>= 1
+	m.setNZ(v, m.wideM())
+	return v
+}
+
+func (m *M65816) opROL(v uint16) uint16 {
+	ci := uint16(m.P & flagC)
+	m.setFlag(flagC, v&m.signBit() != 0)
+	v = (v << 1) | ci
+	m.setNZ(v, m.wideM())
+	return v
+}
+
+func (m *M65816) opROR(v uint16) uint16 {
+	if !m.wideM() {
+		v &= 0xff
+	}
+	ci := m.P&flagC != 0
+	m.setFlag(flagC, v&1 != 0)
+	v >>= 1
+	if ci {
+		v |= m.signBit()
+	}
+	m.setNZ(v, m.wideM())
+	return v
+}
+
+func (m *M65816) opINC(v uint16) uint16 {
+	v++
+	m.setNZ(v, m.wideM())
+	return v
+}
+
+func (m *M65816) opDEC(v uint16) uint16 {
+	v--
+	m.setNZ(v, m.wideM())
+	return v
+}
+
+// rmw does a read-modify-write operation on the accumulator or memory.
+func (m *M65816) rmw(mode adrMode, ea uint32, op func(m *M65816, v uint16) uint16) {
+	if mode == amAcc {
+		m.setA(op(m, m.A))
+		return
+	}
+	wide := m.wideM()
+	v := op(m, m.readData(ea, wide))
+	m.writeData(ea, v, wide)
+}
+
+//-----------------------------------------------------------------------------
+// interrupts
+
+// interrupt pushes the return state and vectors to an interrupt handler.
+func (m *M65816) interrupt(pc uint16, p uint8, vector uint16) uint {
+	n := uint(7)
+	if !m.E {
+		m.push8(m.PBR)
+		n++
+	}
+	m.push16(pc)
+	m.push8(p)
+	m.P |= flagI
+	m.P &= ^flagD
+	m.PBR = 0
+	m.PC = m.read16(vector)
+	return n
+}
+
+//-----------------------------------------------------------------------------
+// instructions
+
+// op816 is the function for a 65C816 instruction.
+// It is passed the effective address and the address mode and returns any extra cycles.
+type op816 func(m *M65816, ea uint32, mode adrMode) uint
+
+func (m *M65816) insADC(ea uint32, mode adrMode) uint {
+	m.opADC(m.readData(ea, m.wideM()))
+	return 0
+}
+
+func (m *M65816) insAND(ea uint32, mode adrMode) uint {
+	m.setA(m.A & m.readData(ea, m.wideM()))
+	return 0
+}
+
+func (m *M65816) insASL(ea uint32, mode adrMode) uint {
+	m.rmw(mode, ea, (*M65816).opASL)
+	return 0
+}
+
+func (m *M65816) insBIT(ea uint32, mode adrMode) uint {
+	wide := m.wideM()
+	v := m.readData(ea, wide)
+	a := m.A
+	if !wide {
+		a &= 0xff
+	}
+	m.setFlag(flagZ, v&a == 0)
+	if mode != amImmM {
+		// immediate mode only affects the Z flag
+		sign := m.signBit()
+		m.setFlag(flagN, v&sign != 0)
+		m.setFlag(flagV, v&(sign>>1) != 0)
+	}
+	return 0
+}
+
+// branch to the target address if a condition is true.
+func (m *M65816) branch(ea uint32, cond bool) uint {
+	if !cond {
+		return 0
+	}
+	n := uint(1)
+	tgt := uint16(ea)
+	if m.E && (tgt>>8) != (m.PC>>8) {
+		// emulation mode: +1 cycle for a page crossing
+		n++
+	}
+	m.PC = tgt
+	return n
+}
+
+func (m *M65816) insBCC(ea uint32, mode adrMode) uint {
+	return m.branch(ea, m.P&flagC == 0)
+}
+
+func (m *M65816) insBCS(ea uint32, mode adrMode) uint {
+	return m.branch(ea, m.P&flagC != 0)
+}
+
+func (m *M65816) insBEQ(ea uint32, mode adrMode) uint {
+	return m.branch(ea, m.P&flagZ != 0)
+}
+
+func (m *M65816) insBMI(ea uint32, mode adrMode) uint {
+	return m.branch(ea, m.P&flagN != 0)
+}
+
+func (m *M65816) insBNE(ea uint32, mode adrMode) uint {
+	return m.branch(ea, m.P&flagZ == 0)
+}
+
+func (m *M65816) insBPL(ea uint32, mode adrMode) uint {
+	return m.branch(ea, m.P&flagN == 0)
+}
+
+func (m *M65816) insBRA(ea uint32, mode adrMode) uint {
+	return m.branch(ea, true)
+}
+
+func (m *M65816) insBRL(ea uint32, mode adrMode) uint {
+	m.PC = uint16(ea)
+	return 0
+}
+
+func (m *M65816) insBVC(ea uint32, mode adrMode) uint {
+	return m.branch(ea, m.P&flagV == 0)
+}
+
+func (m *M65816) insBVS(ea uint32, mode adrMode) uint {
+	return m.branch(ea, m.P&flagV != 0)
+}
+
+func (m *M65816) insBRK(ea uint32, mode adrMode) uint {
+	if m.E {
+		return m.interrupt(m.PC, m.P|flagB, BrkAddress) - 7
+	}
+	return m.interrupt(m.PC, m.P, brkNative816) - 7
+}
+
+func (m *M65816) insCOP(ea uint32, mode adrMode) uint {
+	if m.E {
+		return m.interrupt(m.PC, m.P, copEmulation816) - 7
+	}
+	return m.interrupt(m.PC, m.P, copNative816) - 7
+}
+
+func (m *M65816) insCLC(ea uint32, mode adrMode) uint {
+	m.P &= ^flagC
+	return 0
+}
+
+func (m *M65816) insCLD(ea uint32, mode adrMode) uint {
+	m.P &= ^flagD
+	return 0
+}
+
+func (m *M65816) insCLI(ea uint32, mode adrMode) uint {
+	m.P &= ^flagI
+	return 0
+}
+
+func (m *M65816) insCLV(ea uint32, mode adrMode) uint {
+	m.P &= ^flagV
+	return 0
+}
+
+func (m *M65816) insCMP(ea uint32, mode adrMode) uint {
+	m.opCompare(m.A, m.readData(ea, m.wideM()), m.wideM())
+	return 0
+}
+
+func (m *M65816) insCPX(ea uint32, mode adrMode) uint {
+	m.opCompare(m.X, m.readData(ea, m.wideX()), m.wideX())
+	return 0
+}
+
+func (m *M65816) insCPY(ea uint32, mode adrMode) uint {
+	m.opCompare(m.Y, m.readData(ea, m.wideX()), m.wideX())
+	return 0
+}
+
+func (m *M65816) insDEC(ea uint32, mode adrMode) uint {
+	m.rmw(mode, ea, (*M65816).opDEC)
+	return 0
+}
+
+func (m *M65816) insDEX(ea uint32, mode adrMode) uint {
+	m.X = m.setIndex(m.X - 1)
+	return 0
+}
+
+func (m *M65816) insDEY(ea uint32, mode adrMode) uint {
+	m.Y = m.setIndex(m.Y - 1)
+	return 0
+}
+
+func (m *M65816) insEOR(ea uint32, mode adrMode) uint {
+	m.setA(m.A ^ m.readData(ea, m.wideM()))
+	return 0
+}
+
+func (m *M65816) insINC(ea uint32, mode adrMode) uint {
+	m.rmw(mode, ea, (*M65816).opINC)
+	return 0
+}
+
+func (m *M65816) insINX(ea uint32, mode adrMode) uint {
+	m.X = m.setIndex(m.X + 1)
+	return 0
+}
+
+func (m *M65816) insINY(ea uint32, mode adrMode) uint {
+	m.Y = m.setIndex(m.Y + 1)
+	return 0
+}
+
+func (m *M65816) insJML(ea uint32, mode adrMode) uint {
+	m.PBR = uint8(ea >> 16)
+	m.PC = uint16(ea)
+	m.jmpVSR()
+	return 0
+}
+
+func (m *M65816) insJMP(ea uint32, mode adrMode) uint {
+	m.PC = uint16(ea)
+	m.jmpVSR()
+	return 0
+}
+
+func (m *M65816) insJSL(ea uint32, mode adrMode) uint {
+	m.push8(m.PBR)
+	m.push16(m.PC - 1)
+	m.PBR = uint8(ea >> 16)
+	m.PC = uint16(ea)
+	if m.callVSR() {
+		// simulate RTL
+		m.PC = m.pop16() + 1
+		m.PBR = m.pop8()
+	}
+	return 0
+}
+
+func (m *M65816) insJSR(ea uint32, mode adrMode) uint {
+	m.push16(m.PC - 1)
+	m.PC = uint16(ea)
+	if m.callVSR() {
+		// simulate RTS
+		m.PC = m.pop16() + 1
+	}
+	return 0
+}
+
+func (m *M65816) insLDA(ea uint32, mode adrMode) uint {
+	m.setA(m.readData(ea, m.wideM()))
+	return 0
+}
+
+func (m *M65816) insLDX(ea uint32, mode adrMode) uint {
+	m.X = m.setIndex(m.readData(ea, m.wideX()))
+	return 0
+}
+
+func (m *M65816) insLDY(ea uint32, mode adrMode) uint {
+	m.Y = m.setIndex(m.readData(ea, m.wideX()))
+	return 0
+}
+
+func (m *M65816) insLSR(ea uint32, mode adrMode) uint {
+	m.rmw(mode, ea, (*M65816).opLSR)
+	return 0
+}
+
+// blockMove moves a byte and repeats the instruction until the count in C is exhausted.
+func (m *M65816) blockMove(ea uint32, step uint16) uint {
+	dst := uint8(ea >> 8)
+	src := uint8(ea)
+	m.DBR = dst
+	m.write8(uint32(dst)<<16|uint32(m.Y), m.read8(uint32(src)<<16|uint32(m.X)))
+	m.X = m.setIndexNoFlags(m.X + step)
+	m.Y = m.setIndexNoFlags(m.Y + step)
+	m.A--
+	if m.A != 0xffff {
+		// repeat the instruction
+		m.PC -= 3
+	}
+	return 0
+}
+
+// setIndexNoFlags masks an index register value to the index width.
+func (m *M65816) setIndexNoFlags(val uint16) uint16 {
+	if !m.wideX() {
+		val &= 0xff
+	}
+	return val
+}
+
+func (m *M65816) insMVN(ea uint32, mode adrMode) uint {
+	return m.blockMove(ea, 1)
+}
+
+func (m *M65816) insMVP(ea uint32, mode adrMode) uint {
+	return m.blockMove(ea, 0xffff)
+}
+
+func (m *M65816) insNOP(ea uint32, mode adrMode) uint {
+	return 0
+}
+
+func (m *M65816) insORA(ea uint32, mode adrMode) uint {
+	m.setA(m.A | m.readData(ea, m.wideM()))
+	return 0
+}
+
+func (m *M65816) insPEA(ea uint32, mode adrMode) uint {
+	m.push16(uint16(ea))
+	return 0
+}
+
+func (m *M65816) insPEI(ea uint32, mode adrMode) uint {
+	// the indirect address is the value to push
+	m.push16(uint16(ea))
+	return 0
+}
+
+func (m *M65816) insPER(ea uint32, mode adrMode) uint {
+	m.push16(uint16(ea))
+	return 0
+}
+
+func (m *M65816) insPHA(ea uint32, mode adrMode) uint {
+	if m.wideM() {
+		m.push16(m.A)
+	} else {
+		m.push8(uint8(m.A))
+	}
+	return 0
+}
+
+func (m *M65816) insPHB(ea uint32, mode adrMode) uint {
+	m.push8(m.DBR)
+	return 0
+}
+
+func (m *M65816) insPHD(ea uint32, mode adrMode) uint {
+	m.push16(m.D)
+	return 0
+}
+
+func (m *M65816) insPHK(ea uint32, mode adrMode) uint {
+	m.push8(m.PBR)
+	return 0
+}
+
+func (m *M65816) insPHP(ea uint32, mode adrMode) uint {
+	if m.E {
+		m.push8(m.P | flagB)
+	} else {
+		m.push8(m.P)
+	}
+	return 0
+}
+
+func (m *M65816) pushIndex(val uint16) {
+	if m.wideX() {
+		m.push16(val)
+	} else {
+		m.push8(uint8(val))
+	}
+}
+
+func (m *M65816) popIndex() uint16 {
+	if m.wideX() {
+		return m.setIndex(m.pop16())
+	}
+	return m.setIndex(uint16(m.pop8()))
+}
+
+func (m *M65816) insPHX(ea uint32, mode adrMode) uint {
+	m.pushIndex(m.X)
+	return 0
+}
+
+func (m *M65816) insPHY(ea uint32, mode adrMode) uint {
+	m.pushIndex(m.Y)
+	return 0
+}
+
+func (m *M65816) insPLA(ea uint32, mode adrMode) uint {
+	if m.wideM() {
+		m.setA(m.pop16())
+	} else {
+		m.setA(uint16(m.pop8()))
+	}
+	return 0
+}
+
+func (m *M65816) insPLB(ea uint32, mode adrMode) uint {
+	m.DBR = m.pop8()
+	m.setNZ(uint16(m.DBR), false)
+	return 0
+}
+
+func (m *M65816) insPLD(ea uint32, mode adrMode) uint {
+	m.D = m.pop16()
+	m.setNZ(m.D, true)
+	return 0
+}
+
+func (m *M65816) insPLP(ea uint32, mode adrMode) uint {
+	m.setP(m.pop8())
+	return 0
+}
+
+func (m *M65816) insPLX(ea uint32, mode adrMode) uint {
+	m.X = m.popIndex()
+	return 0
+}
+
+func (m *M65816) insPLY(ea uint32, mode adrMode) uint {
+	m.Y = m.popIndex()
+	return 0
+}
+
+func (m *M65816) insREP(ea uint32, mode adrMode) uint {
+	m.setP(m.P & ^m.read8(ea))
+	return 0
+}
+
+func (m *M65816) insROL(ea uint32, mode adrMode) uint {
+	m.rmw(mode, ea, (*M65816).opROL)
+	return 0
+}
+
+func (m *M65816) insROR(ea uint32, mode adrMode) uint {
+	m.rmw(mode, ea, (*M65816).opROR)
+	return 0
+}
+
+func (m *M65816) insRTI(ea uint32, mode adrMode) uint {
+	m.setP(m.pop8())
+	m.PC = m.pop16()
+	if !m.E {
+		m.PBR = m.pop8()
+		return 1
+	}
+	return 0
+}
+
+func (m *M65816) insRTL(ea uint32, mode adrMode) uint {
+	m.PC = m.pop16() + 1
+	m.PBR = m.pop8()
+	return 0
+}
+
+func (m *M65816) insRTS(ea uint32, mode adrMode) uint {
+	m.PC = m.pop16() + 1
+	return 0
+}
+
+func (m *M65816) insSBC(ea uint32, mode adrMode) uint {
+	m.opSBC(m.readData(ea, m.wideM()))
+	return 0
+}
+
+func (m *M65816) insSEC(ea uint32, mode adrMode) uint {
+	m.P |= flagC
+	return 0
+}
+
+func (m *M65816) insSED(ea uint32, mode adrMode) uint {
+	m.P |= flagD
+	return 0
+}
+
+func (m *M65816) insSEI(ea uint32, mode adrMode) uint {
+	m.P |= flagI
+	return 0
+}
+
+func (m *M65816) insSEP(ea uint32, mode adrMode) uint {
+	m.setP(m.P | m.read8(ea))
+	return 0
+}
+
+func (m *M65816) insSTA(ea uint32, mode adrMode) uint {
+	m.writeData(ea, m.A, m.wideM())
+	return 0
+}
+
+func (m *M65816) insSTP(ea uint32, mode adrMode) uint {
+	m.stop = true
+	return 0
+}
+
+func (m *M65816) insSTX(ea uint32, mode adrMode) uint {
+	m.writeData(ea, m.X, m.wideX())
+	return 0
+}
+
+func (m *M65816) insSTY(ea uint32, mode adrMode) uint {
+	m.writeData(ea, m.Y, m.wideX())
+	return 0
+}
+
+func (m *M65816) insSTZ(ea uint32, mode adrMode) uint {
+	m.writeData(ea, 0, m.wideM())
+	return 0
+}
+
+func (m *M65816) insTAX(ea uint32, mode adrMode) uint {
+	m.X = m.setIndex(m.A)
+	return 0
+}
+
+func (m *M65816) insTAY(ea uint32, mode adrMode) uint {
+	m.Y = m.setIndex(m.A)
+	return 0
+}
+
+func (m *M65816) insTCD(ea uint32, mode adrMode) uint {
+	m.D = m.A
+	m.setNZ(m.D, true)
+	return 0
+}
+
+func (m *M65816) insTCS(ea uint32, mode adrMode) uint {
+	if m.E {
+		m.S = 0x100 | (m.A & 0xff)
+	} else {
+		m.S = m.A
+	}
+	return 0
+}
+
+func (m *M65816) insTDC(ea uint32, mode adrMode) uint {
+	m.A = m.D
+	m.setNZ(m.A, true)
+	return 0
+}
+
+// tsb/trb test and set/reset bits
+func (m *M65816) testBits(ea uint32, set bool) {
+	wide := m.wideM()
+	v := m.readData(ea, wide)
+	a := m.A
+	if !wide {
+		a &= 0xff
+	}
+	m.setFlag(flagZ, v&a == 0)
+	if set {
+		v |= a
+	} else {
+		v &= ^a
+	}
+	m.writeData(ea, v, wide)
+}
+
+func (m *M65816) insTRB(ea uint32, mode adrMode) uint {
+	m.testBits(ea, false)
+	return 0
+}
+
+func (m *M65816) insTSB(ea uint32, mode adrMode) uint {
+	m.testBits(ea, true)
+	return 0
+}
+
+func (m *M65816) insTSC(ea uint32, mode adrMode) uint {
+	m.A = m.S
+	m.setNZ(m.A, true)
+	return 0
+}
+
+func (m *M65816) insTSX(ea uint32, mode adrMode) uint {
+	m.X = m.setIndex(m.S)
+	return 0
+}
+
+func (m *M65816) insTXA(ea uint32, mode adrMode) uint {
+	m.setA(m.X)
+	return 0
+}
+
+func (m *M65816) insTXS(ea uint32, mode adrMode) uint {
+	if m.E {
+		m.S = 0x100 | (m.X & 0xff)
+	} else {
+		m.S = m.X
+	}
+	return 0
+}
+
+func (m *M65816) insTXY(ea uint32, mode adrMode) uint {
+	m.Y = m.setIndex(m.X)
+	return 0
+}
+
+func (m *M65816) insTYA(ea uint32, mode adrMode) uint {
+	m.setA(m.Y)
+	return 0
+}
+
+func (m *M65816) insTYX(ea uint32, mode adrMode) uint {
+	m.X = m.setIndex(m.Y)
+	return 0
+}
+
+func (m *M65816) insWAI(ea uint32, mode adrMode) uint {
+	m.wait = true
+	return 0
+}
+
+func (m *M65816) insWDM(ea uint32, mode adrMode) uint {
+	return 0
+}
+
+func (m *M65816) insXBA(ea uint32, mode adrMode) uint {
+	m.A = (m.A << 8) | (m.A >> 8)
+	m.setNZ(m.A, false)
+	return 0
+}
+
+func (m *M65816) insXCE(ea uint32, mode adrMode) uint {
+	c := m.P&flagC != 0
+	m.setFlag(flagC, m.E)
+	m.E = c
+	if m.E {
+		// entering emulation mode
+		m.S = 0x100 | (m.S & 0xff)
+	}
+	// the M and X flags are set when entering and leaving emulation mode
+	m.P |= flagM | flagX
+	m.setP(m.P)
+	return 0
+}
+
+//-----------------------------------------------------------------------------
+// dispatch
+
+// instruction kinds for cycle counting
+const (
+	k816M     = 1 << iota // 16-bit data with M = 0
+	k816X                 // 16-bit data with X = 0
+	k816Store             // write (no page crossing penalty)
+	k816RMW               // read-modify-write (16-bit data costs 2 extra cycles)
+)
+
+// ins816 is the dispatch information for a 65C816 opcode.
+type ins816 struct {
+	fn     op816 // instruction function
+	cycles uint  // base cycles (8-bit data, emulation mode)
+	kind   uint8 // instruction kind
+}
+
+// opcodeTable816 is the 65C816 dispatch table
+var opcodeTable816 = [256]ins816{
+	0x00: {(*M65816).insBRK, 7, 0},
+	0x01: {(*M65816).insORA, 6, k816M},
+	0x02: {(*M65816).insCOP, 7, 0},
+	0x03: {(*M65816).insORA, 4, k816M},
+	0x04: {(*M65816).insTSB, 5, k816M | k816RMW},
+	0x05: {(*M65816).insORA, 3, k816M},
+	0x06: {(*M65816).insASL, 5, k816M | k816RMW},
+	0x07: {(*M65816).insORA, 6, k816M},
+	0x08: {(*M65816).insPHP, 3, 0},
+	0x09: {(*M65816).insORA, 2, k816M},
+	0x0a: {(*M65816).insASL, 2, 0},
+	0x0b: {(*M65816).insPHD, 4, 0},
+	0x0c: {(*M65816).insTSB, 6, k816M | k816RMW},
+	0x0d: {(*M65816).insORA, 4, k816M},
+	0x0e: {(*M65816).insASL, 6, k816M | k816RMW},
+	0x0f: {(*M65816).insORA, 5, k816M},
+	0x10: {(*M65816).insBPL, 2, 0},
+	0x11: {(*M65816).insORA, 5, k816M},
+	0x12: {(*M65816).insORA, 5, k816M},
+	0x13: {(*M65816).insORA, 7, k816M},
+	0x14: {(*M65816).insTRB, 5, k816M | k816RMW},
+	0x15: {(*M65816).insORA, 4, k816M},
+	0x16: {(*M65816).insASL, 6, k816M | k816RMW},
+	0x17: {(*M65816).insORA, 6, k816M},
+	0x18: {(*M65816).insCLC, 2, 0},
+	0x19: {(*M65816).insORA, 4, k816M},
+	0x1a: {(*M65816).insINC, 2, 0},
+	0x1b: {(*M65816).insTCS, 2, 0},
+	0x1c: {(*M65816).insTRB, 6, k816M | k816RMW},
+	0x1d: {(*M65816).insORA, 4, k816M},
+	0x1e: {(*M65816).insASL, 7, k816M | k816RMW},
+	0x1f: {(*M65816).insORA, 5, k816M},
+	0x20: {(*M65816).insJSR, 6, 0},
+	0x21: {(*M65816).insAND, 6, k816M},
+	0x22: {(*M65816).insJSL, 8, 0},
+	0x23: {(*M65816).insAND, 4, k816M},
+	0x24: {(*M65816).insBIT, 3, k816M},
+	0x25: {(*M65816).insAND, 3, k816M},
+	0x26: {(*M65816).insROL, 5, k816M | k816RMW},
+	0x27: {(*M65816).insAND, 6, k816M},
+	0x28: {(*M65816).insPLP, 4, 0},
+	0x29: {(*M65816).insAND, 2, k816M},
+	0x2a: {(*M65816).insROL, 2, 0},
+	0x2b: {(*M65816).insPLD, 5, 0},
+	0x2c: {(*M65816).insBIT, 4, k816M},
+	0x2d: {(*M65816).insAND, 4, k816M},
+	0x2e: {(*M65816).insROL, 6, k816M | k816RMW},
+	0x2f: {(*M65816).insAND, 5, k816M},
+	0x30: {(*M65816).insBMI, 2, 0},
+	0x31: {(*M65816).insAND, 5, k816M},
+	0x32: {(*M65816).insAND, 5, k816M},
+	0x33: {(*M65816).insAND, 7, k816M},
+	0x34: {(*M65816).insBIT, 4, k816M},
+	0x35: {(*M65816).insAND, 4, k816M},
+	0x36: {(*M65816).insROL, 6, k816M | k816RMW},
+	0x37: {(*M65816).insAND, 6, k816M},
+	0x38: {(*M65816).insSEC, 2, 0},
+	0x39: {(*M65816).insAND, 4, k816M},
+	0x3a: {(*M65816).insDEC, 2, 0},
+	0x3b: {(*M65816).insTSC, 2, 0},
+	0x3c: {(*M65816).insBIT, 4, k816M},
+	0x3d: {(*M65816).insAND, 4, k816M},
+	0x3e: {(*M65816).insROL, 7, k816M | k816RMW},
+	0x3f: {(*M65816).insAND, 5, k816M},
+	0x40: {(*M65816).insRTI, 6, 0},
+	0x41: {(*M65816).insEOR, 6, k816M},
+	0x42: {(*M65816).insWDM, 2, 0},
+	0x43: {(*M65816).insEOR, 4, k816M},
+	0x44: {(*M65816).insMVP, 7, 0},
+	0x45: {(*M65816).insEOR, 3, k816M},
+	0x46: {(*M65816).insLSR, 5, k816M | k816RMW},
+	0x47: {(*M65816).insEOR, 6, k816M},
+	0x48: {(*M65816).insPHA, 3, k816M},
+	0x49: {(*M65816).insEOR, 2, k816M},
+	0x4a: {(*M65816).insLSR, 2, 0},
+	0x4b: {(*M65816).insPHK, 3, 0},
+	0x4c: {(*M65816).insJMP, 3, 0},
+	0x4d: {(*M65816).insEOR, 4, k816M},
+	0x4e: {(*M65816).insLSR, 6, k816M | k816RMW},
+	0x4f: {(*M65816).insEOR, 5, k816M},
+	0x50: {(*M65816).insBVC, 2, 0},
+	0x51: {(*M65816).insEOR, 5, k816M},
+	0x52: {(*M65816).insEOR, 5, k816M},
+	0x53: {(*M65816).insEOR, 7, k816M},
+	0x54: {(*M65816).insMVN, 7, 0},
+	0x55: {(*M65816).insEOR, 4, k816M},
+	0x56: {(*M65816).insLSR, 6, k816M | k816RMW},
+	0x57: {(*M65816).insEOR, 6, k816M},
+	0x58: {(*M65816).insCLI, 2, 0},
+	0x59: {(*M65816).insEOR, 4, k816M},
+	0x5a: {(*M65816).insPHY, 3, k816X},
+	0x5b: {(*M65816).insTCD, 2, 0},
+	0x5c: {(*M65816).insJML, 4, 0},
+	0x5d: {(*M65816).insEOR, 4, k816M},
+	0x5e: {(*M65816).insLSR, 7, k816M | k816RMW},
+	0x5f: {(*M65816).insEOR, 5, k816M},
+	0x60: {(*M65816).insRTS, 6, 0},
+	0x61: {(*M65816).insADC, 6, k816M},
+	0x62: {(*M65816).insPER, 6, 0},
+	0x63: {(*M65816).insADC, 4, k816M},
+	0x64: {(*M65816).insSTZ, 3, k816M | k816Store},
+	0x65: {(*M65816).insADC, 3, k816M},
+	0x66: {(*M65816).insROR, 5, k816M | k816RMW},
+	0x67: {(*M65816).insADC, 6, k816M},
+	0x68: {(*M65816).insPLA, 4, k816M},
+	0x69: {(*M65816).insADC, 2, k816M},
+	0x6a: {(*M65816).insROR, 2, 0},
+	0x6b: {(*M65816).insRTL, 6, 0},
+	0x6c: {(*M65816).insJMP, 5, 0},
+	0x6d: {(*M65816).insADC, 4, k816M},
+	0x6e: {(*M65816).insROR, 6, k816M | k816RMW},
+	0x6f: {(*M65816).insADC, 5, k816M},
+	0x70: {(*M65816).insBVS, 2, 0},
+	0x71: {(*M65816).insADC, 5, k816M},
+	0x72: {(*M65816).insADC, 5, k816M},
+	0x73: {(*M65816).insADC, 7, k816M},
+	0x74: {(*M65816).insSTZ, 4, k816M | k816Store},
+	0x75: {(*M65816).insADC, 4, k816M},
+	0x76: {(*M65816).insROR, 6, k816M | k816RMW},
+	0x77: {(*M65816).insADC, 6, k816M},
+	0x78: {(*M65816).insSEI, 2, 0},
+	0x79: {(*M65816).insADC, 4, k816M},
+	0x7a: {(*M65816).insPLY, 4, k816X},
+	0x7b: {(*M65816).insTDC, 2, 0},
+	0x7c: {(*M65816).insJMP, 6, 0},
+	0x7d: {(*M65816).insADC, 4, k816M},
+	0x7e: {(*M65816).insROR, 7, k816M | k816RMW},
+	0x7f: {(*M65816).insADC, 5, k816M},
+	0x80: {(*M65816).insBRA, 2, 0},
+	0x81: {(*M65816).insSTA, 6, k816M | k816Store},
+	0x82: {(*M65816).insBRL, 4, 0},
+	0x83: {(*M65816).insSTA, 4, k816M | k816Store},
+	0x84: {(*M65816).insSTY, 3, k816X | k816Store},
+	0x85: {(*M65816).insSTA, 3, k816M | k816Store},
+	0x86: {(*M65816).insSTX, 3, k816X | k816Store},
+	0x87: {(*M65816).insSTA, 6, k816M | k816Store},
+	0x88: {(*M65816).insDEY, 2, 0},
+	0x89: {(*M65816).insBIT, 2, k816M},
+	0x8a: {(*M65816).insTXA, 2, 0},
+	0x8b: {(*M65816).insPHB, 3, 0},
+	0x8c: {(*M65816).insSTY, 4, k816X | k816Store},
+	0x8d: {(*M65816).insSTA, 4, k816M | k816Store},
+	0x8e: {(*M65816).insSTX, 4, k816X | k816Store},
+	0x8f: {(*M65816).insSTA, 5, k816M | k816Store},
+	0x90: {(*M65816).insBCC, 2, 0},
+	0x91: {(*M65816).insSTA, 6, k816M | k816Store},
+	0x92: {(*M65816).insSTA, 5, k816M | k816Store},
+	0x93: {(*M65816).insSTA, 7, k816M | k816Store},
+	0x94: {(*M65816).insSTY, 4, k816X | k816Store},
+	0x95: {(*M65816).insSTA, 4, k816M | k816Store},
+	0x96: {(*M65816).insSTX, 4, k816X | k816Store},
+	0x97: {(*M65816).insSTA, 6, k816M | k816Store},
+	0x98: {(*M65816).insTYA, 2, 0},
+	0x99: {(*M65816).insSTA, 5, k816M | k816Store},
+	0x9a: {(*M65816).insTXS, 2, 0},
+	0x9b: {(*M65816).insTXY, 2, 0},
+	0x9c: {(*M65816).insSTZ, 4, k816M | k816Store},
+	0x9d: {(*M65816).insSTA, 5, k816M | k816Store},
+	0x9e: {(*M65816).insSTZ, 5, k816M | k816Store},
+	0x9f: {(*M65816).insSTA, 5, k816M | k816Store},
+	0xa0: {(*M65816).insLDY, 2, k816X},
+	0xa1: {(*M65816).insLDA, 6, k816M},
+	0xa2: {(*M65816).insLDX, 2, k816X},
+	0xa3: {(*M65816).insLDA, 4, k816M},
+	0xa4: {(*M65816).insLDY, 3, k816X},
+	0xa5: {(*M65816).insLDA, 3, k816M},
+	0xa6: {(*M65816).insLDX, 3, k816X},
+	0xa7: {(*M65816).insLDA, 6, k816M},
+	0xa8: {(*M65816).insTAY, 2, 0},
+	0xa9: {(*M65816).insLDA, 2, k816M},
+	0xaa: {(*M65816).insTAX, 2, 0},
+	0xab: {(*M65816).insPLB, 4, 0},
+	0xac: {(*M65816).insLDY, 4, k816X},
+	0xad: {(*M65816).insLDA, 4, k816M},
+	0xae: {(*M65816).insLDX, 4, k816X},
+	0xaf: {(*M65816).insLDA, 5, k816M},
+	0xb0: {(*M65816).insBCS, 2, 0},
+	0xb1: {(*M65816).insLDA, 5, k816M},
+	0xb2: {(*M65816).insLDA, 5, k816M},
+	0xb3: {(*M65816).insLDA, 7, k816M},
+	0xb4: {(*M65816).insLDY, 4, k816X},
+	0xb5: {(*M65816).insLDA, 4, k816M},
+	0xb6: {(*M65816).insLDX, 4, k816X},
+	0xb7: {(*M65816).insLDA, 6, k816M},
+	0xb8: {(*M65816).insCLV, 2, 0},
+	0xb9: {(*M65816).insLDA, 4, k816M},
+	0xba: {(*M65816).insTSX, 2, 0},
+	0xbb: {(*M65816).insTYX, 2, 0},
+	0xbc: {(*M65816).insLDY, 4, k816X},
+	0xbd: {(*M65816).insLDA, 4, k816M},
+	0xbe: {(*M65816).insLDX, 4, k816X},
+	0xbf: {(*M65816).insLDA, 5, k816M},
+	0xc0: {(*M65816).insCPY, 2, k816X},
+	0xc1: {(*M65816).insCMP, 6, k816M},
+	0xc2: {(*M65816).insREP, 3, 0},
+	0xc3: {(*M65816).insCMP, 4, k816M},
+	0xc4: {(*M65816).insCPY, 3, k816X},
+	0xc5: {(*M65816).insCMP, 3, k816M},
+	0xc6: {(*M65816).insDEC, 5, k816M | k816RMW},
+	0xc7: {(*M65816).insCMP, 6, k816M},
+	0xc8: {(*M65816).insINY, 2, 0},
+	0xc9: {(*M65816).insCMP, 2, k816M},
+	0xca: {(*M65816).insDEX, 2, 0},
+	0xcb: {(*M65816).insWAI, 3, 0},
+	0xcc: {(*M65816).insCPY, 4, k816X},
+	0xcd: {(*M65816).insCMP, 4, k816M},
+	0xce: {(*M65816).insDEC, 6, k816M | k816RMW},
+	0xcf: {(*M65816).insCMP, 5, k816M},
+	0xd0: {(*M65816).insBNE, 2, 0},
+	0xd1: {(*M65816).insCMP, 5, k816M},
+	0xd2: {(*M65816).insCMP, 5, k816M},
+	0xd3: {(*M65816).insCMP, 7, k816M},
+	0xd4: {(*M65816).insPEI, 6, 0},
+	0xd5: {(*M65816).insCMP, 4, k816M},
+	0xd6: {(*M65816).insDEC, 6, k816M | k816RMW},
+	0xd7: {(*M65816).insCMP, 6, k816M},
+	0xd8: {(*M65816).insCLD, 2, 0},
+	0xd9: {(*M65816).insCMP, 4, k816M},
+	0xda: {(*M65816).insPHX, 3, k816X},
+	0xdb: {(*M65816).insSTP, 3, 0},
+	0xdc: {(*M65816).insJML, 6, 0},
+	0xdd: {(*M65816).insCMP, 4, k816M},
+	0xde: {(*M65816).insDEC, 7, k816M | k816RMW},
+	0xdf: {(*M65816).insCMP, 5, k816M},
+	0xe0: {(*M65816).insCPX, 2, k816X},
+	0xe1: {(*M65816).insSBC, 6, k816M},
+	0xe2: {(*M65816).insSEP, 3, 0},
+	0xe3: {(*M65816).insSBC, 4, k816M},
+	0xe4: {(*M65816).insCPX, 3, k816X},
+	0xe5: {(*M65816).insSBC, 3, k816M},
+	0xe6: {(*M65816).insINC, 5, k816M | k816RMW},
+	0xe7: {(*M65816).insSBC, 6, k816M},
+	0xe8: {(*M65816).insINX, 2, 0},
+	0xe9: {(*M65816).insSBC, 2, k816M},
+	0xea: {(*M65816).insNOP, 2, 0},
+	0xeb: {(*M65816).insXBA, 3, 0},
+	0xec: {(*M65816).insCPX, 4, k816X},
+	0xed: {(*M65816).insSBC, 4, k816M},
+	0xee: {(*M65816).insINC, 6, k816M | k816RMW},
+	0xef: {(*M65816).insSBC, 5, k816M},
+	0xf0: {(*M65816).insBEQ, 2, 0},
+	0xf1: {(*M65816).insSBC, 5, k816M},
+	0xf2: {(*M65816).insSBC, 5, k816M},
+	0xf3: {(*M65816).insSBC, 7, k816M},
+	0xf4: {(*M65816).insPEA, 5, 0},
+	0xf5: {(*M65816).insSBC, 4, k816M},
+	0xf6: {(*M65816).insINC, 6, k816M | k816RMW},
+	0xf7: {(*M65816).insSBC, 6, k816M},
+	0xf8: {(*M65816).insSED, 2, 0},
+	0xf9: {(*M65816).insSBC, 4, k816M},
+	0xfa: {(*M65816).insPLX, 4, k816X},
+	0xfb: {(*M65816).insXCE, 2, 0},
+	0xfc: {(*M65816).insJSR, 8, 0},
+	0xfd: {(*M65816).insSBC, 4, k816M},
+	0xfe: {(*M65816).insINC, 7, k816M | k816RMW},
+	0xff: {(*M65816).insSBC, 5, k816M},
+}
+
+//-----------------------------------------------------------------------------
+
+// execute executes the instruction at PC and returns the number of cycles used.
+func (m *M65816) execute() uint {
+	op := m.fetch8()
+	ins := &opcodeTable816[op]
+	mode := opcodeInfo816[op].mode
+	kind := ins.kind
+	n := ins.cycles
+	// width penalties
+	if (kind&k816M != 0 && m.wideM()) || (kind&k816X != 0 && m.wideX()) {
+		n++
+		if kind&k816RMW != 0 {
+			n++
+		}
+	}
+	// page crossing/index penalties apply to data reads
+	index := kind&(k816M|k816X) != 0 && kind&(k816Store|k816RMW) == 0
+	ea, extra := m.resolve(mode, index)
+	n += extra
+	n += ins.fn(m, ea, mode)
+	return n
+}
+
+//-----------------------------------------------------------------------------
+// virtual subroutines
+
+// AddVSR adds a virtual subroutine at a 24-bit address.
+func (m *M65816) AddVSR(adr uint32, fn VSR816Func) {
+	m.vsr[adr] = fn
+}
+
+// vsrLookup returns the virtual subroutine at the current PBR:PC.
+func (m *M65816) vsrLookup() VSR816Func {
+	return m.vsr[uint32(m.PBR)<<16|uint32(m.PC)]
+}
+
+// callVSR calls a virtual subroutine at the jsr/jsl target.
+// It returns true if the virtual subroutine was called.
+func (m *M65816) callVSR() bool {
+	fn := m.vsrLookup()
+	if fn == nil {
+		return false
+	}
+	fn(m)
+	return true
+}
+
+// jmpVSR calls a virtual subroutine at a jmp target.
+// The caller of the virtual subroutine is the caller of the jmp.
+func (m *M65816) jmpVSR() {
+	fn := m.vsrLookup()
+	if fn == nil {
+		return
+	}
+	fn(m)
+	// simulate RTS
+	m.PC = m.pop16() + 1
+}
+
+//-----------------------------------------------------------------------------
+
+// NMI signals a non-maskable interrupt (a pulse on the NMI line).
+func (m *M65816) NMI() {
+	m.nmi = true
+}
+
+// NMILine sets the state of the NMI line (true = asserted).
+// The NMI is edge triggered: it is latched when the line is asserted.
+func (m *M65816) NMILine(state bool) {
+	if state && !m.nmiLine {
+		m.nmi = true
+	}
+	m.nmiLine = state
+}
+
+// IRQ sets the state of the IRQ line (true = asserted).
+// The IRQ is level triggered: it is serviced while the line is asserted and
+// the I flag is clear, so a device holds it until it is acknowledged.
+func (m *M65816) IRQ(state bool) {
+	m.irq = state
+}
+
+// Interrupts returns the interrupt sources of the CPU.
+// Devices sharing a line should drive it through a source rather than IRQ or NMILine.
+func (m *M65816) Interrupts() *Interrupts {
+	if m.ints == nil {
+		m.ints = NewInterrupts(m.IRQ, m.NMILine)
+	}
+	return m.ints
+}
+
+// Exit is called from a VSR to stop the emulation with a status value.
+func (m *M65816) Exit(status uint8) {
+	m.A = (m.A & 0xff00) | uint16(status)
+	m.exit = true
+}
+
+// Waiting returns true if the CPU is waiting for an interrupt (WAI).
+func (m *M65816) Waiting() bool {
+	return m.wait
+}
+
+// Stopped returns true if the CPU clock is stopped (STP).
+func (m *M65816) Stopped() bool {
+	return m.stop
+}
+
+// ReadPC returns the 24-bit program counter.
+func (m *M65816) ReadPC() uint32 {
+	return uint32(m.PBR)<<16 | uint32(m.PC)
+}
+
+// Run the 65C816 CPU for a single instruction.
+func (m *M65816) Run() error {
+	// a stopped cpu needs a reset
+	if m.stop {
+		return nil
+	}
+	// wait for an interrupt
+	if m.wait {
+		if !m.nmi && !m.irq {
+			m.cycles++
+			return nil
+		}
+		m.wait = false
+	}
+	// nmi handling
+	if m.nmi {
+		m.nmi = false
+		if m.E {
+			m.cycles += m.interrupt(m.PC, m.P & ^flagB, NmiAddress)
+		} else {
+			m.cycles += m.interrupt(m.PC, m.P, nmiNative816)
+		}
+		return nil
+	}
+	// irq handling
+	if m.irq && (m.P&flagI == 0) {
+		if m.E {
+			m.cycles += m.interrupt(m.PC, m.P & ^flagB, IrqAddress)
+		} else {
+			m.cycles += m.interrupt(m.PC, m.P, irqNative816)
+		}
+		return nil
+	}
+	// normal instructions
+	m.cycles += m.execute()
+
+	if m.exit {
+		return &ExitError{PC: m.PC, PBR: m.PBR, Status: uint8(m.A), Cycles: m.cycles, long: true}
+	}
+
+	// stuck PC detection
+	pc := m.ReadPC()
+	if pc == m.lastPC {
+		m.stuckPC++
+		if m.stuckPC >= 4 {
+			return &StuckError{PC: m.PC, PBR: m.PBR, Cycles: m.cycles, long: true}
+		}
+	} else {
+		m.stuckPC = 0
+		m.lastPC = pc
+	}
+
+	return nil
+}
+
+// RunUntil runs the CPU until the predicate (called after each step) is true,
+// there is an error or the context is done (returning the context error).
+func (m *M65816) RunUntil(ctx context.Context, until func(m *M65816) bool) error {
+	done := ctx.Done()
+	for i := 0; ; i++ {
+		if i%contextPoll == 0 && done != nil {
+			select {
+			case <-done:
+				return ctx.Err()
+			default:
+			}
+		}
+		if m.stop {
+			return nil
+		}
+		if err := m.Run(); err != nil {
+			return err
+		}
+		if until != nil && until(m) {
+			return nil
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// Power on/off the 65C816 CPU.
+func (m *M65816) Power(state bool) {
+	if state {
+		m.PC = initialPC
+		m.S = stkAddress | initialS
+		m.P = initialP
+		m.A = initialA
+		m.X = initialX
+		m.Y = initialY
+	} else {
+		m.PC = 0
+		m.S = 0
+		m.P = 0
+		m.A = 0
+		m.X = 0
+		m.Y = 0
+	}
+	m.PBR = 0
+	m.DBR = 0
+	m.D = 0
+	m.E = true
+	m.irq = false
+	m.nmi = false
+	m.nmiLine = false
+	if m.ints != nil {
+		// the lines are held by the interrupt sources
+		m.irq = m.ints.irqLine
+		m.nmiLine = m.ints.nmiLine
+	}
+	m.wait = false
+	m.stop = false
+	m.exit = false
+	m.cycles = 0
+	m.lastPC = 0
+	m.stuckPC = 0
+}
+
+// Reset the 65C816 CPU, it starts in emulation mode.
+func (m *M65816) Reset() {
+	m.E = true
+	m.PBR = 0
+	m.DBR = 0
+	m.D = 0
+	m.S = stkAddress | initialS
+	m.setP(initialP)
+	m.PC = m.read16(RstAddress)
+	// the interrupt lines are held by the devices
+	m.nmi = false
+	m.wait = false
+	m.stop = false
+}
+
+//-----------------------------------------------------------------------------
+
+// New65816 returns a 65C816 CPU, starting in emulation mode.
+// If the memory implements LongMemory it is used for 24-bit addressing.
+func New65816(mem Memory) *M65816 {
+	m := M65816{
+		Mem: mem,
+		E:   true,
+		vsr: make(map[uint32]VSR816Func),
+	}
+	m.long, _ = mem.(LongMemory)
+	return &m
+}
+
+//-----------------------------------------------------------------------------
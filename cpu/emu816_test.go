@@ -0,0 +1,352 @@
+//-----------------------------------------------------------------------------
+/*
+
+65C816 Emulator Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+// testLongRAM is a sparse 16M memory.
+type testLongRAM map[uint32]uint8
+
+func (r testLongRAM) Read8(adr uint16) uint8 {
+	return r[uint32(adr)]
+}
+
+func (r testLongRAM) Write8(adr uint16, val uint8) {
+	r[uint32(adr)] = val
+}
+
+func (r testLongRAM) ReadLong(adr uint32) uint8 {
+	return r[adr]
+}
+
+func (r testLongRAM) WriteLong(adr uint32, val uint8) {
+	r[adr] = val
+}
+
+// load writes code at a 24-bit address.
+func (r testLongRAM) load(adr uint32, code []uint8) {
+	for i, v := range code {
+		r[adr+uint32(i)] = v
+	}
+}
+
+// test816 returns a 65C816 in emulation mode at $00:0400 running code.
+func test816(code ...uint8) (*M65816, testLongRAM) {
+	r := make(testLongRAM)
+	r.load(0x0400, code)
+	m := New65816(r)
+	m.Power(true)
+	m.PC = 0x0400
+	return m, r
+}
+
+// run816 runs n instructions.
+func run816(t *testing.T, m *M65816, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		if err := m.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func TestWidth816(t *testing.T) {
+	m, _ := test816(
+		0x18,       // clc
+		0xfb,       // xce
+		0xc2, 0x30, // rep #$30
+		0xa9, 0x34, 0x12, // lda #$1234
+		0xa2, 0x78, 0x56, // ldx #$5678
+		0xe2, 0x20, // sep #$20
+		0xa9, 0xff, // lda #$ff
+		0xe2, 0x10, // sep #$10
+		0xea, // nop
+	)
+	run816(t, m, 5)
+	if m.E || m.A != 0x1234 || m.X != 0x5678 || m.PC != 0x040a {
+		t.Fatalf("16-bit loads\n%s", m.Dump())
+	}
+	// an 8-bit accumulator keeps the B register
+	run816(t, m, 2)
+	if m.A != 0x12ff || m.P&flagN == 0 || m.PC != 0x040e {
+		t.Fatalf("8-bit load\n%s", m.Dump())
+	}
+	// 8-bit index registers lose the high byte
+	run816(t, m, 1)
+	if m.X != 0x0078 || m.P&(flagM|flagX) != flagM|flagX {
+		t.Fatalf("8-bit index\n%s", m.Dump())
+	}
+	run816(t, m, 1)
+	if m.PC != 0x0411 {
+		t.Errorf("pc %04x, want 0411", m.PC)
+	}
+}
+
+func TestXCE816(t *testing.T) {
+	m, _ := test816(
+		0x18,       // clc
+		0xfb,       // xce
+		0xc2, 0x30, // rep #$30
+		0xa2, 0x34, 0x12, // ldx #$1234
+		0xa9, 0xcd, 0xab, // lda #$abcd
+		0x38,       // sec
+		0xfb,       // xce
+		0xe2, 0x30, // sep #$30
+		0xc2, 0x30, // rep #$30
+	)
+	run816(t, m, 2)
+	if m.E || m.P&flagC == 0 || m.P&(flagM|flagX) != flagM|flagX {
+		t.Fatalf("native mode\n%s", m.Dump())
+	}
+	m.S = 0x1234
+	run816(t, m, 5)
+	// emulation mode: 8-bit registers, the stack is in page 1
+	if !m.E || m.P&flagC != 0 || m.X != 0x0034 || m.A != 0xabcd || m.S != 0x0134 {
+		t.Fatalf("emulation mode\n%s", m.Dump())
+	}
+	// M and X can't be cleared in emulation mode
+	run816(t, m, 2)
+	if m.P&(flagM|flagX) != flagM|flagX {
+		t.Errorf("rep in emulation mode\n%s", m.Dump())
+	}
+}
+
+func TestBlockMove816(t *testing.T) {
+	tests := []struct {
+		name       string
+		op         uint8
+		x, y       uint16
+		wantX      uint16
+		wantY      uint16
+		start, end uint32 // destination range
+	}{
+		{"mvn", 0x54, 0x1000, 0x2000, 0x1004, 0x2004, 0x022000, 0x022003},
+		{"mvp", 0x44, 0x1003, 0x2003, 0x0fff, 0x1fff, 0x022000, 0x022003},
+	}
+	for _, tt := range tests {
+		m, r := test816(
+			0x18,       // clc
+			0xfb,       // xce
+			0xc2, 0x30, // rep #$30
+			tt.op, 0x02, 0x01, // mvn/mvp $02,$01
+			0xea, // nop
+		)
+		r.load(0x011000, []uint8{0x11, 0x22, 0x33, 0x44})
+		run816(t, m, 3)
+		m.A, m.X, m.Y = 3, tt.x, tt.y
+		start := m.cycles
+		for i := 0; m.PC != 0x0407; i++ {
+			if i == 10 {
+				t.Fatalf("%s: no end at %04x", tt.name, m.PC)
+			}
+			run816(t, m, 1)
+		}
+		if n := m.cycles - start; n != 4*7 {
+			t.Errorf("%s: %d cycles, want 28", tt.name, n)
+		}
+		if m.A != 0xffff || m.X != tt.wantX || m.Y != tt.wantY || m.DBR != 0x02 {
+			t.Errorf("%s: registers\n%s", tt.name, m.Dump())
+		}
+		for i, v := range []uint8{0x11, 0x22, 0x33, 0x44} {
+			if r[tt.start+uint32(i)] != v {
+				t.Errorf("%s: %06x = %02x, want %02x", tt.name, tt.start+uint32(i), r[tt.start+uint32(i)], v)
+			}
+		}
+		if r[tt.start-1] != 0 || r[tt.end+1] != 0 {
+			t.Errorf("%s: wrote outside the destination", tt.name)
+		}
+	}
+}
+
+func TestAddressModes816(t *testing.T) {
+	m, r := test816(
+		0x18,       // clc
+		0xfb,       // xce
+		0xa2, 0x02, // ldx #$02
+		0xbf, 0xff, 0xff, 0xff, // lda $ffffff,x
+		0x8f, 0x56, 0x34, 0x12, // sta $123456
+		0x48,       // pha
+		0xa9, 0x00, // lda #$00
+		0xa3, 0x01, // lda 1,s
+		0x5c, 0x00, 0x80, 0x03, // jml $038000
+	)
+	r[0x000001] = 0x5a
+	r.load(0x038000, []uint8{0xea})
+	m.S = 0x01ff
+	run816(t, m, 5)
+	// long indexed addressing wraps at 24 bits
+	if m.A&0xff != 0x5a || r[0x123456] != 0x5a {
+		t.Fatalf("long addressing\n%s", m.Dump())
+	}
+	// stack relative
+	run816(t, m, 3)
+	if m.A&0xff != 0x5a || m.S != 0x01fe || r[0x0001ff] != 0x5a {
+		t.Fatalf("stack relative\n%s", m.Dump())
+	}
+	run816(t, m, 1)
+	if m.ReadPC() != 0x038000 {
+		t.Fatalf("jml to %06x", m.ReadPC())
+	}
+	run816(t, m, 1)
+	if m.ReadPC() != 0x038001 {
+		t.Errorf("ran to %06x", m.ReadPC())
+	}
+}
+
+func TestEmulation816(t *testing.T) {
+	// a 6502 program: sum 1..10 with a subroutine
+	var r testRAM
+	copy(r[0x0400:], []uint8{
+		0xa9, 0x00, // lda #$00
+		0xa2, 0x0a, // ldx #$0a
+		0x20, 0x10, 0x04, // loop: jsr add
+		0xca,       // dex
+		0xd0, 0xfa, // bne loop
+		0x85, 0x20, // sta $20
+		0x4c, 0x0c, 0x04, // jmp *
+	})
+	copy(r[0x0410:], []uint8{
+		0x86, 0x10, // add: stx $10
+		0x18,       // clc
+		0x65, 0x10, // adc $10
+		0x60, // rts
+	})
+	m := New65816(&r)
+	m.Power(true)
+	m.PC = 0x0400
+	m.S = 0x0100
+	for i := 0; m.PC != 0x040c; i++ {
+		if i == 100 {
+			t.Fatalf("no end at %04x", m.PC)
+		}
+		run816(t, m, 1)
+	}
+	if r[0x20] != 55 || !m.E || m.S != 0x0100 {
+		t.Errorf("sum %d\n%s", r[0x20], m.Dump())
+	}
+	// the stack wrapped within page 1
+	if r[0x01ff] != 0x06 || r[0x0100] != 0x04 {
+		t.Errorf("return address %02x%02x", r[0x0100], r[0x01ff])
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+func TestRunUntil816(t *testing.T) {
+	m, r := test816(
+		0xe8,             // loop: inx
+		0x4c, 0x00, 0x04, // jmp loop
+	)
+	err := m.RunUntil(context.Background(), func(m *M65816) bool {
+		return m.X == 100
+	})
+	if err != nil || m.X != 100 || m.PC != 0x0401 {
+		t.Fatalf("ran to %04x x %02x: %v", m.PC, m.X, err)
+	}
+	// a stopped cpu returns
+	r[0x0400] = 0xdb // stp
+	m.PC = 0x0400
+	if err := m.RunUntil(context.Background(), nil); err != nil || !m.Stopped() {
+		t.Errorf("stp: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.RunUntil(ctx, nil); err != context.Canceled {
+		t.Errorf("cancelled: %v", err)
+	}
+}
+
+func TestInterrupts816(t *testing.T) {
+	m, r := test816(
+		0x58,             // cli
+		0xea,             // loop: nop
+		0x4c, 0x01, 0x04, // jmp loop
+	)
+	r.load(IrqAddress, []uint8{0x00, 0x05})
+	r.load(NmiAddress, []uint8{0x00, 0x06})
+	r.load(0x0500, []uint8{0xe6, 0x10, 0x40}) // inc $10, rti
+	r.load(0x0600, []uint8{0xe6, 0x11, 0x40}) // inc $11, rti
+	ints := m.Interrupts()
+	irq, err := ints.IRQ("via")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nmi, err := ints.NMI("button")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a held IRQ interrupts again after each RTI
+	irq.Assert()
+	run816(t, m, 40)
+	if r[0x10] < 4 {
+		t.Fatalf("held IRQ serviced %d times", r[0x10])
+	}
+	irq.Clear()
+	run816(t, m, 4)
+	n := r[0x10]
+	run816(t, m, 40)
+	if r[0x10] != n {
+		t.Errorf("released IRQ serviced")
+	}
+	// a held NMI interrupts once
+	nmi.Assert()
+	run816(t, m, 40)
+	if r[0x11] != 1 {
+		t.Errorf("held NMI serviced %d times", r[0x11])
+	}
+	// the sources hold the lines over a power cycle
+	irq.Assert()
+	m.Power(true)
+	if !m.irq || !m.nmiLine {
+		t.Errorf("lines irq %t nmi %t after power up", m.irq, m.nmiLine)
+	}
+}
+
+func TestDisassemble816(t *testing.T) {
+	m, _ := test816(
+		0xc2, 0x30, // rep #$30
+		0xa9, 0x34, 0x12, // lda #$1234
+		0xe2, 0x20, // sep #$20
+		0xa9, 0x12, // lda #$12
+		0xa2, 0x78, 0x56, // ldx #$5678
+		0x54, 0x02, 0x01, // mvn
+		0xbf, 0x56, 0x34, 0x12, // lda $123456,x
+		0xa3, 0x03, // lda 3,s
+	)
+	m.E = false
+	m.P = flagM | flagX
+	// the immediate operand widths follow REP/SEP
+	want := []string{
+		"rep #$30",
+		"lda #$1234",
+		"sep #$20",
+		"lda #$12",
+		"ldx #$5678",
+		"mvn $02,$01",
+		"lda $123456,x",
+		"lda $03,s",
+	}
+	lines := strings.Split(m.Disassemble(0x0400, 21), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("%d lines, want %d", len(lines), len(want))
+	}
+	for i := range want {
+		if !strings.Contains(lines[i], want[i]) {
+			t.Errorf("got %q, want %q", lines[i], want[i])
+		}
+	}
+}
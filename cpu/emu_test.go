@@ -0,0 +1,244 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Emulator Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+// testRAM is a flat 64K memory.
+type testRAM [1 << 16]uint8
+
+func (r *testRAM) Read8(adr uint16) uint8 {
+	return r[adr]
+}
+
+func (r *testRAM) Write8(adr uint16, val uint8) {
+	r[adr] = val
+}
+
+var nmosVariants = []Variant{Variant6502, Variant2A03, Variant6510}
+var cmosVariants = []Variant{Variant65C02, VariantR65C02, VariantW65C02S}
+
+// testStep runs one instruction with Run, or with Tick.
+func testStep(t *testing.T, m *M6502, tick bool) {
+	t.Helper()
+	var err error
+	if tick {
+		for {
+			err = m.Tick()
+			if err != nil || m.Sync() {
+				break
+			}
+		}
+	} else {
+		err = m.Run()
+	}
+	if err != nil {
+		t.Fatalf("%s: %v", m.variant, err)
+	}
+}
+
+// testCPUs calls fn with a fresh cpu for each variant and execution mode.
+func testCPUs(t *testing.T, vs []Variant, fn func(m *M6502, r *testRAM, tick bool)) {
+	for _, v := range vs {
+		for _, tick := range []bool{false, true} {
+			var r testRAM
+			m := newCPU(&r, v, nil)
+			m.Power(true)
+			fn(m, &r, tick)
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
+// NMOS quirks
+
+func TestJmpIndirectPageWrap(t *testing.T) {
+	setup := func(m *M6502, r *testRAM) {
+		m.PC = 0x0400
+		copy(r[0x0400:], []uint8{0x6c, 0xff, 0x10}) // jmp ($10ff)
+		r[0x10ff] = 0x34
+		r[0x1000] = 0x12 // NMOS: high byte from the start of the page
+		r[0x1100] = 0x56 // CMOS: high byte from the next page
+	}
+	testCPUs(t, nmosVariants, func(m *M6502, r *testRAM, tick bool) {
+		setup(m, r)
+		testStep(t, m, tick)
+		if m.PC != 0x1234 {
+			t.Errorf("%s: jmp ($10ff) went to %04x, want 1234", m.variant, m.PC)
+		}
+	})
+	testCPUs(t, cmosVariants, func(m *M6502, r *testRAM, tick bool) {
+		setup(m, r)
+		testStep(t, m, tick)
+		if m.PC != 0x5634 {
+			t.Errorf("%s: jmp ($10ff) went to %04x, want 5634", m.variant, m.PC)
+		}
+	})
+}
+
+func TestZeroPagePointerWrap(t *testing.T) {
+	tests := []struct {
+		name string
+		code []uint8
+		x, y uint8
+		adr  uint16 // effective address when the pointer wraps
+	}{
+		{"lda ($ff),y", []uint8{0xb1, 0xff}, 0, 0x01, 0x1235},
+		{"lda ($ff,x)", []uint8{0xa1, 0xff}, 0x00, 0, 0x1234},
+		{"lda ($80,x)", []uint8{0xa1, 0x80}, 0x7f, 0, 0x1234},
+		{"sta ($ff),y", []uint8{0x91, 0xff}, 0, 0x02, 0x1236},
+		{"sta ($ff,x)", []uint8{0x81, 0xff}, 0x00, 0, 0x1234},
+	}
+	for _, tt := range tests {
+		testCPUs(t, append(nmosVariants, cmosVariants...), func(m *M6502, r *testRAM, tick bool) {
+			m.PC = 0x0400
+			copy(r[0x0400:], tt.code)
+			m.A = 0x5a
+			m.X = tt.x
+			m.Y = tt.y
+			r[0x00ff] = 0x34
+			m.write8(0x0000, 0x12) // high byte wraps to the start of the zero page (the 6510 DDR)
+			r[0x0100] = 0x56       // not the stack page
+			r[tt.adr] = 0xa5
+			testStep(t, m, tick)
+			if tt.code[0] == 0x91 || tt.code[0] == 0x81 {
+				if r[tt.adr] != 0x5a {
+					t.Errorf("%s: %s didn't write %04x", m.variant, tt.name, tt.adr)
+				}
+			} else if m.A != 0xa5 {
+				t.Errorf("%s: %s read %02x, want a5 from %04x", m.variant, tt.name, m.A, tt.adr)
+			}
+		})
+	}
+}
+
+func TestZeroPageIndirectWrap(t *testing.T) {
+	testCPUs(t, cmosVariants, func(m *M6502, r *testRAM, tick bool) {
+		m.PC = 0x0400
+		copy(r[0x0400:], []uint8{0xb2, 0xff}) // lda ($ff)
+		r[0x00ff] = 0x34
+		r[0x0000] = 0x12
+		r[0x0100] = 0x56
+		r[0x1234] = 0xa5
+		testStep(t, m, tick)
+		if m.A != 0xa5 {
+			t.Errorf("%s: lda ($ff) read %02x, want a5 from 1234", m.variant, m.A)
+		}
+	})
+}
+
+//-----------------------------------------------------------------------------
+// errors
+
+func TestErrors(t *testing.T) {
+	var r testRAM
+	copy(r[0x0400:], []uint8{
+		0x02,             // jam (illegal when strict)
+		0x20, 0x00, 0x10, // jsr $1000 (exit)
+		0x4c, 0x04, 0x04, // jmp *
+	})
+	run := func(m *M6502) error {
+		for i := 0; i < 8; i++ {
+			if err := m.Run(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	m := newCPU(&r, Variant6502, []Option{Strict()})
+	m.PC = 0x0400
+	err := run(m)
+	var illegal *IllegalOpcodeError
+	if !errors.As(err, &illegal) || illegal.PC != 0x0400 || illegal.Opcode != 0x02 || m.HaltReason() != HaltIllegal {
+		t.Errorf("strict jam: %v", err)
+	}
+
+	m = newCPU(&r, Variant6502, nil)
+	m.PC = 0x0400
+	err = run(m)
+	if !errors.Is(err, ErrJam) || m.HaltReason() != HaltJam {
+		t.Errorf("jam: %v", err)
+	}
+
+	m.Power(true)
+	m.PC = 0x0401
+	m.AddVSR(0x1000, func(m *M6502) { m.Exit(3) })
+	err = run(m)
+	var exit *ExitError
+	if !errors.As(err, &exit) || !errors.Is(err, ErrExit) || exit.Status != 3 || exit.Cycles != 6 || m.HaltReason() != HaltExit {
+		t.Errorf("exit: %v", err)
+	}
+
+	m.Power(true)
+	m.PC = 0x0404
+	err = run(m)
+	var stuck *StuckError
+	if !errors.As(err, &stuck) || !errors.Is(err, ErrStuck) || stuck.PC != 0x0404 || m.HaltReason() != HaltStuck {
+		t.Errorf("stuck: %v", err)
+	}
+}
+
+//-----------------------------------------------------------------------------
+// debug accesses
+
+// testDevMem is RAM with a status register at $8000 that is cleared by a read.
+type testDevMem struct {
+	testRAM
+}
+
+func (r *testDevMem) Read8(adr uint16) uint8 {
+	v := r.testRAM[adr]
+	if adr == 0x8000 {
+		r.testRAM[adr] = 0
+	}
+	return v
+}
+
+func (r *testDevMem) Peek8(adr uint16) uint8 {
+	return r.testRAM[adr]
+}
+
+func (r *testDevMem) Poke8(adr uint16, val uint8) {
+	r.testRAM[adr] = val
+}
+
+func TestPeek(t *testing.T) {
+	var r testDevMem
+	m := New6502(&r)
+	m.PC = 0x0400
+	copy(r.testRAM[0x0400:], []uint8{0xad, 0x00, 0x80}) // lda $8000
+	m.Poke8(0x8000, 0x80)
+	if m.Peek8(0x8000) != 0x80 || Peek8(&r, 0x8000) != 0x80 {
+		t.Errorf("peek: got %02x", m.Peek8(0x8000))
+	}
+	// the debugger views don't clear the status
+	DisassembleVariant(Variant6502, &r, 0x8000, nil)
+	m.Disassemble(0x8000, 4)
+	trace := m.NestestTrace()
+	if r.testRAM[0x8000] != 0x80 {
+		t.Fatalf("status cleared by a debugger access")
+	}
+	if !strings.Contains(trace, "LDA $8000 = 80") {
+		t.Errorf("trace: %s", trace)
+	}
+	// the cpu does
+	m.Run()
+	if m.A != 0x80 || r.testRAM[0x8000] != 0 {
+		t.Errorf("lda: a %02x, status %02x", m.A, r.testRAM[0x8000])
+	}
+}
+
+//-----------------------------------------------------------------------------
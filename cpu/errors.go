@@ -0,0 +1,211 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 CPU Errors
+
+Run returns typed errors so callers can branch on the outcome with errors.Is
+and errors.As rather than matching strings.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"errors"
+	"fmt"
+)
+
+//-----------------------------------------------------------------------------
+
+// Errors matched with errors.Is.
+var (
+	ErrIllegalOpcode = errors.New("illegal instruction")
+	ErrJam           = errors.New("jammed")
+	ErrExit          = errors.New("exit")
+	ErrStuck         = errors.New("PC is stuck")
+	ErrHalt          = errors.New("halted")
+	ErrPanic         = errors.New("panic")
+	ErrNoReturn      = errors.New("call didn't return")
+	ErrSourceLine    = errors.New("interrupt source is on the other line")
+)
+
+// IllegalOpcodeError is returned when an illegal opcode is executed.
+type IllegalOpcodeError struct {
+	PC     uint16 // address of the opcode
+	Opcode uint8
+}
+
+func (e *IllegalOpcodeError) Error() string {
+	return fmt.Sprintf("illegal instruction at %04x (opcode %02x)", e.PC, e.Opcode)
+}
+
+// Is matches ErrIllegalOpcode.
+func (e *IllegalOpcodeError) Is(target error) bool {
+	return target == ErrIllegalOpcode
+}
+
+// JamError is returned when a JAM opcode halts the CPU.
+type JamError struct {
+	PC     uint16 // address of the opcode
+	Opcode uint8
+}
+
+func (e *JamError) Error() string {
+	return fmt.Sprintf("jammed at %04x", e.PC)
+}
+
+// Is matches ErrJam.
+func (e *JamError) Is(target error) bool {
+	return target == ErrJam
+}
+
+// ExitError is returned when the emulated program exits (see Exit).
+type ExitError struct {
+	PC       uint16
+	PBR      uint8 // 65816 program bank
+	Status   uint8 // exit status
+	Cycles   uint
+	Coverage float32 // fraction of the documented opcodes that have run (6502)
+	long     bool    // 65816 (the PC is in a bank, no coverage)
+}
+
+func (e *ExitError) Error() string {
+	if e.long {
+		return fmt.Sprintf("exit at %02x:%04x, status %02x, %d cpu cycles", e.PBR, e.PC, e.Status, e.Cycles)
+	}
+	return fmt.Sprintf("exit at %04x, status %02x, %d cpu cycles, %.2f coverage", e.PC, e.Status, e.Cycles, e.Coverage)
+}
+
+// Is matches ErrExit.
+func (e *ExitError) Is(target error) bool {
+	return target == ErrExit
+}
+
+// StuckError is returned when the PC doesn't change (e.g. a JMP to itself).
+type StuckError struct {
+	PC       uint16
+	PBR      uint8 // 65816 program bank
+	Cycles   uint
+	Coverage float32 // fraction of the documented opcodes that have run (6502)
+	long     bool    // 65816 (the PC is in a bank, no coverage)
+}
+
+func (e *StuckError) Error() string {
+	if e.long {
+		return fmt.Sprintf("PC is stuck at %02x:%04x, %d cpu cycles", e.PBR, e.PC, e.Cycles)
+	}
+	return fmt.Sprintf("PC is stuck at %04x, %d cpu cycles, %.2f coverage", e.PC, e.Cycles, e.Coverage)
+}
+
+// Is matches ErrStuck.
+func (e *StuckError) Is(target error) bool {
+	return target == ErrStuck
+}
+
+// HaltError is returned when a hook halts the CPU.
+type HaltError struct {
+	PC  uint16
+	Err error // the error returned by the hook
+}
+
+func (e *HaltError) Error() string {
+	return fmt.Sprintf("halted at %04x: %s", e.PC, e.Err)
+}
+
+// Is matches ErrHalt.
+func (e *HaltError) Is(target error) bool {
+	return target == ErrHalt
+}
+
+// Unwrap returns the error returned by the hook.
+func (e *HaltError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is returned when a hook or VSR panics.
+type PanicError struct {
+	PC    uint16
+	Value interface{} // the value passed to panic
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic at %04x: %v", e.PC, e.Value)
+}
+
+// Is matches ErrPanic.
+func (e *PanicError) Is(target error) bool {
+	return target == ErrPanic
+}
+
+// CallError is returned when a called subroutine doesn't return within the
+// cycle limit (or stops the clock).
+type CallError struct {
+	Adr    uint16 // subroutine address
+	PC     uint16
+	Cycles uint // cycles used by the call
+}
+
+func (e *CallError) Error() string {
+	return fmt.Sprintf("call to %04x didn't return, pc %04x, %d cpu cycles", e.Adr, e.PC, e.Cycles)
+}
+
+// Is matches ErrNoReturn.
+func (e *CallError) Is(target error) bool {
+	return target == ErrNoReturn
+}
+
+//-----------------------------------------------------------------------------
+
+// HaltReason is the reason the CPU isn't running.
+type HaltReason int
+
+// Halt reasons.
+const (
+	HaltNone    HaltReason = iota // running
+	HaltIllegal                   // illegal opcode
+	HaltJam                       // JAM opcode
+	HaltExit                      // program exit
+	HaltStuck                     // stuck PC
+	HaltWait                      // waiting for an interrupt (WAI)
+	HaltStop                      // clock stopped until reset (STP)
+	HaltHook                      // halted by a hook (or a panic in a hook or VSR)
+)
+
+var haltReasonName = map[HaltReason]string{
+	HaltNone:    "none",
+	HaltIllegal: "illegal",
+	HaltJam:     "jam",
+	HaltExit:    "exit",
+	HaltStuck:   "stuck",
+	HaltWait:    "wait",
+	HaltStop:    "stop",
+	HaltHook:    "hook",
+}
+
+func (r HaltReason) String() string {
+	return haltReasonName[r]
+}
+
+// HaltReason returns the reason the CPU isn't running.
+func (m *M6502) HaltReason() HaltReason {
+	switch {
+	case m.halted != nil:
+		return HaltHook
+	case m.illegal:
+		return HaltIllegal
+	case m.jam:
+		return HaltJam
+	case m.exit:
+		return HaltExit
+	case m.stuckPC >= stuckLimit:
+		return HaltStuck
+	case m.stop:
+		return HaltStop
+	case m.wait:
+		return HaltWait
+	}
+	return HaltNone
+}
+
+//-----------------------------------------------------------------------------
@@ -18,16 +18,19 @@ import (
 
 // generate the opcode function name
 func opcodeFuncName(code uint8) string {
-	x := opcodeLookup(code)
+	x := opcodeLookup(Variant6502, code)
 	if x.ins == "ill" {
 		return "opXX"
 	}
+	if x.ins == "jam" {
+		return "opJAM"
+	}
 	return fmt.Sprintf("op%02X", code)
 }
 
 // generate the opcode function comment
 func opcodeFuncComment(code uint8) string {
-	x := opcodeLookup(code)
+	x := opcodeLookup(Variant6502, code)
 	s := make([]string, 0)
 	s = append(s, opcodeFuncName(code))
 	s = append(s, fmt.Sprintf("%s %s", strings.ToUpper(x.ins), insDescr[x.ins]))
@@ -45,7 +48,7 @@ func genOpcodeFunction(code uint8) string {
 	s = append(s, fmt.Sprintf("func %s(m *M6502) uint {", opcodeFuncName(code)))
 	s = append(s, fmt.Sprintf("panic(\"TODO\")"))
 
-	n := insLength(code)
+	n := insLength(Variant6502, code)
 	if n == 1 {
 		s = append(s, "m.PC ++")
 	} else {
@@ -63,10 +66,10 @@ func genOpcodes() []uint8 {
 	// get the unique set of opcode mneumonics
 	fset := make(map[string]uint8)
 	for code := 0; code < 256; code++ {
-		x := opcodeLookup(uint8(code))
+		x := opcodeLookup(Variant6502, uint8(code))
 		var s string
-		if x.ins == "ill" {
-			s = "ill"
+		if x.ins == "ill" || x.ins == "jam" {
+			s = x.ins
 		} else {
 			s = fmt.Sprintf("%s%02x", x.ins, code)
 		}
@@ -99,33 +102,226 @@ func genOpcodeFunctions() string {
 	return strings.Join(s, "\n\n")
 }
 
-func genOpcodeTable() string {
+// GenOpcodeFunctions generates template opcode functions.
+func GenOpcodeFunctions() string {
+	return genOpcodeFunctions()
+}
+
+//-----------------------------------------------------------------------------
+// opcode tables
+
+// modeName maps an address mode onto the identifier name.
+var modeName = map[adrMode]string{
+	amNone:    "amNone",
+	amAcc:     "amAcc",
+	amAbs:     "amAbs",
+	amAbsX:    "amAbsX",
+	amAbsY:    "amAbsY",
+	amImm:     "amImm",
+	amImpl:    "amImpl",
+	amInd:     "amInd",
+	amXInd:    "amXInd",
+	amIndY:    "amIndY",
+	amRel:     "amRel",
+	amZpg:     "amZpg",
+	amZpgX:    "amZpgX",
+	amZpgY:    "amZpgY",
+	amZpgInd:  "amZpgInd",
+	amAbsXInd: "amAbsXInd",
+	amZpgRel:  "amZpgRel",
+}
 
-	f := make([]string, 16)
-	for i := 0; i < 16; i++ {
-		l := make([]string, 16)
-		for j := 0; j < 16; j++ {
-			code := uint8((i * 16) + j)
-			l[j] = opcodeFuncName(code)
+// variantOpcodes returns the opcode definitions for a variant, nil for an illegal opcode.
+func variantOpcodes(vd *variantDef) ([256]*opcodeDef, [256]bool) {
+	var ops [256]*opcodeDef
+	var undoc [256]bool
+	for _, isa := range vd.isa {
+		for code := range isa {
+			x := isa[code]
+			ops[code] = &x
+		}
+	}
+	for code := range vd.undoc {
+		if ops[code] != nil {
+			panic(fmt.Sprintf("%s: undocumented opcode %02x is already defined", vd.name, code))
 		}
-		f[i] = fmt.Sprintf("%s,", strings.Join(l, ","))
+		x := vd.undoc[code]
+		ops[code] = &x
+		undoc[code] = true
 	}
+	return ops, undoc
+}
+
+// variantIdent returns the identifier suffix for a variant.
+func variantIdent(vd *variantDef) string {
+	return strings.ToUpper(vd.name)
+}
 
-	s := make([]string, 3)
-	s[0] = "var opcodeTable = [256]opFunc{"
-	s[1] = strings.Join(f, "\n")
-	s[2] = "}"
+func genVariantTable(vd *variantDef) string {
+	ops, undoc := variantOpcodes(vd)
+	s := make([]string, 0, 260)
+	s = append(s, fmt.Sprintf("// opcodeTable%s is the %s dispatch and metadata table.", variantIdent(vd), vd.name))
+	s = append(s, fmt.Sprintf("var opcodeTable%s = [256]opcode{", variantIdent(vd)))
+	for code, x := range ops {
+		if x == nil {
+			s = append(s, fmt.Sprintf("opcodeIllegal, // %02x", code))
+			continue
+		}
+		if _, ok := modeName[x.mode]; !ok {
+			panic(fmt.Sprintf("%s: bad address mode for opcode %02x", vd.name, code))
+		}
+		s = append(s, fmt.Sprintf("{%s, %q, %s, %d, %d, %t, 0x%02x, 0x%02x, %t}, // %02x",
+			x.handler, x.ins, modeName[x.mode], insLengthByMode[x.mode], x.cycles, x.penalty, x.flagsR, x.flagsW, undoc[code], code))
+	}
+	s = append(s, "}")
 	return strings.Join(s, "\n")
 }
 
-// GenOpcodeFunctions generates opcode table and template functions.
-func GenOpcodeFunctions() string {
-	s := make([]string, 3)
-	s[0] = genOpcodeFunctions()
-	s[1] = "type opFunc func(m *M6502) uint"
-	s[2] = genOpcodeTable()
-	return strings.Join(s, "\n\n")
+// GenOpcodeTables generates the dispatch and metadata tables for all CPU variants.
+func GenOpcodeTables() string {
+	s := make([]string, 0, 16)
+	s = append(s, "// Code generated by cmd/gen. DO NOT EDIT.")
+	s = append(s, "package cpu")
+
+	v := make([]string, 0, len(variantDefs)+2)
+	v = append(v, "// variants are the supported CPU variants.")
+	v = append(v, "var variants = []variantInfo{")
+	for i := range variantDefs {
+		vd := &variantDefs[i]
+		v = append(v, fmt.Sprintf("Variant%s: {%q, &opcodeTable%s, %t, %t},", variantIdent(vd), vd.name, variantIdent(vd), vd.cmos, vd.decimal))
+	}
+	v = append(v, "}")
+	s = append(s, strings.Join(v, "\n"))
+
+	for i := range variantDefs {
+		s = append(s, genVariantTable(&variantDefs[i]))
+	}
+	return strings.Join(s, "\n\n") + "\n"
+}
+
+//-----------------------------------------------------------------------------
+// cycle stepped operations
+
+// semantics returns the operation of an instruction.
+func semantics(ins string) insSem {
+	if sem, ok := insSemantics[ins]; ok {
+		return sem
+	}
+	// rmb/smb/bbr/bbs have the bit number as a suffix
+	if n := len(ins) - 1; n > 0 && ins[n] >= '0' && ins[n] <= '7' {
+		if sem, ok := insSemantics[ins[:n]]; ok {
+			sem.op = fmt.Sprintf(sem.op, ins[n]-'0')
+			return sem
+		}
+	}
+	panic(fmt.Sprintf("no semantics for %s", ins))
+}
+
+// tickMap is a generated map of cycle stepped operations.
+type tickMap struct {
+	name    string              // map name
+	comment string              // map comment
+	sig     string              // function signature
+	ops     map[string][]string // operation lines by instruction
+}
+
+func (t *tickMap) gen() string {
+	names := make([]string, 0, len(t.ops))
+	for ins := range t.ops {
+		names = append(names, ins)
+	}
+	sort.Strings(names)
+	s := []string{
+		fmt.Sprintf("// %s %s", t.name, t.comment),
+		fmt.Sprintf("var %s = map[string]func%s{", t.name, t.sig),
+	}
+	for _, ins := range names {
+		if len(t.ops[ins]) == 0 {
+			s = append(s, fmt.Sprintf("%q: func%s {},", ins, t.sig))
+			continue
+		}
+		s = append(s, fmt.Sprintf("%q: func%s {", ins, t.sig))
+		s = append(s, t.ops[ins]...)
+		s = append(s, "},")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "\n")
+}
+
+// tickOperation returns the operation of an instruction without the cycle count.
+// The cycle stepped core counts the extra clocks itself.
+func tickOperation(op string) []string {
+	return strings.Split(strings.TrimPrefix(op, "n += "), "\n")
+}
 
+// GenTickOps generates the operations of the cycle stepped core.
+// The stack instructions are split into the push or pull (done by the core)
+// and the value pushed or the operation on the value pulled.
+func GenTickOps() string {
+	impl := &tickMap{"tickImplOps", "are the implied and accumulator operations.", "(m *M6502)", map[string][]string{}}
+	read := &tickMap{"tickReadOps", "are the operations on a value read from memory.", "(m *M6502, v uint8)", map[string][]string{}}
+	imm := &tickMap{"tickImmOps", "are the immediate mode operations that differ from tickReadOps.", "(m *M6502, v uint8)", map[string][]string{}}
+	store := &tickMap{"tickStoreOps", "return the value written to memory.", "(m *M6502) uint8", map[string][]string{}}
+	rmw := &tickMap{"tickRMWOps", "are the read-modify-write operations.", "(m *M6502, v uint8) uint8", map[string][]string{}}
+	branch := &tickMap{"tickBranchOps", "are the branch conditions.", "(m *M6502) bool", map[string][]string{}}
+
+	for i := range variantDefs {
+		ops, _ := variantOpcodes(&variantDefs[i])
+		for _, x := range ops {
+			if x == nil || x.ins == "ill" {
+				continue
+			}
+			sem := semantics(x.ins)
+			op := tickOperation(sem.op)
+			switch sem.kind {
+			case semImpl:
+				switch {
+				case strings.HasPrefix(sem.op, "m.push8("):
+					store.ops[x.ins] = []string{"return " + strings.TrimSuffix(strings.TrimPrefix(sem.op, "m.push8("), ")")}
+				case strings.Contains(sem.op, "m.pop8()"):
+					read.ops[x.ins] = tickOperation(strings.Replace(sem.op, "m.pop8()", "v", 1))
+				default:
+					impl.ops[x.ins] = op
+				}
+			case semRead:
+				read.ops[x.ins] = op
+				if sem.imm != "" {
+					imm.ops[x.ins] = tickOperation(sem.imm)
+				}
+			case semWrite:
+				store.ops[x.ins] = []string{"return " + sem.op}
+			case semRMW:
+				if x.mode == amAcc {
+					impl.ops[x.ins] = append(append([]string{"v := m.A"}, op...), "m.A = v")
+				} else {
+					rmw.ops[x.ins] = append(op, "return v")
+				}
+			case semNop:
+				if x.mode == amImpl {
+					impl.ops[x.ins] = nil
+				} else {
+					read.ops[x.ins] = nil
+				}
+			case semBranch:
+				branch.ops[x.ins] = []string{"return " + sem.op}
+			case semCall:
+				// stores done by a handler give the stored value
+				if sem.op != "" {
+					n := len(op) - 1
+					store.ops[x.ins] = append(op[:n:n], "return "+op[n])
+				}
+			}
+		}
+	}
+
+	s := []string{
+		"// Code generated by cmd/gen -tick. DO NOT EDIT.",
+		"package cpu",
+	}
+	for _, t := range []*tickMap{impl, read, imm, store, rmw, branch} {
+		s = append(s, t.gen())
+	}
+	return strings.Join(s, "\n\n") + "\n"
 }
 
 //-----------------------------------------------------------------------------
@@ -0,0 +1,175 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Execution Hooks
+
+Hooks are called by Run (and Tick) around each instruction:
+
+	before   before an instruction executes
+	pc       before the instruction at an address executes (however it is reached)
+	opcode   before an instruction with a given opcode executes
+	after    after an instruction, with the cycles it used
+
+A hook that returns an error halts the CPU and Run returns a *HaltError that
+wraps it. A halt before an instruction stops it from running. When Run resumes
+at the same PC the hooks called before the halt (and the halting hook) are
+skipped, and the hooks after it are called.
+A panic in a hook or VSR is returned by Run as a *PanicError.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import "errors"
+
+//-----------------------------------------------------------------------------
+
+// Hook is an execution hook. A non-nil error halts the CPU.
+type Hook func(m *M6502) error
+
+// AfterHook is called after an instruction with the cycles it used.
+// A non-nil error halts the CPU.
+type AfterHook func(m *M6502, cycles uint) error
+
+type hooks struct {
+	before []Hook
+	after  []AfterHook
+	pc     map[uint16][]Hook
+	op     map[uint8][]Hook
+	resume bool   // skip the before hooks that have run at the resume PC
+	pc0    uint16 // resume PC
+	next   int    // resume PC: number of before hooks that have run
+}
+
+func (m *M6502) getHooks() *hooks {
+	if m.hooks == nil {
+		m.hooks = &hooks{
+			pc: make(map[uint16][]Hook),
+			op: make(map[uint8][]Hook),
+		}
+	}
+	return m.hooks
+}
+
+// OnBefore adds a hook called before each instruction.
+func (m *M6502) OnBefore(fn Hook) {
+	h := m.getHooks()
+	h.before = append(h.before, fn)
+}
+
+// OnAfter adds a hook called after each instruction.
+func (m *M6502) OnAfter(fn AfterHook) {
+	h := m.getHooks()
+	h.after = append(h.after, fn)
+}
+
+// OnPC adds a hook called when the PC reaches an address.
+func (m *M6502) OnPC(adr uint16, fn Hook) {
+	h := m.getHooks()
+	h.pc[adr] = append(h.pc[adr], fn)
+}
+
+// RemovePC removes the hooks for an address.
+func (m *M6502) RemovePC(adr uint16) {
+	if m.hooks != nil {
+		delete(m.hooks.pc, adr)
+	}
+}
+
+// OnOpcode adds a hook called before each instruction with the opcode.
+func (m *M6502) OnOpcode(op uint8, fn Hook) {
+	h := m.getHooks()
+	h.op[op] = append(h.op[op], fn)
+}
+
+// RemoveOpcode removes the hooks for an opcode.
+func (m *M6502) RemoveOpcode(op uint8) {
+	if m.hooks != nil {
+		delete(m.hooks.op, op)
+	}
+}
+
+// ClearHooks removes all hooks.
+func (m *M6502) ClearHooks() {
+	m.hooks = nil
+}
+
+//-----------------------------------------------------------------------------
+
+// protect calls a hook or VSR. A panic is returned as an error.
+func (m *M6502) protect(fn func() error) (err error) {
+	pc := m.PC
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{PC: pc, Value: r}
+		}
+	}()
+	return fn()
+}
+
+// halt records the error from a hook (if any).
+func (m *M6502) halt(err error) error {
+	if err == nil {
+		return nil
+	}
+	var perr *PanicError
+	if !errors.As(err, &perr) {
+		err = &HaltError{PC: m.PC, Err: err}
+	}
+	if m.halted == nil {
+		m.halted = err
+	}
+	return err
+}
+
+// hook calls a hook list, skipping the first skip hooks.
+// It returns the number of hooks in the list and (if any) the halt error.
+func (m *M6502) hook(list []Hook, skip int) (int, error) {
+	for i, fn := range list {
+		if i < skip {
+			continue
+		}
+		if err := m.halt(m.protect(func() error { return fn(m) })); err != nil {
+			return i, err
+		}
+	}
+	return len(list), nil
+}
+
+// hookBefore calls the before, pc and opcode hooks.
+func (m *M6502) hookBefore(op uint8) error {
+	h := m.hooks
+	skip := 0
+	if h.resume {
+		h.resume = false
+		if m.PC == h.pc0 {
+			skip = h.next
+		}
+	}
+	called := 0
+	for _, list := range [3][]Hook{h.before, h.pc[m.PC], h.op[op]} {
+		n, err := m.hook(list, skip-called)
+		called += n
+		if err != nil {
+			// resume after the halting hook
+			h.resume = true
+			h.pc0 = m.PC
+			h.next = called + 1
+			return err
+		}
+	}
+	return nil
+}
+
+// hookAfter calls the after hooks.
+func (m *M6502) hookAfter(cycles uint) error {
+	for _, fn := range m.hooks.after {
+		if err := m.halt(m.protect(func() error { return fn(m, cycles) })); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,223 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Execution Hook Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"errors"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+// testHookStep runs one instruction with Run or Tick and returns the error.
+func testHookStep(m *M6502, tick bool) error {
+	if !tick {
+		return m.Run()
+	}
+	for {
+		err := m.Tick()
+		if err != nil || m.Sync() {
+			return err
+		}
+	}
+}
+
+// testHookCode loads a short program at $0400.
+func testHookCode(m *M6502, r *testRAM) {
+	m.PC = 0x0400
+	copy(r[0x0400:], []uint8{
+		0xa2, 0x02, // 0400 ldx #2
+		0xca,       // 0402 dex
+		0xd0, 0xfd, // 0403 bne $0402
+		0xea,             // 0405 nop
+		0x4c, 0x05, 0x04, // 0406 jmp $0405
+	})
+}
+
+//-----------------------------------------------------------------------------
+
+func TestHooks(t *testing.T) {
+	testCPUs(t, nmosVariants, func(m *M6502, r *testRAM, tick bool) {
+		testHookCode(m, r)
+		var before, pc, dex int
+		var cycles uint
+		m.OnBefore(func(m *M6502) error { before++; return nil })
+		m.OnAfter(func(m *M6502, n uint) error { cycles += n; return nil })
+		m.OnPC(0x0402, func(m *M6502) error { pc++; return nil })
+		m.OnOpcode(0xca, func(m *M6502) error { dex++; return nil })
+		start := m.Cycles()
+		for i := 0; i < 6; i++ {
+			if err := testHookStep(m, tick); err != nil {
+				t.Fatalf("%s: %v", m.variant, err)
+			}
+		}
+		if before != 6 || pc != 2 || dex != 2 {
+			t.Errorf("%s: before %d pc %d dex %d", m.variant, before, pc, dex)
+		}
+		if cycles != m.Cycles()-start {
+			t.Errorf("%s: after hooks saw %d cycles, ran %d", m.variant, cycles, m.Cycles()-start)
+		}
+		m.ClearHooks()
+	})
+}
+
+func TestHookHalt(t *testing.T) {
+	errBreak := errors.New("break")
+	testCPUs(t, nmosVariants, func(m *M6502, r *testRAM, tick bool) {
+		testHookCode(m, r)
+		m.OnPC(0x0405, func(m *M6502) error { return errBreak })
+		var err error
+		for i := 0; i < 10 && err == nil; i++ {
+			err = testHookStep(m, tick)
+		}
+		var herr *HaltError
+		if !errors.Is(err, ErrHalt) || !errors.Is(err, errBreak) || !errors.As(err, &herr) {
+			t.Fatalf("%s: got %v, want a halt", m.variant, err)
+		}
+		if herr.PC != 0x0405 || m.PC != 0x0405 || m.HaltReason() != HaltHook {
+			t.Errorf("%s: halted at %04x (pc %04x), reason %s", m.variant, herr.PC, m.PC, m.HaltReason())
+		}
+		// resume: the halted instruction runs, then the jmp returns to the hook
+		if err := testHookStep(m, tick); err != nil {
+			t.Fatalf("%s: resume: %v", m.variant, err)
+		}
+		if m.PC != 0x0406 || m.HaltReason() != HaltNone {
+			t.Errorf("%s: resumed to %04x, reason %s", m.variant, m.PC, m.HaltReason())
+		}
+		testHookStep(m, tick)
+		if err := testHookStep(m, tick); !errors.Is(err, errBreak) {
+			t.Errorf("%s: got %v, want a second halt", m.variant, err)
+		}
+	})
+}
+
+func TestHookHaltOrder(t *testing.T) {
+	errBreak := errors.New("break")
+	testCPUs(t, nmosVariants, func(m *M6502, r *testRAM, tick bool) {
+		testHookCode(m, r)
+		// the first hook halts once at $0405, then a pc hook and an opcode hook
+		var halted bool
+		var first, second, third int
+		m.OnBefore(func(m *M6502) error {
+			if m.PC != 0x0405 {
+				return nil
+			}
+			first++
+			if !halted {
+				halted = true
+				return errBreak
+			}
+			return nil
+		})
+		m.OnPC(0x0405, func(m *M6502) error {
+			second++
+			return nil
+		})
+		m.OnOpcode(r[0x0405], func(m *M6502) error {
+			third++
+			return nil
+		})
+		var err error
+		for i := 0; i < 10 && err == nil; i++ {
+			err = testHookStep(m, tick)
+		}
+		if !errors.Is(err, errBreak) || m.PC != 0x0405 || second != 0 || third != 0 {
+			t.Fatalf("%s: halt %v at %04x, hooks %d %d", m.variant, err, m.PC, second, third)
+		}
+		// resume: the hooks after the halting hook run, the halting hook doesn't
+		if err := testHookStep(m, tick); err != nil || m.PC != 0x0406 {
+			t.Fatalf("%s: resume %v at %04x", m.variant, err, m.PC)
+		}
+		if first != 1 || second != 1 || third != 1 {
+			t.Errorf("%s: hooks called %d %d %d times", m.variant, first, second, third)
+		}
+	})
+}
+
+// testReadMem counts the bus reads of each address.
+type testReadMem struct {
+	testRAM
+	reads [1 << 16]int
+}
+
+func (r *testReadMem) Read8(adr uint16) uint8 {
+	r.reads[adr]++
+	return r.testRAM[adr]
+}
+
+func (r *testReadMem) Peek8(adr uint16) uint8 {
+	return r.testRAM[adr]
+}
+
+func (r *testReadMem) Poke8(adr uint16, val uint8) {
+	r.testRAM[adr] = val
+}
+
+func TestHookHaltFetch(t *testing.T) {
+	// a halted instruction is fetched once, when it runs
+	errBreak := errors.New("break")
+	for _, tick := range []bool{false, true} {
+		r := &testReadMem{}
+		var fetches int
+		m := New6502(r, WithObserver(ObserverFunc(func(a Access) {
+			if a.Kind == AccessFetch && a.Adr == 0x0405 {
+				fetches++
+			}
+		})))
+		testHookCode(m, &r.testRAM)
+		halt := true
+		m.OnPC(0x0405, func(m *M6502) error {
+			if halt {
+				halt = false
+				return errBreak
+			}
+			return nil
+		})
+		var err error
+		for i := 0; i < 10 && err == nil; i++ {
+			err = testHookStep(m, tick)
+		}
+		// (Tick does a dummy read of $0405 for the taken branch before it)
+		reads := r.reads[0x0405]
+		if !errors.Is(err, errBreak) || fetches != 0 {
+			t.Fatalf("tick %t: halt %v, %d fetches", tick, err, fetches)
+		}
+		if err := testHookStep(m, tick); err != nil || m.PC != 0x0406 {
+			t.Fatalf("tick %t: resume %v at %04x", tick, err, m.PC)
+		}
+		if fetches != 1 || r.reads[0x0405]-reads != 1 {
+			t.Errorf("tick %t: %d fetches %d reads of the halted instruction", tick, fetches, r.reads[0x0405]-reads)
+		}
+	}
+}
+
+func TestHookPanic(t *testing.T) {
+	testCPUs(t, nmosVariants, func(m *M6502, r *testRAM, tick bool) {
+		testHookCode(m, r)
+		m.OnOpcode(0xca, func(m *M6502) error { panic("oops") })
+		testHookStep(m, tick)
+		err := testHookStep(m, tick)
+		var perr *PanicError
+		if !errors.Is(err, ErrPanic) || !errors.As(err, &perr) || perr.Value != "oops" || perr.PC != 0x0402 {
+			t.Errorf("%s: got %v, want a panic at 0402", m.variant, err)
+		}
+	})
+	// a panic in a vsr
+	testCPUs(t, cmosVariants, func(m *M6502, r *testRAM, tick bool) {
+		m.PC = 0x0400
+		copy(r[0x0400:], []uint8{0x20, 0x00, 0x10}) // jsr $1000
+		m.AddVSR(0x1000, func(m *M6502) { panic("vsr") })
+		err := testHookStep(m, tick)
+		if !errors.Is(err, ErrPanic) || m.PC != 0x1000 {
+			t.Errorf("%s: got %v at %04x, want a panic at 1000", m.variant, err, m.PC)
+		}
+	})
+}
+
+//-----------------------------------------------------------------------------
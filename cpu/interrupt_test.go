@@ -0,0 +1,605 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Interrupt Tests
+
+TestInterruptSuite runs Klaus Dormann's 6502_interrupt_test from test/test2
+(or a built in test of the same form if it hasn't been assembled there).
+The feedback port, interrupt bits and success trap are taken from its listing.
+The other tests follow its checks with a feedback port at $bffc: bit 0 is IRQ
+and bit 1 is NMI.
+
+https://github.com/Klaus2m5/6502_65C02_functional_tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+const testIntPort = 0xbffc
+
+const testIrqHandler = 0x0500
+const testNmiHandler = 0x0600
+
+// testIntMem is memory with an interrupt feedback port.
+type testIntMem struct {
+	testRAM
+	m       *M6502
+	onWrite func(adr uint16) // called on each write
+}
+
+func (r *testIntMem) Write8(adr uint16, val uint8) {
+	r.testRAM[adr] = val
+	if r.onWrite != nil {
+		r.onWrite(adr)
+	}
+	if adr == testIntPort {
+		r.m.IRQ(val&1 != 0)
+		r.m.NMILine(val&2 != 0)
+	}
+}
+
+// testIntCPUs calls fn with a fresh cpu for each variant and execution mode.
+// The IRQ/BRK handler counts in $10 and the NMI handler counts in $11.
+func testIntCPUs(t *testing.T, vs []Variant, fn func(m *M6502, r *testIntMem, tick bool)) {
+	for _, v := range vs {
+		for _, tick := range []bool{false, true} {
+			r := &testIntMem{}
+			m := newCPU(r, v, nil)
+			r.m = m
+			m.Power(true)
+			m.PC = 0x0400
+			m.S = 0xff
+			r.testRAM[IrqAddress] = testIrqHandler & 0xff
+			r.testRAM[IrqAddress+1] = uint8(testIrqHandler >> 8)
+			r.testRAM[NmiAddress] = testNmiHandler & 0xff
+			r.testRAM[NmiAddress+1] = uint8(testNmiHandler >> 8)
+			copy(r.testRAM[testIrqHandler:], []uint8{0xe6, 0x10, 0x40}) // inc $10, rti
+			copy(r.testRAM[testNmiHandler:], []uint8{0xe6, 0x11, 0x40}) // inc $11, rti
+			// cli, nop, jmp $0401
+			copy(r.testRAM[0x0400:], []uint8{0x58, 0xea, 0x4c, 0x01, 0x04})
+			fn(m, r, tick)
+		}
+	}
+}
+
+// testRun runs n instructions.
+func testRun(t *testing.T, m *M6502, tick bool, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		testStep(t, m, tick)
+	}
+}
+
+// testEnter runs until the cpu enters an interrupt handler.
+func testEnter(t *testing.T, m *M6502, tick bool) {
+	t.Helper()
+	for i := 0; i < 8; i++ {
+		if m.PC == testIrqHandler || m.PC == testNmiHandler {
+			return
+		}
+		testStep(t, m, tick)
+	}
+	t.Fatalf("%s: no interrupt handler entered", m.variant)
+}
+
+//-----------------------------------------------------------------------------
+
+func TestIRQLevel(t *testing.T) {
+	testIntCPUs(t, append(nmosVariants, cmosVariants...), func(m *M6502, r *testIntMem, tick bool) {
+		// a device holding IRQ interrupts again after each RTI
+		m.IRQ(true)
+		testRun(t, m, tick, 40)
+		n := r.testRAM[0x10]
+		if n < 4 {
+			t.Errorf("%s: held IRQ serviced %d times", m.variant, n)
+		}
+		// released
+		m.IRQ(false)
+		testRun(t, m, tick, 4)
+		n = r.testRAM[0x10]
+		testRun(t, m, tick, 40)
+		if r.testRAM[0x10] != n {
+			t.Errorf("%s: released IRQ serviced", m.variant)
+		}
+	})
+}
+
+func TestIRQAcknowledge(t *testing.T) {
+	testIntCPUs(t, append(nmosVariants, cmosVariants...), func(m *M6502, r *testIntMem, tick bool) {
+		// the handler releases the IRQ through the feedback port
+		copy(r.testRAM[testIrqHandler:], []uint8{0xe6, 0x10, 0xa9, 0x00, 0x8d, 0xfc, 0xbf, 0x40})
+		r.Write8(testIntPort, 1)
+		testRun(t, m, tick, 40)
+		if r.testRAM[0x10] != 1 {
+			t.Errorf("%s: acknowledged IRQ serviced %d times", m.variant, r.testRAM[0x10])
+		}
+	})
+}
+
+func TestIRQDisabled(t *testing.T) {
+	testIntCPUs(t, append(nmosVariants, cmosVariants...), func(m *M6502, r *testIntMem, tick bool) {
+		r.testRAM[0x0400] = 0x78 // sei
+		m.IRQ(true)
+		testRun(t, m, tick, 40)
+		if r.testRAM[0x10] != 0 {
+			t.Errorf("%s: IRQ serviced with the I flag set", m.variant)
+		}
+	})
+}
+
+func TestNMIEdge(t *testing.T) {
+	testIntCPUs(t, append(nmosVariants, cmosVariants...), func(m *M6502, r *testIntMem, tick bool) {
+		// a held NMI line interrupts once
+		r.Write8(testIntPort, 2)
+		testRun(t, m, tick, 40)
+		if r.testRAM[0x11] != 1 {
+			t.Errorf("%s: held NMI serviced %d times", m.variant, r.testRAM[0x11])
+		}
+		// the next edge interrupts again
+		r.Write8(testIntPort, 0)
+		r.Write8(testIntPort, 2)
+		testRun(t, m, tick, 40)
+		if r.testRAM[0x11] != 2 {
+			t.Errorf("%s: second NMI edge serviced %d times", m.variant, r.testRAM[0x11])
+		}
+	})
+}
+
+func TestNMIPriority(t *testing.T) {
+	testIntCPUs(t, append(nmosVariants, cmosVariants...), func(m *M6502, r *testIntMem, tick bool) {
+		// with both lines asserted the NMI is taken first
+		r.Write8(testIntPort, 3)
+		testEnter(t, m, tick)
+		if m.PC != testNmiHandler {
+			t.Errorf("%s: at %04x, want the NMI handler", m.variant, m.PC)
+		}
+	})
+}
+
+//-----------------------------------------------------------------------------
+
+func TestPushedStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		start func(m *M6502, r *testIntMem)
+		want  uint8
+	}{
+		{"brk", func(m *M6502, r *testIntMem) { r.testRAM[0x0400] = 0x00 }, flagB | flagU},
+		{"php", func(m *M6502, r *testIntMem) { r.testRAM[0x0400] = 0x08 }, flagB | flagU},
+		{"irq", func(m *M6502, r *testIntMem) { r.testRAM[0x0400] = 0xea; m.IRQ(true) }, flagU},
+		{"nmi", func(m *M6502, r *testIntMem) { r.testRAM[0x0400] = 0xea; m.NMILine(true) }, flagU},
+	}
+	for _, tt := range tests {
+		testIntCPUs(t, append(nmosVariants, cmosVariants...), func(m *M6502, r *testIntMem, tick bool) {
+			m.P = 0
+			tt.start(m, r)
+			if tt.name == "irq" || tt.name == "nmi" {
+				testEnter(t, m, tick)
+			} else {
+				testStep(t, m, tick)
+			}
+			if p := r.testRAM[stkAddress+uint16(m.S)+1]; p != tt.want {
+				t.Errorf("%s: %s pushed status %02x, want %02x", m.variant, tt.name, p, tt.want)
+			}
+		})
+	}
+}
+
+func TestRtiStatus(t *testing.T) {
+	testIntCPUs(t, append(nmosVariants, cmosVariants...), func(m *M6502, r *testIntMem, tick bool) {
+		// rti with a status byte that has B and bit 5 clear
+		r.testRAM[0x0400] = 0x40
+		r.testRAM[0x01fd] = flagC
+		r.testRAM[0x01fe] = 0x34
+		r.testRAM[0x01ff] = 0x12
+		m.S = 0xfc
+		testStep(t, m, tick)
+		if m.PC != 0x1234 || m.P != flagB|flagU|flagC {
+			t.Errorf("%s: rti to %04x with status %02x", m.variant, m.PC, m.P)
+		}
+	})
+}
+
+func TestBrkHijack(t *testing.T) {
+	arm := func(m *M6502, r *testIntMem) {
+		// an NMI arrives while BRK is pushing the return address
+		r.testRAM[0x0400] = 0x00
+		r.onWrite = func(adr uint16) {
+			if adr>>8 == 1 {
+				m.NMILine(true)
+			}
+		}
+	}
+	testIntCPUs(t, nmosVariants, func(m *M6502, r *testIntMem, tick bool) {
+		arm(m, r)
+		testStep(t, m, tick)
+		// the NMI takes the vector, the pushed B flag shows it was a BRK
+		p := r.testRAM[stkAddress+uint16(m.S)+1]
+		if m.PC != testNmiHandler || p&flagB == 0 {
+			t.Errorf("%s: BRK went to %04x with status %02x, want the NMI handler", m.variant, m.PC, p)
+		}
+		// the NMI has been serviced
+		r.onWrite = nil
+		testRun(t, m, tick, 8)
+		if r.testRAM[0x10] != 0 || r.testRAM[0x11] != 1 {
+			t.Errorf("%s: hijacked BRK ran the handlers %d/%d times", m.variant, r.testRAM[0x10], r.testRAM[0x11])
+		}
+	})
+	testIntCPUs(t, cmosVariants, func(m *M6502, r *testIntMem, tick bool) {
+		// the CMOS parts finish the BRK and then take the NMI
+		arm(m, r)
+		testStep(t, m, tick)
+		if m.PC != testIrqHandler {
+			t.Errorf("%s: BRK went to %04x, want the BRK handler", m.variant, m.PC)
+		}
+		// cycle stepped, the first instruction of the BRK handler runs
+		r.onWrite = nil
+		for i := 0; i < 2 && m.PC != testNmiHandler; i++ {
+			testStep(t, m, tick)
+		}
+		if m.PC != testNmiHandler {
+			t.Errorf("%s: at %04x after BRK, want the NMI handler", m.variant, m.PC)
+		}
+	})
+}
+
+//-----------------------------------------------------------------------------
+// WAI and STP
+
+func TestWaiIRQ(t *testing.T) {
+	testIntCPUs(t, []Variant{VariantW65C02S}, func(m *M6502, r *testIntMem, tick bool) {
+		// cli, wai, nop
+		copy(r.testRAM[0x0400:], []uint8{0x58, 0xcb, 0xea})
+		testRun(t, m, tick, 2)
+		testRun(t, m, tick, 10)
+		if !m.Waiting() || m.PC != 0x0402 || m.HaltReason() != HaltWait {
+			t.Fatalf("%s: not waiting at %04x", m.variant, m.PC)
+		}
+		// the IRQ wakes the cpu and is serviced
+		m.IRQ(true)
+		testEnter(t, m, tick)
+		if m.Waiting() || m.PC != testIrqHandler {
+			t.Errorf("%s: IRQ woke the cpu at %04x", m.variant, m.PC)
+		}
+		if ret := uint16(r.testRAM[0x01fe]) | uint16(r.testRAM[0x01ff])<<8; ret != 0x0402 {
+			t.Errorf("%s: IRQ returns to %04x, want 0402", m.variant, ret)
+		}
+	})
+}
+
+func TestWaiIRQDisabled(t *testing.T) {
+	testIntCPUs(t, []Variant{VariantW65C02S}, func(m *M6502, r *testIntMem, tick bool) {
+		// sei, wai, nop, nop
+		copy(r.testRAM[0x0400:], []uint8{0x78, 0xcb, 0xea, 0xea})
+		testRun(t, m, tick, 2)
+		if !m.Waiting() {
+			t.Fatalf("%s: not waiting", m.variant)
+		}
+		// with I set the IRQ wakes the cpu to run the next instruction
+		m.IRQ(true)
+		testRun(t, m, tick, 1)
+		if m.Waiting() || m.PC != 0x0403 || r.testRAM[0x10] != 0 {
+			t.Errorf("%s: woke to %04x, IRQ serviced %d times", m.variant, m.PC, r.testRAM[0x10])
+		}
+	})
+}
+
+func TestWaiNMI(t *testing.T) {
+	testIntCPUs(t, []Variant{VariantW65C02S}, func(m *M6502, r *testIntMem, tick bool) {
+		// sei, wai, nop
+		copy(r.testRAM[0x0400:], []uint8{0x78, 0xcb, 0xea})
+		testRun(t, m, tick, 5)
+		if !m.Waiting() {
+			t.Fatalf("%s: not waiting", m.variant)
+		}
+		// the NMI is serviced with I set
+		r.Write8(testIntPort, 2)
+		testEnter(t, m, tick)
+		if m.Waiting() || m.PC != testNmiHandler {
+			t.Errorf("%s: NMI woke the cpu at %04x", m.variant, m.PC)
+		}
+		testRun(t, m, tick, 3)
+		if r.testRAM[0x11] != 1 || m.PC != 0x0403 {
+			t.Errorf("%s: NMI serviced %d times, returned to %04x", m.variant, r.testRAM[0x11], m.PC)
+		}
+	})
+}
+
+func TestStpReset(t *testing.T) {
+	testIntCPUs(t, []Variant{VariantW65C02S}, func(m *M6502, r *testIntMem, tick bool) {
+		// cli, stp
+		copy(r.testRAM[0x0400:], []uint8{0x58, 0xdb})
+		r.testRAM[RstAddress] = 0x00
+		r.testRAM[RstAddress+1] = 0x06
+		copy(r.testRAM[0x0600:], []uint8{0xea, 0xea})
+		testRun(t, m, tick, 2)
+		if !m.Stopped() || m.HaltReason() != HaltStop {
+			t.Fatalf("%s: not stopped", m.variant)
+		}
+		// interrupts do not restart the clock
+		cycles := m.cycles
+		r.Write8(testIntPort, 3)
+		testRun(t, m, tick, 10)
+		if !m.Stopped() || m.PC != 0x0402 || m.cycles != cycles || r.testRAM[0x10] != 0 || r.testRAM[0x11] != 0 {
+			t.Fatalf("%s: stopped cpu ran to %04x", m.variant, m.PC)
+		}
+		// reset does
+		r.Write8(testIntPort, 0)
+		m.Reset()
+		if m.Stopped() || m.PC != 0x0600 {
+			t.Fatalf("%s: reset to %04x", m.variant, m.PC)
+		}
+		testRun(t, m, tick, 2)
+		if m.PC != 0x0602 {
+			t.Errorf("%s: ran to %04x after reset", m.variant, m.PC)
+		}
+	})
+}
+
+//-----------------------------------------------------------------------------
+// Klaus Dormann interrupt test suite
+
+// interruptTest is the Klaus Dormann 6502 interrupt test.
+const interruptTest = "../test/test2/6502_interrupt_test"
+
+var (
+	lstEquate = regexp.MustCompile(`^([0-9a-f]{4}) =\s+(\w+)\s*=`)
+	lstCode   = regexp.MustCompile(`^([0-9a-f]{4}) :`)
+)
+
+// listing is the symbols and success trap address of an as65 listing.
+type listing struct {
+	equ     map[string]uint16
+	success uint16
+}
+
+// readListing reads the equates and the success trap address from a listing.
+func readListing(name string) (*listing, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	l := &listing{equ: make(map[string]uint16)}
+	found, inSuccess := false, false
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if x := lstEquate.FindStringSubmatch(line); x != nil {
+			v, _ := strconv.ParseUint(x[1], 16, 16)
+			l.equ[x[2]] = uint16(v)
+			continue
+		}
+		// the success macro is followed by its trap
+		fields := strings.Fields(line)
+		if len(fields) != 0 && fields[0] == "success" && (len(fields) == 1 || fields[1] != "macro") {
+			inSuccess = true
+			continue
+		}
+		if x := lstCode.FindStringSubmatch(line); x != nil && inSuccess && !found {
+			v, _ := strconv.ParseUint(x[1], 16, 16)
+			l.success = uint16(v)
+			found = true
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%s: no success trap", name)
+	}
+	return l, nil
+}
+
+// intSuite is an interrupt test in the form of the Klaus Dormann test. It is
+// run by TestInterruptSuite when the assembled test isn't in test/test2.
+// The IRQ/BRK handler counts in $10, saves the pushed status in $12 and
+// releases the IRQ line when the count reaches the limit in $14. The NMI
+// handler counts in $11. A failed check traps at $0476.
+var intSuite = map[uint16][]uint8{
+	0x0400: {
+		0x78, // start: sei
+		// the IRQ handler releases the line after one service
+		0xa9, 0x01, // lda #$01
+		0x85, 0x14, // sta $14
+		// the IRQ is masked by I
+		0xa9, 0x01, // lda #$01
+		0x8d, 0xfc, 0xbf, // sta $bffc
+		0xea,       // nop
+		0xea,       // nop
+		0xa5, 0x10, // lda $10
+		0xc9, 0x00, // cmp #$00
+		0xd0, 0x64, // bne fail
+		// serviced with I clear, pushed with B clear and bit 5 set
+		0x58,       // cli
+		0xea,       // nop
+		0x78,       // sei
+		0xa5, 0x10, // lda $10
+		0xc9, 0x01, // cmp #$01
+		0xd0, 0x5b, // bne fail
+		0xa5, 0x12, // lda $12
+		0x29, 0x30, // and #$30
+		0xc9, 0x20, // cmp #$20
+		0xd0, 0x53, // bne fail
+		// BRK is pushed with B, bit 5 and I set
+		0x00,       // brk
+		0xea,       // nop
+		0xa5, 0x10, // lda $10
+		0xc9, 0x02, // cmp #$02
+		0xd0, 0x4b, // bne fail
+		0xa5, 0x12, // lda $12
+		0x29, 0x34, // and #$34
+		0xc9, 0x34, // cmp #$34
+		0xd0, 0x43, // bne fail
+		// a held IRQ is serviced until the device releases it (count 5)
+		0xa9, 0x05, // lda #$05
+		0x85, 0x14, // sta $14
+		0xa9, 0x01, // lda #$01
+		0x8d, 0xfc, 0xbf, // sta $bffc
+		0x58,       // cli
+		0xea,       // nop
+		0xea,       // nop
+		0xea,       // nop
+		0xea,       // nop
+		0x78,       // sei
+		0xa5, 0x10, // lda $10
+		0xc9, 0x05, // cmp #$05
+		0xd0, 0x2e, // bne fail
+		// NMI is taken on the edge of the line, with I set
+		0xa9, 0x02, // lda #$02
+		0x8d, 0xfc, 0xbf, // sta $bffc
+		0xea,       // nop
+		0xa5, 0x11, // lda $11
+		0xc9, 0x01, // cmp #$01
+		0xd0, 0x22, // bne fail
+		0xea,       // nop
+		0xea,       // nop
+		0xa5, 0x11, // lda $11
+		0xc9, 0x01, // cmp #$01
+		0xd0, 0x1a, // bne fail
+		// the line is released and asserted again
+		0xa9, 0x00, // lda #$00
+		0x8d, 0xfc, 0xbf, // sta $bffc
+		0xa9, 0x02, // lda #$02
+		0x8d, 0xfc, 0xbf, // sta $bffc
+		0xea,       // nop
+		0xa5, 0x11, // lda $11
+		0xc9, 0x02, // cmp #$02
+		0xd0, 0x09, // bne fail
+		0xa5, 0x10, // lda $10
+		0xc9, 0x05, // cmp #$05
+		0xd0, 0x03, // bne fail
+		0x4c, 0x73, 0x04, // success: jmp success
+		0x4c, 0x76, 0x04, // fail: jmp fail
+	},
+	// irq/brk handler
+	0x0500: {
+		0x48,             // pha
+		0x8a,             // txa
+		0x48,             // pha
+		0xba,             // tsx
+		0xbd, 0x03, 0x01, // lda $0103,x
+		0x85, 0x12, // sta $12
+		0x68,       // pla
+		0xaa,       // tax
+		0xe6, 0x10, // inc $10
+		0xa5, 0x10, // lda $10
+		0xc5, 0x14, // cmp $14
+		0x90, 0x05, // bcc keep
+		0xa9, 0x00, // lda #$00
+		0x8d, 0xfc, 0xbf, // sta $bffc
+		0x68, // keep: pla
+		0x40, // rti
+	},
+	// nmi handler
+	0x0600: {
+		0xe6, 0x11, // inc $11
+		0x40, // rti
+	},
+	NmiAddress: {0x00, 0x06, 0x00, 0x04, 0x00, 0x05},
+}
+
+// intSuiteListing is the listing of intSuite.
+var intSuiteListing = listing{
+	equ: map[string]uint16{
+		"I_port":       testIntPort,
+		"IRQ_bit":      0,
+		"NMI_bit":      1,
+		"code_segment": 0x0400,
+	},
+	success: 0x0473,
+}
+
+// loadInterruptTest returns the interrupt test image and its listing.
+func loadInterruptTest() ([]uint8, *listing, error) {
+	img, err := ioutil.ReadFile(interruptTest + ".bin")
+	if os.IsNotExist(err) {
+		img = make([]uint8, 1<<16)
+		for adr, code := range intSuite {
+			copy(img[adr:], code)
+		}
+		return img, &intSuiteListing, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	l, err := readListing(interruptTest + ".lst")
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, l, nil
+}
+
+// feedbackMem is memory with the interrupt test feedback register.
+// A set bit asserts the interrupt line.
+type feedbackMem struct {
+	testRAM
+	m        *M6502
+	port     uint16
+	irq, nmi uint8 // interrupt bit masks
+}
+
+func (r *feedbackMem) Write8(adr uint16, val uint8) {
+	r.testRAM[adr] = val
+	if adr == r.port {
+		r.m.IRQ(val&r.irq != 0)
+		r.m.NMILine(val&r.nmi != 0)
+	}
+}
+
+func TestInterruptSuite(t *testing.T) {
+	img, l, err := loadInterruptTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"I_port", "IRQ_bit", "NMI_bit", "code_segment"} {
+		if _, ok := l.equ[k]; !ok {
+			t.Fatalf("listing has no %s", k)
+		}
+	}
+	for _, v := range []Variant{Variant6502, Variant65C02} {
+		r := &feedbackMem{
+			port: l.equ["I_port"],
+			irq:  1 << l.equ["IRQ_bit"],
+			nmi:  1 << l.equ["NMI_bit"],
+		}
+		copy(r.testRAM[:], img)
+		m := newCPU(r, v, nil)
+		r.m = m
+		m.PC = l.equ["code_segment"]
+		err := m.RunUntil(context.Background(), nil)
+		var stuck *StuckError
+		if !errors.As(err, &stuck) || m.PC != l.success {
+			t.Errorf("%s: %v, want the success trap at %04x", v, err, l.success)
+		}
+	}
+}
+
+func TestReadListing(t *testing.T) {
+	// the functional test listing has the same format
+	l, err := readListing("../test/test2/6502_functional_test.lst")
+	if err != nil {
+		t.Skip(err)
+	}
+	if l.success != 0x3469 || l.equ["code_segment"] != 0x0400 {
+		t.Errorf("success %04x code_segment %04x", l.success, l.equ["code_segment"])
+	}
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,188 @@
+//-----------------------------------------------------------------------------
+/*
+
+Interrupt Sources
+
+The IRQ and NMI lines are open collector: any device can pull them low and a
+line stays asserted until every device has released it. Devices register a
+named source on a line and assert or clear it independently of each other.
+A source name is unique: it can't be used on both lines.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import "fmt"
+
+//-----------------------------------------------------------------------------
+
+// IntSource is a device output driving an interrupt line.
+type IntSource struct {
+	ints     *Interrupts
+	name     string
+	nmi      bool // drives NMI (else IRQ)
+	asserted bool
+}
+
+// Name returns the name of the interrupt source.
+func (s *IntSource) Name() string {
+	return s.name
+}
+
+// NMI returns true if the source drives the NMI line.
+func (s *IntSource) NMI() bool {
+	return s.nmi
+}
+
+// Asserted returns true if the source is asserting its line.
+func (s *IntSource) Asserted() bool {
+	return s.asserted
+}
+
+// Set asserts (true) or clears (false) the source.
+func (s *IntSource) Set(state bool) {
+	if s.asserted != state {
+		s.asserted = state
+		s.ints.update()
+	}
+}
+
+// Assert asserts the source.
+func (s *IntSource) Assert() {
+	s.Set(true)
+}
+
+// Clear clears the source.
+func (s *IntSource) Clear() {
+	s.Set(false)
+}
+
+func (s *IntSource) String() string {
+	line := "irq"
+	if s.nmi {
+		line = "nmi"
+	}
+	state := "clear"
+	if s.asserted {
+		state = "asserted"
+	}
+	return fmt.Sprintf("%-12s %s %s", s.name, line, state)
+}
+
+//-----------------------------------------------------------------------------
+
+// Interrupts wire-ORs the interrupt sources onto the IRQ and NMI lines.
+type Interrupts struct {
+	irq     func(state bool) // drive the IRQ line
+	nmi     func(state bool) // drive the NMI line
+	sources []*IntSource
+	irqLine bool
+	nmiLine bool
+}
+
+// NewInterrupts returns interrupt sources driving the IRQ and NMI lines with the functions.
+func NewInterrupts(irq, nmi func(state bool)) *Interrupts {
+	return &Interrupts{
+		irq: irq,
+		nmi: nmi,
+	}
+}
+
+// Interrupts returns the interrupt sources of the CPU.
+// Devices sharing a line should drive it through a source rather than IRQ or NMILine.
+func (m *M6502) Interrupts() *Interrupts {
+	if m.ints == nil {
+		m.ints = NewInterrupts(m.IRQ, m.NMILine)
+	}
+	return m.ints
+}
+
+// update drives the lines from the sources.
+func (ic *Interrupts) update() {
+	var irq, nmi bool
+	for _, s := range ic.sources {
+		if s.asserted {
+			if s.nmi {
+				nmi = true
+			} else {
+				irq = true
+			}
+		}
+	}
+	if irq != ic.irqLine {
+		ic.irqLine = irq
+		ic.irq(irq)
+	}
+	if nmi != ic.nmiLine {
+		ic.nmiLine = nmi
+		ic.nmi(nmi)
+	}
+}
+
+// add returns the named source, adding it if needed.
+func (ic *Interrupts) add(name string, nmi bool) (*IntSource, error) {
+	if s := ic.Source(name); s != nil {
+		if s.nmi != nmi {
+			return nil, fmt.Errorf("%s: %w", name, ErrSourceLine)
+		}
+		return s, nil
+	}
+	s := &IntSource{
+		ints: ic,
+		name: name,
+		nmi:  nmi,
+	}
+	ic.sources = append(ic.sources, s)
+	return s, nil
+}
+
+// IRQ returns the named source on the IRQ line.
+// It returns ErrSourceLine if the name is used on the NMI line.
+func (ic *Interrupts) IRQ(name string) (*IntSource, error) {
+	return ic.add(name, false)
+}
+
+// NMI returns the named source on the NMI line.
+// It returns ErrSourceLine if the name is used on the IRQ line.
+func (ic *Interrupts) NMI(name string) (*IntSource, error) {
+	return ic.add(name, true)
+}
+
+// Source returns the named source (nil if it doesn't exist).
+func (ic *Interrupts) Source(name string) *IntSource {
+	for _, s := range ic.sources {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Sources returns the interrupt sources in the order they were added.
+func (ic *Interrupts) Sources() []*IntSource {
+	return ic.sources
+}
+
+// Pending returns the asserted interrupt sources.
+func (ic *Interrupts) Pending() []*IntSource {
+	var pending []*IntSource
+	for _, s := range ic.sources {
+		if s.asserted {
+			pending = append(pending, s)
+		}
+	}
+	return pending
+}
+
+// IRQLine returns true if the IRQ line is asserted.
+func (ic *Interrupts) IRQLine() bool {
+	return ic.irqLine
+}
+
+// NMILine returns true if the NMI line is asserted.
+func (ic *Interrupts) NMILine() bool {
+	return ic.nmiLine
+}
+
+//-----------------------------------------------------------------------------
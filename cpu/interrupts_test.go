@@ -0,0 +1,132 @@
+//-----------------------------------------------------------------------------
+/*
+
+Interrupt Source Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"errors"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+// testLines records the changes of the interrupt lines.
+type testLines struct {
+	irq, nmi []bool
+}
+
+func newTestInts() (*Interrupts, *testLines) {
+	l := &testLines{}
+	ic := NewInterrupts(
+		func(state bool) { l.irq = append(l.irq, state) },
+		func(state bool) { l.nmi = append(l.nmi, state) },
+	)
+	return ic, l
+}
+
+// testSource returns the named source on the IRQ or NMI line.
+func testSource(t *testing.T, ic *Interrupts, name string, nmi bool) *IntSource {
+	t.Helper()
+	add := ic.IRQ
+	if nmi {
+		add = ic.NMI
+	}
+	s, err := add(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+//-----------------------------------------------------------------------------
+
+func TestWireOr(t *testing.T) {
+	ic, l := newTestInts()
+	a := testSource(t, ic, "via1", false)
+	b := testSource(t, ic, "via2", false)
+	a.Assert()
+	b.Assert()
+	// the line stays asserted until both sources clear
+	a.Clear()
+	if !ic.IRQLine() || len(l.irq) != 1 {
+		t.Fatalf("line %t after one source cleared, changes %v", ic.IRQLine(), l.irq)
+	}
+	b.Clear()
+	if ic.IRQLine() || len(l.irq) != 2 || l.irq[1] {
+		t.Fatalf("line %t after both sources cleared, changes %v", ic.IRQLine(), l.irq)
+	}
+	// clearing a clear source doesn't drive the line
+	a.Clear()
+	if len(l.irq) != 2 || len(l.nmi) != 0 {
+		t.Errorf("changes irq %v nmi %v", l.irq, l.nmi)
+	}
+}
+
+func TestPending(t *testing.T) {
+	ic, _ := newTestInts()
+	a := testSource(t, ic, "a", false)
+	b := testSource(t, ic, "b", true)
+	c := testSource(t, ic, "c", false)
+	if len(ic.Pending()) != 0 {
+		t.Fatalf("pending %v", ic.Pending())
+	}
+	c.Assert()
+	b.Assert()
+	p := ic.Pending()
+	if len(p) != 2 || p[0] != b || p[1] != c {
+		t.Fatalf("pending %v", p)
+	}
+	b.Clear()
+	a.Assert()
+	p = ic.Pending()
+	if len(p) != 2 || p[0] != a || p[1] != c || !ic.IRQLine() || ic.NMILine() {
+		t.Errorf("pending %v", p)
+	}
+}
+
+func TestSourceNames(t *testing.T) {
+	ic, _ := newTestInts()
+	s := testSource(t, ic, "timer", false)
+	// the same name on the same line is the same source
+	if s2 := testSource(t, ic, "timer", false); s2 != s || len(ic.Sources()) != 1 {
+		t.Errorf("second IRQ(\"timer\") is a new source")
+	}
+	// a name can't be used on the other line
+	if s2, err := ic.NMI("timer"); s2 != nil || !errors.Is(err, ErrSourceLine) {
+		t.Errorf("NMI(\"timer\"): %v", err)
+	}
+	if ic.Source("timer") != s || ic.Source("none") != nil {
+		t.Errorf("source lookup")
+	}
+}
+
+func TestSourceNMIEdge(t *testing.T) {
+	testIntCPUs(t, append(nmosVariants, cmosVariants...), func(m *M6502, r *testIntMem, tick bool) {
+		ints := m.Interrupts()
+		a := testSource(t, ints, "a", true)
+		b := testSource(t, ints, "b", true)
+		a.Assert()
+		testRun(t, m, tick, 20)
+		// a second source on the asserted line is not a new edge
+		b.Assert()
+		a.Clear()
+		testRun(t, m, tick, 20)
+		if r.testRAM[0x11] != 1 {
+			t.Fatalf("%s: NMI serviced %d times, want 1", m.variant, r.testRAM[0x11])
+		}
+		// the line is released and asserted again
+		b.Clear()
+		a.Assert()
+		testRun(t, m, tick, 20)
+		if r.testRAM[0x11] != 2 {
+			t.Errorf("%s: NMI serviced %d times, want 2", m.variant, r.testRAM[0x11])
+		}
+	})
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,513 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 CPU Instruction Sets
+
+Each CPU variant is described declaratively as a stack of instruction sets.
+cmd/gen builds the per-variant dispatch and metadata tables (opcodes.go) from
+these descriptions, so the emulator, disassembler and coverage all use the
+same opcode definitions.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+//-----------------------------------------------------------------------------
+
+// opcodeDef is the declarative description of an opcode.
+type opcodeDef struct {
+	ins     string  // mneumonic
+	mode    adrMode // address mode (determines the length)
+	cycles  uint    // base cycles
+	penalty bool    // +1 cycle when indexing crosses a page
+	flagsR  uint8   // status flags read
+	flagsW  uint8   // status flags written
+	handler string  // handler function, returns cycles beyond the base cycles
+}
+
+// variantDef describes a CPU variant.
+type variantDef struct {
+	name    string                // variant name
+	isa     []map[uint8]opcodeDef // instruction sets, later sets override earlier sets
+	undoc   map[uint8]opcodeDef   // undocumented opcodes (illegal with the Strict option)
+	cmos    bool                  // CMOS: decimal flags, JMP indirect fix, D cleared on interrupt
+	decimal bool                  // decimal mode is implemented
+}
+
+// variantDefs are the CPU variant descriptions.
+var variantDefs = []variantDef{
+	Variant6502:    {"6502", []map[uint8]opcodeDef{isaNMOS}, isaUndocumented, false, true},
+	Variant65C02:   {"65c02", []map[uint8]opcodeDef{isaNMOS, isaCMOS, isaCMOSNop}, nil, true, true},
+	VariantR65C02:  {"r65c02", []map[uint8]opcodeDef{isaNMOS, isaCMOS, isaCMOSNop, isaRockwell}, nil, true, true},
+	VariantW65C02S: {"w65c02s", []map[uint8]opcodeDef{isaNMOS, isaCMOS, isaCMOSNop, isaRockwell, isaWDC}, nil, true, true},
+	Variant2A03:    {"2a03", []map[uint8]opcodeDef{isaNMOS}, isaUndocumented, false, false},
+	Variant6510:    {"6510", []map[uint8]opcodeDef{isaNMOS}, isaUndocumented, false, true},
+}
+
+//-----------------------------------------------------------------------------
+
+// isaNMOS is the documented NMOS 6502 instruction set.
+var isaNMOS = map[uint8]opcodeDef{
+
+	0x00: {"brk", amImpl, 7, false, 0, flagI, "op00"},
+	0x10: {"bpl", amRel, 2, false, flagN, 0, "op10"},
+	0x20: {"jsr", amAbs, 6, false, 0, 0, "op20"},
+	0x30: {"bmi", amRel, 2, false, flagN, 0, "op30"},
+	0x40: {"rti", amImpl, 6, false, 0, flagAll, "op40"},
+	0x50: {"bvc", amRel, 2, false, flagV, 0, "op50"},
+	0x60: {"rts", amImpl, 6, false, 0, 0, "op60"},
+	0x70: {"bvs", amRel, 2, false, flagV, 0, "op70"},
+	0x90: {"bcc", amRel, 2, false, flagC, 0, "op90"},
+	0xa0: {"ldy", amImm, 2, false, 0, flagNZ, "opA0"},
+	0xb0: {"bcs", amRel, 2, false, flagC, 0, "opB0"},
+	0xc0: {"cpy", amImm, 2, false, 0, flagNZC, "opC0"},
+	0xd0: {"bne", amRel, 2, false, flagZ, 0, "opD0"},
+	0xe0: {"cpx", amImm, 2, false, 0, flagNZC, "opE0"},
+	0xf0: {"beq", amRel, 2, false, flagZ, 0, "opF0"},
+
+	0x01: {"ora", amXInd, 6, false, 0, flagNZ, "op01"},
+	0x11: {"ora", amIndY, 5, true, 0, flagNZ, "op11"},
+	0x21: {"and", amXInd, 6, false, 0, flagNZ, "op21"},
+	0x31: {"and", amIndY, 5, true, 0, flagNZ, "op31"},
+	0x41: {"eor", amXInd, 6, false, 0, flagNZ, "op41"},
+	0x51: {"eor", amIndY, 5, true, 0, flagNZ, "op51"},
+	0x61: {"adc", amXInd, 6, false, flagC | flagD, flagNVZC, "op61"},
+	0x71: {"adc", amIndY, 5, true, flagC | flagD, flagNVZC, "op71"},
+	0x81: {"sta", amXInd, 6, false, 0, 0, "op81"},
+	0x91: {"sta", amIndY, 6, false, 0, 0, "op91"},
+	0xa1: {"lda", amXInd, 6, false, 0, flagNZ, "opA1"},
+	0xb1: {"lda", amIndY, 5, true, 0, flagNZ, "opB1"},
+	0xc1: {"cmp", amXInd, 6, false, 0, flagNZC, "opC1"},
+	0xd1: {"cmp", amIndY, 5, true, 0, flagNZC, "opD1"},
+	0xe1: {"sbc", amXInd, 6, false, flagC | flagD, flagNVZC, "opE1"},
+	0xf1: {"sbc", amIndY, 5, true, flagC | flagD, flagNVZC, "opF1"},
+
+	0xa2: {"ldx", amImm, 2, false, 0, flagNZ, "opA2"},
+
+	0x24: {"bit", amZpg, 3, false, 0, flagNVZ, "op24"},
+	0x84: {"sty", amZpg, 3, false, 0, 0, "op84"},
+	0x94: {"sty", amZpgX, 4, false, 0, 0, "op94"},
+	0xa4: {"ldy", amZpg, 3, false, 0, flagNZ, "opA4"},
+	0xb4: {"ldy", amZpgX, 4, false, 0, flagNZ, "opB4"},
+	0xc4: {"cpy", amZpg, 3, false, 0, flagNZC, "opC4"},
+	0xe4: {"cpx", amZpg, 3, false, 0, flagNZC, "opE4"},
+
+	0x05: {"ora", amZpg, 3, false, 0, flagNZ, "op05"},
+	0x15: {"ora", amZpgX, 4, false, 0, flagNZ, "op15"},
+	0x25: {"and", amZpg, 3, false, 0, flagNZ, "op25"},
+	0x35: {"and", amZpgX, 4, false, 0, flagNZ, "op35"},
+	0x45: {"eor", amZpg, 3, false, 0, flagNZ, "op45"},
+	0x55: {"eor", amZpgX, 4, false, 0, flagNZ, "op55"},
+	0x65: {"adc", amZpg, 3, false, flagC | flagD, flagNVZC, "op65"},
+	0x75: {"adc", amZpgX, 4, false, flagC | flagD, flagNVZC, "op75"},
+	0x85: {"sta", amZpg, 3, false, 0, 0, "op85"},
+	0x95: {"sta", amZpgX, 4, false, 0, 0, "op95"},
+	0xa5: {"lda", amZpg, 3, false, 0, flagNZ, "opA5"},
+	0xb5: {"lda", amZpgX, 4, false, 0, flagNZ, "opB5"},
+	0xc5: {"cmp", amZpg, 3, false, 0, flagNZC, "opC5"},
+	0xd5: {"cmp", amZpgX, 4, false, 0, flagNZC, "opD5"},
+	0xe5: {"sbc", amZpg, 3, false, flagC | flagD, flagNVZC, "opE5"},
+	0xf5: {"sbc", amZpgX, 4, false, flagC | flagD, flagNVZC, "opF5"},
+
+	0x06: {"asl", amZpg, 5, false, 0, flagNZC, "op06"},
+	0x16: {"asl", amZpgX, 6, false, 0, flagNZC, "op16"},
+	0x26: {"rol", amZpg, 5, false, flagC, flagNZC, "op26"},
+	0x36: {"rol", amZpgX, 6, false, flagC, flagNZC, "op36"},
+	0x46: {"lsr", amZpg, 5, false, 0, flagNZC, "op46"},
+	0x56: {"lsr", amZpgX, 6, false, 0, flagNZC, "op56"},
+	0x66: {"ror", amZpg, 5, false, flagC, flagNZC, "op66"},
+	0x76: {"ror", amZpgX, 6, false, flagC, flagNZC, "op76"},
+	0x86: {"stx", amZpg, 3, false, 0, 0, "op86"},
+	0x96: {"stx", amZpgY, 4, false, 0, 0, "op96"},
+	0xa6: {"ldx", amZpg, 3, false, 0, flagNZ, "opA6"},
+	0xb6: {"ldx", amZpgY, 4, false, 0, flagNZ, "opB6"},
+	0xc6: {"dec", amZpg, 5, false, 0, flagNZ, "opC6"},
+	0xd6: {"dec", amZpgX, 6, false, 0, flagNZ, "opD6"},
+	0xe6: {"inc", amZpg, 5, false, 0, flagNZ, "opE6"},
+	0xf6: {"inc", amZpgX, 6, false, 0, flagNZ, "opF6"},
+
+	0x08: {"php", amImpl, 3, false, flagAll, 0, "op08"},
+	0x18: {"clc", amImpl, 2, false, 0, flagC, "op18"},
+	0x28: {"plp", amImpl, 4, false, 0, flagAll, "op28"},
+	0x38: {"sec", amImpl, 2, false, 0, flagC, "op38"},
+	0x48: {"pha", amImpl, 3, false, 0, 0, "op48"},
+	0x58: {"cli", amImpl, 2, false, 0, flagI, "op58"},
+	0x68: {"pla", amImpl, 4, false, 0, flagNZ, "op68"},
+	0x78: {"sei", amImpl, 2, false, 0, flagI, "op78"},
+	0x88: {"dey", amImpl, 2, false, 0, flagNZ, "op88"},
+	0x98: {"tya", amImpl, 2, false, 0, flagNZ, "op98"},
+	0xa8: {"tay", amImpl, 2, false, 0, flagNZ, "opA8"},
+	0xb8: {"clv", amImpl, 2, false, 0, flagV, "opB8"},
+	0xc8: {"iny", amImpl, 2, false, 0, flagNZ, "opC8"},
+	0xd8: {"cld", amImpl, 2, false, 0, flagD, "opD8"},
+	0xe8: {"inx", amImpl, 2, false, 0, flagNZ, "opE8"},
+	0xf8: {"sed", amImpl, 2, false, 0, flagD, "opF8"},
+
+	0x09: {"ora", amImm, 2, false, 0, flagNZ, "op09"},
+	0x19: {"ora", amAbsY, 4, true, 0, flagNZ, "op19"},
+	0x29: {"and", amImm, 2, false, 0, flagNZ, "op29"},
+	0x39: {"and", amAbsY, 4, true, 0, flagNZ, "op39"},
+	0x49: {"eor", amImm, 2, false, 0, flagNZ, "op49"},
+	0x59: {"eor", amAbsY, 4, true, 0, flagNZ, "op59"},
+	0x69: {"adc", amImm, 2, false, flagC | flagD, flagNVZC, "op69"},
+	0x79: {"adc", amAbsY, 4, true, flagC | flagD, flagNVZC, "op79"},
+	0x99: {"sta", amAbsY, 5, false, 0, 0, "op99"},
+	0xa9: {"lda", amImm, 2, false, 0, flagNZ, "opA9"},
+	0xb9: {"lda", amAbsY, 4, true, 0, flagNZ, "opB9"},
+	0xc9: {"cmp", amImm, 2, false, 0, flagNZC, "opC9"},
+	0xd9: {"cmp", amAbsY, 4, true, 0, flagNZC, "opD9"},
+	0xe9: {"sbc", amImm, 2, false, flagC | flagD, flagNVZC, "opE9"},
+	0xf9: {"sbc", amAbsY, 4, true, flagC | flagD, flagNVZC, "opF9"},
+
+	0x0a: {"asl", amAcc, 2, false, 0, flagNZC, "op0A"},
+	0x2a: {"rol", amAcc, 2, false, flagC, flagNZC, "op2A"},
+	0x4a: {"lsr", amAcc, 2, false, 0, flagNZC, "op4A"},
+	0x6a: {"ror", amAcc, 2, false, flagC, flagNZC, "op6A"},
+	0x8a: {"txa", amImpl, 2, false, 0, flagNZ, "op8A"},
+	0x9a: {"txs", amImpl, 2, false, 0, 0, "op9A"},
+	0xaa: {"tax", amImpl, 2, false, 0, flagNZ, "opAA"},
+	0xba: {"tsx", amImpl, 2, false, 0, flagNZ, "opBA"},
+	0xca: {"dex", amImpl, 2, false, 0, flagNZ, "opCA"},
+	0xea: {"nop", amImpl, 2, false, 0, 0, "opEA"},
+
+	0x2c: {"bit", amAbs, 4, false, 0, flagNVZ, "op2C"},
+	0x4c: {"jmp", amAbs, 3, false, 0, 0, "op4C"},
+	0x6c: {"jmp", amInd, 5, false, 0, 0, "op6C"},
+	0x8c: {"sty", amAbs, 4, false, 0, 0, "op8C"},
+	0xac: {"ldy", amAbs, 4, false, 0, flagNZ, "opAC"},
+	0xbc: {"ldy", amAbsX, 4, true, 0, flagNZ, "opBC"},
+	0xcc: {"cpy", amAbs, 4, false, 0, flagNZC, "opCC"},
+	0xec: {"cpx", amAbs, 4, false, 0, flagNZC, "opEC"},
+
+	0x0d: {"ora", amAbs, 4, false, 0, flagNZ, "op0D"},
+	0x1d: {"ora", amAbsX, 4, true, 0, flagNZ, "op1D"},
+	0x2d: {"and", amAbs, 4, false, 0, flagNZ, "op2D"},
+	0x3d: {"and", amAbsX, 4, true, 0, flagNZ, "op3D"},
+	0x4d: {"eor", amAbs, 4, false, 0, flagNZ, "op4D"},
+	0x5d: {"eor", amAbsX, 4, true, 0, flagNZ, "op5D"},
+	0x6d: {"adc", amAbs, 4, false, flagC | flagD, flagNVZC, "op6D"},
+	0x7d: {"adc", amAbsX, 4, true, flagC | flagD, flagNVZC, "op7D"},
+	0x8d: {"sta", amAbs, 4, false, 0, 0, "op8D"},
+	0x9d: {"sta", amAbsX, 5, false, 0, 0, "op9D"},
+	0xad: {"lda", amAbs, 4, false, 0, flagNZ, "opAD"},
+	0xbd: {"lda", amAbsX, 4, true, 0, flagNZ, "opBD"},
+	0xcd: {"cmp", amAbs, 4, false, 0, flagNZC, "opCD"},
+	0xdd: {"cmp", amAbsX, 4, true, 0, flagNZC, "opDD"},
+	0xed: {"sbc", amAbs, 4, false, flagC | flagD, flagNVZC, "opED"},
+	0xfd: {"sbc", amAbsX, 4, true, flagC | flagD, flagNVZC, "opFD"},
+
+	0x0e: {"asl", amAbs, 6, false, 0, flagNZC, "op0E"},
+	0x1e: {"asl", amAbsX, 7, false, 0, flagNZC, "op1E"},
+	0x2e: {"rol", amAbs, 6, false, flagC, flagNZC, "op2E"},
+	0x3e: {"rol", amAbsX, 7, false, flagC, flagNZC, "op3E"},
+	0x4e: {"lsr", amAbs, 6, false, 0, flagNZC, "op4E"},
+	0x5e: {"lsr", amAbsX, 7, false, 0, flagNZC, "op5E"},
+	0x6e: {"ror", amAbs, 6, false, flagC, flagNZC, "op6E"},
+	0x7e: {"ror", amAbsX, 7, false, flagC, flagNZC, "op7E"},
+	0x8e: {"stx", amAbs, 4, false, 0, 0, "op8E"},
+	0xae: {"ldx", amAbs, 4, false, 0, flagNZ, "opAE"},
+	0xbe: {"ldx", amAbsY, 4, true, 0, flagNZ, "opBE"},
+	0xce: {"dec", amAbs, 6, false, 0, flagNZ, "opCE"},
+	0xde: {"dec", amAbsX, 7, false, 0, flagNZ, "opDE"},
+	0xee: {"inc", amAbs, 6, false, 0, flagNZ, "opEE"},
+	0xfe: {"inc", amAbsX, 7, false, 0, flagNZ, "opFE"},
+}
+
+// isaUndocumented are the undocumented NMOS 6502 opcodes.
+var isaUndocumented = map[uint8]opcodeDef{
+
+	0x80: {"nop", amImm, 2, false, 0, 0, "op80"},
+
+	0x02: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0x12: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0x22: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0x32: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0x42: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0x52: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0x62: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0x72: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0x82: {"nop", amImm, 2, false, 0, 0, "op82"},
+	0x92: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0xb2: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0xc2: {"nop", amImm, 2, false, 0, 0, "opC2"},
+	0xd2: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+	0xe2: {"nop", amImm, 2, false, 0, 0, "opE2"},
+	0xf2: {"jam", amImpl, 0, false, 0, 0, "opJAM"},
+
+	0x03: {"slo", amXInd, 8, false, 0, flagNZC, "op03"},
+	0x13: {"slo", amIndY, 8, false, 0, flagNZC, "op13"},
+	0x23: {"rla", amXInd, 8, false, flagC, flagNZC, "op23"},
+	0x33: {"rla", amIndY, 8, false, flagC, flagNZC, "op33"},
+	0x43: {"sre", amXInd, 8, false, 0, flagNZC, "op43"},
+	0x53: {"sre", amIndY, 8, false, 0, flagNZC, "op53"},
+	0x63: {"rra", amXInd, 8, false, flagC | flagD, flagNVZC, "op63"},
+	0x73: {"rra", amIndY, 8, false, flagC | flagD, flagNVZC, "op73"},
+	0x83: {"sax", amXInd, 6, false, 0, 0, "op83"},
+	0x93: {"sha", amIndY, 6, false, 0, 0, "op93"},
+	0xa3: {"lax", amXInd, 6, false, 0, flagNZ, "opA3"},
+	0xb3: {"lax", amIndY, 5, true, 0, flagNZ, "opB3"},
+	0xc3: {"dcp", amXInd, 8, false, 0, flagNZC, "opC3"},
+	0xd3: {"dcp", amIndY, 8, false, 0, flagNZC, "opD3"},
+	0xe3: {"isc", amXInd, 8, false, flagC | flagD, flagNVZC, "opE3"},
+	0xf3: {"isc", amIndY, 8, false, flagC | flagD, flagNVZC, "opF3"},
+
+	0x04: {"nop", amZpg, 3, false, 0, 0, "op04"},
+	0x14: {"nop", amZpgX, 4, false, 0, 0, "op14"},
+	0x34: {"nop", amZpgX, 4, false, 0, 0, "op34"},
+	0x44: {"nop", amZpg, 3, false, 0, 0, "op44"},
+	0x54: {"nop", amZpgX, 4, false, 0, 0, "op54"},
+	0x64: {"nop", amZpg, 3, false, 0, 0, "op64"},
+	0x74: {"nop", amZpgX, 4, false, 0, 0, "op74"},
+	0xd4: {"nop", amZpgX, 4, false, 0, 0, "opD4"},
+	0xf4: {"nop", amZpgX, 4, false, 0, 0, "opF4"},
+
+	0x07: {"slo", amZpg, 5, false, 0, flagNZC, "op07"},
+	0x17: {"slo", amZpgX, 6, false, 0, flagNZC, "op17"},
+	0x27: {"rla", amZpg, 5, false, flagC, flagNZC, "op27"},
+	0x37: {"rla", amZpgX, 6, false, flagC, flagNZC, "op37"},
+	0x47: {"sre", amZpg, 5, false, 0, flagNZC, "op47"},
+	0x57: {"sre", amZpgX, 6, false, 0, flagNZC, "op57"},
+	0x67: {"rra", amZpg, 5, false, flagC | flagD, flagNVZC, "op67"},
+	0x77: {"rra", amZpgX, 6, false, flagC | flagD, flagNVZC, "op77"},
+	0x87: {"sax", amZpg, 3, false, 0, 0, "op87"},
+	0x97: {"sax", amZpgY, 4, false, 0, 0, "op97"},
+	0xa7: {"lax", amZpg, 3, false, 0, flagNZ, "opA7"},
+	0xb7: {"lax", amZpgY, 4, false, 0, flagNZ, "opB7"},
+	0xc7: {"dcp", amZpg, 5, false, 0, flagNZC, "opC7"},
+	0xd7: {"dcp", amZpgX, 6, false, 0, flagNZC, "opD7"},
+	0xe7: {"isc", amZpg, 5, false, flagC | flagD, flagNVZC, "opE7"},
+	0xf7: {"isc", amZpgX, 6, false, flagC | flagD, flagNVZC, "opF7"},
+
+	0x89: {"nop", amImm, 2, false, 0, 0, "op89"},
+
+	0x1a: {"nop", amImpl, 2, false, 0, 0, "op1A"},
+	0x3a: {"nop", amImpl, 2, false, 0, 0, "op3A"},
+	0x5a: {"nop", amImpl, 2, false, 0, 0, "op5A"},
+	0x7a: {"nop", amImpl, 2, false, 0, 0, "op7A"},
+	0xda: {"nop", amImpl, 2, false, 0, 0, "opDA"},
+	0xfa: {"nop", amImpl, 2, false, 0, 0, "opFA"},
+
+	0x0b: {"anc", amImm, 2, false, 0, flagNZC, "op0B"},
+	0x1b: {"slo", amAbsY, 7, false, 0, flagNZC, "op1B"},
+	0x2b: {"anc", amImm, 2, false, 0, flagNZC, "op2B"},
+	0x3b: {"rla", amAbsY, 7, false, flagC, flagNZC, "op3B"},
+	0x4b: {"alr", amImm, 2, false, 0, flagNZC, "op4B"},
+	0x5b: {"sre", amAbsY, 7, false, 0, flagNZC, "op5B"},
+	0x6b: {"arr", amImm, 2, false, flagC | flagD, flagNVZC, "op6B"},
+	0x7b: {"rra", amAbsY, 7, false, flagC | flagD, flagNVZC, "op7B"},
+	0x8b: {"xaa", amImm, 2, false, 0, flagNZ, "op8B"},
+	0x9b: {"tas", amAbsY, 5, false, 0, 0, "op9B"},
+	0xab: {"lax", amImm, 2, false, 0, flagNZ, "opAB"},
+	0xbb: {"las", amAbsY, 4, true, 0, flagNZ, "opBB"},
+	0xcb: {"sbx", amImm, 2, false, 0, flagNZC, "opCB"},
+	0xdb: {"dcp", amAbsY, 7, false, 0, flagNZC, "opDB"},
+	0xeb: {"sbc", amImm, 2, false, flagC | flagD, flagNVZC, "opEB"},
+	0xfb: {"isc", amAbsY, 7, false, flagC | flagD, flagNVZC, "opFB"},
+
+	0x0c: {"nop", amAbs, 4, false, 0, 0, "op0C"},
+	0x1c: {"nop", amAbsX, 4, true, 0, 0, "op1C"},
+	0x3c: {"nop", amAbsX, 4, true, 0, 0, "op3C"},
+	0x5c: {"nop", amAbsX, 4, true, 0, 0, "op5C"},
+	0x7c: {"nop", amAbsX, 4, true, 0, 0, "op7C"},
+	0x9c: {"shy", amAbsX, 5, false, 0, 0, "op9C"},
+	0xdc: {"nop", amAbsX, 4, true, 0, 0, "opDC"},
+	0xfc: {"nop", amAbsX, 4, true, 0, 0, "opFC"},
+
+	0x9e: {"shx", amAbsY, 5, false, 0, 0, "op9E"},
+
+	0x0f: {"slo", amAbs, 6, false, 0, flagNZC, "op0F"},
+	0x1f: {"slo", amAbsX, 7, false, 0, flagNZC, "op1F"},
+	0x2f: {"rla", amAbs, 6, false, flagC, flagNZC, "op2F"},
+	0x3f: {"rla", amAbsX, 7, false, flagC, flagNZC, "op3F"},
+	0x4f: {"sre", amAbs, 6, false, 0, flagNZC, "op4F"},
+	0x5f: {"sre", amAbsX, 7, false, 0, flagNZC, "op5F"},
+	0x6f: {"rra", amAbs, 6, false, flagC | flagD, flagNVZC, "op6F"},
+	0x7f: {"rra", amAbsX, 7, false, flagC | flagD, flagNVZC, "op7F"},
+	0x8f: {"sax", amAbs, 4, false, 0, 0, "op8F"},
+	0x9f: {"sha", amAbsY, 5, false, 0, 0, "op9F"},
+	0xaf: {"lax", amAbs, 4, false, 0, flagNZ, "opAF"},
+	0xbf: {"lax", amAbsY, 4, true, 0, flagNZ, "opBF"},
+	0xcf: {"dcp", amAbs, 6, false, 0, flagNZC, "opCF"},
+	0xdf: {"dcp", amAbsX, 7, false, 0, flagNZC, "opDF"},
+	0xef: {"isc", amAbs, 6, false, flagC | flagD, flagNVZC, "opEF"},
+	0xff: {"isc", amAbsX, 7, false, flagC | flagD, flagNVZC, "opFF"},
+}
+
+// isaCMOS are the 65C02 changes to the NMOS instruction set.
+var isaCMOS = map[uint8]opcodeDef{
+
+	0x00: {"brk", amImpl, 7, false, 0, flagI | flagD, "cmos00"},
+	0x80: {"bra", amRel, 2, false, 0, 0, "cmos80"},
+
+	0x12: {"ora", amZpgInd, 5, false, 0, flagNZ, "cmos12"},
+	0x32: {"and", amZpgInd, 5, false, 0, flagNZ, "cmos32"},
+	0x52: {"eor", amZpgInd, 5, false, 0, flagNZ, "cmos52"},
+	0x72: {"adc", amZpgInd, 5, false, flagC | flagD, flagNVZC, "cmos72"},
+	0x92: {"sta", amZpgInd, 5, false, 0, 0, "cmos92"},
+	0xb2: {"lda", amZpgInd, 5, false, 0, flagNZ, "cmosB2"},
+	0xd2: {"cmp", amZpgInd, 5, false, 0, flagNZC, "cmosD2"},
+	0xf2: {"sbc", amZpgInd, 5, false, flagC | flagD, flagNVZC, "cmosF2"},
+
+	0x04: {"tsb", amZpg, 5, false, 0, flagZ, "cmos04"},
+	0x14: {"trb", amZpg, 5, false, 0, flagZ, "cmos14"},
+	0x34: {"bit", amZpgX, 4, false, 0, flagNVZ, "cmos34"},
+	0x64: {"stz", amZpg, 3, false, 0, 0, "cmos64"},
+	0x74: {"stz", amZpgX, 4, false, 0, 0, "cmos74"},
+
+	0x89: {"bit", amImm, 2, false, 0, flagZ, "cmos89"},
+
+	0x1a: {"inc", amAcc, 2, false, 0, flagNZ, "cmos1A"},
+	0x3a: {"dec", amAcc, 2, false, 0, flagNZ, "cmos3A"},
+	0x5a: {"phy", amImpl, 3, false, 0, 0, "cmos5A"},
+	0x7a: {"ply", amImpl, 4, false, 0, flagNZ, "cmos7A"},
+	0xda: {"phx", amImpl, 3, false, 0, 0, "cmosDA"},
+	0xfa: {"plx", amImpl, 4, false, 0, flagNZ, "cmosFA"},
+
+	0x0c: {"tsb", amAbs, 6, false, 0, flagZ, "cmos0C"},
+	0x1c: {"trb", amAbs, 6, false, 0, flagZ, "cmos1C"},
+	0x3c: {"bit", amAbsX, 4, true, 0, flagNVZ, "cmos3C"},
+	0x6c: {"jmp", amInd, 6, false, 0, 0, "cmos6C"},
+	0x7c: {"jmp", amAbsXInd, 6, false, 0, 0, "cmos7C"},
+	0x9c: {"stz", amAbs, 4, false, 0, 0, "cmos9C"},
+
+	0x1e: {"asl", amAbsX, 6, true, 0, flagNZC, "cmos1E"},
+	0x3e: {"rol", amAbsX, 6, true, flagC, flagNZC, "cmos3E"},
+	0x5e: {"lsr", amAbsX, 6, true, 0, flagNZC, "cmos5E"},
+	0x7e: {"ror", amAbsX, 6, true, flagC, flagNZC, "cmos7E"},
+	0x9e: {"stz", amAbsX, 5, false, 0, 0, "cmos9E"},
+}
+
+// isaCMOSNop are the undefined 65C02 opcodes. They are NOPs with a fixed length
+// and cycle count. The Rockwell and WDC opcodes replace some of them.
+var isaCMOSNop = map[uint8]opcodeDef{
+
+	0x02: {"nop", amImm, 2, false, 0, 0, "op82"},
+	0x22: {"nop", amImm, 2, false, 0, 0, "op82"},
+	0x42: {"nop", amImm, 2, false, 0, 0, "op82"},
+	0x62: {"nop", amImm, 2, false, 0, 0, "op82"},
+	0x82: {"nop", amImm, 2, false, 0, 0, "op82"},
+	0xc2: {"nop", amImm, 2, false, 0, 0, "op82"},
+	0xe2: {"nop", amImm, 2, false, 0, 0, "op82"},
+
+	0x03: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x07: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x0b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x0f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x13: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x17: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x1b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x1f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x23: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x27: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x2b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x2f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x33: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x37: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x3b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x3f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x43: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x47: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x4b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x4f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x53: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x57: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x5b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x5f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x63: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x67: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x6b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x6f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x73: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x77: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x7b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x7f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x83: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x87: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x8b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x8f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x93: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x97: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x9b: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0x9f: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xa3: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xa7: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xab: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xaf: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xb3: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xb7: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xbb: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xbf: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xc3: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xc7: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xcb: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xcf: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xd3: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xd7: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xdb: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xdf: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xe3: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xe7: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xeb: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xef: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xf3: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xf7: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xfb: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+	0xff: {"nop", amImpl, 1, false, 0, 0, "opEA"},
+
+	0x44: {"nop", amZpg, 3, false, 0, 0, "op44"},
+	0x54: {"nop", amZpgX, 4, false, 0, 0, "op54"},
+	0xd4: {"nop", amZpgX, 4, false, 0, 0, "op54"},
+	0xf4: {"nop", amZpgX, 4, false, 0, 0, "op54"},
+
+	0x5c: {"nop", amAbs, 8, false, 0, 0, "op0C"},
+	0xdc: {"nop", amAbs, 4, false, 0, 0, "op0C"},
+	0xfc: {"nop", amAbs, 4, false, 0, 0, "op0C"},
+}
+
+// isaRockwell are the Rockwell bit manipulation opcodes.
+var isaRockwell = map[uint8]opcodeDef{
+
+	0x07: {"rmb0", amZpg, 5, false, 0, 0, "cmos07"},
+	0x17: {"rmb1", amZpg, 5, false, 0, 0, "cmos17"},
+	0x27: {"rmb2", amZpg, 5, false, 0, 0, "cmos27"},
+	0x37: {"rmb3", amZpg, 5, false, 0, 0, "cmos37"},
+	0x47: {"rmb4", amZpg, 5, false, 0, 0, "cmos47"},
+	0x57: {"rmb5", amZpg, 5, false, 0, 0, "cmos57"},
+	0x67: {"rmb6", amZpg, 5, false, 0, 0, "cmos67"},
+	0x77: {"rmb7", amZpg, 5, false, 0, 0, "cmos77"},
+	0x87: {"smb0", amZpg, 5, false, 0, 0, "cmos87"},
+	0x97: {"smb1", amZpg, 5, false, 0, 0, "cmos97"},
+	0xa7: {"smb2", amZpg, 5, false, 0, 0, "cmosA7"},
+	0xb7: {"smb3", amZpg, 5, false, 0, 0, "cmosB7"},
+	0xc7: {"smb4", amZpg, 5, false, 0, 0, "cmosC7"},
+	0xd7: {"smb5", amZpg, 5, false, 0, 0, "cmosD7"},
+	0xe7: {"smb6", amZpg, 5, false, 0, 0, "cmosE7"},
+	0xf7: {"smb7", amZpg, 5, false, 0, 0, "cmosF7"},
+
+	0x0f: {"bbr0", amZpgRel, 5, false, 0, 0, "cmos0F"},
+	0x1f: {"bbr1", amZpgRel, 5, false, 0, 0, "cmos1F"},
+	0x2f: {"bbr2", amZpgRel, 5, false, 0, 0, "cmos2F"},
+	0x3f: {"bbr3", amZpgRel, 5, false, 0, 0, "cmos3F"},
+	0x4f: {"bbr4", amZpgRel, 5, false, 0, 0, "cmos4F"},
+	0x5f: {"bbr5", amZpgRel, 5, false, 0, 0, "cmos5F"},
+	0x6f: {"bbr6", amZpgRel, 5, false, 0, 0, "cmos6F"},
+	0x7f: {"bbr7", amZpgRel, 5, false, 0, 0, "cmos7F"},
+	0x8f: {"bbs0", amZpgRel, 5, false, 0, 0, "cmos8F"},
+	0x9f: {"bbs1", amZpgRel, 5, false, 0, 0, "cmos9F"},
+	0xaf: {"bbs2", amZpgRel, 5, false, 0, 0, "cmosAF"},
+	0xbf: {"bbs3", amZpgRel, 5, false, 0, 0, "cmosBF"},
+	0xcf: {"bbs4", amZpgRel, 5, false, 0, 0, "cmosCF"},
+	0xdf: {"bbs5", amZpgRel, 5, false, 0, 0, "cmosDF"},
+	0xef: {"bbs6", amZpgRel, 5, false, 0, 0, "cmosEF"},
+	0xff: {"bbs7", amZpgRel, 5, false, 0, 0, "cmosFF"},
+}
+
+// isaWDC are the WDC low power opcodes.
+var isaWDC = map[uint8]opcodeDef{
+
+	0xcb: {"wai", amImpl, 3, false, 0, 0, "cmosCB"},
+	0xdb: {"stp", amImpl, 3, false, 0, 0, "cmosDB"},
+}
+
+//-----------------------------------------------------------------------------
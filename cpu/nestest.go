@@ -0,0 +1,126 @@
+//-----------------------------------------------------------------------------
+/*
+
+nestest.log Trace Format
+
+Trace lines in the Nintendulator layout used by the nestest.log reference.
+Running nestest.nes from $c000 and diffing the output against the log
+checks all documented and common undocumented NMOS opcodes.
+
+http://www.qmtpro.com/~nes/misc/nestest.txt
+http://www.qmtpro.com/~nes/misc/nestest.log
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"fmt"
+	"strings"
+)
+
+//-----------------------------------------------------------------------------
+
+// nestest mnemonics that differ from the disassembler
+var nestestName = map[string]string{
+	"isc": "isb",
+}
+
+// PPU timing (NTSC)
+const ppuDotsPerCycle = 3
+const ppuDotsPerLine = 341
+const ppuLinesPerFrame = 262
+
+// nestestOperand returns the nestest style operand for the instruction at the PC.
+// Memory values are shown as they are before the instruction executes.
+func (m *M6502) nestestOperand(info *opcode, mem []uint8) string {
+	switch info.mode {
+	case amNone, amImpl:
+		return ""
+	case amAcc:
+		return "A"
+	case amImm:
+		return fmt.Sprintf("#$%02X", mem[1])
+	case amZpg:
+		adr := uint16(mem[1])
+		return fmt.Sprintf("$%02X = %02X", adr, m.Peek8(adr))
+	case amZpgX, amZpgY:
+		idx, reg := m.X, "X"
+		if info.mode == amZpgY {
+			idx, reg = m.Y, "Y"
+		}
+		adr := uint16(mem[1] + idx)
+		return fmt.Sprintf("$%02X,%s @ %02X = %02X", mem[1], reg, adr, m.Peek8(adr))
+	case amAbs:
+		adr := uint16(mem[1]) | uint16(mem[2])<<8
+		if info.ins == "jmp" || info.ins == "jsr" {
+			return fmt.Sprintf("$%04X", adr)
+		}
+		return fmt.Sprintf("$%04X = %02X", adr, m.Peek8(adr))
+	case amAbsX, amAbsY:
+		idx, reg := m.X, "X"
+		if info.mode == amAbsY {
+			idx, reg = m.Y, "Y"
+		}
+		base := uint16(mem[1]) | uint16(mem[2])<<8
+		adr := base + uint16(idx)
+		return fmt.Sprintf("$%04X,%s @ %04X = %02X", base, reg, adr, m.Peek8(adr))
+	case amInd:
+		ptr := uint16(mem[1]) | uint16(mem[2])<<8
+		hi := ptr + 1
+		if !m.cmos {
+			// NMOS page wrap
+			hi = (ptr & 0xff00) | uint16(uint8(ptr)+1)
+		}
+		return fmt.Sprintf("($%04X) = %04X", ptr, m.peek16(ptr, hi))
+	case amXInd:
+		ptr := mem[1] + m.X
+		adr := m.peek16(uint16(ptr), uint16(ptr+1))
+		return fmt.Sprintf("($%02X,X) @ %02X = %04X = %02X", mem[1], ptr, adr, m.Peek8(adr))
+	case amIndY:
+		base := m.peek16(uint16(mem[1]), uint16(mem[1]+1))
+		adr := base + uint16(m.Y)
+		return fmt.Sprintf("($%02X),Y = %04X @ %04X = %02X", mem[1], base, adr, m.Peek8(adr))
+	case amRel:
+		return fmt.Sprintf("$%04X", uint16(int(m.PC)+int(int8(mem[1]))+2))
+	}
+	return "?"
+}
+
+// NestestTrace returns a trace line for the instruction at the PC in the
+// nestest.log layout: PC, instruction bytes, disassembly, registers, PPU
+// position (derived from the cycle count) and CPU cycles.
+func (m *M6502) NestestTrace() string {
+	info := opcodeLookup(m.variant, m.Peek8(m.PC))
+	mem := make([]uint8, insLengthByMode[info.mode])
+	bytes := make([]string, len(mem))
+	for i := range mem {
+		mem[i] = m.Peek8(m.PC + uint16(i))
+		bytes[i] = fmt.Sprintf("%02X", mem[i])
+	}
+
+	// undocumented opcodes are marked with '*'
+	mark := " "
+	if info.undoc {
+		mark = "*"
+	}
+	name := info.ins
+	if x, ok := nestestName[name]; ok {
+		name = x
+	}
+	ins := strings.ToUpper(name)
+	if operand := m.nestestOperand(info, mem); operand != "" {
+		ins = fmt.Sprintf("%s %s", ins, operand)
+	}
+
+	dots := m.cycles * ppuDotsPerCycle
+	line := (dots / ppuDotsPerLine) % ppuLinesPerFrame
+	dot := dots % ppuDotsPerLine
+	p := (m.P | flagU) & ^flagB
+
+	return fmt.Sprintf("%04X  %-9s%s%-32sA:%02X X:%02X Y:%02X P:%02X SP:%02X PPU:%3d,%3d CYC:%d",
+		m.PC, strings.Join(bytes, " "), mark, ins, m.A, m.X, m.Y, p, m.S, line, dot, m.cycles)
+}
+
+//-----------------------------------------------------------------------------
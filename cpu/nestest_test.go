@@ -0,0 +1,80 @@
+//-----------------------------------------------------------------------------
+/*
+
+Ricoh 2A03 and nestest.log Trace Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import "testing"
+
+//-----------------------------------------------------------------------------
+
+func TestDecimalDisabled(t *testing.T) {
+	tests := []struct {
+		name string
+		code []uint8
+		bin  uint8 // 2A03 result
+		dec  uint8 // 6502 result
+	}{
+		{"adc", []uint8{0xf8, 0x18, 0xa9, 0x09, 0x69, 0x01}, 0x0a, 0x10},       // sed, clc, lda #$09, adc #$01
+		{"sbc", []uint8{0xf8, 0x38, 0xa9, 0x10, 0xe9, 0x01}, 0x0f, 0x09},       // sed, sec, lda #$10, sbc #$01
+		{"adc carry", []uint8{0xf8, 0x38, 0xa9, 0x99, 0x69, 0x00}, 0x9a, 0x00}, // sed, sec, lda #$99, adc #$00
+	}
+	for _, tt := range tests {
+		testCPUs(t, []Variant{Variant2A03, Variant6502}, func(m *M6502, r *testRAM, tick bool) {
+			m.PC = 0x0400
+			copy(r[0x0400:], tt.code)
+			for i := 0; i < 4; i++ {
+				testStep(t, m, tick)
+			}
+			want := tt.dec
+			if m.variant == Variant2A03 {
+				want = tt.bin
+			}
+			if m.A != want {
+				t.Errorf("%s: %s gives %02x, want %02x", m.variant, tt.name, m.A, want)
+			}
+			// D is still stored and the timing is the same
+			if m.P&flagD == 0 || m.Cycles() != 2*4 {
+				t.Errorf("%s: %s p %02x, %d cycles", m.variant, tt.name, m.P, m.Cycles())
+			}
+		})
+	}
+}
+
+// nestestLog is the start of the nestest.log reference trace.
+var nestestLog = []string{
+	"C000  4C F5 C5  JMP $C5F5                       A:00 X:00 Y:00 P:24 SP:FD PPU:  0, 21 CYC:7",
+	"C5F5  A2 00     LDX #$00                        A:00 X:00 Y:00 P:24 SP:FD PPU:  0, 30 CYC:10",
+	"C5F7  86 00     STX $00 = 00                    A:00 X:00 Y:00 P:26 SP:FD PPU:  0, 36 CYC:12",
+	"C5F9  86 10     STX $10 = 00                    A:00 X:00 Y:00 P:26 SP:FD PPU:  0, 45 CYC:15",
+	"C5FB  86 11     STX $11 = 00                    A:00 X:00 Y:00 P:26 SP:FD PPU:  0, 54 CYC:18",
+	"C5FD  20 2D C7  JSR $C72D                       A:00 X:00 Y:00 P:26 SP:FD PPU:  0, 63 CYC:21",
+	"C72D  EA        NOP                             A:00 X:00 Y:00 P:26 SP:FB PPU:  0, 81 CYC:27",
+	"C72E  38        SEC                             A:00 X:00 Y:00 P:26 SP:FB PPU:  0, 87 CYC:29",
+	"C72F  B0 04     BCS $C735                       A:00 X:00 Y:00 P:27 SP:FB PPU:  0, 93 CYC:31",
+	"C735  EA        NOP                             A:00 X:00 Y:00 P:27 SP:FB PPU:  0,102 CYC:34",
+}
+
+func TestNestestTrace(t *testing.T) {
+	testCPUs(t, []Variant{Variant2A03}, func(m *M6502, r *testRAM, tick bool) {
+		copy(r[0xc000:], []uint8{0x4c, 0xf5, 0xc5})
+		copy(r[0xc5f5:], []uint8{0xa2, 0x00, 0x86, 0x00, 0x86, 0x10, 0x86, 0x11, 0x20, 0x2d, 0xc7})
+		copy(r[0xc72d:], []uint8{0xea, 0x38, 0xb0, 0x04})
+		r[0xc735] = 0xea
+		// nestest starts at $c000 after the reset sequence
+		m.PC = 0xc000
+		m.cycles = 7
+		for i, want := range nestestLog {
+			if got := m.NestestTrace(); got != want {
+				t.Fatalf("tick %t: line %d\n%s\nwant\n%s", tick, i+1, got, want)
+			}
+			testStep(t, m, tick)
+		}
+	})
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,113 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Memory Access Observer
+
+An observer is told about each memory access done by the CPU: the kind of
+access, the address and value, and the PC of the instruction (or interrupt)
+doing it. It is the basis for watchpoints, heatmaps and the like.
+
+The accesses are those of the instruction emulation. Run reports the accesses
+an instruction needs, Tick also reports the dummy reads and writes.
+
+With no observer the cost is a nil check per access.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+//-----------------------------------------------------------------------------
+
+// AccessKind is the kind of a memory access.
+type AccessKind uint8
+
+// Memory access kinds.
+const (
+	AccessFetch   AccessKind = iota // opcode fetch
+	AccessOperand                   // operand fetch
+	AccessRead                      // data read
+	AccessWrite                     // data write
+	AccessPush                      // stack write
+	AccessPull                      // stack read
+	AccessVector                    // interrupt/reset vector read
+)
+
+var accessKindName = map[AccessKind]string{
+	AccessFetch:   "fetch",
+	AccessOperand: "operand",
+	AccessRead:    "read",
+	AccessWrite:   "write",
+	AccessPush:    "push",
+	AccessPull:    "pull",
+	AccessVector:  "vector",
+}
+
+func (k AccessKind) String() string {
+	return accessKindName[k]
+}
+
+// Write returns true if the access is a write.
+func (k AccessKind) Write() bool {
+	return k == AccessWrite || k == AccessPush
+}
+
+// Access is a memory access.
+type Access struct {
+	Kind AccessKind
+	Adr  uint16 // address
+	Val  uint8  // value read or written
+	PC   uint16 // address of the instruction doing the access
+}
+
+// Observer is told about memory accesses.
+type Observer interface {
+	Observe(a Access)
+}
+
+// ObserverFunc is a function used as an Observer.
+type ObserverFunc func(a Access)
+
+// Observe calls the function.
+func (fn ObserverFunc) Observe(a Access) {
+	fn(a)
+}
+
+// WithObserver sets an observer for the memory accesses.
+func WithObserver(o Observer) Option {
+	return func(m *M6502) {
+		m.obs = o
+	}
+}
+
+// SetObserver sets (or with nil removes) the memory access observer.
+func (m *M6502) SetObserver(o Observer) {
+	m.obs = o
+}
+
+//-----------------------------------------------------------------------------
+
+// load reads a byte from the target memory.
+func (m *M6502) load(kind AccessKind, adr uint16) uint8 {
+	v := m.mem8(adr)
+	if m.obs != nil {
+		m.obs.Observe(Access{kind, adr, v, m.opPC})
+	}
+	return v
+}
+
+// store writes a byte to the target memory.
+func (m *M6502) store(kind AccessKind, adr uint16, val uint8) {
+	if m.pre != nil {
+		m.pre.invalidate(adr)
+	}
+	if m.port != nil && adr <= 1 {
+		m.port.write(adr, val)
+	}
+	m.Mem.Write8(adr, val)
+	if m.obs != nil {
+		m.obs.Observe(Access{kind, adr, val, m.opPC})
+	}
+}
+
+//-----------------------------------------------------------------------------
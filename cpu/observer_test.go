@@ -0,0 +1,97 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Memory Access Observer Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import "testing"
+
+//-----------------------------------------------------------------------------
+
+func TestObserver(t *testing.T) {
+	var r testRAM
+	var got []Access
+	m := New6502(&r, WithObserver(ObserverFunc(func(a Access) {
+		got = append(got, a)
+	})))
+	r[0xfffc] = 0x00
+	r[0xfffd] = 0x04
+	copy(r[0x0400:], []uint8{
+		0xa9, 0x12, // 0400 lda #$12
+		0x8d, 0x00, 0x02, // 0402 sta $0200
+		0x48, // 0405 pha
+		0x00, // 0406 brk
+	})
+	r[0xfffe] = 0x00
+	r[0xffff] = 0x05
+	m.Reset()
+	for i := 0; i < 4; i++ {
+		if err := m.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := m.S
+	want := []Access{
+		{AccessVector, 0xfffc, 0x00, 0},
+		{AccessVector, 0xfffd, 0x04, 0},
+		{AccessFetch, 0x0400, 0xa9, 0x0400},
+		{AccessOperand, 0x0401, 0x12, 0x0400},
+		{AccessFetch, 0x0402, 0x8d, 0x0402},
+		{AccessOperand, 0x0403, 0x00, 0x0402},
+		{AccessOperand, 0x0404, 0x02, 0x0402},
+		{AccessWrite, 0x0200, 0x12, 0x0402},
+		{AccessFetch, 0x0405, 0x48, 0x0405},
+		{AccessPush, 0x0100 + uint16(s+4), 0x12, 0x0405},
+		{AccessFetch, 0x0406, 0x00, 0x0406},
+		{AccessPush, 0x0100 + uint16(s+3), 0x04, 0x0406},
+		{AccessPush, 0x0100 + uint16(s+2), 0x08, 0x0406},
+		{AccessPush, 0x0100 + uint16(s+1), m.mem8(0x0100 + uint16(s+1)), 0x0406},
+		{AccessVector, 0xfffe, 0x00, 0x0406},
+		{AccessVector, 0xffff, 0x05, 0x0406},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d accesses, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("access %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestObserverTick(t *testing.T) {
+	// every bus cycle is observed
+	var r testRAM
+	var accesses, cycles int
+	m := New6502(&r,
+		WithObserver(ObserverFunc(func(a Access) { accesses++ })),
+		BusCycle(func(adr uint16, data uint8, write, sync bool) { cycles++ }),
+	)
+	m.PC = 0x0400
+	copy(r[0x0400:], []uint8{
+		0x20, 0x10, 0x04, // 0400 jsr $0410
+		0x4c, 0x00, 0x04, // 0403 jmp $0400
+	})
+	copy(r[0x0410:], []uint8{
+		0xbd, 0xff, 0x02, // 0410 lda $02ff,x
+		0xfe, 0x00, 0x03, // 0413 inc $0300,x
+		0x48, // 0416 pha
+		0x68, // 0417 pla
+		0x60, // 0418 rts
+	})
+	m.X = 1
+	for i := 0; i < 100; i++ {
+		if err := m.Tick(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if accesses != cycles {
+		t.Errorf("observed %d accesses in %d bus cycles", accesses, cycles)
+	}
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,1573 @@
+// Code generated by cmd/gen. DO NOT EDIT.
+
+package cpu
+
+// variants are the supported CPU variants.
+var variants = []variantInfo{
+	Variant6502:    {"6502", &opcodeTable6502, false, true},
+	Variant65C02:   {"65c02", &opcodeTable65C02, true, true},
+	VariantR65C02:  {"r65c02", &opcodeTableR65C02, true, true},
+	VariantW65C02S: {"w65c02s", &opcodeTableW65C02S, true, true},
+	Variant2A03:    {"2a03", &opcodeTable2A03, false, false},
+	Variant6510:    {"6510", &opcodeTable6510, false, true},
+}
+
+// opcodeTable6502 is the 6502 dispatch and metadata table.
+var opcodeTable6502 = [256]opcode{
+	{op00, "brk", amImpl, 1, 7, false, 0x00, 0x04, false}, // 00
+	{op01, "ora", amXInd, 2, 6, false, 0x00, 0x82, false}, // 01
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 02
+	{op03, "slo", amXInd, 2, 8, false, 0x00, 0x83, true},  // 03
+	{op04, "nop", amZpg, 2, 3, false, 0x00, 0x00, true},   // 04
+	{op05, "ora", amZpg, 2, 3, false, 0x00, 0x82, false},  // 05
+	{op06, "asl", amZpg, 2, 5, false, 0x00, 0x83, false},  // 06
+	{op07, "slo", amZpg, 2, 5, false, 0x00, 0x83, true},   // 07
+	{op08, "php", amImpl, 1, 3, false, 0xdf, 0x00, false}, // 08
+	{op09, "ora", amImm, 2, 2, false, 0x00, 0x82, false},  // 09
+	{op0A, "asl", amAcc, 1, 2, false, 0x00, 0x83, false},  // 0a
+	{op0B, "anc", amImm, 2, 2, false, 0x00, 0x83, true},   // 0b
+	{op0C, "nop", amAbs, 3, 4, false, 0x00, 0x00, true},   // 0c
+	{op0D, "ora", amAbs, 3, 4, false, 0x00, 0x82, false},  // 0d
+	{op0E, "asl", amAbs, 3, 6, false, 0x00, 0x83, false},  // 0e
+	{op0F, "slo", amAbs, 3, 6, false, 0x00, 0x83, true},   // 0f
+	{op10, "bpl", amRel, 2, 2, false, 0x80, 0x00, false},  // 10
+	{op11, "ora", amIndY, 2, 5, true, 0x00, 0x82, false},  // 11
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 12
+	{op13, "slo", amIndY, 2, 8, false, 0x00, 0x83, true},  // 13
+	{op14, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 14
+	{op15, "ora", amZpgX, 2, 4, false, 0x00, 0x82, false}, // 15
+	{op16, "asl", amZpgX, 2, 6, false, 0x00, 0x83, false}, // 16
+	{op17, "slo", amZpgX, 2, 6, false, 0x00, 0x83, true},  // 17
+	{op18, "clc", amImpl, 1, 2, false, 0x00, 0x01, false}, // 18
+	{op19, "ora", amAbsY, 3, 4, true, 0x00, 0x82, false},  // 19
+	{op1A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 1a
+	{op1B, "slo", amAbsY, 3, 7, false, 0x00, 0x83, true},  // 1b
+	{op1C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 1c
+	{op1D, "ora", amAbsX, 3, 4, true, 0x00, 0x82, false},  // 1d
+	{op1E, "asl", amAbsX, 3, 7, false, 0x00, 0x83, false}, // 1e
+	{op1F, "slo", amAbsX, 3, 7, false, 0x00, 0x83, true},  // 1f
+	{op20, "jsr", amAbs, 3, 6, false, 0x00, 0x00, false},  // 20
+	{op21, "and", amXInd, 2, 6, false, 0x00, 0x82, false}, // 21
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 22
+	{op23, "rla", amXInd, 2, 8, false, 0x01, 0x83, true},  // 23
+	{op24, "bit", amZpg, 2, 3, false, 0x00, 0xc2, false},  // 24
+	{op25, "and", amZpg, 2, 3, false, 0x00, 0x82, false},  // 25
+	{op26, "rol", amZpg, 2, 5, false, 0x01, 0x83, false},  // 26
+	{op27, "rla", amZpg, 2, 5, false, 0x01, 0x83, true},   // 27
+	{op28, "plp", amImpl, 1, 4, false, 0x00, 0xdf, false}, // 28
+	{op29, "and", amImm, 2, 2, false, 0x00, 0x82, false},  // 29
+	{op2A, "rol", amAcc, 1, 2, false, 0x01, 0x83, false},  // 2a
+	{op2B, "anc", amImm, 2, 2, false, 0x00, 0x83, true},   // 2b
+	{op2C, "bit", amAbs, 3, 4, false, 0x00, 0xc2, false},  // 2c
+	{op2D, "and", amAbs, 3, 4, false, 0x00, 0x82, false},  // 2d
+	{op2E, "rol", amAbs, 3, 6, false, 0x01, 0x83, false},  // 2e
+	{op2F, "rla", amAbs, 3, 6, false, 0x01, 0x83, true},   // 2f
+	{op30, "bmi", amRel, 2, 2, false, 0x80, 0x00, false},  // 30
+	{op31, "and", amIndY, 2, 5, true, 0x00, 0x82, false},  // 31
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 32
+	{op33, "rla", amIndY, 2, 8, false, 0x01, 0x83, true},  // 33
+	{op34, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 34
+	{op35, "and", amZpgX, 2, 4, false, 0x00, 0x82, false}, // 35
+	{op36, "rol", amZpgX, 2, 6, false, 0x01, 0x83, false}, // 36
+	{op37, "rla", amZpgX, 2, 6, false, 0x01, 0x83, true},  // 37
+	{op38, "sec", amImpl, 1, 2, false, 0x00, 0x01, false}, // 38
+	{op39, "and", amAbsY, 3, 4, true, 0x00, 0x82, false},  // 39
+	{op3A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 3a
+	{op3B, "rla", amAbsY, 3, 7, false, 0x01, 0x83, true},  // 3b
+	{op3C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 3c
+	{op3D, "and", amAbsX, 3, 4, true, 0x00, 0x82, false},  // 3d
+	{op3E, "rol", amAbsX, 3, 7, false, 0x01, 0x83, false}, // 3e
+	{op3F, "rla", amAbsX, 3, 7, false, 0x01, 0x83, true},  // 3f
+	{op40, "rti", amImpl, 1, 6, false, 0x00, 0xdf, false}, // 40
+	{op41, "eor", amXInd, 2, 6, false, 0x00, 0x82, false}, // 41
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 42
+	{op43, "sre", amXInd, 2, 8, false, 0x00, 0x83, true},  // 43
+	{op44, "nop", amZpg, 2, 3, false, 0x00, 0x00, true},   // 44
+	{op45, "eor", amZpg, 2, 3, false, 0x00, 0x82, false},  // 45
+	{op46, "lsr", amZpg, 2, 5, false, 0x00, 0x83, false},  // 46
+	{op47, "sre", amZpg, 2, 5, false, 0x00, 0x83, true},   // 47
+	{op48, "pha", amImpl, 1, 3, false, 0x00, 0x00, false}, // 48
+	{op49, "eor", amImm, 2, 2, false, 0x00, 0x82, false},  // 49
+	{op4A, "lsr", amAcc, 1, 2, false, 0x00, 0x83, false},  // 4a
+	{op4B, "alr", amImm, 2, 2, false, 0x00, 0x83, true},   // 4b
+	{op4C, "jmp", amAbs, 3, 3, false, 0x00, 0x00, false},  // 4c
+	{op4D, "eor", amAbs, 3, 4, false, 0x00, 0x82, false},  // 4d
+	{op4E, "lsr", amAbs, 3, 6, false, 0x00, 0x83, false},  // 4e
+	{op4F, "sre", amAbs, 3, 6, false, 0x00, 0x83, true},   // 4f
+	{op50, "bvc", amRel, 2, 2, false, 0x40, 0x00, false},  // 50
+	{op51, "eor", amIndY, 2, 5, true, 0x00, 0x82, false},  // 51
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 52
+	{op53, "sre", amIndY, 2, 8, false, 0x00, 0x83, true},  // 53
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 54
+	{op55, "eor", amZpgX, 2, 4, false, 0x00, 0x82, false}, // 55
+	{op56, "lsr", amZpgX, 2, 6, false, 0x00, 0x83, false}, // 56
+	{op57, "sre", amZpgX, 2, 6, false, 0x00, 0x83, true},  // 57
+	{op58, "cli", amImpl, 1, 2, false, 0x00, 0x04, false}, // 58
+	{op59, "eor", amAbsY, 3, 4, true, 0x00, 0x82, false},  // 59
+	{op5A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 5a
+	{op5B, "sre", amAbsY, 3, 7, false, 0x00, 0x83, true},  // 5b
+	{op5C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 5c
+	{op5D, "eor", amAbsX, 3, 4, true, 0x00, 0x82, false},  // 5d
+	{op5E, "lsr", amAbsX, 3, 7, false, 0x00, 0x83, false}, // 5e
+	{op5F, "sre", amAbsX, 3, 7, false, 0x00, 0x83, true},  // 5f
+	{op60, "rts", amImpl, 1, 6, false, 0x00, 0x00, false}, // 60
+	{op61, "adc", amXInd, 2, 6, false, 0x09, 0xc3, false}, // 61
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 62
+	{op63, "rra", amXInd, 2, 8, false, 0x09, 0xc3, true},  // 63
+	{op64, "nop", amZpg, 2, 3, false, 0x00, 0x00, true},   // 64
+	{op65, "adc", amZpg, 2, 3, false, 0x09, 0xc3, false},  // 65
+	{op66, "ror", amZpg, 2, 5, false, 0x01, 0x83, false},  // 66
+	{op67, "rra", amZpg, 2, 5, false, 0x09, 0xc3, true},   // 67
+	{op68, "pla", amImpl, 1, 4, false, 0x00, 0x82, false}, // 68
+	{op69, "adc", amImm, 2, 2, false, 0x09, 0xc3, false},  // 69
+	{op6A, "ror", amAcc, 1, 2, false, 0x01, 0x83, false},  // 6a
+	{op6B, "arr", amImm, 2, 2, false, 0x09, 0xc3, true},   // 6b
+	{op6C, "jmp", amInd, 3, 5, false, 0x00, 0x00, false},  // 6c
+	{op6D, "adc", amAbs, 3, 4, false, 0x09, 0xc3, false},  // 6d
+	{op6E, "ror", amAbs, 3, 6, false, 0x01, 0x83, false},  // 6e
+	{op6F, "rra", amAbs, 3, 6, false, 0x09, 0xc3, true},   // 6f
+	{op70, "bvs", amRel, 2, 2, false, 0x40, 0x00, false},  // 70
+	{op71, "adc", amIndY, 2, 5, true, 0x09, 0xc3, false},  // 71
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 72
+	{op73, "rra", amIndY, 2, 8, false, 0x09, 0xc3, true},  // 73
+	{op74, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 74
+	{op75, "adc", amZpgX, 2, 4, false, 0x09, 0xc3, false}, // 75
+	{op76, "ror", amZpgX, 2, 6, false, 0x01, 0x83, false}, // 76
+	{op77, "rra", amZpgX, 2, 6, false, 0x09, 0xc3, true},  // 77
+	{op78, "sei", amImpl, 1, 2, false, 0x00, 0x04, false}, // 78
+	{op79, "adc", amAbsY, 3, 4, true, 0x09, 0xc3, false},  // 79
+	{op7A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 7a
+	{op7B, "rra", amAbsY, 3, 7, false, 0x09, 0xc3, true},  // 7b
+	{op7C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 7c
+	{op7D, "adc", amAbsX, 3, 4, true, 0x09, 0xc3, false},  // 7d
+	{op7E, "ror", amAbsX, 3, 7, false, 0x01, 0x83, false}, // 7e
+	{op7F, "rra", amAbsX, 3, 7, false, 0x09, 0xc3, true},  // 7f
+	{op80, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // 80
+	{op81, "sta", amXInd, 2, 6, false, 0x00, 0x00, false}, // 81
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // 82
+	{op83, "sax", amXInd, 2, 6, false, 0x00, 0x00, true},  // 83
+	{op84, "sty", amZpg, 2, 3, false, 0x00, 0x00, false},  // 84
+	{op85, "sta", amZpg, 2, 3, false, 0x00, 0x00, false},  // 85
+	{op86, "stx", amZpg, 2, 3, false, 0x00, 0x00, false},  // 86
+	{op87, "sax", amZpg, 2, 3, false, 0x00, 0x00, true},   // 87
+	{op88, "dey", amImpl, 1, 2, false, 0x00, 0x82, false}, // 88
+	{op89, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // 89
+	{op8A, "txa", amImpl, 1, 2, false, 0x00, 0x82, false}, // 8a
+	{op8B, "xaa", amImm, 2, 2, false, 0x00, 0x82, true},   // 8b
+	{op8C, "sty", amAbs, 3, 4, false, 0x00, 0x00, false},  // 8c
+	{op8D, "sta", amAbs, 3, 4, false, 0x00, 0x00, false},  // 8d
+	{op8E, "stx", amAbs, 3, 4, false, 0x00, 0x00, false},  // 8e
+	{op8F, "sax", amAbs, 3, 4, false, 0x00, 0x00, true},   // 8f
+	{op90, "bcc", amRel, 2, 2, false, 0x01, 0x00, false},  // 90
+	{op91, "sta", amIndY, 2, 6, false, 0x00, 0x00, false}, // 91
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 92
+	{op93, "sha", amIndY, 2, 6, false, 0x00, 0x00, true},  // 93
+	{op94, "sty", amZpgX, 2, 4, false, 0x00, 0x00, false}, // 94
+	{op95, "sta", amZpgX, 2, 4, false, 0x00, 0x00, false}, // 95
+	{op96, "stx", amZpgY, 2, 4, false, 0x00, 0x00, false}, // 96
+	{op97, "sax", amZpgY, 2, 4, false, 0x00, 0x00, true},  // 97
+	{op98, "tya", amImpl, 1, 2, false, 0x00, 0x82, false}, // 98
+	{op99, "sta", amAbsY, 3, 5, false, 0x00, 0x00, false}, // 99
+	{op9A, "txs", amImpl, 1, 2, false, 0x00, 0x00, false}, // 9a
+	{op9B, "tas", amAbsY, 3, 5, false, 0x00, 0x00, true},  // 9b
+	{op9C, "shy", amAbsX, 3, 5, false, 0x00, 0x00, true},  // 9c
+	{op9D, "sta", amAbsX, 3, 5, false, 0x00, 0x00, false}, // 9d
+	{op9E, "shx", amAbsY, 3, 5, false, 0x00, 0x00, true},  // 9e
+	{op9F, "sha", amAbsY, 3, 5, false, 0x00, 0x00, true},  // 9f
+	{opA0, "ldy", amImm, 2, 2, false, 0x00, 0x82, false},  // a0
+	{opA1, "lda", amXInd, 2, 6, false, 0x00, 0x82, false}, // a1
+	{opA2, "ldx", amImm, 2, 2, false, 0x00, 0x82, false},  // a2
+	{opA3, "lax", amXInd, 2, 6, false, 0x00, 0x82, true},  // a3
+	{opA4, "ldy", amZpg, 2, 3, false, 0x00, 0x82, false},  // a4
+	{opA5, "lda", amZpg, 2, 3, false, 0x00, 0x82, false},  // a5
+	{opA6, "ldx", amZpg, 2, 3, false, 0x00, 0x82, false},  // a6
+	{opA7, "lax", amZpg, 2, 3, false, 0x00, 0x82, true},   // a7
+	{opA8, "tay", amImpl, 1, 2, false, 0x00, 0x82, false}, // a8
+	{opA9, "lda", amImm, 2, 2, false, 0x00, 0x82, false},  // a9
+	{opAA, "tax", amImpl, 1, 2, false, 0x00, 0x82, false}, // aa
+	{opAB, "lax", amImm, 2, 2, false, 0x00, 0x82, true},   // ab
+	{opAC, "ldy", amAbs, 3, 4, false, 0x00, 0x82, false},  // ac
+	{opAD, "lda", amAbs, 3, 4, false, 0x00, 0x82, false},  // ad
+	{opAE, "ldx", amAbs, 3, 4, false, 0x00, 0x82, false},  // ae
+	{opAF, "lax", amAbs, 3, 4, false, 0x00, 0x82, true},   // af
+	{opB0, "bcs", amRel, 2, 2, false, 0x01, 0x00, false},  // b0
+	{opB1, "lda", amIndY, 2, 5, true, 0x00, 0x82, false},  // b1
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // b2
+	{opB3, "lax", amIndY, 2, 5, true, 0x00, 0x82, true},   // b3
+	{opB4, "ldy", amZpgX, 2, 4, false, 0x00, 0x82, false}, // b4
+	{opB5, "lda", amZpgX, 2, 4, false, 0x00, 0x82, false}, // b5
+	{opB6, "ldx", amZpgY, 2, 4, false, 0x00, 0x82, false}, // b6
+	{opB7, "lax", amZpgY, 2, 4, false, 0x00, 0x82, true},  // b7
+	{opB8, "clv", amImpl, 1, 2, false, 0x00, 0x40, false}, // b8
+	{opB9, "lda", amAbsY, 3, 4, true, 0x00, 0x82, false},  // b9
+	{opBA, "tsx", amImpl, 1, 2, false, 0x00, 0x82, false}, // ba
+	{opBB, "las", amAbsY, 3, 4, true, 0x00, 0x82, true},   // bb
+	{opBC, "ldy", amAbsX, 3, 4, true, 0x00, 0x82, false},  // bc
+	{opBD, "lda", amAbsX, 3, 4, true, 0x00, 0x82, false},  // bd
+	{opBE, "ldx", amAbsY, 3, 4, true, 0x00, 0x82, false},  // be
+	{opBF, "lax", amAbsY, 3, 4, true, 0x00, 0x82, true},   // bf
+	{opC0, "cpy", amImm, 2, 2, false, 0x00, 0x83, false},  // c0
+	{opC1, "cmp", amXInd, 2, 6, false, 0x00, 0x83, false}, // c1
+	{opC2, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // c2
+	{opC3, "dcp", amXInd, 2, 8, false, 0x00, 0x83, true},  // c3
+	{opC4, "cpy", amZpg, 2, 3, false, 0x00, 0x83, false},  // c4
+	{opC5, "cmp", amZpg, 2, 3, false, 0x00, 0x83, false},  // c5
+	{opC6, "dec", amZpg, 2, 5, false, 0x00, 0x82, false},  // c6
+	{opC7, "dcp", amZpg, 2, 5, false, 0x00, 0x83, true},   // c7
+	{opC8, "iny", amImpl, 1, 2, false, 0x00, 0x82, false}, // c8
+	{opC9, "cmp", amImm, 2, 2, false, 0x00, 0x83, false},  // c9
+	{opCA, "dex", amImpl, 1, 2, false, 0x00, 0x82, false}, // ca
+	{opCB, "sbx", amImm, 2, 2, false, 0x00, 0x83, true},   // cb
+	{opCC, "cpy", amAbs, 3, 4, false, 0x00, 0x83, false},  // cc
+	{opCD, "cmp", amAbs, 3, 4, false, 0x00, 0x83, false},  // cd
+	{opCE, "dec", amAbs, 3, 6, false, 0x00, 0x82, false},  // ce
+	{opCF, "dcp", amAbs, 3, 6, false, 0x00, 0x83, true},   // cf
+	{opD0, "bne", amRel, 2, 2, false, 0x02, 0x00, false},  // d0
+	{opD1, "cmp", amIndY, 2, 5, true, 0x00, 0x83, false},  // d1
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // d2
+	{opD3, "dcp", amIndY, 2, 8, false, 0x00, 0x83, true},  // d3
+	{opD4, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // d4
+	{opD5, "cmp", amZpgX, 2, 4, false, 0x00, 0x83, false}, // d5
+	{opD6, "dec", amZpgX, 2, 6, false, 0x00, 0x82, false}, // d6
+	{opD7, "dcp", amZpgX, 2, 6, false, 0x00, 0x83, true},  // d7
+	{opD8, "cld", amImpl, 1, 2, false, 0x00, 0x08, false}, // d8
+	{opD9, "cmp", amAbsY, 3, 4, true, 0x00, 0x83, false},  // d9
+	{opDA, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // da
+	{opDB, "dcp", amAbsY, 3, 7, false, 0x00, 0x83, true},  // db
+	{opDC, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // dc
+	{opDD, "cmp", amAbsX, 3, 4, true, 0x00, 0x83, false},  // dd
+	{opDE, "dec", amAbsX, 3, 7, false, 0x00, 0x82, false}, // de
+	{opDF, "dcp", amAbsX, 3, 7, false, 0x00, 0x83, true},  // df
+	{opE0, "cpx", amImm, 2, 2, false, 0x00, 0x83, false},  // e0
+	{opE1, "sbc", amXInd, 2, 6, false, 0x09, 0xc3, false}, // e1
+	{opE2, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // e2
+	{opE3, "isc", amXInd, 2, 8, false, 0x09, 0xc3, true},  // e3
+	{opE4, "cpx", amZpg, 2, 3, false, 0x00, 0x83, false},  // e4
+	{opE5, "sbc", amZpg, 2, 3, false, 0x09, 0xc3, false},  // e5
+	{opE6, "inc", amZpg, 2, 5, false, 0x00, 0x82, false},  // e6
+	{opE7, "isc", amZpg, 2, 5, false, 0x09, 0xc3, true},   // e7
+	{opE8, "inx", amImpl, 1, 2, false, 0x00, 0x82, false}, // e8
+	{opE9, "sbc", amImm, 2, 2, false, 0x09, 0xc3, false},  // e9
+	{opEA, "nop", amImpl, 1, 2, false, 0x00, 0x00, false}, // ea
+	{opEB, "sbc", amImm, 2, 2, false, 0x09, 0xc3, true},   // eb
+	{opEC, "cpx", amAbs, 3, 4, false, 0x00, 0x83, false},  // ec
+	{opED, "sbc", amAbs, 3, 4, false, 0x09, 0xc3, false},  // ed
+	{opEE, "inc", amAbs, 3, 6, false, 0x00, 0x82, false},  // ee
+	{opEF, "isc", amAbs, 3, 6, false, 0x09, 0xc3, true},   // ef
+	{opF0, "beq", amRel, 2, 2, false, 0x02, 0x00, false},  // f0
+	{opF1, "sbc", amIndY, 2, 5, true, 0x09, 0xc3, false},  // f1
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // f2
+	{opF3, "isc", amIndY, 2, 8, false, 0x09, 0xc3, true},  // f3
+	{opF4, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // f4
+	{opF5, "sbc", amZpgX, 2, 4, false, 0x09, 0xc3, false}, // f5
+	{opF6, "inc", amZpgX, 2, 6, false, 0x00, 0x82, false}, // f6
+	{opF7, "isc", amZpgX, 2, 6, false, 0x09, 0xc3, true},  // f7
+	{opF8, "sed", amImpl, 1, 2, false, 0x00, 0x08, false}, // f8
+	{opF9, "sbc", amAbsY, 3, 4, true, 0x09, 0xc3, false},  // f9
+	{opFA, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // fa
+	{opFB, "isc", amAbsY, 3, 7, false, 0x09, 0xc3, true},  // fb
+	{opFC, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // fc
+	{opFD, "sbc", amAbsX, 3, 4, true, 0x09, 0xc3, false},  // fd
+	{opFE, "inc", amAbsX, 3, 7, false, 0x00, 0x82, false}, // fe
+	{opFF, "isc", amAbsX, 3, 7, false, 0x09, 0xc3, true},  // ff
+}
+
+// opcodeTable65C02 is the 65c02 dispatch and metadata table.
+var opcodeTable65C02 = [256]opcode{
+	{cmos00, "brk", amImpl, 1, 7, false, 0x00, 0x0c, false},    // 00
+	{op01, "ora", amXInd, 2, 6, false, 0x00, 0x82, false},      // 01
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 02
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 03
+	{cmos04, "tsb", amZpg, 2, 5, false, 0x00, 0x02, false},     // 04
+	{op05, "ora", amZpg, 2, 3, false, 0x00, 0x82, false},       // 05
+	{op06, "asl", amZpg, 2, 5, false, 0x00, 0x83, false},       // 06
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 07
+	{op08, "php", amImpl, 1, 3, false, 0xdf, 0x00, false},      // 08
+	{op09, "ora", amImm, 2, 2, false, 0x00, 0x82, false},       // 09
+	{op0A, "asl", amAcc, 1, 2, false, 0x00, 0x83, false},       // 0a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 0b
+	{cmos0C, "tsb", amAbs, 3, 6, false, 0x00, 0x02, false},     // 0c
+	{op0D, "ora", amAbs, 3, 4, false, 0x00, 0x82, false},       // 0d
+	{op0E, "asl", amAbs, 3, 6, false, 0x00, 0x83, false},       // 0e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 0f
+	{op10, "bpl", amRel, 2, 2, false, 0x80, 0x00, false},       // 10
+	{op11, "ora", amIndY, 2, 5, true, 0x00, 0x82, false},       // 11
+	{cmos12, "ora", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // 12
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 13
+	{cmos14, "trb", amZpg, 2, 5, false, 0x00, 0x02, false},     // 14
+	{op15, "ora", amZpgX, 2, 4, false, 0x00, 0x82, false},      // 15
+	{op16, "asl", amZpgX, 2, 6, false, 0x00, 0x83, false},      // 16
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 17
+	{op18, "clc", amImpl, 1, 2, false, 0x00, 0x01, false},      // 18
+	{op19, "ora", amAbsY, 3, 4, true, 0x00, 0x82, false},       // 19
+	{cmos1A, "inc", amAcc, 1, 2, false, 0x00, 0x82, false},     // 1a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 1b
+	{cmos1C, "trb", amAbs, 3, 6, false, 0x00, 0x02, false},     // 1c
+	{op1D, "ora", amAbsX, 3, 4, true, 0x00, 0x82, false},       // 1d
+	{cmos1E, "asl", amAbsX, 3, 6, true, 0x00, 0x83, false},     // 1e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 1f
+	{op20, "jsr", amAbs, 3, 6, false, 0x00, 0x00, false},       // 20
+	{op21, "and", amXInd, 2, 6, false, 0x00, 0x82, false},      // 21
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 22
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 23
+	{op24, "bit", amZpg, 2, 3, false, 0x00, 0xc2, false},       // 24
+	{op25, "and", amZpg, 2, 3, false, 0x00, 0x82, false},       // 25
+	{op26, "rol", amZpg, 2, 5, false, 0x01, 0x83, false},       // 26
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 27
+	{op28, "plp", amImpl, 1, 4, false, 0x00, 0xdf, false},      // 28
+	{op29, "and", amImm, 2, 2, false, 0x00, 0x82, false},       // 29
+	{op2A, "rol", amAcc, 1, 2, false, 0x01, 0x83, false},       // 2a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 2b
+	{op2C, "bit", amAbs, 3, 4, false, 0x00, 0xc2, false},       // 2c
+	{op2D, "and", amAbs, 3, 4, false, 0x00, 0x82, false},       // 2d
+	{op2E, "rol", amAbs, 3, 6, false, 0x01, 0x83, false},       // 2e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 2f
+	{op30, "bmi", amRel, 2, 2, false, 0x80, 0x00, false},       // 30
+	{op31, "and", amIndY, 2, 5, true, 0x00, 0x82, false},       // 31
+	{cmos32, "and", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // 32
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 33
+	{cmos34, "bit", amZpgX, 2, 4, false, 0x00, 0xc2, false},    // 34
+	{op35, "and", amZpgX, 2, 4, false, 0x00, 0x82, false},      // 35
+	{op36, "rol", amZpgX, 2, 6, false, 0x01, 0x83, false},      // 36
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 37
+	{op38, "sec", amImpl, 1, 2, false, 0x00, 0x01, false},      // 38
+	{op39, "and", amAbsY, 3, 4, true, 0x00, 0x82, false},       // 39
+	{cmos3A, "dec", amAcc, 1, 2, false, 0x00, 0x82, false},     // 3a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 3b
+	{cmos3C, "bit", amAbsX, 3, 4, true, 0x00, 0xc2, false},     // 3c
+	{op3D, "and", amAbsX, 3, 4, true, 0x00, 0x82, false},       // 3d
+	{cmos3E, "rol", amAbsX, 3, 6, true, 0x01, 0x83, false},     // 3e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 3f
+	{op40, "rti", amImpl, 1, 6, false, 0x00, 0xdf, false},      // 40
+	{op41, "eor", amXInd, 2, 6, false, 0x00, 0x82, false},      // 41
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 42
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 43
+	{op44, "nop", amZpg, 2, 3, false, 0x00, 0x00, false},       // 44
+	{op45, "eor", amZpg, 2, 3, false, 0x00, 0x82, false},       // 45
+	{op46, "lsr", amZpg, 2, 5, false, 0x00, 0x83, false},       // 46
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 47
+	{op48, "pha", amImpl, 1, 3, false, 0x00, 0x00, false},      // 48
+	{op49, "eor", amImm, 2, 2, false, 0x00, 0x82, false},       // 49
+	{op4A, "lsr", amAcc, 1, 2, false, 0x00, 0x83, false},       // 4a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 4b
+	{op4C, "jmp", amAbs, 3, 3, false, 0x00, 0x00, false},       // 4c
+	{op4D, "eor", amAbs, 3, 4, false, 0x00, 0x82, false},       // 4d
+	{op4E, "lsr", amAbs, 3, 6, false, 0x00, 0x83, false},       // 4e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 4f
+	{op50, "bvc", amRel, 2, 2, false, 0x40, 0x00, false},       // 50
+	{op51, "eor", amIndY, 2, 5, true, 0x00, 0x82, false},       // 51
+	{cmos52, "eor", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // 52
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 53
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, false},      // 54
+	{op55, "eor", amZpgX, 2, 4, false, 0x00, 0x82, false},      // 55
+	{op56, "lsr", amZpgX, 2, 6, false, 0x00, 0x83, false},      // 56
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 57
+	{op58, "cli", amImpl, 1, 2, false, 0x00, 0x04, false},      // 58
+	{op59, "eor", amAbsY, 3, 4, true, 0x00, 0x82, false},       // 59
+	{cmos5A, "phy", amImpl, 1, 3, false, 0x00, 0x00, false},    // 5a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 5b
+	{op0C, "nop", amAbs, 3, 8, false, 0x00, 0x00, false},       // 5c
+	{op5D, "eor", amAbsX, 3, 4, true, 0x00, 0x82, false},       // 5d
+	{cmos5E, "lsr", amAbsX, 3, 6, true, 0x00, 0x83, false},     // 5e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 5f
+	{op60, "rts", amImpl, 1, 6, false, 0x00, 0x00, false},      // 60
+	{op61, "adc", amXInd, 2, 6, false, 0x09, 0xc3, false},      // 61
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 62
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 63
+	{cmos64, "stz", amZpg, 2, 3, false, 0x00, 0x00, false},     // 64
+	{op65, "adc", amZpg, 2, 3, false, 0x09, 0xc3, false},       // 65
+	{op66, "ror", amZpg, 2, 5, false, 0x01, 0x83, false},       // 66
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 67
+	{op68, "pla", amImpl, 1, 4, false, 0x00, 0x82, false},      // 68
+	{op69, "adc", amImm, 2, 2, false, 0x09, 0xc3, false},       // 69
+	{op6A, "ror", amAcc, 1, 2, false, 0x01, 0x83, false},       // 6a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 6b
+	{cmos6C, "jmp", amInd, 3, 6, false, 0x00, 0x00, false},     // 6c
+	{op6D, "adc", amAbs, 3, 4, false, 0x09, 0xc3, false},       // 6d
+	{op6E, "ror", amAbs, 3, 6, false, 0x01, 0x83, false},       // 6e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 6f
+	{op70, "bvs", amRel, 2, 2, false, 0x40, 0x00, false},       // 70
+	{op71, "adc", amIndY, 2, 5, true, 0x09, 0xc3, false},       // 71
+	{cmos72, "adc", amZpgInd, 2, 5, false, 0x09, 0xc3, false},  // 72
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 73
+	{cmos74, "stz", amZpgX, 2, 4, false, 0x00, 0x00, false},    // 74
+	{op75, "adc", amZpgX, 2, 4, false, 0x09, 0xc3, false},      // 75
+	{op76, "ror", amZpgX, 2, 6, false, 0x01, 0x83, false},      // 76
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 77
+	{op78, "sei", amImpl, 1, 2, false, 0x00, 0x04, false},      // 78
+	{op79, "adc", amAbsY, 3, 4, true, 0x09, 0xc3, false},       // 79
+	{cmos7A, "ply", amImpl, 1, 4, false, 0x00, 0x82, false},    // 7a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 7b
+	{cmos7C, "jmp", amAbsXInd, 3, 6, false, 0x00, 0x00, false}, // 7c
+	{op7D, "adc", amAbsX, 3, 4, true, 0x09, 0xc3, false},       // 7d
+	{cmos7E, "ror", amAbsX, 3, 6, true, 0x01, 0x83, false},     // 7e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 7f
+	{cmos80, "bra", amRel, 2, 2, false, 0x00, 0x00, false},     // 80
+	{op81, "sta", amXInd, 2, 6, false, 0x00, 0x00, false},      // 81
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 82
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 83
+	{op84, "sty", amZpg, 2, 3, false, 0x00, 0x00, false},       // 84
+	{op85, "sta", amZpg, 2, 3, false, 0x00, 0x00, false},       // 85
+	{op86, "stx", amZpg, 2, 3, false, 0x00, 0x00, false},       // 86
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 87
+	{op88, "dey", amImpl, 1, 2, false, 0x00, 0x82, false},      // 88
+	{cmos89, "bit", amImm, 2, 2, false, 0x00, 0x02, false},     // 89
+	{op8A, "txa", amImpl, 1, 2, false, 0x00, 0x82, false},      // 8a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 8b
+	{op8C, "sty", amAbs, 3, 4, false, 0x00, 0x00, false},       // 8c
+	{op8D, "sta", amAbs, 3, 4, false, 0x00, 0x00, false},       // 8d
+	{op8E, "stx", amAbs, 3, 4, false, 0x00, 0x00, false},       // 8e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 8f
+	{op90, "bcc", amRel, 2, 2, false, 0x01, 0x00, false},       // 90
+	{op91, "sta", amIndY, 2, 6, false, 0x00, 0x00, false},      // 91
+	{cmos92, "sta", amZpgInd, 2, 5, false, 0x00, 0x00, false},  // 92
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 93
+	{op94, "sty", amZpgX, 2, 4, false, 0x00, 0x00, false},      // 94
+	{op95, "sta", amZpgX, 2, 4, false, 0x00, 0x00, false},      // 95
+	{op96, "stx", amZpgY, 2, 4, false, 0x00, 0x00, false},      // 96
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 97
+	{op98, "tya", amImpl, 1, 2, false, 0x00, 0x82, false},      // 98
+	{op99, "sta", amAbsY, 3, 5, false, 0x00, 0x00, false},      // 99
+	{op9A, "txs", amImpl, 1, 2, false, 0x00, 0x00, false},      // 9a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 9b
+	{cmos9C, "stz", amAbs, 3, 4, false, 0x00, 0x00, false},     // 9c
+	{op9D, "sta", amAbsX, 3, 5, false, 0x00, 0x00, false},      // 9d
+	{cmos9E, "stz", amAbsX, 3, 5, false, 0x00, 0x00, false},    // 9e
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 9f
+	{opA0, "ldy", amImm, 2, 2, false, 0x00, 0x82, false},       // a0
+	{opA1, "lda", amXInd, 2, 6, false, 0x00, 0x82, false},      // a1
+	{opA2, "ldx", amImm, 2, 2, false, 0x00, 0x82, false},       // a2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // a3
+	{opA4, "ldy", amZpg, 2, 3, false, 0x00, 0x82, false},       // a4
+	{opA5, "lda", amZpg, 2, 3, false, 0x00, 0x82, false},       // a5
+	{opA6, "ldx", amZpg, 2, 3, false, 0x00, 0x82, false},       // a6
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // a7
+	{opA8, "tay", amImpl, 1, 2, false, 0x00, 0x82, false},      // a8
+	{opA9, "lda", amImm, 2, 2, false, 0x00, 0x82, false},       // a9
+	{opAA, "tax", amImpl, 1, 2, false, 0x00, 0x82, false},      // aa
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // ab
+	{opAC, "ldy", amAbs, 3, 4, false, 0x00, 0x82, false},       // ac
+	{opAD, "lda", amAbs, 3, 4, false, 0x00, 0x82, false},       // ad
+	{opAE, "ldx", amAbs, 3, 4, false, 0x00, 0x82, false},       // ae
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // af
+	{opB0, "bcs", amRel, 2, 2, false, 0x01, 0x00, false},       // b0
+	{opB1, "lda", amIndY, 2, 5, true, 0x00, 0x82, false},       // b1
+	{cmosB2, "lda", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // b2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // b3
+	{opB4, "ldy", amZpgX, 2, 4, false, 0x00, 0x82, false},      // b4
+	{opB5, "lda", amZpgX, 2, 4, false, 0x00, 0x82, false},      // b5
+	{opB6, "ldx", amZpgY, 2, 4, false, 0x00, 0x82, false},      // b6
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // b7
+	{opB8, "clv", amImpl, 1, 2, false, 0x00, 0x40, false},      // b8
+	{opB9, "lda", amAbsY, 3, 4, true, 0x00, 0x82, false},       // b9
+	{opBA, "tsx", amImpl, 1, 2, false, 0x00, 0x82, false},      // ba
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // bb
+	{opBC, "ldy", amAbsX, 3, 4, true, 0x00, 0x82, false},       // bc
+	{opBD, "lda", amAbsX, 3, 4, true, 0x00, 0x82, false},       // bd
+	{opBE, "ldx", amAbsY, 3, 4, true, 0x00, 0x82, false},       // be
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // bf
+	{opC0, "cpy", amImm, 2, 2, false, 0x00, 0x83, false},       // c0
+	{opC1, "cmp", amXInd, 2, 6, false, 0x00, 0x83, false},      // c1
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // c2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // c3
+	{opC4, "cpy", amZpg, 2, 3, false, 0x00, 0x83, false},       // c4
+	{opC5, "cmp", amZpg, 2, 3, false, 0x00, 0x83, false},       // c5
+	{opC6, "dec", amZpg, 2, 5, false, 0x00, 0x82, false},       // c6
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // c7
+	{opC8, "iny", amImpl, 1, 2, false, 0x00, 0x82, false},      // c8
+	{opC9, "cmp", amImm, 2, 2, false, 0x00, 0x83, false},       // c9
+	{opCA, "dex", amImpl, 1, 2, false, 0x00, 0x82, false},      // ca
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // cb
+	{opCC, "cpy", amAbs, 3, 4, false, 0x00, 0x83, false},       // cc
+	{opCD, "cmp", amAbs, 3, 4, false, 0x00, 0x83, false},       // cd
+	{opCE, "dec", amAbs, 3, 6, false, 0x00, 0x82, false},       // ce
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // cf
+	{opD0, "bne", amRel, 2, 2, false, 0x02, 0x00, false},       // d0
+	{opD1, "cmp", amIndY, 2, 5, true, 0x00, 0x83, false},       // d1
+	{cmosD2, "cmp", amZpgInd, 2, 5, false, 0x00, 0x83, false},  // d2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // d3
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, false},      // d4
+	{opD5, "cmp", amZpgX, 2, 4, false, 0x00, 0x83, false},      // d5
+	{opD6, "dec", amZpgX, 2, 6, false, 0x00, 0x82, false},      // d6
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // d7
+	{opD8, "cld", amImpl, 1, 2, false, 0x00, 0x08, false},      // d8
+	{opD9, "cmp", amAbsY, 3, 4, true, 0x00, 0x83, false},       // d9
+	{cmosDA, "phx", amImpl, 1, 3, false, 0x00, 0x00, false},    // da
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // db
+	{op0C, "nop", amAbs, 3, 4, false, 0x00, 0x00, false},       // dc
+	{opDD, "cmp", amAbsX, 3, 4, true, 0x00, 0x83, false},       // dd
+	{opDE, "dec", amAbsX, 3, 7, false, 0x00, 0x82, false},      // de
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // df
+	{opE0, "cpx", amImm, 2, 2, false, 0x00, 0x83, false},       // e0
+	{opE1, "sbc", amXInd, 2, 6, false, 0x09, 0xc3, false},      // e1
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // e2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // e3
+	{opE4, "cpx", amZpg, 2, 3, false, 0x00, 0x83, false},       // e4
+	{opE5, "sbc", amZpg, 2, 3, false, 0x09, 0xc3, false},       // e5
+	{opE6, "inc", amZpg, 2, 5, false, 0x00, 0x82, false},       // e6
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // e7
+	{opE8, "inx", amImpl, 1, 2, false, 0x00, 0x82, false},      // e8
+	{opE9, "sbc", amImm, 2, 2, false, 0x09, 0xc3, false},       // e9
+	{opEA, "nop", amImpl, 1, 2, false, 0x00, 0x00, false},      // ea
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // eb
+	{opEC, "cpx", amAbs, 3, 4, false, 0x00, 0x83, false},       // ec
+	{opED, "sbc", amAbs, 3, 4, false, 0x09, 0xc3, false},       // ed
+	{opEE, "inc", amAbs, 3, 6, false, 0x00, 0x82, false},       // ee
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // ef
+	{opF0, "beq", amRel, 2, 2, false, 0x02, 0x00, false},       // f0
+	{opF1, "sbc", amIndY, 2, 5, true, 0x09, 0xc3, false},       // f1
+	{cmosF2, "sbc", amZpgInd, 2, 5, false, 0x09, 0xc3, false},  // f2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // f3
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, false},      // f4
+	{opF5, "sbc", amZpgX, 2, 4, false, 0x09, 0xc3, false},      // f5
+	{opF6, "inc", amZpgX, 2, 6, false, 0x00, 0x82, false},      // f6
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // f7
+	{opF8, "sed", amImpl, 1, 2, false, 0x00, 0x08, false},      // f8
+	{opF9, "sbc", amAbsY, 3, 4, true, 0x09, 0xc3, false},       // f9
+	{cmosFA, "plx", amImpl, 1, 4, false, 0x00, 0x82, false},    // fa
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // fb
+	{op0C, "nop", amAbs, 3, 4, false, 0x00, 0x00, false},       // fc
+	{opFD, "sbc", amAbsX, 3, 4, true, 0x09, 0xc3, false},       // fd
+	{opFE, "inc", amAbsX, 3, 7, false, 0x00, 0x82, false},      // fe
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // ff
+}
+
+// opcodeTableR65C02 is the r65c02 dispatch and metadata table.
+var opcodeTableR65C02 = [256]opcode{
+	{cmos00, "brk", amImpl, 1, 7, false, 0x00, 0x0c, false},    // 00
+	{op01, "ora", amXInd, 2, 6, false, 0x00, 0x82, false},      // 01
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 02
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 03
+	{cmos04, "tsb", amZpg, 2, 5, false, 0x00, 0x02, false},     // 04
+	{op05, "ora", amZpg, 2, 3, false, 0x00, 0x82, false},       // 05
+	{op06, "asl", amZpg, 2, 5, false, 0x00, 0x83, false},       // 06
+	{cmos07, "rmb0", amZpg, 2, 5, false, 0x00, 0x00, false},    // 07
+	{op08, "php", amImpl, 1, 3, false, 0xdf, 0x00, false},      // 08
+	{op09, "ora", amImm, 2, 2, false, 0x00, 0x82, false},       // 09
+	{op0A, "asl", amAcc, 1, 2, false, 0x00, 0x83, false},       // 0a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 0b
+	{cmos0C, "tsb", amAbs, 3, 6, false, 0x00, 0x02, false},     // 0c
+	{op0D, "ora", amAbs, 3, 4, false, 0x00, 0x82, false},       // 0d
+	{op0E, "asl", amAbs, 3, 6, false, 0x00, 0x83, false},       // 0e
+	{cmos0F, "bbr0", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 0f
+	{op10, "bpl", amRel, 2, 2, false, 0x80, 0x00, false},       // 10
+	{op11, "ora", amIndY, 2, 5, true, 0x00, 0x82, false},       // 11
+	{cmos12, "ora", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // 12
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 13
+	{cmos14, "trb", amZpg, 2, 5, false, 0x00, 0x02, false},     // 14
+	{op15, "ora", amZpgX, 2, 4, false, 0x00, 0x82, false},      // 15
+	{op16, "asl", amZpgX, 2, 6, false, 0x00, 0x83, false},      // 16
+	{cmos17, "rmb1", amZpg, 2, 5, false, 0x00, 0x00, false},    // 17
+	{op18, "clc", amImpl, 1, 2, false, 0x00, 0x01, false},      // 18
+	{op19, "ora", amAbsY, 3, 4, true, 0x00, 0x82, false},       // 19
+	{cmos1A, "inc", amAcc, 1, 2, false, 0x00, 0x82, false},     // 1a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 1b
+	{cmos1C, "trb", amAbs, 3, 6, false, 0x00, 0x02, false},     // 1c
+	{op1D, "ora", amAbsX, 3, 4, true, 0x00, 0x82, false},       // 1d
+	{cmos1E, "asl", amAbsX, 3, 6, true, 0x00, 0x83, false},     // 1e
+	{cmos1F, "bbr1", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 1f
+	{op20, "jsr", amAbs, 3, 6, false, 0x00, 0x00, false},       // 20
+	{op21, "and", amXInd, 2, 6, false, 0x00, 0x82, false},      // 21
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 22
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 23
+	{op24, "bit", amZpg, 2, 3, false, 0x00, 0xc2, false},       // 24
+	{op25, "and", amZpg, 2, 3, false, 0x00, 0x82, false},       // 25
+	{op26, "rol", amZpg, 2, 5, false, 0x01, 0x83, false},       // 26
+	{cmos27, "rmb2", amZpg, 2, 5, false, 0x00, 0x00, false},    // 27
+	{op28, "plp", amImpl, 1, 4, false, 0x00, 0xdf, false},      // 28
+	{op29, "and", amImm, 2, 2, false, 0x00, 0x82, false},       // 29
+	{op2A, "rol", amAcc, 1, 2, false, 0x01, 0x83, false},       // 2a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 2b
+	{op2C, "bit", amAbs, 3, 4, false, 0x00, 0xc2, false},       // 2c
+	{op2D, "and", amAbs, 3, 4, false, 0x00, 0x82, false},       // 2d
+	{op2E, "rol", amAbs, 3, 6, false, 0x01, 0x83, false},       // 2e
+	{cmos2F, "bbr2", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 2f
+	{op30, "bmi", amRel, 2, 2, false, 0x80, 0x00, false},       // 30
+	{op31, "and", amIndY, 2, 5, true, 0x00, 0x82, false},       // 31
+	{cmos32, "and", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // 32
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 33
+	{cmos34, "bit", amZpgX, 2, 4, false, 0x00, 0xc2, false},    // 34
+	{op35, "and", amZpgX, 2, 4, false, 0x00, 0x82, false},      // 35
+	{op36, "rol", amZpgX, 2, 6, false, 0x01, 0x83, false},      // 36
+	{cmos37, "rmb3", amZpg, 2, 5, false, 0x00, 0x00, false},    // 37
+	{op38, "sec", amImpl, 1, 2, false, 0x00, 0x01, false},      // 38
+	{op39, "and", amAbsY, 3, 4, true, 0x00, 0x82, false},       // 39
+	{cmos3A, "dec", amAcc, 1, 2, false, 0x00, 0x82, false},     // 3a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 3b
+	{cmos3C, "bit", amAbsX, 3, 4, true, 0x00, 0xc2, false},     // 3c
+	{op3D, "and", amAbsX, 3, 4, true, 0x00, 0x82, false},       // 3d
+	{cmos3E, "rol", amAbsX, 3, 6, true, 0x01, 0x83, false},     // 3e
+	{cmos3F, "bbr3", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 3f
+	{op40, "rti", amImpl, 1, 6, false, 0x00, 0xdf, false},      // 40
+	{op41, "eor", amXInd, 2, 6, false, 0x00, 0x82, false},      // 41
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 42
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 43
+	{op44, "nop", amZpg, 2, 3, false, 0x00, 0x00, false},       // 44
+	{op45, "eor", amZpg, 2, 3, false, 0x00, 0x82, false},       // 45
+	{op46, "lsr", amZpg, 2, 5, false, 0x00, 0x83, false},       // 46
+	{cmos47, "rmb4", amZpg, 2, 5, false, 0x00, 0x00, false},    // 47
+	{op48, "pha", amImpl, 1, 3, false, 0x00, 0x00, false},      // 48
+	{op49, "eor", amImm, 2, 2, false, 0x00, 0x82, false},       // 49
+	{op4A, "lsr", amAcc, 1, 2, false, 0x00, 0x83, false},       // 4a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 4b
+	{op4C, "jmp", amAbs, 3, 3, false, 0x00, 0x00, false},       // 4c
+	{op4D, "eor", amAbs, 3, 4, false, 0x00, 0x82, false},       // 4d
+	{op4E, "lsr", amAbs, 3, 6, false, 0x00, 0x83, false},       // 4e
+	{cmos4F, "bbr4", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 4f
+	{op50, "bvc", amRel, 2, 2, false, 0x40, 0x00, false},       // 50
+	{op51, "eor", amIndY, 2, 5, true, 0x00, 0x82, false},       // 51
+	{cmos52, "eor", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // 52
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 53
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, false},      // 54
+	{op55, "eor", amZpgX, 2, 4, false, 0x00, 0x82, false},      // 55
+	{op56, "lsr", amZpgX, 2, 6, false, 0x00, 0x83, false},      // 56
+	{cmos57, "rmb5", amZpg, 2, 5, false, 0x00, 0x00, false},    // 57
+	{op58, "cli", amImpl, 1, 2, false, 0x00, 0x04, false},      // 58
+	{op59, "eor", amAbsY, 3, 4, true, 0x00, 0x82, false},       // 59
+	{cmos5A, "phy", amImpl, 1, 3, false, 0x00, 0x00, false},    // 5a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 5b
+	{op0C, "nop", amAbs, 3, 8, false, 0x00, 0x00, false},       // 5c
+	{op5D, "eor", amAbsX, 3, 4, true, 0x00, 0x82, false},       // 5d
+	{cmos5E, "lsr", amAbsX, 3, 6, true, 0x00, 0x83, false},     // 5e
+	{cmos5F, "bbr5", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 5f
+	{op60, "rts", amImpl, 1, 6, false, 0x00, 0x00, false},      // 60
+	{op61, "adc", amXInd, 2, 6, false, 0x09, 0xc3, false},      // 61
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 62
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 63
+	{cmos64, "stz", amZpg, 2, 3, false, 0x00, 0x00, false},     // 64
+	{op65, "adc", amZpg, 2, 3, false, 0x09, 0xc3, false},       // 65
+	{op66, "ror", amZpg, 2, 5, false, 0x01, 0x83, false},       // 66
+	{cmos67, "rmb6", amZpg, 2, 5, false, 0x00, 0x00, false},    // 67
+	{op68, "pla", amImpl, 1, 4, false, 0x00, 0x82, false},      // 68
+	{op69, "adc", amImm, 2, 2, false, 0x09, 0xc3, false},       // 69
+	{op6A, "ror", amAcc, 1, 2, false, 0x01, 0x83, false},       // 6a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 6b
+	{cmos6C, "jmp", amInd, 3, 6, false, 0x00, 0x00, false},     // 6c
+	{op6D, "adc", amAbs, 3, 4, false, 0x09, 0xc3, false},       // 6d
+	{op6E, "ror", amAbs, 3, 6, false, 0x01, 0x83, false},       // 6e
+	{cmos6F, "bbr6", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 6f
+	{op70, "bvs", amRel, 2, 2, false, 0x40, 0x00, false},       // 70
+	{op71, "adc", amIndY, 2, 5, true, 0x09, 0xc3, false},       // 71
+	{cmos72, "adc", amZpgInd, 2, 5, false, 0x09, 0xc3, false},  // 72
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 73
+	{cmos74, "stz", amZpgX, 2, 4, false, 0x00, 0x00, false},    // 74
+	{op75, "adc", amZpgX, 2, 4, false, 0x09, 0xc3, false},      // 75
+	{op76, "ror", amZpgX, 2, 6, false, 0x01, 0x83, false},      // 76
+	{cmos77, "rmb7", amZpg, 2, 5, false, 0x00, 0x00, false},    // 77
+	{op78, "sei", amImpl, 1, 2, false, 0x00, 0x04, false},      // 78
+	{op79, "adc", amAbsY, 3, 4, true, 0x09, 0xc3, false},       // 79
+	{cmos7A, "ply", amImpl, 1, 4, false, 0x00, 0x82, false},    // 7a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 7b
+	{cmos7C, "jmp", amAbsXInd, 3, 6, false, 0x00, 0x00, false}, // 7c
+	{op7D, "adc", amAbsX, 3, 4, true, 0x09, 0xc3, false},       // 7d
+	{cmos7E, "ror", amAbsX, 3, 6, true, 0x01, 0x83, false},     // 7e
+	{cmos7F, "bbr7", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 7f
+	{cmos80, "bra", amRel, 2, 2, false, 0x00, 0x00, false},     // 80
+	{op81, "sta", amXInd, 2, 6, false, 0x00, 0x00, false},      // 81
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 82
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 83
+	{op84, "sty", amZpg, 2, 3, false, 0x00, 0x00, false},       // 84
+	{op85, "sta", amZpg, 2, 3, false, 0x00, 0x00, false},       // 85
+	{op86, "stx", amZpg, 2, 3, false, 0x00, 0x00, false},       // 86
+	{cmos87, "smb0", amZpg, 2, 5, false, 0x00, 0x00, false},    // 87
+	{op88, "dey", amImpl, 1, 2, false, 0x00, 0x82, false},      // 88
+	{cmos89, "bit", amImm, 2, 2, false, 0x00, 0x02, false},     // 89
+	{op8A, "txa", amImpl, 1, 2, false, 0x00, 0x82, false},      // 8a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 8b
+	{op8C, "sty", amAbs, 3, 4, false, 0x00, 0x00, false},       // 8c
+	{op8D, "sta", amAbs, 3, 4, false, 0x00, 0x00, false},       // 8d
+	{op8E, "stx", amAbs, 3, 4, false, 0x00, 0x00, false},       // 8e
+	{cmos8F, "bbs0", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 8f
+	{op90, "bcc", amRel, 2, 2, false, 0x01, 0x00, false},       // 90
+	{op91, "sta", amIndY, 2, 6, false, 0x00, 0x00, false},      // 91
+	{cmos92, "sta", amZpgInd, 2, 5, false, 0x00, 0x00, false},  // 92
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 93
+	{op94, "sty", amZpgX, 2, 4, false, 0x00, 0x00, false},      // 94
+	{op95, "sta", amZpgX, 2, 4, false, 0x00, 0x00, false},      // 95
+	{op96, "stx", amZpgY, 2, 4, false, 0x00, 0x00, false},      // 96
+	{cmos97, "smb1", amZpg, 2, 5, false, 0x00, 0x00, false},    // 97
+	{op98, "tya", amImpl, 1, 2, false, 0x00, 0x82, false},      // 98
+	{op99, "sta", amAbsY, 3, 5, false, 0x00, 0x00, false},      // 99
+	{op9A, "txs", amImpl, 1, 2, false, 0x00, 0x00, false},      // 9a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 9b
+	{cmos9C, "stz", amAbs, 3, 4, false, 0x00, 0x00, false},     // 9c
+	{op9D, "sta", amAbsX, 3, 5, false, 0x00, 0x00, false},      // 9d
+	{cmos9E, "stz", amAbsX, 3, 5, false, 0x00, 0x00, false},    // 9e
+	{cmos9F, "bbs1", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 9f
+	{opA0, "ldy", amImm, 2, 2, false, 0x00, 0x82, false},       // a0
+	{opA1, "lda", amXInd, 2, 6, false, 0x00, 0x82, false},      // a1
+	{opA2, "ldx", amImm, 2, 2, false, 0x00, 0x82, false},       // a2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // a3
+	{opA4, "ldy", amZpg, 2, 3, false, 0x00, 0x82, false},       // a4
+	{opA5, "lda", amZpg, 2, 3, false, 0x00, 0x82, false},       // a5
+	{opA6, "ldx", amZpg, 2, 3, false, 0x00, 0x82, false},       // a6
+	{cmosA7, "smb2", amZpg, 2, 5, false, 0x00, 0x00, false},    // a7
+	{opA8, "tay", amImpl, 1, 2, false, 0x00, 0x82, false},      // a8
+	{opA9, "lda", amImm, 2, 2, false, 0x00, 0x82, false},       // a9
+	{opAA, "tax", amImpl, 1, 2, false, 0x00, 0x82, false},      // aa
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // ab
+	{opAC, "ldy", amAbs, 3, 4, false, 0x00, 0x82, false},       // ac
+	{opAD, "lda", amAbs, 3, 4, false, 0x00, 0x82, false},       // ad
+	{opAE, "ldx", amAbs, 3, 4, false, 0x00, 0x82, false},       // ae
+	{cmosAF, "bbs2", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // af
+	{opB0, "bcs", amRel, 2, 2, false, 0x01, 0x00, false},       // b0
+	{opB1, "lda", amIndY, 2, 5, true, 0x00, 0x82, false},       // b1
+	{cmosB2, "lda", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // b2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // b3
+	{opB4, "ldy", amZpgX, 2, 4, false, 0x00, 0x82, false},      // b4
+	{opB5, "lda", amZpgX, 2, 4, false, 0x00, 0x82, false},      // b5
+	{opB6, "ldx", amZpgY, 2, 4, false, 0x00, 0x82, false},      // b6
+	{cmosB7, "smb3", amZpg, 2, 5, false, 0x00, 0x00, false},    // b7
+	{opB8, "clv", amImpl, 1, 2, false, 0x00, 0x40, false},      // b8
+	{opB9, "lda", amAbsY, 3, 4, true, 0x00, 0x82, false},       // b9
+	{opBA, "tsx", amImpl, 1, 2, false, 0x00, 0x82, false},      // ba
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // bb
+	{opBC, "ldy", amAbsX, 3, 4, true, 0x00, 0x82, false},       // bc
+	{opBD, "lda", amAbsX, 3, 4, true, 0x00, 0x82, false},       // bd
+	{opBE, "ldx", amAbsY, 3, 4, true, 0x00, 0x82, false},       // be
+	{cmosBF, "bbs3", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // bf
+	{opC0, "cpy", amImm, 2, 2, false, 0x00, 0x83, false},       // c0
+	{opC1, "cmp", amXInd, 2, 6, false, 0x00, 0x83, false},      // c1
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // c2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // c3
+	{opC4, "cpy", amZpg, 2, 3, false, 0x00, 0x83, false},       // c4
+	{opC5, "cmp", amZpg, 2, 3, false, 0x00, 0x83, false},       // c5
+	{opC6, "dec", amZpg, 2, 5, false, 0x00, 0x82, false},       // c6
+	{cmosC7, "smb4", amZpg, 2, 5, false, 0x00, 0x00, false},    // c7
+	{opC8, "iny", amImpl, 1, 2, false, 0x00, 0x82, false},      // c8
+	{opC9, "cmp", amImm, 2, 2, false, 0x00, 0x83, false},       // c9
+	{opCA, "dex", amImpl, 1, 2, false, 0x00, 0x82, false},      // ca
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // cb
+	{opCC, "cpy", amAbs, 3, 4, false, 0x00, 0x83, false},       // cc
+	{opCD, "cmp", amAbs, 3, 4, false, 0x00, 0x83, false},       // cd
+	{opCE, "dec", amAbs, 3, 6, false, 0x00, 0x82, false},       // ce
+	{cmosCF, "bbs4", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // cf
+	{opD0, "bne", amRel, 2, 2, false, 0x02, 0x00, false},       // d0
+	{opD1, "cmp", amIndY, 2, 5, true, 0x00, 0x83, false},       // d1
+	{cmosD2, "cmp", amZpgInd, 2, 5, false, 0x00, 0x83, false},  // d2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // d3
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, false},      // d4
+	{opD5, "cmp", amZpgX, 2, 4, false, 0x00, 0x83, false},      // d5
+	{opD6, "dec", amZpgX, 2, 6, false, 0x00, 0x82, false},      // d6
+	{cmosD7, "smb5", amZpg, 2, 5, false, 0x00, 0x00, false},    // d7
+	{opD8, "cld", amImpl, 1, 2, false, 0x00, 0x08, false},      // d8
+	{opD9, "cmp", amAbsY, 3, 4, true, 0x00, 0x83, false},       // d9
+	{cmosDA, "phx", amImpl, 1, 3, false, 0x00, 0x00, false},    // da
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // db
+	{op0C, "nop", amAbs, 3, 4, false, 0x00, 0x00, false},       // dc
+	{opDD, "cmp", amAbsX, 3, 4, true, 0x00, 0x83, false},       // dd
+	{opDE, "dec", amAbsX, 3, 7, false, 0x00, 0x82, false},      // de
+	{cmosDF, "bbs5", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // df
+	{opE0, "cpx", amImm, 2, 2, false, 0x00, 0x83, false},       // e0
+	{opE1, "sbc", amXInd, 2, 6, false, 0x09, 0xc3, false},      // e1
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // e2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // e3
+	{opE4, "cpx", amZpg, 2, 3, false, 0x00, 0x83, false},       // e4
+	{opE5, "sbc", amZpg, 2, 3, false, 0x09, 0xc3, false},       // e5
+	{opE6, "inc", amZpg, 2, 5, false, 0x00, 0x82, false},       // e6
+	{cmosE7, "smb6", amZpg, 2, 5, false, 0x00, 0x00, false},    // e7
+	{opE8, "inx", amImpl, 1, 2, false, 0x00, 0x82, false},      // e8
+	{opE9, "sbc", amImm, 2, 2, false, 0x09, 0xc3, false},       // e9
+	{opEA, "nop", amImpl, 1, 2, false, 0x00, 0x00, false},      // ea
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // eb
+	{opEC, "cpx", amAbs, 3, 4, false, 0x00, 0x83, false},       // ec
+	{opED, "sbc", amAbs, 3, 4, false, 0x09, 0xc3, false},       // ed
+	{opEE, "inc", amAbs, 3, 6, false, 0x00, 0x82, false},       // ee
+	{cmosEF, "bbs6", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // ef
+	{opF0, "beq", amRel, 2, 2, false, 0x02, 0x00, false},       // f0
+	{opF1, "sbc", amIndY, 2, 5, true, 0x09, 0xc3, false},       // f1
+	{cmosF2, "sbc", amZpgInd, 2, 5, false, 0x09, 0xc3, false},  // f2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // f3
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, false},      // f4
+	{opF5, "sbc", amZpgX, 2, 4, false, 0x09, 0xc3, false},      // f5
+	{opF6, "inc", amZpgX, 2, 6, false, 0x00, 0x82, false},      // f6
+	{cmosF7, "smb7", amZpg, 2, 5, false, 0x00, 0x00, false},    // f7
+	{opF8, "sed", amImpl, 1, 2, false, 0x00, 0x08, false},      // f8
+	{opF9, "sbc", amAbsY, 3, 4, true, 0x09, 0xc3, false},       // f9
+	{cmosFA, "plx", amImpl, 1, 4, false, 0x00, 0x82, false},    // fa
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // fb
+	{op0C, "nop", amAbs, 3, 4, false, 0x00, 0x00, false},       // fc
+	{opFD, "sbc", amAbsX, 3, 4, true, 0x09, 0xc3, false},       // fd
+	{opFE, "inc", amAbsX, 3, 7, false, 0x00, 0x82, false},      // fe
+	{cmosFF, "bbs7", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // ff
+}
+
+// opcodeTableW65C02S is the w65c02s dispatch and metadata table.
+var opcodeTableW65C02S = [256]opcode{
+	{cmos00, "brk", amImpl, 1, 7, false, 0x00, 0x0c, false},    // 00
+	{op01, "ora", amXInd, 2, 6, false, 0x00, 0x82, false},      // 01
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 02
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 03
+	{cmos04, "tsb", amZpg, 2, 5, false, 0x00, 0x02, false},     // 04
+	{op05, "ora", amZpg, 2, 3, false, 0x00, 0x82, false},       // 05
+	{op06, "asl", amZpg, 2, 5, false, 0x00, 0x83, false},       // 06
+	{cmos07, "rmb0", amZpg, 2, 5, false, 0x00, 0x00, false},    // 07
+	{op08, "php", amImpl, 1, 3, false, 0xdf, 0x00, false},      // 08
+	{op09, "ora", amImm, 2, 2, false, 0x00, 0x82, false},       // 09
+	{op0A, "asl", amAcc, 1, 2, false, 0x00, 0x83, false},       // 0a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 0b
+	{cmos0C, "tsb", amAbs, 3, 6, false, 0x00, 0x02, false},     // 0c
+	{op0D, "ora", amAbs, 3, 4, false, 0x00, 0x82, false},       // 0d
+	{op0E, "asl", amAbs, 3, 6, false, 0x00, 0x83, false},       // 0e
+	{cmos0F, "bbr0", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 0f
+	{op10, "bpl", amRel, 2, 2, false, 0x80, 0x00, false},       // 10
+	{op11, "ora", amIndY, 2, 5, true, 0x00, 0x82, false},       // 11
+	{cmos12, "ora", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // 12
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 13
+	{cmos14, "trb", amZpg, 2, 5, false, 0x00, 0x02, false},     // 14
+	{op15, "ora", amZpgX, 2, 4, false, 0x00, 0x82, false},      // 15
+	{op16, "asl", amZpgX, 2, 6, false, 0x00, 0x83, false},      // 16
+	{cmos17, "rmb1", amZpg, 2, 5, false, 0x00, 0x00, false},    // 17
+	{op18, "clc", amImpl, 1, 2, false, 0x00, 0x01, false},      // 18
+	{op19, "ora", amAbsY, 3, 4, true, 0x00, 0x82, false},       // 19
+	{cmos1A, "inc", amAcc, 1, 2, false, 0x00, 0x82, false},     // 1a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 1b
+	{cmos1C, "trb", amAbs, 3, 6, false, 0x00, 0x02, false},     // 1c
+	{op1D, "ora", amAbsX, 3, 4, true, 0x00, 0x82, false},       // 1d
+	{cmos1E, "asl", amAbsX, 3, 6, true, 0x00, 0x83, false},     // 1e
+	{cmos1F, "bbr1", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 1f
+	{op20, "jsr", amAbs, 3, 6, false, 0x00, 0x00, false},       // 20
+	{op21, "and", amXInd, 2, 6, false, 0x00, 0x82, false},      // 21
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 22
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 23
+	{op24, "bit", amZpg, 2, 3, false, 0x00, 0xc2, false},       // 24
+	{op25, "and", amZpg, 2, 3, false, 0x00, 0x82, false},       // 25
+	{op26, "rol", amZpg, 2, 5, false, 0x01, 0x83, false},       // 26
+	{cmos27, "rmb2", amZpg, 2, 5, false, 0x00, 0x00, false},    // 27
+	{op28, "plp", amImpl, 1, 4, false, 0x00, 0xdf, false},      // 28
+	{op29, "and", amImm, 2, 2, false, 0x00, 0x82, false},       // 29
+	{op2A, "rol", amAcc, 1, 2, false, 0x01, 0x83, false},       // 2a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 2b
+	{op2C, "bit", amAbs, 3, 4, false, 0x00, 0xc2, false},       // 2c
+	{op2D, "and", amAbs, 3, 4, false, 0x00, 0x82, false},       // 2d
+	{op2E, "rol", amAbs, 3, 6, false, 0x01, 0x83, false},       // 2e
+	{cmos2F, "bbr2", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 2f
+	{op30, "bmi", amRel, 2, 2, false, 0x80, 0x00, false},       // 30
+	{op31, "and", amIndY, 2, 5, true, 0x00, 0x82, false},       // 31
+	{cmos32, "and", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // 32
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 33
+	{cmos34, "bit", amZpgX, 2, 4, false, 0x00, 0xc2, false},    // 34
+	{op35, "and", amZpgX, 2, 4, false, 0x00, 0x82, false},      // 35
+	{op36, "rol", amZpgX, 2, 6, false, 0x01, 0x83, false},      // 36
+	{cmos37, "rmb3", amZpg, 2, 5, false, 0x00, 0x00, false},    // 37
+	{op38, "sec", amImpl, 1, 2, false, 0x00, 0x01, false},      // 38
+	{op39, "and", amAbsY, 3, 4, true, 0x00, 0x82, false},       // 39
+	{cmos3A, "dec", amAcc, 1, 2, false, 0x00, 0x82, false},     // 3a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 3b
+	{cmos3C, "bit", amAbsX, 3, 4, true, 0x00, 0xc2, false},     // 3c
+	{op3D, "and", amAbsX, 3, 4, true, 0x00, 0x82, false},       // 3d
+	{cmos3E, "rol", amAbsX, 3, 6, true, 0x01, 0x83, false},     // 3e
+	{cmos3F, "bbr3", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 3f
+	{op40, "rti", amImpl, 1, 6, false, 0x00, 0xdf, false},      // 40
+	{op41, "eor", amXInd, 2, 6, false, 0x00, 0x82, false},      // 41
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 42
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 43
+	{op44, "nop", amZpg, 2, 3, false, 0x00, 0x00, false},       // 44
+	{op45, "eor", amZpg, 2, 3, false, 0x00, 0x82, false},       // 45
+	{op46, "lsr", amZpg, 2, 5, false, 0x00, 0x83, false},       // 46
+	{cmos47, "rmb4", amZpg, 2, 5, false, 0x00, 0x00, false},    // 47
+	{op48, "pha", amImpl, 1, 3, false, 0x00, 0x00, false},      // 48
+	{op49, "eor", amImm, 2, 2, false, 0x00, 0x82, false},       // 49
+	{op4A, "lsr", amAcc, 1, 2, false, 0x00, 0x83, false},       // 4a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 4b
+	{op4C, "jmp", amAbs, 3, 3, false, 0x00, 0x00, false},       // 4c
+	{op4D, "eor", amAbs, 3, 4, false, 0x00, 0x82, false},       // 4d
+	{op4E, "lsr", amAbs, 3, 6, false, 0x00, 0x83, false},       // 4e
+	{cmos4F, "bbr4", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 4f
+	{op50, "bvc", amRel, 2, 2, false, 0x40, 0x00, false},       // 50
+	{op51, "eor", amIndY, 2, 5, true, 0x00, 0x82, false},       // 51
+	{cmos52, "eor", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // 52
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 53
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, false},      // 54
+	{op55, "eor", amZpgX, 2, 4, false, 0x00, 0x82, false},      // 55
+	{op56, "lsr", amZpgX, 2, 6, false, 0x00, 0x83, false},      // 56
+	{cmos57, "rmb5", amZpg, 2, 5, false, 0x00, 0x00, false},    // 57
+	{op58, "cli", amImpl, 1, 2, false, 0x00, 0x04, false},      // 58
+	{op59, "eor", amAbsY, 3, 4, true, 0x00, 0x82, false},       // 59
+	{cmos5A, "phy", amImpl, 1, 3, false, 0x00, 0x00, false},    // 5a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 5b
+	{op0C, "nop", amAbs, 3, 8, false, 0x00, 0x00, false},       // 5c
+	{op5D, "eor", amAbsX, 3, 4, true, 0x00, 0x82, false},       // 5d
+	{cmos5E, "lsr", amAbsX, 3, 6, true, 0x00, 0x83, false},     // 5e
+	{cmos5F, "bbr5", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 5f
+	{op60, "rts", amImpl, 1, 6, false, 0x00, 0x00, false},      // 60
+	{op61, "adc", amXInd, 2, 6, false, 0x09, 0xc3, false},      // 61
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 62
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 63
+	{cmos64, "stz", amZpg, 2, 3, false, 0x00, 0x00, false},     // 64
+	{op65, "adc", amZpg, 2, 3, false, 0x09, 0xc3, false},       // 65
+	{op66, "ror", amZpg, 2, 5, false, 0x01, 0x83, false},       // 66
+	{cmos67, "rmb6", amZpg, 2, 5, false, 0x00, 0x00, false},    // 67
+	{op68, "pla", amImpl, 1, 4, false, 0x00, 0x82, false},      // 68
+	{op69, "adc", amImm, 2, 2, false, 0x09, 0xc3, false},       // 69
+	{op6A, "ror", amAcc, 1, 2, false, 0x01, 0x83, false},       // 6a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 6b
+	{cmos6C, "jmp", amInd, 3, 6, false, 0x00, 0x00, false},     // 6c
+	{op6D, "adc", amAbs, 3, 4, false, 0x09, 0xc3, false},       // 6d
+	{op6E, "ror", amAbs, 3, 6, false, 0x01, 0x83, false},       // 6e
+	{cmos6F, "bbr6", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 6f
+	{op70, "bvs", amRel, 2, 2, false, 0x40, 0x00, false},       // 70
+	{op71, "adc", amIndY, 2, 5, true, 0x09, 0xc3, false},       // 71
+	{cmos72, "adc", amZpgInd, 2, 5, false, 0x09, 0xc3, false},  // 72
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 73
+	{cmos74, "stz", amZpgX, 2, 4, false, 0x00, 0x00, false},    // 74
+	{op75, "adc", amZpgX, 2, 4, false, 0x09, 0xc3, false},      // 75
+	{op76, "ror", amZpgX, 2, 6, false, 0x01, 0x83, false},      // 76
+	{cmos77, "rmb7", amZpg, 2, 5, false, 0x00, 0x00, false},    // 77
+	{op78, "sei", amImpl, 1, 2, false, 0x00, 0x04, false},      // 78
+	{op79, "adc", amAbsY, 3, 4, true, 0x09, 0xc3, false},       // 79
+	{cmos7A, "ply", amImpl, 1, 4, false, 0x00, 0x82, false},    // 7a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 7b
+	{cmos7C, "jmp", amAbsXInd, 3, 6, false, 0x00, 0x00, false}, // 7c
+	{op7D, "adc", amAbsX, 3, 4, true, 0x09, 0xc3, false},       // 7d
+	{cmos7E, "ror", amAbsX, 3, 6, true, 0x01, 0x83, false},     // 7e
+	{cmos7F, "bbr7", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 7f
+	{cmos80, "bra", amRel, 2, 2, false, 0x00, 0x00, false},     // 80
+	{op81, "sta", amXInd, 2, 6, false, 0x00, 0x00, false},      // 81
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // 82
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 83
+	{op84, "sty", amZpg, 2, 3, false, 0x00, 0x00, false},       // 84
+	{op85, "sta", amZpg, 2, 3, false, 0x00, 0x00, false},       // 85
+	{op86, "stx", amZpg, 2, 3, false, 0x00, 0x00, false},       // 86
+	{cmos87, "smb0", amZpg, 2, 5, false, 0x00, 0x00, false},    // 87
+	{op88, "dey", amImpl, 1, 2, false, 0x00, 0x82, false},      // 88
+	{cmos89, "bit", amImm, 2, 2, false, 0x00, 0x02, false},     // 89
+	{op8A, "txa", amImpl, 1, 2, false, 0x00, 0x82, false},      // 8a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 8b
+	{op8C, "sty", amAbs, 3, 4, false, 0x00, 0x00, false},       // 8c
+	{op8D, "sta", amAbs, 3, 4, false, 0x00, 0x00, false},       // 8d
+	{op8E, "stx", amAbs, 3, 4, false, 0x00, 0x00, false},       // 8e
+	{cmos8F, "bbs0", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 8f
+	{op90, "bcc", amRel, 2, 2, false, 0x01, 0x00, false},       // 90
+	{op91, "sta", amIndY, 2, 6, false, 0x00, 0x00, false},      // 91
+	{cmos92, "sta", amZpgInd, 2, 5, false, 0x00, 0x00, false},  // 92
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 93
+	{op94, "sty", amZpgX, 2, 4, false, 0x00, 0x00, false},      // 94
+	{op95, "sta", amZpgX, 2, 4, false, 0x00, 0x00, false},      // 95
+	{op96, "stx", amZpgY, 2, 4, false, 0x00, 0x00, false},      // 96
+	{cmos97, "smb1", amZpg, 2, 5, false, 0x00, 0x00, false},    // 97
+	{op98, "tya", amImpl, 1, 2, false, 0x00, 0x82, false},      // 98
+	{op99, "sta", amAbsY, 3, 5, false, 0x00, 0x00, false},      // 99
+	{op9A, "txs", amImpl, 1, 2, false, 0x00, 0x00, false},      // 9a
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // 9b
+	{cmos9C, "stz", amAbs, 3, 4, false, 0x00, 0x00, false},     // 9c
+	{op9D, "sta", amAbsX, 3, 5, false, 0x00, 0x00, false},      // 9d
+	{cmos9E, "stz", amAbsX, 3, 5, false, 0x00, 0x00, false},    // 9e
+	{cmos9F, "bbs1", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // 9f
+	{opA0, "ldy", amImm, 2, 2, false, 0x00, 0x82, false},       // a0
+	{opA1, "lda", amXInd, 2, 6, false, 0x00, 0x82, false},      // a1
+	{opA2, "ldx", amImm, 2, 2, false, 0x00, 0x82, false},       // a2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // a3
+	{opA4, "ldy", amZpg, 2, 3, false, 0x00, 0x82, false},       // a4
+	{opA5, "lda", amZpg, 2, 3, false, 0x00, 0x82, false},       // a5
+	{opA6, "ldx", amZpg, 2, 3, false, 0x00, 0x82, false},       // a6
+	{cmosA7, "smb2", amZpg, 2, 5, false, 0x00, 0x00, false},    // a7
+	{opA8, "tay", amImpl, 1, 2, false, 0x00, 0x82, false},      // a8
+	{opA9, "lda", amImm, 2, 2, false, 0x00, 0x82, false},       // a9
+	{opAA, "tax", amImpl, 1, 2, false, 0x00, 0x82, false},      // aa
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // ab
+	{opAC, "ldy", amAbs, 3, 4, false, 0x00, 0x82, false},       // ac
+	{opAD, "lda", amAbs, 3, 4, false, 0x00, 0x82, false},       // ad
+	{opAE, "ldx", amAbs, 3, 4, false, 0x00, 0x82, false},       // ae
+	{cmosAF, "bbs2", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // af
+	{opB0, "bcs", amRel, 2, 2, false, 0x01, 0x00, false},       // b0
+	{opB1, "lda", amIndY, 2, 5, true, 0x00, 0x82, false},       // b1
+	{cmosB2, "lda", amZpgInd, 2, 5, false, 0x00, 0x82, false},  // b2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // b3
+	{opB4, "ldy", amZpgX, 2, 4, false, 0x00, 0x82, false},      // b4
+	{opB5, "lda", amZpgX, 2, 4, false, 0x00, 0x82, false},      // b5
+	{opB6, "ldx", amZpgY, 2, 4, false, 0x00, 0x82, false},      // b6
+	{cmosB7, "smb3", amZpg, 2, 5, false, 0x00, 0x00, false},    // b7
+	{opB8, "clv", amImpl, 1, 2, false, 0x00, 0x40, false},      // b8
+	{opB9, "lda", amAbsY, 3, 4, true, 0x00, 0x82, false},       // b9
+	{opBA, "tsx", amImpl, 1, 2, false, 0x00, 0x82, false},      // ba
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // bb
+	{opBC, "ldy", amAbsX, 3, 4, true, 0x00, 0x82, false},       // bc
+	{opBD, "lda", amAbsX, 3, 4, true, 0x00, 0x82, false},       // bd
+	{opBE, "ldx", amAbsY, 3, 4, true, 0x00, 0x82, false},       // be
+	{cmosBF, "bbs3", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // bf
+	{opC0, "cpy", amImm, 2, 2, false, 0x00, 0x83, false},       // c0
+	{opC1, "cmp", amXInd, 2, 6, false, 0x00, 0x83, false},      // c1
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // c2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // c3
+	{opC4, "cpy", amZpg, 2, 3, false, 0x00, 0x83, false},       // c4
+	{opC5, "cmp", amZpg, 2, 3, false, 0x00, 0x83, false},       // c5
+	{opC6, "dec", amZpg, 2, 5, false, 0x00, 0x82, false},       // c6
+	{cmosC7, "smb4", amZpg, 2, 5, false, 0x00, 0x00, false},    // c7
+	{opC8, "iny", amImpl, 1, 2, false, 0x00, 0x82, false},      // c8
+	{opC9, "cmp", amImm, 2, 2, false, 0x00, 0x83, false},       // c9
+	{opCA, "dex", amImpl, 1, 2, false, 0x00, 0x82, false},      // ca
+	{cmosCB, "wai", amImpl, 1, 3, false, 0x00, 0x00, false},    // cb
+	{opCC, "cpy", amAbs, 3, 4, false, 0x00, 0x83, false},       // cc
+	{opCD, "cmp", amAbs, 3, 4, false, 0x00, 0x83, false},       // cd
+	{opCE, "dec", amAbs, 3, 6, false, 0x00, 0x82, false},       // ce
+	{cmosCF, "bbs4", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // cf
+	{opD0, "bne", amRel, 2, 2, false, 0x02, 0x00, false},       // d0
+	{opD1, "cmp", amIndY, 2, 5, true, 0x00, 0x83, false},       // d1
+	{cmosD2, "cmp", amZpgInd, 2, 5, false, 0x00, 0x83, false},  // d2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // d3
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, false},      // d4
+	{opD5, "cmp", amZpgX, 2, 4, false, 0x00, 0x83, false},      // d5
+	{opD6, "dec", amZpgX, 2, 6, false, 0x00, 0x82, false},      // d6
+	{cmosD7, "smb5", amZpg, 2, 5, false, 0x00, 0x00, false},    // d7
+	{opD8, "cld", amImpl, 1, 2, false, 0x00, 0x08, false},      // d8
+	{opD9, "cmp", amAbsY, 3, 4, true, 0x00, 0x83, false},       // d9
+	{cmosDA, "phx", amImpl, 1, 3, false, 0x00, 0x00, false},    // da
+	{cmosDB, "stp", amImpl, 1, 3, false, 0x00, 0x00, false},    // db
+	{op0C, "nop", amAbs, 3, 4, false, 0x00, 0x00, false},       // dc
+	{opDD, "cmp", amAbsX, 3, 4, true, 0x00, 0x83, false},       // dd
+	{opDE, "dec", amAbsX, 3, 7, false, 0x00, 0x82, false},      // de
+	{cmosDF, "bbs5", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // df
+	{opE0, "cpx", amImm, 2, 2, false, 0x00, 0x83, false},       // e0
+	{opE1, "sbc", amXInd, 2, 6, false, 0x09, 0xc3, false},      // e1
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, false},       // e2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // e3
+	{opE4, "cpx", amZpg, 2, 3, false, 0x00, 0x83, false},       // e4
+	{opE5, "sbc", amZpg, 2, 3, false, 0x09, 0xc3, false},       // e5
+	{opE6, "inc", amZpg, 2, 5, false, 0x00, 0x82, false},       // e6
+	{cmosE7, "smb6", amZpg, 2, 5, false, 0x00, 0x00, false},    // e7
+	{opE8, "inx", amImpl, 1, 2, false, 0x00, 0x82, false},      // e8
+	{opE9, "sbc", amImm, 2, 2, false, 0x09, 0xc3, false},       // e9
+	{opEA, "nop", amImpl, 1, 2, false, 0x00, 0x00, false},      // ea
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // eb
+	{opEC, "cpx", amAbs, 3, 4, false, 0x00, 0x83, false},       // ec
+	{opED, "sbc", amAbs, 3, 4, false, 0x09, 0xc3, false},       // ed
+	{opEE, "inc", amAbs, 3, 6, false, 0x00, 0x82, false},       // ee
+	{cmosEF, "bbs6", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // ef
+	{opF0, "beq", amRel, 2, 2, false, 0x02, 0x00, false},       // f0
+	{opF1, "sbc", amIndY, 2, 5, true, 0x09, 0xc3, false},       // f1
+	{cmosF2, "sbc", amZpgInd, 2, 5, false, 0x09, 0xc3, false},  // f2
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // f3
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, false},      // f4
+	{opF5, "sbc", amZpgX, 2, 4, false, 0x09, 0xc3, false},      // f5
+	{opF6, "inc", amZpgX, 2, 6, false, 0x00, 0x82, false},      // f6
+	{cmosF7, "smb7", amZpg, 2, 5, false, 0x00, 0x00, false},    // f7
+	{opF8, "sed", amImpl, 1, 2, false, 0x00, 0x08, false},      // f8
+	{opF9, "sbc", amAbsY, 3, 4, true, 0x09, 0xc3, false},       // f9
+	{cmosFA, "plx", amImpl, 1, 4, false, 0x00, 0x82, false},    // fa
+	{opEA, "nop", amImpl, 1, 1, false, 0x00, 0x00, false},      // fb
+	{op0C, "nop", amAbs, 3, 4, false, 0x00, 0x00, false},       // fc
+	{opFD, "sbc", amAbsX, 3, 4, true, 0x09, 0xc3, false},       // fd
+	{opFE, "inc", amAbsX, 3, 7, false, 0x00, 0x82, false},      // fe
+	{cmosFF, "bbs7", amZpgRel, 3, 5, false, 0x00, 0x00, false}, // ff
+}
+
+// opcodeTable2A03 is the 2a03 dispatch and metadata table.
+var opcodeTable2A03 = [256]opcode{
+	{op00, "brk", amImpl, 1, 7, false, 0x00, 0x04, false}, // 00
+	{op01, "ora", amXInd, 2, 6, false, 0x00, 0x82, false}, // 01
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 02
+	{op03, "slo", amXInd, 2, 8, false, 0x00, 0x83, true},  // 03
+	{op04, "nop", amZpg, 2, 3, false, 0x00, 0x00, true},   // 04
+	{op05, "ora", amZpg, 2, 3, false, 0x00, 0x82, false},  // 05
+	{op06, "asl", amZpg, 2, 5, false, 0x00, 0x83, false},  // 06
+	{op07, "slo", amZpg, 2, 5, false, 0x00, 0x83, true},   // 07
+	{op08, "php", amImpl, 1, 3, false, 0xdf, 0x00, false}, // 08
+	{op09, "ora", amImm, 2, 2, false, 0x00, 0x82, false},  // 09
+	{op0A, "asl", amAcc, 1, 2, false, 0x00, 0x83, false},  // 0a
+	{op0B, "anc", amImm, 2, 2, false, 0x00, 0x83, true},   // 0b
+	{op0C, "nop", amAbs, 3, 4, false, 0x00, 0x00, true},   // 0c
+	{op0D, "ora", amAbs, 3, 4, false, 0x00, 0x82, false},  // 0d
+	{op0E, "asl", amAbs, 3, 6, false, 0x00, 0x83, false},  // 0e
+	{op0F, "slo", amAbs, 3, 6, false, 0x00, 0x83, true},   // 0f
+	{op10, "bpl", amRel, 2, 2, false, 0x80, 0x00, false},  // 10
+	{op11, "ora", amIndY, 2, 5, true, 0x00, 0x82, false},  // 11
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 12
+	{op13, "slo", amIndY, 2, 8, false, 0x00, 0x83, true},  // 13
+	{op14, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 14
+	{op15, "ora", amZpgX, 2, 4, false, 0x00, 0x82, false}, // 15
+	{op16, "asl", amZpgX, 2, 6, false, 0x00, 0x83, false}, // 16
+	{op17, "slo", amZpgX, 2, 6, false, 0x00, 0x83, true},  // 17
+	{op18, "clc", amImpl, 1, 2, false, 0x00, 0x01, false}, // 18
+	{op19, "ora", amAbsY, 3, 4, true, 0x00, 0x82, false},  // 19
+	{op1A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 1a
+	{op1B, "slo", amAbsY, 3, 7, false, 0x00, 0x83, true},  // 1b
+	{op1C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 1c
+	{op1D, "ora", amAbsX, 3, 4, true, 0x00, 0x82, false},  // 1d
+	{op1E, "asl", amAbsX, 3, 7, false, 0x00, 0x83, false}, // 1e
+	{op1F, "slo", amAbsX, 3, 7, false, 0x00, 0x83, true},  // 1f
+	{op20, "jsr", amAbs, 3, 6, false, 0x00, 0x00, false},  // 20
+	{op21, "and", amXInd, 2, 6, false, 0x00, 0x82, false}, // 21
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 22
+	{op23, "rla", amXInd, 2, 8, false, 0x01, 0x83, true},  // 23
+	{op24, "bit", amZpg, 2, 3, false, 0x00, 0xc2, false},  // 24
+	{op25, "and", amZpg, 2, 3, false, 0x00, 0x82, false},  // 25
+	{op26, "rol", amZpg, 2, 5, false, 0x01, 0x83, false},  // 26
+	{op27, "rla", amZpg, 2, 5, false, 0x01, 0x83, true},   // 27
+	{op28, "plp", amImpl, 1, 4, false, 0x00, 0xdf, false}, // 28
+	{op29, "and", amImm, 2, 2, false, 0x00, 0x82, false},  // 29
+	{op2A, "rol", amAcc, 1, 2, false, 0x01, 0x83, false},  // 2a
+	{op2B, "anc", amImm, 2, 2, false, 0x00, 0x83, true},   // 2b
+	{op2C, "bit", amAbs, 3, 4, false, 0x00, 0xc2, false},  // 2c
+	{op2D, "and", amAbs, 3, 4, false, 0x00, 0x82, false},  // 2d
+	{op2E, "rol", amAbs, 3, 6, false, 0x01, 0x83, false},  // 2e
+	{op2F, "rla", amAbs, 3, 6, false, 0x01, 0x83, true},   // 2f
+	{op30, "bmi", amRel, 2, 2, false, 0x80, 0x00, false},  // 30
+	{op31, "and", amIndY, 2, 5, true, 0x00, 0x82, false},  // 31
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 32
+	{op33, "rla", amIndY, 2, 8, false, 0x01, 0x83, true},  // 33
+	{op34, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 34
+	{op35, "and", amZpgX, 2, 4, false, 0x00, 0x82, false}, // 35
+	{op36, "rol", amZpgX, 2, 6, false, 0x01, 0x83, false}, // 36
+	{op37, "rla", amZpgX, 2, 6, false, 0x01, 0x83, true},  // 37
+	{op38, "sec", amImpl, 1, 2, false, 0x00, 0x01, false}, // 38
+	{op39, "and", amAbsY, 3, 4, true, 0x00, 0x82, false},  // 39
+	{op3A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 3a
+	{op3B, "rla", amAbsY, 3, 7, false, 0x01, 0x83, true},  // 3b
+	{op3C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 3c
+	{op3D, "and", amAbsX, 3, 4, true, 0x00, 0x82, false},  // 3d
+	{op3E, "rol", amAbsX, 3, 7, false, 0x01, 0x83, false}, // 3e
+	{op3F, "rla", amAbsX, 3, 7, false, 0x01, 0x83, true},  // 3f
+	{op40, "rti", amImpl, 1, 6, false, 0x00, 0xdf, false}, // 40
+	{op41, "eor", amXInd, 2, 6, false, 0x00, 0x82, false}, // 41
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 42
+	{op43, "sre", amXInd, 2, 8, false, 0x00, 0x83, true},  // 43
+	{op44, "nop", amZpg, 2, 3, false, 0x00, 0x00, true},   // 44
+	{op45, "eor", amZpg, 2, 3, false, 0x00, 0x82, false},  // 45
+	{op46, "lsr", amZpg, 2, 5, false, 0x00, 0x83, false},  // 46
+	{op47, "sre", amZpg, 2, 5, false, 0x00, 0x83, true},   // 47
+	{op48, "pha", amImpl, 1, 3, false, 0x00, 0x00, false}, // 48
+	{op49, "eor", amImm, 2, 2, false, 0x00, 0x82, false},  // 49
+	{op4A, "lsr", amAcc, 1, 2, false, 0x00, 0x83, false},  // 4a
+	{op4B, "alr", amImm, 2, 2, false, 0x00, 0x83, true},   // 4b
+	{op4C, "jmp", amAbs, 3, 3, false, 0x00, 0x00, false},  // 4c
+	{op4D, "eor", amAbs, 3, 4, false, 0x00, 0x82, false},  // 4d
+	{op4E, "lsr", amAbs, 3, 6, false, 0x00, 0x83, false},  // 4e
+	{op4F, "sre", amAbs, 3, 6, false, 0x00, 0x83, true},   // 4f
+	{op50, "bvc", amRel, 2, 2, false, 0x40, 0x00, false},  // 50
+	{op51, "eor", amIndY, 2, 5, true, 0x00, 0x82, false},  // 51
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 52
+	{op53, "sre", amIndY, 2, 8, false, 0x00, 0x83, true},  // 53
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 54
+	{op55, "eor", amZpgX, 2, 4, false, 0x00, 0x82, false}, // 55
+	{op56, "lsr", amZpgX, 2, 6, false, 0x00, 0x83, false}, // 56
+	{op57, "sre", amZpgX, 2, 6, false, 0x00, 0x83, true},  // 57
+	{op58, "cli", amImpl, 1, 2, false, 0x00, 0x04, false}, // 58
+	{op59, "eor", amAbsY, 3, 4, true, 0x00, 0x82, false},  // 59
+	{op5A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 5a
+	{op5B, "sre", amAbsY, 3, 7, false, 0x00, 0x83, true},  // 5b
+	{op5C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 5c
+	{op5D, "eor", amAbsX, 3, 4, true, 0x00, 0x82, false},  // 5d
+	{op5E, "lsr", amAbsX, 3, 7, false, 0x00, 0x83, false}, // 5e
+	{op5F, "sre", amAbsX, 3, 7, false, 0x00, 0x83, true},  // 5f
+	{op60, "rts", amImpl, 1, 6, false, 0x00, 0x00, false}, // 60
+	{op61, "adc", amXInd, 2, 6, false, 0x09, 0xc3, false}, // 61
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 62
+	{op63, "rra", amXInd, 2, 8, false, 0x09, 0xc3, true},  // 63
+	{op64, "nop", amZpg, 2, 3, false, 0x00, 0x00, true},   // 64
+	{op65, "adc", amZpg, 2, 3, false, 0x09, 0xc3, false},  // 65
+	{op66, "ror", amZpg, 2, 5, false, 0x01, 0x83, false},  // 66
+	{op67, "rra", amZpg, 2, 5, false, 0x09, 0xc3, true},   // 67
+	{op68, "pla", amImpl, 1, 4, false, 0x00, 0x82, false}, // 68
+	{op69, "adc", amImm, 2, 2, false, 0x09, 0xc3, false},  // 69
+	{op6A, "ror", amAcc, 1, 2, false, 0x01, 0x83, false},  // 6a
+	{op6B, "arr", amImm, 2, 2, false, 0x09, 0xc3, true},   // 6b
+	{op6C, "jmp", amInd, 3, 5, false, 0x00, 0x00, false},  // 6c
+	{op6D, "adc", amAbs, 3, 4, false, 0x09, 0xc3, false},  // 6d
+	{op6E, "ror", amAbs, 3, 6, false, 0x01, 0x83, false},  // 6e
+	{op6F, "rra", amAbs, 3, 6, false, 0x09, 0xc3, true},   // 6f
+	{op70, "bvs", amRel, 2, 2, false, 0x40, 0x00, false},  // 70
+	{op71, "adc", amIndY, 2, 5, true, 0x09, 0xc3, false},  // 71
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 72
+	{op73, "rra", amIndY, 2, 8, false, 0x09, 0xc3, true},  // 73
+	{op74, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 74
+	{op75, "adc", amZpgX, 2, 4, false, 0x09, 0xc3, false}, // 75
+	{op76, "ror", amZpgX, 2, 6, false, 0x01, 0x83, false}, // 76
+	{op77, "rra", amZpgX, 2, 6, false, 0x09, 0xc3, true},  // 77
+	{op78, "sei", amImpl, 1, 2, false, 0x00, 0x04, false}, // 78
+	{op79, "adc", amAbsY, 3, 4, true, 0x09, 0xc3, false},  // 79
+	{op7A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 7a
+	{op7B, "rra", amAbsY, 3, 7, false, 0x09, 0xc3, true},  // 7b
+	{op7C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 7c
+	{op7D, "adc", amAbsX, 3, 4, true, 0x09, 0xc3, false},  // 7d
+	{op7E, "ror", amAbsX, 3, 7, false, 0x01, 0x83, false}, // 7e
+	{op7F, "rra", amAbsX, 3, 7, false, 0x09, 0xc3, true},  // 7f
+	{op80, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // 80
+	{op81, "sta", amXInd, 2, 6, false, 0x00, 0x00, false}, // 81
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // 82
+	{op83, "sax", amXInd, 2, 6, false, 0x00, 0x00, true},  // 83
+	{op84, "sty", amZpg, 2, 3, false, 0x00, 0x00, false},  // 84
+	{op85, "sta", amZpg, 2, 3, false, 0x00, 0x00, false},  // 85
+	{op86, "stx", amZpg, 2, 3, false, 0x00, 0x00, false},  // 86
+	{op87, "sax", amZpg, 2, 3, false, 0x00, 0x00, true},   // 87
+	{op88, "dey", amImpl, 1, 2, false, 0x00, 0x82, false}, // 88
+	{op89, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // 89
+	{op8A, "txa", amImpl, 1, 2, false, 0x00, 0x82, false}, // 8a
+	{op8B, "xaa", amImm, 2, 2, false, 0x00, 0x82, true},   // 8b
+	{op8C, "sty", amAbs, 3, 4, false, 0x00, 0x00, false},  // 8c
+	{op8D, "sta", amAbs, 3, 4, false, 0x00, 0x00, false},  // 8d
+	{op8E, "stx", amAbs, 3, 4, false, 0x00, 0x00, false},  // 8e
+	{op8F, "sax", amAbs, 3, 4, false, 0x00, 0x00, true},   // 8f
+	{op90, "bcc", amRel, 2, 2, false, 0x01, 0x00, false},  // 90
+	{op91, "sta", amIndY, 2, 6, false, 0x00, 0x00, false}, // 91
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 92
+	{op93, "sha", amIndY, 2, 6, false, 0x00, 0x00, true},  // 93
+	{op94, "sty", amZpgX, 2, 4, false, 0x00, 0x00, false}, // 94
+	{op95, "sta", amZpgX, 2, 4, false, 0x00, 0x00, false}, // 95
+	{op96, "stx", amZpgY, 2, 4, false, 0x00, 0x00, false}, // 96
+	{op97, "sax", amZpgY, 2, 4, false, 0x00, 0x00, true},  // 97
+	{op98, "tya", amImpl, 1, 2, false, 0x00, 0x82, false}, // 98
+	{op99, "sta", amAbsY, 3, 5, false, 0x00, 0x00, false}, // 99
+	{op9A, "txs", amImpl, 1, 2, false, 0x00, 0x00, false}, // 9a
+	{op9B, "tas", amAbsY, 3, 5, false, 0x00, 0x00, true},  // 9b
+	{op9C, "shy", amAbsX, 3, 5, false, 0x00, 0x00, true},  // 9c
+	{op9D, "sta", amAbsX, 3, 5, false, 0x00, 0x00, false}, // 9d
+	{op9E, "shx", amAbsY, 3, 5, false, 0x00, 0x00, true},  // 9e
+	{op9F, "sha", amAbsY, 3, 5, false, 0x00, 0x00, true},  // 9f
+	{opA0, "ldy", amImm, 2, 2, false, 0x00, 0x82, false},  // a0
+	{opA1, "lda", amXInd, 2, 6, false, 0x00, 0x82, false}, // a1
+	{opA2, "ldx", amImm, 2, 2, false, 0x00, 0x82, false},  // a2
+	{opA3, "lax", amXInd, 2, 6, false, 0x00, 0x82, true},  // a3
+	{opA4, "ldy", amZpg, 2, 3, false, 0x00, 0x82, false},  // a4
+	{opA5, "lda", amZpg, 2, 3, false, 0x00, 0x82, false},  // a5
+	{opA6, "ldx", amZpg, 2, 3, false, 0x00, 0x82, false},  // a6
+	{opA7, "lax", amZpg, 2, 3, false, 0x00, 0x82, true},   // a7
+	{opA8, "tay", amImpl, 1, 2, false, 0x00, 0x82, false}, // a8
+	{opA9, "lda", amImm, 2, 2, false, 0x00, 0x82, false},  // a9
+	{opAA, "tax", amImpl, 1, 2, false, 0x00, 0x82, false}, // aa
+	{opAB, "lax", amImm, 2, 2, false, 0x00, 0x82, true},   // ab
+	{opAC, "ldy", amAbs, 3, 4, false, 0x00, 0x82, false},  // ac
+	{opAD, "lda", amAbs, 3, 4, false, 0x00, 0x82, false},  // ad
+	{opAE, "ldx", amAbs, 3, 4, false, 0x00, 0x82, false},  // ae
+	{opAF, "lax", amAbs, 3, 4, false, 0x00, 0x82, true},   // af
+	{opB0, "bcs", amRel, 2, 2, false, 0x01, 0x00, false},  // b0
+	{opB1, "lda", amIndY, 2, 5, true, 0x00, 0x82, false},  // b1
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // b2
+	{opB3, "lax", amIndY, 2, 5, true, 0x00, 0x82, true},   // b3
+	{opB4, "ldy", amZpgX, 2, 4, false, 0x00, 0x82, false}, // b4
+	{opB5, "lda", amZpgX, 2, 4, false, 0x00, 0x82, false}, // b5
+	{opB6, "ldx", amZpgY, 2, 4, false, 0x00, 0x82, false}, // b6
+	{opB7, "lax", amZpgY, 2, 4, false, 0x00, 0x82, true},  // b7
+	{opB8, "clv", amImpl, 1, 2, false, 0x00, 0x40, false}, // b8
+	{opB9, "lda", amAbsY, 3, 4, true, 0x00, 0x82, false},  // b9
+	{opBA, "tsx", amImpl, 1, 2, false, 0x00, 0x82, false}, // ba
+	{opBB, "las", amAbsY, 3, 4, true, 0x00, 0x82, true},   // bb
+	{opBC, "ldy", amAbsX, 3, 4, true, 0x00, 0x82, false},  // bc
+	{opBD, "lda", amAbsX, 3, 4, true, 0x00, 0x82, false},  // bd
+	{opBE, "ldx", amAbsY, 3, 4, true, 0x00, 0x82, false},  // be
+	{opBF, "lax", amAbsY, 3, 4, true, 0x00, 0x82, true},   // bf
+	{opC0, "cpy", amImm, 2, 2, false, 0x00, 0x83, false},  // c0
+	{opC1, "cmp", amXInd, 2, 6, false, 0x00, 0x83, false}, // c1
+	{opC2, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // c2
+	{opC3, "dcp", amXInd, 2, 8, false, 0x00, 0x83, true},  // c3
+	{opC4, "cpy", amZpg, 2, 3, false, 0x00, 0x83, false},  // c4
+	{opC5, "cmp", amZpg, 2, 3, false, 0x00, 0x83, false},  // c5
+	{opC6, "dec", amZpg, 2, 5, false, 0x00, 0x82, false},  // c6
+	{opC7, "dcp", amZpg, 2, 5, false, 0x00, 0x83, true},   // c7
+	{opC8, "iny", amImpl, 1, 2, false, 0x00, 0x82, false}, // c8
+	{opC9, "cmp", amImm, 2, 2, false, 0x00, 0x83, false},  // c9
+	{opCA, "dex", amImpl, 1, 2, false, 0x00, 0x82, false}, // ca
+	{opCB, "sbx", amImm, 2, 2, false, 0x00, 0x83, true},   // cb
+	{opCC, "cpy", amAbs, 3, 4, false, 0x00, 0x83, false},  // cc
+	{opCD, "cmp", amAbs, 3, 4, false, 0x00, 0x83, false},  // cd
+	{opCE, "dec", amAbs, 3, 6, false, 0x00, 0x82, false},  // ce
+	{opCF, "dcp", amAbs, 3, 6, false, 0x00, 0x83, true},   // cf
+	{opD0, "bne", amRel, 2, 2, false, 0x02, 0x00, false},  // d0
+	{opD1, "cmp", amIndY, 2, 5, true, 0x00, 0x83, false},  // d1
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // d2
+	{opD3, "dcp", amIndY, 2, 8, false, 0x00, 0x83, true},  // d3
+	{opD4, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // d4
+	{opD5, "cmp", amZpgX, 2, 4, false, 0x00, 0x83, false}, // d5
+	{opD6, "dec", amZpgX, 2, 6, false, 0x00, 0x82, false}, // d6
+	{opD7, "dcp", amZpgX, 2, 6, false, 0x00, 0x83, true},  // d7
+	{opD8, "cld", amImpl, 1, 2, false, 0x00, 0x08, false}, // d8
+	{opD9, "cmp", amAbsY, 3, 4, true, 0x00, 0x83, false},  // d9
+	{opDA, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // da
+	{opDB, "dcp", amAbsY, 3, 7, false, 0x00, 0x83, true},  // db
+	{opDC, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // dc
+	{opDD, "cmp", amAbsX, 3, 4, true, 0x00, 0x83, false},  // dd
+	{opDE, "dec", amAbsX, 3, 7, false, 0x00, 0x82, false}, // de
+	{opDF, "dcp", amAbsX, 3, 7, false, 0x00, 0x83, true},  // df
+	{opE0, "cpx", amImm, 2, 2, false, 0x00, 0x83, false},  // e0
+	{opE1, "sbc", amXInd, 2, 6, false, 0x09, 0xc3, false}, // e1
+	{opE2, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // e2
+	{opE3, "isc", amXInd, 2, 8, false, 0x09, 0xc3, true},  // e3
+	{opE4, "cpx", amZpg, 2, 3, false, 0x00, 0x83, false},  // e4
+	{opE5, "sbc", amZpg, 2, 3, false, 0x09, 0xc3, false},  // e5
+	{opE6, "inc", amZpg, 2, 5, false, 0x00, 0x82, false},  // e6
+	{opE7, "isc", amZpg, 2, 5, false, 0x09, 0xc3, true},   // e7
+	{opE8, "inx", amImpl, 1, 2, false, 0x00, 0x82, false}, // e8
+	{opE9, "sbc", amImm, 2, 2, false, 0x09, 0xc3, false},  // e9
+	{opEA, "nop", amImpl, 1, 2, false, 0x00, 0x00, false}, // ea
+	{opEB, "sbc", amImm, 2, 2, false, 0x09, 0xc3, true},   // eb
+	{opEC, "cpx", amAbs, 3, 4, false, 0x00, 0x83, false},  // ec
+	{opED, "sbc", amAbs, 3, 4, false, 0x09, 0xc3, false},  // ed
+	{opEE, "inc", amAbs, 3, 6, false, 0x00, 0x82, false},  // ee
+	{opEF, "isc", amAbs, 3, 6, false, 0x09, 0xc3, true},   // ef
+	{opF0, "beq", amRel, 2, 2, false, 0x02, 0x00, false},  // f0
+	{opF1, "sbc", amIndY, 2, 5, true, 0x09, 0xc3, false},  // f1
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // f2
+	{opF3, "isc", amIndY, 2, 8, false, 0x09, 0xc3, true},  // f3
+	{opF4, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // f4
+	{opF5, "sbc", amZpgX, 2, 4, false, 0x09, 0xc3, false}, // f5
+	{opF6, "inc", amZpgX, 2, 6, false, 0x00, 0x82, false}, // f6
+	{opF7, "isc", amZpgX, 2, 6, false, 0x09, 0xc3, true},  // f7
+	{opF8, "sed", amImpl, 1, 2, false, 0x00, 0x08, false}, // f8
+	{opF9, "sbc", amAbsY, 3, 4, true, 0x09, 0xc3, false},  // f9
+	{opFA, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // fa
+	{opFB, "isc", amAbsY, 3, 7, false, 0x09, 0xc3, true},  // fb
+	{opFC, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // fc
+	{opFD, "sbc", amAbsX, 3, 4, true, 0x09, 0xc3, false},  // fd
+	{opFE, "inc", amAbsX, 3, 7, false, 0x00, 0x82, false}, // fe
+	{opFF, "isc", amAbsX, 3, 7, false, 0x09, 0xc3, true},  // ff
+}
+
+// opcodeTable6510 is the 6510 dispatch and metadata table.
+var opcodeTable6510 = [256]opcode{
+	{op00, "brk", amImpl, 1, 7, false, 0x00, 0x04, false}, // 00
+	{op01, "ora", amXInd, 2, 6, false, 0x00, 0x82, false}, // 01
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 02
+	{op03, "slo", amXInd, 2, 8, false, 0x00, 0x83, true},  // 03
+	{op04, "nop", amZpg, 2, 3, false, 0x00, 0x00, true},   // 04
+	{op05, "ora", amZpg, 2, 3, false, 0x00, 0x82, false},  // 05
+	{op06, "asl", amZpg, 2, 5, false, 0x00, 0x83, false},  // 06
+	{op07, "slo", amZpg, 2, 5, false, 0x00, 0x83, true},   // 07
+	{op08, "php", amImpl, 1, 3, false, 0xdf, 0x00, false}, // 08
+	{op09, "ora", amImm, 2, 2, false, 0x00, 0x82, false},  // 09
+	{op0A, "asl", amAcc, 1, 2, false, 0x00, 0x83, false},  // 0a
+	{op0B, "anc", amImm, 2, 2, false, 0x00, 0x83, true},   // 0b
+	{op0C, "nop", amAbs, 3, 4, false, 0x00, 0x00, true},   // 0c
+	{op0D, "ora", amAbs, 3, 4, false, 0x00, 0x82, false},  // 0d
+	{op0E, "asl", amAbs, 3, 6, false, 0x00, 0x83, false},  // 0e
+	{op0F, "slo", amAbs, 3, 6, false, 0x00, 0x83, true},   // 0f
+	{op10, "bpl", amRel, 2, 2, false, 0x80, 0x00, false},  // 10
+	{op11, "ora", amIndY, 2, 5, true, 0x00, 0x82, false},  // 11
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 12
+	{op13, "slo", amIndY, 2, 8, false, 0x00, 0x83, true},  // 13
+	{op14, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 14
+	{op15, "ora", amZpgX, 2, 4, false, 0x00, 0x82, false}, // 15
+	{op16, "asl", amZpgX, 2, 6, false, 0x00, 0x83, false}, // 16
+	{op17, "slo", amZpgX, 2, 6, false, 0x00, 0x83, true},  // 17
+	{op18, "clc", amImpl, 1, 2, false, 0x00, 0x01, false}, // 18
+	{op19, "ora", amAbsY, 3, 4, true, 0x00, 0x82, false},  // 19
+	{op1A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 1a
+	{op1B, "slo", amAbsY, 3, 7, false, 0x00, 0x83, true},  // 1b
+	{op1C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 1c
+	{op1D, "ora", amAbsX, 3, 4, true, 0x00, 0x82, false},  // 1d
+	{op1E, "asl", amAbsX, 3, 7, false, 0x00, 0x83, false}, // 1e
+	{op1F, "slo", amAbsX, 3, 7, false, 0x00, 0x83, true},  // 1f
+	{op20, "jsr", amAbs, 3, 6, false, 0x00, 0x00, false},  // 20
+	{op21, "and", amXInd, 2, 6, false, 0x00, 0x82, false}, // 21
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 22
+	{op23, "rla", amXInd, 2, 8, false, 0x01, 0x83, true},  // 23
+	{op24, "bit", amZpg, 2, 3, false, 0x00, 0xc2, false},  // 24
+	{op25, "and", amZpg, 2, 3, false, 0x00, 0x82, false},  // 25
+	{op26, "rol", amZpg, 2, 5, false, 0x01, 0x83, false},  // 26
+	{op27, "rla", amZpg, 2, 5, false, 0x01, 0x83, true},   // 27
+	{op28, "plp", amImpl, 1, 4, false, 0x00, 0xdf, false}, // 28
+	{op29, "and", amImm, 2, 2, false, 0x00, 0x82, false},  // 29
+	{op2A, "rol", amAcc, 1, 2, false, 0x01, 0x83, false},  // 2a
+	{op2B, "anc", amImm, 2, 2, false, 0x00, 0x83, true},   // 2b
+	{op2C, "bit", amAbs, 3, 4, false, 0x00, 0xc2, false},  // 2c
+	{op2D, "and", amAbs, 3, 4, false, 0x00, 0x82, false},  // 2d
+	{op2E, "rol", amAbs, 3, 6, false, 0x01, 0x83, false},  // 2e
+	{op2F, "rla", amAbs, 3, 6, false, 0x01, 0x83, true},   // 2f
+	{op30, "bmi", amRel, 2, 2, false, 0x80, 0x00, false},  // 30
+	{op31, "and", amIndY, 2, 5, true, 0x00, 0x82, false},  // 31
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 32
+	{op33, "rla", amIndY, 2, 8, false, 0x01, 0x83, true},  // 33
+	{op34, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 34
+	{op35, "and", amZpgX, 2, 4, false, 0x00, 0x82, false}, // 35
+	{op36, "rol", amZpgX, 2, 6, false, 0x01, 0x83, false}, // 36
+	{op37, "rla", amZpgX, 2, 6, false, 0x01, 0x83, true},  // 37
+	{op38, "sec", amImpl, 1, 2, false, 0x00, 0x01, false}, // 38
+	{op39, "and", amAbsY, 3, 4, true, 0x00, 0x82, false},  // 39
+	{op3A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 3a
+	{op3B, "rla", amAbsY, 3, 7, false, 0x01, 0x83, true},  // 3b
+	{op3C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 3c
+	{op3D, "and", amAbsX, 3, 4, true, 0x00, 0x82, false},  // 3d
+	{op3E, "rol", amAbsX, 3, 7, false, 0x01, 0x83, false}, // 3e
+	{op3F, "rla", amAbsX, 3, 7, false, 0x01, 0x83, true},  // 3f
+	{op40, "rti", amImpl, 1, 6, false, 0x00, 0xdf, false}, // 40
+	{op41, "eor", amXInd, 2, 6, false, 0x00, 0x82, false}, // 41
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 42
+	{op43, "sre", amXInd, 2, 8, false, 0x00, 0x83, true},  // 43
+	{op44, "nop", amZpg, 2, 3, false, 0x00, 0x00, true},   // 44
+	{op45, "eor", amZpg, 2, 3, false, 0x00, 0x82, false},  // 45
+	{op46, "lsr", amZpg, 2, 5, false, 0x00, 0x83, false},  // 46
+	{op47, "sre", amZpg, 2, 5, false, 0x00, 0x83, true},   // 47
+	{op48, "pha", amImpl, 1, 3, false, 0x00, 0x00, false}, // 48
+	{op49, "eor", amImm, 2, 2, false, 0x00, 0x82, false},  // 49
+	{op4A, "lsr", amAcc, 1, 2, false, 0x00, 0x83, false},  // 4a
+	{op4B, "alr", amImm, 2, 2, false, 0x00, 0x83, true},   // 4b
+	{op4C, "jmp", amAbs, 3, 3, false, 0x00, 0x00, false},  // 4c
+	{op4D, "eor", amAbs, 3, 4, false, 0x00, 0x82, false},  // 4d
+	{op4E, "lsr", amAbs, 3, 6, false, 0x00, 0x83, false},  // 4e
+	{op4F, "sre", amAbs, 3, 6, false, 0x00, 0x83, true},   // 4f
+	{op50, "bvc", amRel, 2, 2, false, 0x40, 0x00, false},  // 50
+	{op51, "eor", amIndY, 2, 5, true, 0x00, 0x82, false},  // 51
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 52
+	{op53, "sre", amIndY, 2, 8, false, 0x00, 0x83, true},  // 53
+	{op54, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 54
+	{op55, "eor", amZpgX, 2, 4, false, 0x00, 0x82, false}, // 55
+	{op56, "lsr", amZpgX, 2, 6, false, 0x00, 0x83, false}, // 56
+	{op57, "sre", amZpgX, 2, 6, false, 0x00, 0x83, true},  // 57
+	{op58, "cli", amImpl, 1, 2, false, 0x00, 0x04, false}, // 58
+	{op59, "eor", amAbsY, 3, 4, true, 0x00, 0x82, false},  // 59
+	{op5A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 5a
+	{op5B, "sre", amAbsY, 3, 7, false, 0x00, 0x83, true},  // 5b
+	{op5C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 5c
+	{op5D, "eor", amAbsX, 3, 4, true, 0x00, 0x82, false},  // 5d
+	{op5E, "lsr", amAbsX, 3, 7, false, 0x00, 0x83, false}, // 5e
+	{op5F, "sre", amAbsX, 3, 7, false, 0x00, 0x83, true},  // 5f
+	{op60, "rts", amImpl, 1, 6, false, 0x00, 0x00, false}, // 60
+	{op61, "adc", amXInd, 2, 6, false, 0x09, 0xc3, false}, // 61
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 62
+	{op63, "rra", amXInd, 2, 8, false, 0x09, 0xc3, true},  // 63
+	{op64, "nop", amZpg, 2, 3, false, 0x00, 0x00, true},   // 64
+	{op65, "adc", amZpg, 2, 3, false, 0x09, 0xc3, false},  // 65
+	{op66, "ror", amZpg, 2, 5, false, 0x01, 0x83, false},  // 66
+	{op67, "rra", amZpg, 2, 5, false, 0x09, 0xc3, true},   // 67
+	{op68, "pla", amImpl, 1, 4, false, 0x00, 0x82, false}, // 68
+	{op69, "adc", amImm, 2, 2, false, 0x09, 0xc3, false},  // 69
+	{op6A, "ror", amAcc, 1, 2, false, 0x01, 0x83, false},  // 6a
+	{op6B, "arr", amImm, 2, 2, false, 0x09, 0xc3, true},   // 6b
+	{op6C, "jmp", amInd, 3, 5, false, 0x00, 0x00, false},  // 6c
+	{op6D, "adc", amAbs, 3, 4, false, 0x09, 0xc3, false},  // 6d
+	{op6E, "ror", amAbs, 3, 6, false, 0x01, 0x83, false},  // 6e
+	{op6F, "rra", amAbs, 3, 6, false, 0x09, 0xc3, true},   // 6f
+	{op70, "bvs", amRel, 2, 2, false, 0x40, 0x00, false},  // 70
+	{op71, "adc", amIndY, 2, 5, true, 0x09, 0xc3, false},  // 71
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 72
+	{op73, "rra", amIndY, 2, 8, false, 0x09, 0xc3, true},  // 73
+	{op74, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // 74
+	{op75, "adc", amZpgX, 2, 4, false, 0x09, 0xc3, false}, // 75
+	{op76, "ror", amZpgX, 2, 6, false, 0x01, 0x83, false}, // 76
+	{op77, "rra", amZpgX, 2, 6, false, 0x09, 0xc3, true},  // 77
+	{op78, "sei", amImpl, 1, 2, false, 0x00, 0x04, false}, // 78
+	{op79, "adc", amAbsY, 3, 4, true, 0x09, 0xc3, false},  // 79
+	{op7A, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // 7a
+	{op7B, "rra", amAbsY, 3, 7, false, 0x09, 0xc3, true},  // 7b
+	{op7C, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // 7c
+	{op7D, "adc", amAbsX, 3, 4, true, 0x09, 0xc3, false},  // 7d
+	{op7E, "ror", amAbsX, 3, 7, false, 0x01, 0x83, false}, // 7e
+	{op7F, "rra", amAbsX, 3, 7, false, 0x09, 0xc3, true},  // 7f
+	{op80, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // 80
+	{op81, "sta", amXInd, 2, 6, false, 0x00, 0x00, false}, // 81
+	{op82, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // 82
+	{op83, "sax", amXInd, 2, 6, false, 0x00, 0x00, true},  // 83
+	{op84, "sty", amZpg, 2, 3, false, 0x00, 0x00, false},  // 84
+	{op85, "sta", amZpg, 2, 3, false, 0x00, 0x00, false},  // 85
+	{op86, "stx", amZpg, 2, 3, false, 0x00, 0x00, false},  // 86
+	{op87, "sax", amZpg, 2, 3, false, 0x00, 0x00, true},   // 87
+	{op88, "dey", amImpl, 1, 2, false, 0x00, 0x82, false}, // 88
+	{op89, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // 89
+	{op8A, "txa", amImpl, 1, 2, false, 0x00, 0x82, false}, // 8a
+	{op8B, "xaa", amImm, 2, 2, false, 0x00, 0x82, true},   // 8b
+	{op8C, "sty", amAbs, 3, 4, false, 0x00, 0x00, false},  // 8c
+	{op8D, "sta", amAbs, 3, 4, false, 0x00, 0x00, false},  // 8d
+	{op8E, "stx", amAbs, 3, 4, false, 0x00, 0x00, false},  // 8e
+	{op8F, "sax", amAbs, 3, 4, false, 0x00, 0x00, true},   // 8f
+	{op90, "bcc", amRel, 2, 2, false, 0x01, 0x00, false},  // 90
+	{op91, "sta", amIndY, 2, 6, false, 0x00, 0x00, false}, // 91
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // 92
+	{op93, "sha", amIndY, 2, 6, false, 0x00, 0x00, true},  // 93
+	{op94, "sty", amZpgX, 2, 4, false, 0x00, 0x00, false}, // 94
+	{op95, "sta", amZpgX, 2, 4, false, 0x00, 0x00, false}, // 95
+	{op96, "stx", amZpgY, 2, 4, false, 0x00, 0x00, false}, // 96
+	{op97, "sax", amZpgY, 2, 4, false, 0x00, 0x00, true},  // 97
+	{op98, "tya", amImpl, 1, 2, false, 0x00, 0x82, false}, // 98
+	{op99, "sta", amAbsY, 3, 5, false, 0x00, 0x00, false}, // 99
+	{op9A, "txs", amImpl, 1, 2, false, 0x00, 0x00, false}, // 9a
+	{op9B, "tas", amAbsY, 3, 5, false, 0x00, 0x00, true},  // 9b
+	{op9C, "shy", amAbsX, 3, 5, false, 0x00, 0x00, true},  // 9c
+	{op9D, "sta", amAbsX, 3, 5, false, 0x00, 0x00, false}, // 9d
+	{op9E, "shx", amAbsY, 3, 5, false, 0x00, 0x00, true},  // 9e
+	{op9F, "sha", amAbsY, 3, 5, false, 0x00, 0x00, true},  // 9f
+	{opA0, "ldy", amImm, 2, 2, false, 0x00, 0x82, false},  // a0
+	{opA1, "lda", amXInd, 2, 6, false, 0x00, 0x82, false}, // a1
+	{opA2, "ldx", amImm, 2, 2, false, 0x00, 0x82, false},  // a2
+	{opA3, "lax", amXInd, 2, 6, false, 0x00, 0x82, true},  // a3
+	{opA4, "ldy", amZpg, 2, 3, false, 0x00, 0x82, false},  // a4
+	{opA5, "lda", amZpg, 2, 3, false, 0x00, 0x82, false},  // a5
+	{opA6, "ldx", amZpg, 2, 3, false, 0x00, 0x82, false},  // a6
+	{opA7, "lax", amZpg, 2, 3, false, 0x00, 0x82, true},   // a7
+	{opA8, "tay", amImpl, 1, 2, false, 0x00, 0x82, false}, // a8
+	{opA9, "lda", amImm, 2, 2, false, 0x00, 0x82, false},  // a9
+	{opAA, "tax", amImpl, 1, 2, false, 0x00, 0x82, false}, // aa
+	{opAB, "lax", amImm, 2, 2, false, 0x00, 0x82, true},   // ab
+	{opAC, "ldy", amAbs, 3, 4, false, 0x00, 0x82, false},  // ac
+	{opAD, "lda", amAbs, 3, 4, false, 0x00, 0x82, false},  // ad
+	{opAE, "ldx", amAbs, 3, 4, false, 0x00, 0x82, false},  // ae
+	{opAF, "lax", amAbs, 3, 4, false, 0x00, 0x82, true},   // af
+	{opB0, "bcs", amRel, 2, 2, false, 0x01, 0x00, false},  // b0
+	{opB1, "lda", amIndY, 2, 5, true, 0x00, 0x82, false},  // b1
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // b2
+	{opB3, "lax", amIndY, 2, 5, true, 0x00, 0x82, true},   // b3
+	{opB4, "ldy", amZpgX, 2, 4, false, 0x00, 0x82, false}, // b4
+	{opB5, "lda", amZpgX, 2, 4, false, 0x00, 0x82, false}, // b5
+	{opB6, "ldx", amZpgY, 2, 4, false, 0x00, 0x82, false}, // b6
+	{opB7, "lax", amZpgY, 2, 4, false, 0x00, 0x82, true},  // b7
+	{opB8, "clv", amImpl, 1, 2, false, 0x00, 0x40, false}, // b8
+	{opB9, "lda", amAbsY, 3, 4, true, 0x00, 0x82, false},  // b9
+	{opBA, "tsx", amImpl, 1, 2, false, 0x00, 0x82, false}, // ba
+	{opBB, "las", amAbsY, 3, 4, true, 0x00, 0x82, true},   // bb
+	{opBC, "ldy", amAbsX, 3, 4, true, 0x00, 0x82, false},  // bc
+	{opBD, "lda", amAbsX, 3, 4, true, 0x00, 0x82, false},  // bd
+	{opBE, "ldx", amAbsY, 3, 4, true, 0x00, 0x82, false},  // be
+	{opBF, "lax", amAbsY, 3, 4, true, 0x00, 0x82, true},   // bf
+	{opC0, "cpy", amImm, 2, 2, false, 0x00, 0x83, false},  // c0
+	{opC1, "cmp", amXInd, 2, 6, false, 0x00, 0x83, false}, // c1
+	{opC2, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // c2
+	{opC3, "dcp", amXInd, 2, 8, false, 0x00, 0x83, true},  // c3
+	{opC4, "cpy", amZpg, 2, 3, false, 0x00, 0x83, false},  // c4
+	{opC5, "cmp", amZpg, 2, 3, false, 0x00, 0x83, false},  // c5
+	{opC6, "dec", amZpg, 2, 5, false, 0x00, 0x82, false},  // c6
+	{opC7, "dcp", amZpg, 2, 5, false, 0x00, 0x83, true},   // c7
+	{opC8, "iny", amImpl, 1, 2, false, 0x00, 0x82, false}, // c8
+	{opC9, "cmp", amImm, 2, 2, false, 0x00, 0x83, false},  // c9
+	{opCA, "dex", amImpl, 1, 2, false, 0x00, 0x82, false}, // ca
+	{opCB, "sbx", amImm, 2, 2, false, 0x00, 0x83, true},   // cb
+	{opCC, "cpy", amAbs, 3, 4, false, 0x00, 0x83, false},  // cc
+	{opCD, "cmp", amAbs, 3, 4, false, 0x00, 0x83, false},  // cd
+	{opCE, "dec", amAbs, 3, 6, false, 0x00, 0x82, false},  // ce
+	{opCF, "dcp", amAbs, 3, 6, false, 0x00, 0x83, true},   // cf
+	{opD0, "bne", amRel, 2, 2, false, 0x02, 0x00, false},  // d0
+	{opD1, "cmp", amIndY, 2, 5, true, 0x00, 0x83, false},  // d1
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // d2
+	{opD3, "dcp", amIndY, 2, 8, false, 0x00, 0x83, true},  // d3
+	{opD4, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // d4
+	{opD5, "cmp", amZpgX, 2, 4, false, 0x00, 0x83, false}, // d5
+	{opD6, "dec", amZpgX, 2, 6, false, 0x00, 0x82, false}, // d6
+	{opD7, "dcp", amZpgX, 2, 6, false, 0x00, 0x83, true},  // d7
+	{opD8, "cld", amImpl, 1, 2, false, 0x00, 0x08, false}, // d8
+	{opD9, "cmp", amAbsY, 3, 4, true, 0x00, 0x83, false},  // d9
+	{opDA, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // da
+	{opDB, "dcp", amAbsY, 3, 7, false, 0x00, 0x83, true},  // db
+	{opDC, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // dc
+	{opDD, "cmp", amAbsX, 3, 4, true, 0x00, 0x83, false},  // dd
+	{opDE, "dec", amAbsX, 3, 7, false, 0x00, 0x82, false}, // de
+	{opDF, "dcp", amAbsX, 3, 7, false, 0x00, 0x83, true},  // df
+	{opE0, "cpx", amImm, 2, 2, false, 0x00, 0x83, false},  // e0
+	{opE1, "sbc", amXInd, 2, 6, false, 0x09, 0xc3, false}, // e1
+	{opE2, "nop", amImm, 2, 2, false, 0x00, 0x00, true},   // e2
+	{opE3, "isc", amXInd, 2, 8, false, 0x09, 0xc3, true},  // e3
+	{opE4, "cpx", amZpg, 2, 3, false, 0x00, 0x83, false},  // e4
+	{opE5, "sbc", amZpg, 2, 3, false, 0x09, 0xc3, false},  // e5
+	{opE6, "inc", amZpg, 2, 5, false, 0x00, 0x82, false},  // e6
+	{opE7, "isc", amZpg, 2, 5, false, 0x09, 0xc3, true},   // e7
+	{opE8, "inx", amImpl, 1, 2, false, 0x00, 0x82, false}, // e8
+	{opE9, "sbc", amImm, 2, 2, false, 0x09, 0xc3, false},  // e9
+	{opEA, "nop", amImpl, 1, 2, false, 0x00, 0x00, false}, // ea
+	{opEB, "sbc", amImm, 2, 2, false, 0x09, 0xc3, true},   // eb
+	{opEC, "cpx", amAbs, 3, 4, false, 0x00, 0x83, false},  // ec
+	{opED, "sbc", amAbs, 3, 4, false, 0x09, 0xc3, false},  // ed
+	{opEE, "inc", amAbs, 3, 6, false, 0x00, 0x82, false},  // ee
+	{opEF, "isc", amAbs, 3, 6, false, 0x09, 0xc3, true},   // ef
+	{opF0, "beq", amRel, 2, 2, false, 0x02, 0x00, false},  // f0
+	{opF1, "sbc", amIndY, 2, 5, true, 0x09, 0xc3, false},  // f1
+	{opJAM, "jam", amImpl, 1, 0, false, 0x00, 0x00, true}, // f2
+	{opF3, "isc", amIndY, 2, 8, false, 0x09, 0xc3, true},  // f3
+	{opF4, "nop", amZpgX, 2, 4, false, 0x00, 0x00, true},  // f4
+	{opF5, "sbc", amZpgX, 2, 4, false, 0x09, 0xc3, false}, // f5
+	{opF6, "inc", amZpgX, 2, 6, false, 0x00, 0x82, false}, // f6
+	{opF7, "isc", amZpgX, 2, 6, false, 0x09, 0xc3, true},  // f7
+	{opF8, "sed", amImpl, 1, 2, false, 0x00, 0x08, false}, // f8
+	{opF9, "sbc", amAbsY, 3, 4, true, 0x09, 0xc3, false},  // f9
+	{opFA, "nop", amImpl, 1, 2, false, 0x00, 0x00, true},  // fa
+	{opFB, "isc", amAbsY, 3, 7, false, 0x09, 0xc3, true},  // fb
+	{opFC, "nop", amAbsX, 3, 4, true, 0x00, 0x00, true},   // fc
+	{opFD, "sbc", amAbsX, 3, 4, true, 0x09, 0xc3, false},  // fd
+	{opFE, "inc", amAbsX, 3, 7, false, 0x00, 0x82, false}, // fe
+	{opFF, "isc", amAbsX, 3, 7, false, 0x09, 0xc3, true},  // ff
+}
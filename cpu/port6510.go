@@ -0,0 +1,166 @@
+//-----------------------------------------------------------------------------
+/*
+
+6510 On-Chip I/O Port
+
+The 6510 has a 6-bit I/O port mapped at $0000 (data direction register) and
+$0001 (port register). On the C64 the port lines select the BASIC, KERNAL and
+character ROMs and drive the cassette.
+
+The port has 6 pins. Bits 6 and 7 of both registers aren't stored and read
+as 0. Reads of $0000/$0001 come from the port. Writes also reach the memory below
+it, as the RAM of the C64 is written with the value on the bus.
+
+Input pins that are not driven by the host and have no pull-up float. They
+hold the last level that was output on them for a while and then decay to 0.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+//-----------------------------------------------------------------------------
+
+// PortFunc is called with the port pin levels when they change.
+type PortFunc func(lines uint8)
+
+// c64PullUps are the port pins with pull-up resistors on the C64 (LORAM, HIRAM, CHAREN, cassette sense).
+const c64PullUps = 0x17
+
+// portMask are the port pins.
+const portMask = 0x3f
+
+// portFallOff is the number of cycles a floating pin holds its level.
+const portFallOff = 350000
+
+// ioPort is the state of the 6510 I/O port.
+type ioPort struct {
+	m        *M6502   // the cpu (for the cycle count)
+	ddr      uint8    // data direction register, 1 = output
+	data     uint8    // port register
+	pullUps  uint8    // pins pulled high when not driven
+	input    uint8    // levels on host driven input pins
+	driven   uint8    // pins driven by the host
+	floating uint8    // last level output on now floating pins
+	fallOff  [8]uint  // cycle at which a floating pin decays to 0
+	lines    uint8    // last reported pin levels
+	out      PortFunc // pin level callback
+}
+
+func newIOPort(m *M6502) *ioPort {
+	return &ioPort{
+		m:       m,
+		pullUps: c64PullUps,
+	}
+}
+
+// pins returns the levels on the port pins.
+func (p *ioPort) pins() uint8 {
+	val := p.data & p.ddr
+	in := ^p.ddr
+	val |= in & p.driven & p.input
+	val |= in & ^p.driven & p.pullUps
+	// floating pins
+	float := in & ^p.driven & ^p.pullUps
+	for i := uint(0); i < 6; i++ {
+		bit := uint8(1 << i)
+		if float&bit != 0 && p.floating&bit != 0 && p.m.cycles < p.fallOff[i] {
+			val |= bit
+		}
+	}
+	return val & portMask
+}
+
+// update reports changed pin levels to the host.
+func (p *ioPort) update() {
+	lines := p.pins()
+	if lines != p.lines {
+		p.lines = lines
+		p.report()
+	}
+}
+
+// report gives the pin levels to the host.
+func (p *ioPort) report() {
+	if p.out != nil {
+		p.out(p.lines)
+		// the host may have switched the memory map
+		p.m.Invalidate(0, 0xffff)
+	}
+}
+
+func (p *ioPort) read(adr uint16) uint8 {
+	if adr == 0 {
+		return p.ddr
+	}
+	return p.pins()
+}
+
+func (p *ioPort) write(adr uint16, val uint8) {
+	val &= portMask
+	if adr == 0 {
+		// output pins that become inputs start to float
+		released := p.ddr & ^val
+		for i := uint(0); i < 6; i++ {
+			bit := uint8(1 << i)
+			if released&bit != 0 {
+				p.floating = (p.floating & ^bit) | (p.data & bit)
+				p.fallOff[i] = p.m.cycles + portFallOff
+			}
+		}
+		p.ddr = val
+	} else {
+		p.data = val
+	}
+	p.update()
+}
+
+// reset sets all port pins as inputs.
+func (p *ioPort) reset() {
+	p.ddr = 0
+	p.data = 0
+	p.floating = 0
+	p.update()
+}
+
+//-----------------------------------------------------------------------------
+
+// PortOutput sets a callback for the 6510 I/O port pin levels.
+// It is called when the levels change, e.g. to switch the C64 ROMs in and out.
+func PortOutput(fn PortFunc) Option {
+	return func(m *M6502) {
+		if m.port != nil {
+			m.port.out = fn
+		}
+	}
+}
+
+// PortPullUps sets the 6510 I/O port pins that have pull-up resistors (the default is the C64 wiring).
+func PortPullUps(mask uint8) Option {
+	return func(m *M6502) {
+		if m.port != nil {
+			m.port.pullUps = mask
+		}
+	}
+}
+
+// PortInput drives the 6510 I/O port input pins in the mask to the levels in val.
+// Pins not in the mask are released.
+func (m *M6502) PortInput(mask, val uint8) {
+	if m.port == nil {
+		return
+	}
+	m.port.driven = mask
+	m.port.input = val
+	m.port.update()
+}
+
+// PortLines returns the 6510 I/O port pin levels.
+func (m *M6502) PortLines() uint8 {
+	if m.port == nil {
+		return 0
+	}
+	return m.port.pins()
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,155 @@
+//-----------------------------------------------------------------------------
+/*
+
+6510 I/O Port Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import "testing"
+
+//-----------------------------------------------------------------------------
+
+func TestPortRegisters(t *testing.T) {
+	testCPUs(t, []Variant{Variant6510}, func(m *M6502, r *testRAM, tick bool) {
+		var lines []uint8
+		PortOutput(func(l uint8) { lines = append(lines, l) })(m)
+		m.PC = 0x0400
+		copy(r[0x0400:], []uint8{
+			0xa9, 0x2f, // lda #$2f
+			0x85, 0x00, // sta $00
+			0xa9, 0x35, // lda #$35
+			0x85, 0x01, // sta $01
+			0xa5, 0x00, // lda $00
+			0xa6, 0x01, // ldx $01
+		})
+		for i := 0; i < 6; i++ {
+			testStep(t, m, tick)
+		}
+		// outputs 0x25, pulled up cassette sense 0x10, bits 6 and 7 low
+		if m.A != 0x2f || m.X != 0x35 {
+			t.Errorf("tick %t: ddr %02x port %02x", tick, m.A, m.X)
+		}
+		if len(lines) == 0 || lines[len(lines)-1] != 0x35 {
+			t.Errorf("tick %t: reported lines %02x", tick, lines)
+		}
+		// the writes reach the RAM below the port, the reads don't
+		if r[0] != 0x2f || r[1] != 0x35 {
+			t.Errorf("tick %t: ram %02x %02x", tick, r[0], r[1])
+		}
+		r[0], r[1] = 0, 0
+		if m.Peek8(0) != 0x2f || m.Peek8(1) != 0x35 {
+			t.Errorf("tick %t: port read %02x %02x from the ram", tick, m.Peek8(0), m.Peek8(1))
+		}
+	})
+}
+
+func TestPortPullUps(t *testing.T) {
+	var r testRAM
+	m := New6510(&r)
+	// all inputs: the pulled up pins are high
+	if l := m.PortLines(); l != c64PullUps {
+		t.Fatalf("lines %02x, want %02x", l, c64PullUps)
+	}
+	// a driven input overrides the pull-up
+	m.PortInput(0x10, 0x00)
+	if l := m.PortLines(); l != 0x07 {
+		t.Errorf("cassette sense driven low: lines %02x", l)
+	}
+	// and so does an output
+	m.Poke8(1, 0x00)
+	m.Poke8(0, 0x01)
+	if l := m.PortLines(); l != 0x06 {
+		t.Errorf("bit 0 output low: lines %02x", l)
+	}
+	// another pull-up wiring
+	m = New6510(&r, PortPullUps(0xff))
+	if l := m.PortLines(); l != portMask {
+		t.Errorf("all pulled up: lines %02x", l)
+	}
+}
+
+func TestPortFallOff(t *testing.T) {
+	var r testRAM
+	m := New6510(&r)
+	m.Poke8(1, 0xff)
+	m.Poke8(0, 0xff)
+	// the released pins without a pull-up float high for a while
+	m.Poke8(0, 0x00)
+	m.cycles += portFallOff - 1
+	if l := m.PortLines(); l != portMask {
+		t.Fatalf("floating pins fell early: lines %02x", l)
+	}
+	m.cycles++
+	if l := m.PortLines(); l != c64PullUps {
+		t.Errorf("floating pins held: lines %02x", l)
+	}
+	// a pin released while low floats low
+	m.Poke8(1, 0x00)
+	m.Poke8(0, 0x08)
+	m.Poke8(0, 0x00)
+	if l := m.PortLines(); l != c64PullUps {
+		t.Errorf("pin released low: lines %02x", l)
+	}
+}
+
+func TestPortBits67(t *testing.T) {
+	// the port has no bits 6 and 7
+	var r testRAM
+	var lines []uint8
+	m := New6510(&r, PortPullUps(0xff), PortOutput(func(l uint8) { lines = append(lines, l) }))
+	m.Reset()
+	lines = nil
+	m.Poke8(0, 0xc0)
+	m.Poke8(1, 0xc0)
+	if m.Peek8(0) != 0 || m.Peek8(1) != portMask || len(lines) != 0 {
+		t.Errorf("ddr %02x port %02x reported %02x", m.Peek8(0), m.Peek8(1), lines)
+	}
+	m.Poke8(0, 0xff)
+	m.Poke8(1, 0x80)
+	if m.Peek8(0) != portMask || m.Peek8(1) != 0 || m.PortLines() != 0 {
+		t.Errorf("ddr %02x port %02x lines %02x", m.Peek8(0), m.Peek8(1), m.PortLines())
+	}
+	m.Poke8(0, 0x00)
+	m.PortInput(0xff, 0xc0)
+	if l := m.PortLines(); l != 0 {
+		t.Errorf("driven lines %02x", l)
+	}
+	// the writes still reach the ram below
+	if r[0] != 0x00 || r[1] != 0x80 {
+		t.Errorf("ram %02x %02x", r[0], r[1])
+	}
+}
+
+func TestPortPredecode(t *testing.T) {
+	// the port selects the code at $8000 (as the C64 switches the ROMs)
+	var r testRAM
+	banks := [2][]uint8{
+		{0xa9, 0x11, 0x60}, // lda #$11, rts
+		{0xa9, 0x22, 0x60}, // lda #$22, rts
+	}
+	m := New6510(&r, WithPredecode(), PortOutput(func(l uint8) {
+		copy(r[0x8000:], banks[l&1])
+	}))
+	// bit 0 is pulled up
+	copy(r[0x8000:], banks[1])
+	copy(r[0x0400:], []uint8{
+		0x20, 0x00, 0x80, // jsr $8000
+		0x85, 0x10, // sta $10
+		0xa9, 0x01, // lda #1
+		0x85, 0x00, // sta $00 (bit 0 outputs 0)
+		0x20, 0x00, 0x80, // jsr $8000
+		0x85, 0x11, // sta $11
+	})
+	m.PC = 0x0400
+	for i := 0; i < 11; i++ {
+		testStep(t, m, false)
+	}
+	if r[0x10] != 0x22 || r[0x11] != 0x11 {
+		t.Errorf("ran %02x %02x, want 22 11", r[0x10], r[0x11])
+	}
+}
+
+//-----------------------------------------------------------------------------
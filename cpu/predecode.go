@@ -0,0 +1,118 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Predecode Cache
+
+The predecode cache keeps the decoded instruction (opcode table entry and
+instruction bytes) for each address that has been run. An instruction from
+the cache doesn't fetch the opcode and operands through the Memory interface.
+
+CPU writes invalidate the cached instructions they overlap, so self modifying
+code works. Memory changed by something else (DMA, bank switching, loading a
+program) must be reported with Invalidate. The bus package reports its
+mappings and bank switches through Bus.OnRemap, and a change of the 6510 port
+output lines (which may switch the memory map) drops the whole cache.
+
+The cache is for code in memory without read side effects (RAM and ROM).
+It isn't used while there are hooks or an observer, or by Tick.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+//-----------------------------------------------------------------------------
+
+// preOp is a predecoded instruction.
+type preOp struct {
+	x *opcode  // opcode table entry (nil if not decoded)
+	b [3]uint8 // instruction bytes
+	n uint16   // instruction length
+}
+
+// predecode is the predecode cache.
+type predecode struct {
+	ops [1 << 16]preOp
+	cur *preOp // instruction being run from the cache
+	pc  uint16 // address of the instruction being run
+}
+
+// WithPredecode enables the predecode cache.
+func WithPredecode() Option {
+	return func(m *M6502) {
+		m.SetPredecode(true)
+	}
+}
+
+// SetPredecode enables or disables the predecode cache.
+func (m *M6502) SetPredecode(on bool) {
+	if !on {
+		m.pre = nil
+	} else if m.pre == nil {
+		m.pre = &predecode{}
+	}
+}
+
+// Invalidate reports a change to the memory from start to end (inclusive).
+// The predecode cache drops the instructions overlapping the range.
+func (m *M6502) Invalidate(start, end uint16) {
+	if m.pre == nil {
+		return
+	}
+	if start == 0 && end == 0xffff {
+		m.pre.ops = [1 << 16]preOp{}
+		return
+	}
+	for adr := uint(start); adr <= uint(end); adr++ {
+		m.pre.invalidate(uint16(adr))
+	}
+}
+
+// invalidate drops the instructions with a byte at the address.
+func (c *predecode) invalidate(adr uint16) {
+	c.ops[adr].x = nil
+	c.ops[adr-1].x = nil
+	c.ops[adr-2].x = nil
+}
+
+// decode predecodes the instruction at the address.
+func (m *M6502) decode(p *preOp, adr uint16) {
+	p.b[0] = m.mem8(adr)
+	p.x = &m.table[p.b[0]]
+	p.n = uint16(p.x.length)
+	for i := uint16(1); i < p.n; i++ {
+		p.b[i] = m.mem8(adr + i)
+	}
+}
+
+// predecoded runs the instruction at the PC from the predecode cache.
+// It returns the opcode and the cycles used.
+func (m *M6502) predecoded() (uint8, uint) {
+	c := m.pre
+	p := &c.ops[m.PC]
+	if p.x == nil {
+		m.decode(p, m.PC)
+	}
+	// the instruction may invalidate its own entry
+	op, x := p.b[0], p.x
+	// a virtual subroutine may run the cpu
+	cur, pc := c.cur, c.pc
+	c.cur, c.pc = p, m.PC
+	n := x.cycles + x.fn(m)
+	c.cur, c.pc = cur, pc
+	return op, n
+}
+
+// operand returns an operand byte of the instruction being run from the cache.
+// The handlers read the operands before changing the PC.
+func (c *predecode) operand(pc, adr uint16) (uint8, bool) {
+	if c.cur == nil || pc != c.pc {
+		return 0, false
+	}
+	if i := adr - c.pc; i < c.cur.n {
+		return c.cur.b[i], true
+	}
+	return 0, false
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,72 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Predecode Cache Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+func TestPredecodeSelfModify(t *testing.T) {
+	var r testRAM
+	m := New6502(&r, WithPredecode())
+	m.PC = 0x0400
+	copy(r[0x0400:], []uint8{
+		0xa9, 0x00, // 0400 lda #$00
+		0xee, 0x01, 0x04, // 0402 inc $0401 (the lda operand)
+		0x4c, 0x00, 0x04, // 0405 jmp $0400
+	})
+	if _, err := m.RunInstructions(7); err != nil || m.A != 2 {
+		t.Errorf("cpu write: a %02x %v", m.A, err)
+	}
+	// a write by something else
+	r[0x0401] = 0x55
+	m.Invalidate(0x0401, 0x0401)
+	m.PC = 0x0400
+	if err := m.Run(); err != nil || m.A != 0x55 {
+		t.Errorf("invalidate: a %02x %v", m.A, err)
+	}
+}
+
+// functionalRun runs the Klaus Dormann functional test to the success trap.
+func functionalRun(t *testing.T, v Variant, img []uint8, opts ...Option) *M6502 {
+	var r testRAM
+	copy(r[:], img)
+	m := newCPU(&r, v, opts)
+	m.PC = 0x0400
+	err := m.RunUntil(context.Background(), nil)
+	var stuck *StuckError
+	if !errors.As(err, &stuck) || m.PC != 0x3469 {
+		t.Fatalf("%s: %v", v, err)
+	}
+	return m
+}
+
+func TestPredecodeFunctional(t *testing.T) {
+	if testing.Short() {
+		t.Skip("long test")
+	}
+	img, err := ioutil.ReadFile(functionalTest)
+	if err != nil {
+		t.Skip(err)
+	}
+	for _, v := range []Variant{Variant6502, Variant65C02} {
+		want := functionalRun(t, v, img)
+		got := functionalRun(t, v, img, WithPredecode())
+		if got.Cycles() != want.Cycles() || got.Coverage() != want.Coverage() {
+			t.Errorf("%s: %d cycles, want %d", v, got.Cycles(), want.Cycles())
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,120 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Run Loops
+
+Run executes a single step: an instruction, an interrupt entry or a clock of
+waiting for an interrupt. These loops run the CPU for a budget of cycles or
+steps, or until a condition is met.
+
+The loops run batches of instructions inline. A step falls back to Run when it
+needs more than a fetch and execute (hooks, an observer, an interrupt, waiting
+or finishing an instruction started by Tick).
+
+A stopped CPU (STP) doesn't use any cycles, so the loops return early when the
+clock is stopped. Check HaltReason to tell why a loop returned early.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import "context"
+
+//-----------------------------------------------------------------------------
+
+// slowPath returns true if the next step can't be a plain fetch and execute.
+func (m *M6502) slowPath() bool {
+	return m.tick.n != 0 || m.hooks != nil || m.obs != nil ||
+		m.jam || m.stop || m.wait || m.nmi || (m.irq && m.P&flagI == 0)
+}
+
+// batch runs up to n steps or until the cycle budget is used.
+// It returns the steps run.
+func (m *M6502) batch(n int, budget uint) (int, error) {
+	m.halted = nil
+	start := m.cycles
+	for i := 0; i < n; i++ {
+		if m.cycles-start >= budget || m.stop {
+			return i, nil
+		}
+		if m.slowPath() {
+			if err := m.Run(); err != nil {
+				return i + 1, err
+			}
+			continue
+		}
+		var op uint8
+		if m.pre != nil {
+			var n uint
+			op, n = m.predecoded()
+			m.cycles += n
+		} else {
+			op = m.mem8(m.PC)
+			x := &m.table[op]
+			m.cycles += x.cycles + x.fn(m)
+		}
+		if err := m.retire(op); err != nil {
+			return i + 1, err
+		}
+	}
+	return n, nil
+}
+
+// RunCycles runs the CPU for at least n cycles. It returns the cycles run,
+// which overshoot n by the remainder of the last instruction.
+func (m *M6502) RunCycles(n uint) (uint, error) {
+	start := m.cycles
+	_, err := m.batch(int(^uint(0)>>1), n)
+	return m.cycles - start, err
+}
+
+// RunInstructions runs the CPU for n steps. It returns the steps run.
+func (m *M6502) RunInstructions(n int) (int, error) {
+	return m.batch(n, ^uint(0))
+}
+
+// contextPoll is the number of steps between checks of the context.
+const contextPoll = 1024
+
+// RunUntil runs the CPU until the predicate (called after each step) is true,
+// there is an error or the context is done (returning the context error).
+func (m *M6502) RunUntil(ctx context.Context, until func(m *M6502) bool) error {
+	done := ctx.Done()
+	if until == nil {
+		// run batches between checks of the context
+		for !m.stop {
+			if done != nil {
+				select {
+				case <-done:
+					return ctx.Err()
+				default:
+				}
+			}
+			if _, err := m.batch(contextPoll, ^uint(0)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := 0; ; i++ {
+		if i%contextPoll == 0 && done != nil {
+			select {
+			case <-done:
+				return ctx.Err()
+			default:
+			}
+		}
+		if m.stop {
+			return nil
+		}
+		if err := m.Run(); err != nil {
+			return err
+		}
+		if until(m) {
+			return nil
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
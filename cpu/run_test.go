@@ -0,0 +1,95 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Run Loop Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"context"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+// testLoop is a loop of 2 cycle and 3 cycle instructions.
+func testLoop(m *M6502, r *testRAM) {
+	m.PC = 0x0400
+	copy(r[0x0400:], []uint8{
+		0xe8,             // 0400 inx (2 cycles)
+		0x4c, 0x00, 0x04, // 0401 jmp $0400 (3 cycles)
+	})
+}
+
+func TestRunCycles(t *testing.T) {
+	var r testRAM
+	m := New6502(&r)
+	testLoop(m, &r)
+	start := m.Cycles()
+	n, err := m.RunCycles(11)
+	if err != nil || n != 12 || m.Cycles()-start != 12 || m.X != 3 {
+		t.Errorf("got %d cycles, x %d: %v", n, m.X, err)
+	}
+	n, err = m.RunCycles(0)
+	if err != nil || n != 0 {
+		t.Errorf("zero budget: %d %v", n, err)
+	}
+}
+
+func TestRunInstructions(t *testing.T) {
+	var r testRAM
+	m := New6502(&r)
+	testLoop(m, &r)
+	n, err := m.RunInstructions(5)
+	if err != nil || n != 5 || m.X != 3 || m.PC != 0x0401 {
+		t.Errorf("got %d steps, x %d pc %04x: %v", n, m.X, m.PC, err)
+	}
+}
+
+func TestRunUntil(t *testing.T) {
+	var r testRAM
+	m := New6502(&r)
+	testLoop(m, &r)
+	err := m.RunUntil(context.Background(), func(m *M6502) bool { return m.X == 0x80 })
+	if err != nil || m.X != 0x80 || m.PC != 0x0401 {
+		t.Errorf("x %d pc %04x: %v", m.X, m.PC, err)
+	}
+	// a cancelled context stops the cpu between instructions
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pc := m.PC
+	if err := m.RunUntil(ctx, nil); err != context.Canceled || m.PC != pc {
+		t.Errorf("cancel: %v", err)
+	}
+}
+
+func TestRunAllocs(t *testing.T) {
+	var r testRAM
+	m := New6502(&r)
+	testLoop(m, &r)
+	if n := testing.AllocsPerRun(100, func() { m.RunCycles(1000) }); n != 0 {
+		t.Errorf("%v allocations per run", n)
+	}
+}
+
+func TestRunStopped(t *testing.T) {
+	var r testRAM
+	m := NewW65C02S(&r)
+	m.PC = 0x0400
+	r[0x0400] = 0xdb // stp
+	n, err := m.RunCycles(100)
+	if err != nil || n != 3 || m.HaltReason() != HaltStop {
+		t.Errorf("cycles: %d %v", n, err)
+	}
+	if n, err := m.RunInstructions(10); err != nil || n != 0 {
+		t.Errorf("instructions: %d %v", n, err)
+	}
+	if err := m.RunUntil(context.Background(), nil); err != nil {
+		t.Errorf("until: %v", err)
+	}
+}
+
+//-----------------------------------------------------------------------------
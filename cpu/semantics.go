@@ -0,0 +1,149 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 CPU Instruction Semantics
+
+The operations of the instructions, described for cmd/gen. Together with the
+instruction sets in isa.go they generate the operations of the cycle stepped
+core (tickops.go).
+
+An operation is Go code using the CPU (m), the operand value (v), the
+effective address (ea) and the cycle count (n). Irregular instructions call
+their handler. The handled stores give the value stored (the last line).
+The cycle stepped core does the control flow instructions itself.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+//-----------------------------------------------------------------------------
+
+// semKind is how an instruction uses its operand.
+type semKind int
+
+const (
+	semImpl   semKind = iota // implied: no operand
+	semRead                  // reads the operand value (v)
+	semWrite                 // writes the value of an expression to the operand address
+	semRMW                   // read/modify/write of the operand value (v)
+	semNop                   // reads the operand and discards it (no read for immediate)
+	semBranch                // relative branch on a condition expression
+	semJump                  // jump to the operand address (ea), sets the PC
+	semFlow                  // sets the PC (not advanced by the instruction length)
+	semCall                  // calls the opcode handler
+)
+
+// insSem describes the operation of an instruction.
+type insSem struct {
+	kind semKind
+	op   string // operation
+	imm  string // operation for the immediate mode (if different)
+}
+
+// insSemantics maps the instruction mneumonic onto its operation.
+// The bit number of rmb/smb/bbr/bbs is the %d of the operation.
+var insSemantics = map[string]insSem{
+	// loads, stores and transfers
+	"lda": {semRead, "m.A = v\nm.setNZ(m.A)", ""},
+	"ldx": {semRead, "m.X = v\nm.setNZ(m.X)", ""},
+	"ldy": {semRead, "m.Y = v\nm.setNZ(m.Y)", ""},
+	"sta": {semWrite, "m.A", ""},
+	"stx": {semWrite, "m.X", ""},
+	"sty": {semWrite, "m.Y", ""},
+	"stz": {semWrite, "0", ""},
+	"tax": {semImpl, "m.X = m.A\nm.setNZ(m.X)", ""},
+	"tay": {semImpl, "m.Y = m.A\nm.setNZ(m.Y)", ""},
+	"tsx": {semImpl, "m.X = m.S\nm.setNZ(m.X)", ""},
+	"txa": {semImpl, "m.A = m.X\nm.setNZ(m.A)", ""},
+	"txs": {semImpl, "m.S = m.X", ""},
+	"tya": {semImpl, "m.A = m.Y\nm.setNZ(m.A)", ""},
+
+	// stack
+	"pha": {semImpl, "m.push8(m.A)", ""},
+	"php": {semImpl, "m.push8(m.P | flagB | flagU)", ""},
+	"phx": {semImpl, "m.push8(m.X)", ""},
+	"phy": {semImpl, "m.push8(m.Y)", ""},
+	"pla": {semImpl, "m.A = m.pop8()\nm.setNZ(m.A)", ""},
+	"plp": {semImpl, "m.P = m.pop8() | flagB | flagU", ""},
+	"plx": {semImpl, "m.X = m.pop8()\nm.setNZ(m.X)", ""},
+	"ply": {semImpl, "m.Y = m.pop8()\nm.setNZ(m.Y)", ""},
+
+	// arithmetic and logic
+	"adc": {semRead, "n += m.opADC(v)", ""},
+	"sbc": {semRead, "n += m.opSBC(v)", ""},
+	"and": {semRead, "m.A &= v\nm.setNZ(m.A)", ""},
+	"ora": {semRead, "m.A |= v\nm.setNZ(m.A)", ""},
+	"eor": {semRead, "m.A ^= v\nm.setNZ(m.A)", ""},
+	"cmp": {semRead, "m.opCompare(m.A, v)", ""},
+	"cpx": {semRead, "m.opCompare(m.X, v)", ""},
+	"cpy": {semRead, "m.opCompare(m.Y, v)", ""},
+	"bit": {semRead, "m.opBit(v)", "m.P &= ^flagZ\nm.setZ(v&m.A == 0)"},
+	"inc": {semRMW, "v++\nm.setNZ(v)", ""},
+	"dec": {semRMW, "v--\nm.setNZ(v)", ""},
+	"inx": {semImpl, "m.X++\nm.setNZ(m.X)", ""},
+	"iny": {semImpl, "m.Y++\nm.setNZ(m.Y)", ""},
+	"dex": {semImpl, "m.X--\nm.setNZ(m.X)", ""},
+	"dey": {semImpl, "m.Y--\nm.setNZ(m.Y)", ""},
+	"asl": {semRMW, "v = m.opASL(v)", ""},
+	"lsr": {semRMW, "v = m.opLSR(v)", ""},
+	"rol": {semRMW, "v = m.opROL(v)", ""},
+	"ror": {semRMW, "v = m.opROR(v)", ""},
+	"tsb": {semRMW, "m.P &= ^flagZ\nm.setZ(v&m.A == 0)\nv |= m.A", ""},
+	"trb": {semRMW, "m.P &= ^flagZ\nm.setZ(v&m.A == 0)\nv &^= m.A", ""},
+	"rmb": {semRMW, "v &^= 1 << %d", ""},
+	"smb": {semRMW, "v |= 1 << %d", ""},
+
+	// flags
+	"clc": {semImpl, "m.P &= ^flagC", ""},
+	"cld": {semImpl, "m.P &= ^flagD", ""},
+	"cli": {semImpl, "m.P &= ^flagI", ""},
+	"clv": {semImpl, "m.P &= ^flagV", ""},
+	"sec": {semImpl, "m.P |= flagC", ""},
+	"sed": {semImpl, "m.P |= flagD", ""},
+	"sei": {semImpl, "m.P |= flagI", ""},
+
+	// control flow
+	"bcc": {semBranch, "m.P&flagC == 0", ""},
+	"bcs": {semBranch, "m.P&flagC != 0", ""},
+	"beq": {semBranch, "m.P&flagZ != 0", ""},
+	"bne": {semBranch, "m.P&flagZ == 0", ""},
+	"bmi": {semBranch, "m.P&flagN != 0", ""},
+	"bpl": {semBranch, "m.P&flagN == 0", ""},
+	"bvc": {semBranch, "m.P&flagV == 0", ""},
+	"bvs": {semBranch, "m.P&flagV != 0", ""},
+	"bra": {semBranch, "true", ""},
+	"bbr": {semFlow, "n += m.opBBx(%d, false)", ""},
+	"bbs": {semFlow, "n += m.opBBx(%d, true)", ""},
+	"jmp": {semJump, "m.PC = ea\nm.jmpVSR()", ""},
+	"jsr": {semFlow, "m.push16(m.PC + 2)\nm.PC = m.operand16(m.PC + 1)\nm.jsrVSR()", ""},
+	"rts": {semFlow, "m.PC = m.pop16() + 1", ""},
+	"rti": {semFlow, "m.P = m.pop8() | flagB | flagU\nm.PC = m.pop16()", ""},
+	"brk": {semCall, "", ""},
+	"nop": {semNop, "", ""},
+	"wai": {semImpl, "m.wait = true", ""},
+	"stp": {semImpl, "m.stop = true", ""},
+
+	// undocumented
+	"slo": {semRMW, "v = m.opSLO(v)", ""},
+	"rla": {semRMW, "v = m.opRLA(v)", ""},
+	"sre": {semRMW, "v = m.opSRE(v)", ""},
+	"rra": {semRMW, "v = m.opRRA(v)", ""},
+	"dcp": {semRMW, "v = m.opDCP(v)", ""},
+	"isc": {semRMW, "v = m.opISC(v)", ""},
+	"sax": {semWrite, "m.A & m.X", ""},
+	"lax": {semRead, "m.A = v\nm.X = v\nm.setNZ(v)", "m.A = (m.A | unstableMagic) & v\nm.X = m.A\nm.setNZ(m.A)"},
+	"las": {semRead, "v &= m.S\nm.A = v\nm.X = v\nm.S = v\nm.setNZ(v)", ""},
+	"alr": {semRead, "m.A = m.opLSR(m.A & v)", ""},
+	"anc": {semRead, "m.P &= ^flagC\nm.A &= v\nm.setNZ(m.A)\nm.setC(m.A&0x80 != 0)", ""},
+	"arr": {semRead, "m.opARR(v)", ""},
+	"sbx": {semRead, "x := m.A & m.X\nm.opCompare(x, v)\nm.X = x - v", ""},
+	"xaa": {semRead, "m.A = (m.A | unstableMagic) & m.X & v\nm.setNZ(m.A)", ""},
+	"sha": {semCall, "m.A & m.X", ""},
+	"shx": {semCall, "m.X", ""},
+	"shy": {semCall, "m.Y", ""},
+	"tas": {semCall, "m.S = m.A & m.X\nm.S", ""},
+	"jam": {semCall, "", ""},
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,298 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 CPU Snapshots
+
+A snapshot is the CPU state between instructions. It can be restored into a
+CPU of the same variant and encoded in a versioned binary format.
+
+The interrupt sources (see Interrupts) belong to the devices and are not part
+of a snapshot. When a CPU has sources the restored IRQ and NMI lines follow
+them, as they do after Power.
+
+Restoring a 6510 reports the restored port lines to the host (see PortOutput)
+so it can switch its memory map back. Restoring drops the predecoded
+instructions, so restore the memory before the CPU.
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+//-----------------------------------------------------------------------------
+
+// PortState is the state of the 6510 I/O port.
+type PortState struct {
+	DDR      uint8   // data direction register
+	Data     uint8   // port register
+	PullUps  uint8   // pins pulled high when not driven
+	Input    uint8   // levels on host driven input pins
+	Driven   uint8   // pins driven by the host
+	Floating uint8   // last level output on now floating pins
+	Lines    uint8   // last reported pin levels
+	FallOff  [8]uint // cycle at which a floating pin decays to 0
+}
+
+// Snapshot is the saved state of an M6502.
+type Snapshot struct {
+	Variant Variant
+	PC      uint16
+	S       uint8
+	P       uint8
+	A       uint8
+	X       uint8
+	Y       uint8
+	Cycles  uint
+	NMI     bool // nmi latched
+	NMILine bool // nmi line state
+	IRQ     bool // irq line state
+	Pending bool // interrupt polled by Tick for the next instruction
+	Illegal bool
+	Jam     bool
+	Wait    bool
+	Stop    bool
+	Exit    bool
+	LastPC  uint16
+	StuckPC uint
+	Usage   [256]uint  // opcode usage
+	Port    *PortState // 6510 I/O port (nil for other variants)
+}
+
+// Snapshot returns the state of the CPU.
+// A cycle stepped instruction in progress is not saved, so take it when Sync is true.
+func (m *M6502) Snapshot() *Snapshot {
+	s := &Snapshot{
+		Variant: m.variant,
+		PC:      m.PC,
+		S:       m.S,
+		P:       m.P,
+		A:       m.A,
+		X:       m.X,
+		Y:       m.Y,
+		Cycles:  m.cycles,
+		NMI:     m.nmi,
+		NMILine: m.nmiLine,
+		IRQ:     m.irq,
+		Pending: m.tick.pending,
+		Illegal: m.illegal,
+		Jam:     m.jam,
+		Wait:    m.wait,
+		Stop:    m.stop,
+		Exit:    m.exit,
+		LastPC:  m.lastPC,
+		StuckPC: m.stuckPC,
+		Usage:   m.usage,
+	}
+	if p := m.port; p != nil {
+		s.Port = &PortState{
+			DDR:      p.ddr,
+			Data:     p.data,
+			PullUps:  p.pullUps,
+			Input:    p.input,
+			Driven:   p.driven,
+			Floating: p.floating,
+			Lines:    p.lines,
+			FallOff:  p.fallOff,
+		}
+	}
+	return s
+}
+
+// Restore sets the state of the CPU from a snapshot.
+// The IRQ and NMI lines are held by the interrupt sources, if there are any.
+func (m *M6502) Restore(s *Snapshot) error {
+	if s.Variant != m.variant {
+		return fmt.Errorf("snapshot is for a %s, not a %s", s.Variant, m.variant)
+	}
+	if (s.Port == nil) != (m.port == nil) {
+		return errors.New("snapshot I/O port doesn't match the cpu")
+	}
+	m.PC = s.PC
+	m.S = s.S
+	m.P = s.P
+	m.A = s.A
+	m.X = s.X
+	m.Y = s.Y
+	m.cycles = s.Cycles
+	m.nmi = s.NMI
+	m.nmiLine = s.NMILine
+	m.irq = s.IRQ
+	if m.ints != nil {
+		// the lines are held by the interrupt sources
+		m.irq = m.ints.irqLine
+		m.nmiLine = m.ints.nmiLine
+	}
+	m.illegal = s.Illegal
+	m.jam = s.Jam
+	m.wait = s.Wait
+	m.stop = s.Stop
+	m.exit = s.Exit
+	m.lastPC = s.LastPC
+	m.stuckPC = s.StuckPC
+	m.tick = tickState{pending: s.Pending}
+	m.usage = s.Usage
+	if p := m.port; p != nil {
+		p.ddr = s.Port.DDR & portMask
+		p.data = s.Port.Data & portMask
+		p.pullUps = s.Port.PullUps
+		p.input = s.Port.Input
+		p.driven = s.Port.Driven
+		p.floating = s.Port.Floating
+		p.lines = s.Port.Lines
+		p.fallOff = s.Port.FallOff
+		p.report()
+	}
+	m.Invalidate(0, 0xffff)
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+// binary encoding
+
+// snapshotVersion is the version of the snapshot encoding.
+const snapshotVersion = 1
+
+// snapshot flags
+const (
+	snapNMI = 1 << iota
+	snapNMILine
+	snapIRQ
+	snapPending
+	snapIllegal
+	snapJam
+	snapWait
+	snapStop
+	snapExit
+	snapPort
+)
+
+// snapshotData is the fixed size encoding of a snapshot.
+type snapshotData struct {
+	Version uint16
+	Variant uint16
+	PC      uint16
+	S       uint8
+	P       uint8
+	A       uint8
+	X       uint8
+	Y       uint8
+	Flags   uint16
+	Cycles  uint64
+	LastPC  uint16
+	StuckPC uint64
+	Usage   [256]uint64
+	Port    [7]uint8
+	FallOff [8]uint64
+}
+
+var snapshotFlags = []struct {
+	flag uint16
+	val  func(s *Snapshot) *bool
+}{
+	{snapNMI, func(s *Snapshot) *bool { return &s.NMI }},
+	{snapNMILine, func(s *Snapshot) *bool { return &s.NMILine }},
+	{snapIRQ, func(s *Snapshot) *bool { return &s.IRQ }},
+	{snapPending, func(s *Snapshot) *bool { return &s.Pending }},
+	{snapIllegal, func(s *Snapshot) *bool { return &s.Illegal }},
+	{snapJam, func(s *Snapshot) *bool { return &s.Jam }},
+	{snapWait, func(s *Snapshot) *bool { return &s.Wait }},
+	{snapStop, func(s *Snapshot) *bool { return &s.Stop }},
+	{snapExit, func(s *Snapshot) *bool { return &s.Exit }},
+}
+
+// MarshalBinary encodes the snapshot.
+func (s *Snapshot) MarshalBinary() ([]byte, error) {
+	d := snapshotData{
+		Version: snapshotVersion,
+		Variant: uint16(s.Variant),
+		PC:      s.PC,
+		S:       s.S,
+		P:       s.P,
+		A:       s.A,
+		X:       s.X,
+		Y:       s.Y,
+		Cycles:  uint64(s.Cycles),
+		LastPC:  s.LastPC,
+		StuckPC: uint64(s.StuckPC),
+	}
+	for _, f := range snapshotFlags {
+		if *f.val(s) {
+			d.Flags |= f.flag
+		}
+	}
+	for i, v := range s.Usage {
+		d.Usage[i] = uint64(v)
+	}
+	if p := s.Port; p != nil {
+		d.Flags |= snapPort
+		d.Port = [7]uint8{p.DDR, p.Data, p.PullUps, p.Input, p.Driven, p.Floating, p.Lines}
+		for i, v := range p.FallOff {
+			d.FallOff[i] = uint64(v)
+		}
+	}
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, &d); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the snapshot.
+func (s *Snapshot) UnmarshalBinary(data []byte) error {
+	var d snapshotData
+	if len(data) != binary.Size(&d) {
+		return errors.New("bad snapshot length")
+	}
+	if err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &d); err != nil {
+		return err
+	}
+	if d.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", d.Version)
+	}
+	if int(d.Variant) >= len(variants) {
+		return fmt.Errorf("bad snapshot variant %d", d.Variant)
+	}
+	*s = Snapshot{
+		Variant: Variant(d.Variant),
+		PC:      d.PC,
+		S:       d.S,
+		P:       d.P,
+		A:       d.A,
+		X:       d.X,
+		Y:       d.Y,
+		Cycles:  uint(d.Cycles),
+		LastPC:  d.LastPC,
+		StuckPC: uint(d.StuckPC),
+	}
+	for _, f := range snapshotFlags {
+		*f.val(s) = d.Flags&f.flag != 0
+	}
+	for i, v := range d.Usage {
+		s.Usage[i] = uint(v)
+	}
+	if d.Flags&snapPort != 0 {
+		p := &PortState{
+			DDR:      d.Port[0],
+			Data:     d.Port[1],
+			PullUps:  d.Port[2],
+			Input:    d.Port[3],
+			Driven:   d.Port[4],
+			Floating: d.Port[5],
+			Lines:    d.Port[6],
+		}
+		for i, v := range d.FallOff {
+			p.FallOff[i] = uint(v)
+		}
+		s.Port = p
+	}
+	return nil
+}
+
+//-----------------------------------------------------------------------------
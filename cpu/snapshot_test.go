@@ -0,0 +1,206 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 CPU Snapshot Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+// testSnapshot runs some code on a cpu and returns a snapshot of it.
+func testSnapshot(t *testing.T, v Variant) (*M6502, *Snapshot) {
+	t.Helper()
+	var r testRAM
+	m := newCPU(&r, v, nil)
+	m.Power(true)
+	m.PC = 0x0400
+	// lda #$81, ldx #$42, sec, sed, pha, nop
+	copy(r[0x0400:], []uint8{0xa9, 0x81, 0xa2, 0x42, 0x38, 0xf8, 0x48, 0xea})
+	if m.port != nil {
+		// drive the cassette sense pin and leave bit 5 floating high
+		m.PortInput(0x10, 0x00)
+		m.write8(0, 0x2f)
+		m.write8(1, 0x35)
+		m.write8(0, 0x07)
+	}
+	for i := 0; i < 5; i++ {
+		testStep(t, m, false)
+	}
+	m.NMILine(true)
+	m.IRQ(true)
+	return m, m.Snapshot()
+}
+
+//-----------------------------------------------------------------------------
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	for _, v := range append(nmosVariants, cmosVariants...) {
+		m, s := testSnapshot(t, v)
+		if (s.Port != nil) != (v == Variant6510) {
+			t.Fatalf("%s: port %v", v, s.Port)
+		}
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatalf("%s: %v", v, err)
+		}
+		var s2 Snapshot
+		if err := s2.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%s: %v", v, err)
+		}
+		if !reflect.DeepEqual(s, &s2) {
+			t.Fatalf("%s: decoded snapshot\n%+v\nwant\n%+v", v, s2, *s)
+		}
+		// restore into a fresh cpu
+		var r testRAM
+		m2 := newCPU(&r, v, nil)
+		m2.Power(true)
+		if err := m2.Restore(&s2); err != nil {
+			t.Fatalf("%s: %v", v, err)
+		}
+		if !reflect.DeepEqual(m2.Snapshot(), s) {
+			t.Errorf("%s: restored cpu\n%s\nwant\n%s", v, m2.Dump(), m.Dump())
+		}
+		if m2.port != nil && m2.PortLines() != m.PortLines() {
+			t.Errorf("%s: port lines %02x, want %02x", v, m2.PortLines(), m.PortLines())
+		}
+	}
+}
+
+func TestSnapshotErrors(t *testing.T) {
+	_, s := testSnapshot(t, Variant6502)
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s2 Snapshot
+	// length
+	if err := s2.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("short snapshot decoded")
+	}
+	if err := s2.UnmarshalBinary(append(data, 0)); err == nil {
+		t.Errorf("long snapshot decoded")
+	}
+	// version
+	bad := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint16(bad[0:], snapshotVersion+1)
+	if err := s2.UnmarshalBinary(bad); err == nil {
+		t.Errorf("bad version decoded")
+	}
+	// variant
+	bad = append([]byte(nil), data...)
+	binary.LittleEndian.PutUint16(bad[2:], uint16(len(variants)))
+	if err := s2.UnmarshalBinary(bad); err == nil {
+		t.Errorf("bad variant decoded")
+	}
+	// restore into another variant
+	var r testRAM
+	if err := newCPU(&r, Variant65C02, nil).Restore(s); err == nil {
+		t.Errorf("6502 snapshot restored into a 65c02")
+	}
+	// a 6510 snapshot needs the port state
+	_, s = testSnapshot(t, Variant6510)
+	s.Port = nil
+	if err := newCPU(&r, Variant6510, nil).Restore(s); err == nil {
+		t.Errorf("6510 snapshot without a port restored")
+	}
+}
+
+func TestSnapshotSources(t *testing.T) {
+	// the interrupt sources hold the lines over a restore
+	_, s := testSnapshot(t, Variant6502)
+	var r testRAM
+	m := newCPU(&r, Variant6502, nil)
+	m.Power(true)
+	src, err := m.Interrupts().IRQ("via")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Restore(s); err != nil {
+		t.Fatal(err)
+	}
+	if m.irq || m.nmiLine {
+		t.Errorf("lines irq %t nmi %t with no source asserted", m.irq, m.nmiLine)
+	}
+	src.Assert()
+	s.IRQ = false
+	if err := m.Restore(s); err != nil {
+		t.Fatal(err)
+	}
+	if !m.irq {
+		t.Errorf("irq line released with the source asserted")
+	}
+}
+
+func TestSnapshotPort(t *testing.T) {
+	// the port selects the code at $8000 (as the C64 switches the ROMs)
+	var r testRAM
+	banks := [2][]uint8{
+		{0xa9, 0x11, 0x60}, // lda #$11, rts
+		{0xa9, 0x22, 0x60}, // lda #$22, rts
+	}
+	var lines []uint8
+	m := New6510(&r, WithPredecode(), PortOutput(func(l uint8) {
+		lines = append(lines, l)
+		copy(r[0x8000:], banks[l&1])
+	}))
+	m.Power(true)
+	copy(r[0x0400:], []uint8{0x20, 0x00, 0x80}) // jsr $8000
+	// bit 0 outputs 0
+	m.Poke8(1, 0x00)
+	m.Poke8(0, 0x01)
+	s := m.Snapshot()
+	// back to bit 0 pulled up, run the code in bank 1
+	m.Poke8(0, 0x00)
+	m.PC = 0x0400
+	for i := 0; i < 2; i++ {
+		testStep(t, m, false)
+	}
+	if m.A != 0x22 {
+		t.Fatalf("ran %02x in bank 1", m.A)
+	}
+	// the restore reports the lines and drops the code of bank 1
+	lines = nil
+	if err := m.Restore(s); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 1 || lines[0] != 0x16 {
+		t.Errorf("restore reported lines %02x, want 16", lines)
+	}
+	m.PC = 0x0400
+	for i := 0; i < 2; i++ {
+		testStep(t, m, false)
+	}
+	if m.A != 0x11 {
+		t.Errorf("ran %02x after the restore, want 11", m.A)
+	}
+}
+
+func TestSnapshotPredecode(t *testing.T) {
+	// restoring drops the code predecoded from the memory before it
+	var r testRAM
+	m := New6502(&r, WithPredecode())
+	m.Power(true)
+	copy(r[0x0400:], []uint8{0xa9, 0x11}) // lda #$11
+	m.PC = 0x0400
+	s := m.Snapshot()
+	testStep(t, m, false)
+	r[0x0401] = 0x22
+	if err := m.Restore(s); err != nil {
+		t.Fatal(err)
+	}
+	testStep(t, m, false)
+	if m.A != 0x22 {
+		t.Errorf("ran %02x after the restore, want 22", m.A)
+	}
+}
+
+//-----------------------------------------------------------------------------
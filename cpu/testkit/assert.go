@@ -0,0 +1,235 @@
+//-----------------------------------------------------------------------------
+/*
+
+Assertions
+
+Assertions on the result of a call. A failed assertion reports the expected
+value with a display of the registers (as cpu.M6502.Dump) or memory. The
+differences are marked with ^ under the display.
+
+*/
+//-----------------------------------------------------------------------------
+
+package testkit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+// Result is the result of a call.
+type Result struct {
+	k      *Kit
+	regs   cpu.Regs
+	cycles uint
+	dump   string // register display at the end of the call
+	traced bool   // the trace has been reported
+}
+
+// Regs returns the registers after the call.
+func (r *Result) Regs() cpu.Regs {
+	return r.regs
+}
+
+// Used returns the cycles used by the call.
+func (r *Result) Used() uint {
+	return r.cycles
+}
+
+// report returns a failure message with the trace (for the first failure).
+func (r *Result) report(msg, detail string) string {
+	s := []string{msg, detail}
+	if r.k.trace != nil && !r.traced {
+		s = append(s, r.k.trace.String(r.k.CPU.Variant()))
+		r.traced = true
+	}
+	return strings.Join(s, "\n")
+}
+
+// fail reports a failed assertion.
+func (r *Result) fail(msg, detail string) {
+	r.k.t.Helper()
+	r.k.t.Error(r.report(msg, detail))
+}
+
+//-----------------------------------------------------------------------------
+// registers
+
+// register display columns (as cpu.M6502.Dump)
+const (
+	colA    = 5
+	colX    = 8
+	colY    = 11
+	colS    = 14
+	colP    = 17
+	colBits = 22
+)
+
+// dumpRegs returns the register display with marks under the columns.
+func (r *Result) dumpRegs(want string, cols ...int) string {
+	mark := []byte(strings.Repeat(" ", colBits+16))
+	for _, c := range cols {
+		mark[c] = '^'
+		if c < colBits {
+			mark[c+1] = '^'
+		}
+	}
+	s := []string{r.dump}
+	if want != "" {
+		s = append(s, want)
+	}
+	s = append(s, strings.TrimRight(string(mark), " "))
+	return strings.Join(s, "\n")
+}
+
+func (r *Result) reg(name string, got, want uint8, col int) *Result {
+	r.k.t.Helper()
+	if got != want {
+		r.fail(fmt.Sprintf("%s = %02x, want %02x", name, got, want), r.dumpRegs("", col))
+	}
+	return r
+}
+
+// A asserts the value of the A register.
+func (r *Result) A(want uint8) *Result {
+	r.k.t.Helper()
+	return r.reg("a", r.regs.A, want, colA)
+}
+
+// X asserts the value of the X register.
+func (r *Result) X(want uint8) *Result {
+	r.k.t.Helper()
+	return r.reg("x", r.regs.X, want, colX)
+}
+
+// Y asserts the value of the Y register.
+func (r *Result) Y(want uint8) *Result {
+	r.k.t.Helper()
+	return r.reg("y", r.regs.Y, want, colY)
+}
+
+// S asserts the value of the stack pointer.
+func (r *Result) S(want uint8) *Result {
+	r.k.t.Helper()
+	return r.reg("s", r.k.CPU.S, want, colS)
+}
+
+// P asserts the value of the processor status flags.
+func (r *Result) P(want uint8) *Result {
+	r.k.t.Helper()
+	return r.flags(fmt.Sprintf("p = %02x, want %02x", r.regs.P, want), want, 0xff)
+}
+
+// Flags asserts the values of processor status flags.
+func (r *Result) Flags(flags string) *Result {
+	r.k.t.Helper()
+	set, mask, err := parseFlags(flags)
+	if err != nil {
+		r.k.t.Fatal(err)
+	}
+	return r.flags(fmt.Sprintf("flags = %s, want %s", flagString(r.regs.P, mask), flags), set, mask)
+}
+
+// flags checks the flags in the mask, showing the wanted flag bits.
+func (r *Result) flags(msg string, want, mask uint8) *Result {
+	r.k.t.Helper()
+	if (r.regs.P^want)&mask == 0 {
+		return r
+	}
+	w := []byte(fmt.Sprintf("%-*s", colBits+16, "want"))
+	var cols []int
+	for i := 0; i < 8; i++ {
+		bit := uint8(0x80) >> uint(i)
+		if mask&bit == 0 {
+			continue
+		}
+		col := colBits + 2*i
+		w[col] = '0' + (want>>uint(7-i))&1
+		if (r.regs.P^want)&bit != 0 {
+			cols = append(cols, col)
+		}
+	}
+	r.fail(msg, r.dumpRegs(strings.TrimRight(string(w), " "), cols...))
+	return r
+}
+
+// flagString returns the flags in the mask as a flag string.
+func flagString(p, mask uint8) string {
+	var s []byte
+	for _, c := range "nvbdizc" {
+		bit := flagBits[c]
+		if mask&bit == 0 {
+			continue
+		}
+		if p&bit != 0 {
+			c &^= 0x20
+		}
+		s = append(s, byte(c))
+	}
+	return string(s)
+}
+
+//-----------------------------------------------------------------------------
+// memory and cycles
+
+// Mem asserts the bytes in memory at an address.
+func (r *Result) Mem(adr uint16, want ...uint8) *Result {
+	r.k.t.Helper()
+	got := make([]uint8, len(want))
+	diff := 0
+	for i := range want {
+		got[i] = r.k.CPU.Peek8(adr + uint16(i))
+		if got[i] != want[i] {
+			diff++
+		}
+	}
+	if diff != 0 {
+		r.fail(fmt.Sprintf("mem %04x: %d bytes differ", adr, diff), dumpMem(adr, got, want))
+	}
+	return r
+}
+
+// Word asserts a 16-bit little endian value in memory at an address.
+func (r *Result) Word(adr uint16, want uint16) *Result {
+	r.k.t.Helper()
+	return r.Mem(adr, uint8(want), uint8(want>>8))
+}
+
+// dumpMem returns a display of the memory with the differing bytes marked.
+func dumpMem(adr uint16, got, want []uint8) string {
+	var s []string
+	for i := 0; i < len(want); i += 16 {
+		j := i + 16
+		if j > len(want) {
+			j = len(want)
+		}
+		g := fmt.Sprintf("% x", got[i:j])
+		w := fmt.Sprintf("% x", want[i:j])
+		mark := []byte(strings.Repeat(" ", len(w)))
+		for k := i; k < j; k++ {
+			if got[k] != want[k] {
+				mark[3*(k-i)] = '^'
+				mark[3*(k-i)+1] = '^'
+			}
+		}
+		s = append(s, fmt.Sprintf("%04x got  %s", adr+uint16(i), g))
+		s = append(s, fmt.Sprintf("     want %s", w))
+		s = append(s, strings.TrimRight("          "+string(mark), " "))
+	}
+	return strings.Join(s, "\n")
+}
+
+// Cycles asserts the cycles used by the call.
+func (r *Result) Cycles(want uint) *Result {
+	r.k.t.Helper()
+	if r.cycles != want {
+		r.fail(fmt.Sprintf("cycles = %d, want %d", r.cycles, want), r.dump)
+	}
+	return r
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,167 @@
+//-----------------------------------------------------------------------------
+/*
+
+Flat RAM
+
+A 64K RAM implementing cpu.Memory (and cpu.DebugMemory), with loaders for
+binary images and hex files. Two hex formats are accepted:
+
+	0200  A9 00 A8 91 02 ...    address and data bytes (as in code/code.txt)
+	:10020000A900A891...        Intel HEX records
+
+*/
+//-----------------------------------------------------------------------------
+
+package testkit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//-----------------------------------------------------------------------------
+
+// RAM is a flat 64K memory.
+type RAM [1 << 16]uint8
+
+// Read8 reads a byte from memory.
+func (r *RAM) Read8(adr uint16) uint8 {
+	return r[adr]
+}
+
+// Write8 writes a byte to memory.
+func (r *RAM) Write8(adr uint16, val uint8) {
+	r[adr] = val
+}
+
+// Peek8 reads a byte from memory.
+func (r *RAM) Peek8(adr uint16) uint8 {
+	return r[adr]
+}
+
+// Poke8 writes a byte to memory.
+func (r *RAM) Poke8(adr uint16, val uint8) {
+	r[adr] = val
+}
+
+// Load copies data to memory at an address.
+// It returns an error if the data doesn't fit.
+func (r *RAM) Load(adr uint16, data []uint8) error {
+	if int(adr)+len(data) > len(r) {
+		return fmt.Errorf("%d bytes at %04x overflow memory", len(data), adr)
+	}
+	copy(r[adr:], data)
+	return nil
+}
+
+// LoadBin loads a binary file to memory at an address.
+func (r *RAM) LoadBin(adr uint16, name string) error {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	return r.Load(adr, data)
+}
+
+// LoadHex loads a hex file to memory.
+func (r *RAM) LoadHex(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := r.ReadHex(f); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	return nil
+}
+
+// ReadHex loads hex lines to memory.
+func (r *RAM) ReadHex(rd io.Reader) error {
+	s := bufio.NewScanner(rd)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		var err error
+		if strings.HasPrefix(line, ":") {
+			var eof bool
+			eof, err = r.ihexRecord(line[1:])
+			if eof {
+				return nil
+			}
+		} else if line != "" {
+			err = r.dumpLine(line)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %v", n, err)
+		}
+	}
+	return s.Err()
+}
+
+// dumpLine loads an address and data bytes line.
+func (r *RAM) dumpLine(line string) error {
+	x := strings.Fields(line)
+	adr, err := strconv.ParseUint(x[0], 16, 16)
+	if err != nil {
+		return err
+	}
+	data, err := Hex(strings.Join(x[1:], " "))
+	if err != nil {
+		return err
+	}
+	return r.Load(uint16(adr), data)
+}
+
+// ihexRecord loads an Intel HEX record. It returns true for the EOF record.
+func (r *RAM) ihexRecord(rec string) (bool, error) {
+	b, err := Hex(rec)
+	if err != nil {
+		return false, err
+	}
+	if len(b) < 5 || len(b) != int(b[0])+5 {
+		return false, fmt.Errorf("bad record length")
+	}
+	var sum uint8
+	for _, v := range b {
+		sum += v
+	}
+	if sum != 0 {
+		return false, fmt.Errorf("bad checksum")
+	}
+	adr := uint16(b[1])<<8 | uint16(b[2])
+	switch b[3] {
+	case 0x00: // data
+		return false, r.Load(adr, b[4:len(b)-1])
+	case 0x01: // end of file
+		return true, nil
+	case 0x03, 0x05: // start address
+		return false, nil
+	}
+	return false, fmt.Errorf("unsupported record type %02x", b[3])
+}
+
+//-----------------------------------------------------------------------------
+
+// Hex converts a hex string to bytes. Bytes may be separated by spaces.
+func Hex(s string) ([]uint8, error) {
+	s = strings.Join(strings.Fields(s), "")
+	if len(s)&1 != 0 {
+		return nil, fmt.Errorf("odd number of hex digits")
+	}
+	b := make([]uint8, len(s)/2)
+	for i := range b {
+		v, err := strconv.ParseUint(s[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return nil, err
+		}
+		b[i] = uint8(v)
+	}
+	return b, nil
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,233 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Test Kit
+
+Go unit tests for 6502 routines. A Kit is a CPU with flat RAM. A test loads
+code, sets the registers and memory, calls a routine and asserts on the
+result:
+
+	k := testkit.New(t)
+	k.Code(0x0400, "86 10 18 65 10 60") // stx $10, clc, adc $10, rts
+	k.SetA(0x12).SetX(0x34).SetFlags("d")
+	k.Call(0x0400).A(0x46).Flags("nzc").Mem(0x10, 0x34).Cycles(14)
+
+Setup errors fail the test immediately. Assertion failures are reported with
+a display of the registers or memory showing the differences, followed by a
+trace of the last instructions of the call.
+
+Flags are given as a string of the letters nvbdizc: upper case for a set flag
+and lower case for a clear flag. Flags not in the string aren't changed (or
+checked).
+
+*/
+//-----------------------------------------------------------------------------
+
+package testkit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+// Kit is a CPU and RAM for unit testing 6502 routines.
+type Kit struct {
+	CPU   *cpu.M6502
+	RAM   *RAM
+	t     testing.TB
+	regs  cpu.Regs // registers for the next call
+	trace *trace
+}
+
+// New returns a test kit with a 6502 (use cpu.WithVariant to select another CPU).
+func New(t testing.TB, opts ...cpu.Option) *Kit {
+	k := &Kit{
+		RAM:   &RAM{},
+		t:     t,
+		trace: newTrace(DefaultTrace),
+	}
+	k.CPU = cpu.New6502(k.RAM, opts...)
+	k.CPU.OnBefore(func(m *cpu.M6502) error {
+		if k.trace == nil {
+			return nil
+		}
+		return k.trace.record(m)
+	})
+	return k
+}
+
+//-----------------------------------------------------------------------------
+// setup
+
+// fatal fails the test for a setup error.
+func (k *Kit) fatal(err error) {
+	k.t.Helper()
+	if err != nil {
+		k.t.Fatal(err)
+	}
+}
+
+// loaded reports a change to memory from start to end (inclusive).
+func (k *Kit) loaded(start, end uint16) {
+	k.CPU.Invalidate(start, end)
+}
+
+// Load copies data to memory at an address.
+func (k *Kit) Load(adr uint16, data []uint8) *Kit {
+	k.t.Helper()
+	k.fatal(k.RAM.Load(adr, data))
+	if len(data) != 0 {
+		k.loaded(adr, adr+uint16(len(data)-1))
+	}
+	return k
+}
+
+// Code loads a hex string ("a9 12 60") to memory at an address.
+func (k *Kit) Code(adr uint16, hex string) *Kit {
+	k.t.Helper()
+	data, err := Hex(hex)
+	k.fatal(err)
+	return k.Load(adr, data)
+}
+
+// LoadBin loads a binary file to memory at an address.
+func (k *Kit) LoadBin(adr uint16, name string) *Kit {
+	k.t.Helper()
+	k.fatal(k.RAM.LoadBin(adr, name))
+	k.loaded(0, 0xffff)
+	return k
+}
+
+// LoadHex loads a hex file to memory.
+func (k *Kit) LoadHex(name string) *Kit {
+	k.t.Helper()
+	k.fatal(k.RAM.LoadHex(name))
+	k.loaded(0, 0xffff)
+	return k
+}
+
+// Mem writes bytes to memory at an address.
+func (k *Kit) Mem(adr uint16, vals ...uint8) *Kit {
+	for i, v := range vals {
+		k.CPU.Poke8(adr+uint16(i), v)
+	}
+	return k
+}
+
+// Word writes a 16-bit little endian value to memory at an address.
+func (k *Kit) Word(adr uint16, val uint16) *Kit {
+	return k.Mem(adr, uint8(val), uint8(val>>8))
+}
+
+// SetA sets the A register for the next call.
+func (k *Kit) SetA(v uint8) *Kit {
+	k.regs.A = v
+	return k
+}
+
+// SetX sets the X register for the next call.
+func (k *Kit) SetX(v uint8) *Kit {
+	k.regs.X = v
+	return k
+}
+
+// SetY sets the Y register for the next call.
+func (k *Kit) SetY(v uint8) *Kit {
+	k.regs.Y = v
+	return k
+}
+
+// SetP sets the processor status flags for the next call.
+func (k *Kit) SetP(v uint8) *Kit {
+	k.regs.P = v
+	return k
+}
+
+// SetFlags sets and clears processor status flags for the next call.
+func (k *Kit) SetFlags(flags string) *Kit {
+	k.t.Helper()
+	set, mask, err := parseFlags(flags)
+	k.fatal(err)
+	k.regs.P = k.regs.P&^mask | set
+	return k
+}
+
+// SetS sets the stack pointer.
+func (k *Kit) SetS(v uint8) *Kit {
+	k.CPU.S = v
+	return k
+}
+
+// Limit sets the cycle limit for a call (0 for the default).
+func (k *Kit) Limit(n uint) *Kit {
+	k.CPU.SetCallLimit(n)
+	return k
+}
+
+// Trace sets the number of instructions traced by a call (0 for no trace).
+func (k *Kit) Trace(n int) *Kit {
+	if n <= 0 {
+		k.trace = nil
+	} else {
+		k.trace = newTrace(n)
+	}
+	return k
+}
+
+//-----------------------------------------------------------------------------
+
+// Call calls the subroutine at an address. The test fails immediately if the
+// subroutine doesn't return.
+func (k *Kit) Call(adr uint16) *Result {
+	k.t.Helper()
+	if k.trace != nil {
+		k.trace.reset()
+	}
+	regs, n, err := k.CPU.Call(adr, k.regs)
+	// the cpu has the caller's registers back, display the callee's
+	end := cpu.M6502{PC: k.CPU.PC, A: regs.A, X: regs.X, Y: regs.Y, S: k.CPU.S, P: regs.P}
+	var cerr *cpu.CallError
+	if errors.As(err, &cerr) {
+		end.PC = cerr.PC
+	}
+	r := &Result{k: k, regs: regs, cycles: n, dump: end.Dump()}
+	if err != nil {
+		k.t.Fatal(r.report(fmt.Sprintf("call %04x: %v", adr, err), r.dump))
+	}
+	return r
+}
+
+//-----------------------------------------------------------------------------
+
+// flagBits maps the flag letters onto the status register bits.
+var flagBits = map[rune]uint8{
+	'n': 1 << 7,
+	'v': 1 << 6,
+	'b': 1 << 4,
+	'd': 1 << 3,
+	'i': 1 << 2,
+	'z': 1 << 1,
+	'c': 1 << 0,
+}
+
+// parseFlags returns the set flags and the mask of the flags in a string.
+func parseFlags(flags string) (set, mask uint8, err error) {
+	for _, c := range flags {
+		bit, ok := flagBits[c|0x20]
+		if !ok {
+			return 0, 0, fmt.Errorf("bad flag %q in %q", c, flags)
+		}
+		mask |= bit
+		if c&0x20 == 0 {
+			set |= bit
+		}
+	}
+	return set, mask, nil
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,132 @@
+//-----------------------------------------------------------------------------
+/*
+
+6502 Test Kit Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package testkit
+
+import (
+	"strings"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+// errorLog records the errors reported by a test kit.
+type errorLog struct {
+	testing.TB
+	errs []string
+}
+
+func (l *errorLog) Helper() {}
+
+func (l *errorLog) Error(args ...interface{}) {
+	l.errs = append(l.errs, args[0].(string))
+}
+
+const addCode = "86 10 18 65 10 60" // stx $10, clc, adc $10, rts
+
+func TestKit(t *testing.T) {
+	k := New(t)
+	k.Code(0x0400, addCode).SetA(0x12).SetX(0x34).SetFlags("C")
+	k.Call(0x0400).A(0x46).X(0x34).Flags("nvzc").Mem(0x10, 0x34).Cycles(14)
+	k.SetA(0xff).SetX(0x01).Call(0x0400).A(0x00).Flags("nZC")
+}
+
+func TestKitFailure(t *testing.T) {
+	l := &errorLog{TB: t}
+	k := New(l)
+	k.Code(0x0400, addCode).SetA(0x12).SetX(0x34)
+	k.Call(0x0400).A(0x47).Flags("Zc").Mem(0x0f, 0x00, 0x35).Cycles(14)
+	if len(l.errs) != 3 {
+		t.Fatalf("%d errors, want 3", len(l.errs))
+	}
+	for i, want := range []string{
+		// a with the trace
+		"a = 46, want 47\n" +
+			"pc   a  x  y  s  p    n v - b d i z c\n" +
+			"0000 46 34 00 00 30   0 0 1 1 0 0 0 0\n" +
+			"     ^^\n" +
+			"last 4 instructions:\n" +
+			"0400: 86 10               stx $10        a 12 x 34 y 00 s fe p 30 cyc 0\n" +
+			"0402: 18                  clc            a 12 x 34 y 00 s fe p 30 cyc 3\n" +
+			"0403: 65 10               adc $10        a 12 x 34 y 00 s fe p 30 cyc 5\n" +
+			"0405: 60                  rts            a 46 x 34 y 00 s fe p 30 cyc 8",
+		// flags
+		"flags = zc, want Zc\n" +
+			"pc   a  x  y  s  p    n v - b d i z c\n" +
+			"0000 46 34 00 00 30   0 0 1 1 0 0 0 0\n" +
+			"want                              1 0\n" +
+			"                                  ^",
+		// memory
+		"mem 000f: 1 bytes differ\n" +
+			"000f got  00 34\n" +
+			"     want 00 35\n" +
+			"             ^^",
+	} {
+		if l.errs[i] != want {
+			t.Errorf("error %d:\n%s\nwant\n%s", i, l.errs[i], want)
+		}
+	}
+}
+
+func TestTraceWrap(t *testing.T) {
+	k := New(t).Trace(2)
+	k.Code(0x0400, addCode).Call(0x0400)
+	s := k.trace.String(k.CPU.Variant())
+	if !strings.HasPrefix(s, "last 2 instructions:\n0403: 65 10") {
+		t.Errorf("trace:\n%s", s)
+	}
+}
+
+func TestReadHex(t *testing.T) {
+	var r RAM
+	err := r.ReadHex(strings.NewReader(`
+0200  A9 00 A8 91
+:0402020086101865E5
+:00000001FF
+:01020400FFFA
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the record after the eof record isn't loaded
+	if got := r[0x0200:0x0205]; string(got) != "\xa9\x00\x86\x10\x18" {
+		t.Errorf("got % x", got)
+	}
+	err = r.ReadHex(strings.NewReader(":0402020086101865E6\n"))
+	if err == nil || err.Error() != "line 1: bad checksum" {
+		t.Errorf("checksum: %v", err)
+	}
+}
+
+//-----------------------------------------------------------------------------
+// floating point package (code/code.txt)
+
+// float is a floating point package number (lsw, nsw, msw, exponent).
+type float [4]uint8
+
+func TestFloatMultiply(t *testing.T) {
+	const fpmult = 0x0337
+	k := New(t).LoadHex("../../code/code.txt")
+	for _, tc := range []struct {
+		name    string
+		acc, op float
+		want    float
+	}{
+		{"3*2", float{0x00, 0x00, 0x60, 0x02}, float{0x00, 0x00, 0x40, 0x02}, float{0x00, 0x00, 0x60, 0x03}},
+		{"2*2", float{0x00, 0x00, 0x40, 0x02}, float{0x00, 0x00, 0x40, 0x02}, float{0x00, 0x00, 0x40, 0x03}},
+		{"0.5*3", float{0x00, 0x00, 0x40, 0x00}, float{0x00, 0x00, 0x60, 0x02}, float{0x00, 0x00, 0x60, 0x01}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			k.t = t
+			k.Mem(0x08, tc.acc[:]...).Mem(0x10, tc.op[:]...)
+			k.Call(fpmult).Mem(0x08, tc.want[:]...)
+		})
+	}
+}
+
+//-----------------------------------------------------------------------------
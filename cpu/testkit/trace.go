@@ -0,0 +1,97 @@
+//-----------------------------------------------------------------------------
+/*
+
+Instruction Trace
+
+The trace keeps the CPU state and instruction bytes before each of the last N
+instructions. It is shown when an assertion fails.
+
+*/
+//-----------------------------------------------------------------------------
+
+package testkit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/deadsy/bender/cpu"
+)
+
+//-----------------------------------------------------------------------------
+
+// DefaultTrace is the default number of traced instructions.
+const DefaultTrace = 16
+
+// step is the state before an instruction.
+type step struct {
+	pc      uint16
+	a, x, y uint8
+	s, p    uint8
+	cycles  uint
+	ins     [3]uint8 // instruction bytes
+}
+
+// Read8 reads the instruction bytes (for the disassembler).
+func (st *step) Read8(adr uint16) uint8 {
+	if i := adr - st.pc; i < 3 {
+		return st.ins[i]
+	}
+	return 0
+}
+
+// Write8 is a dummy write.
+func (st *step) Write8(adr uint16, val uint8) {}
+
+// trace is a ring buffer of steps.
+type trace struct {
+	steps []step
+	next  int  // next step to write
+	full  bool // the ring has wrapped
+}
+
+func newTrace(n int) *trace {
+	return &trace{steps: make([]step, n)}
+}
+
+// record records the state before an instruction.
+func (t *trace) record(m *cpu.M6502) error {
+	st := &t.steps[t.next]
+	st.pc = m.PC
+	st.a, st.x, st.y, st.s, st.p = m.A, m.X, m.Y, m.S, m.P
+	st.cycles = m.Cycles()
+	for i := range st.ins {
+		st.ins[i] = m.Peek8(m.PC + uint16(i))
+	}
+	t.next++
+	if t.next == len(t.steps) {
+		t.next = 0
+		t.full = true
+	}
+	return nil
+}
+
+// reset clears the trace.
+func (t *trace) reset() {
+	t.next = 0
+	t.full = false
+}
+
+// String returns the traced instructions, oldest first.
+func (t *trace) String(v cpu.Variant) string {
+	steps := t.steps[:t.next]
+	if t.full {
+		steps = append(append([]step{}, t.steps[t.next:]...), steps...)
+	}
+	s := make([]string, 0, len(steps)+1)
+	s = append(s, fmt.Sprintf("last %d instructions:", len(steps)))
+	for i := range steps {
+		st := &steps[i]
+		da := cpu.DisassembleVariant(v, st, st.pc, nil)
+		s = append(s, fmt.Sprintf("%-40s a %02x x %02x y %02x s %02x p %02x cyc %d",
+			da.String(), st.a, st.x, st.y, st.s, st.p, st.cycles))
+	}
+	return strings.Join(s, "\n")
+}
+
+//-----------------------------------------------------------------------------
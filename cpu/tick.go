@@ -0,0 +1,795 @@
+//-----------------------------------------------------------------------------
+/*
+
+Cycle Stepped 6502 Execution
+
+Tick advances the CPU by one clock and does the bus access the chip does on
+that clock. This includes the dummy reads on indexed page crossings, the
+double write of read-modify-write instructions and the dummy stack reads of
+JSR, RTS, RTI and the pull instructions. The interrupt lines are sampled at
+the end of the second to last clock of each instruction.
+
+The CMOS parts (65C02, R65C02, W65C02S) differ from the NMOS parts:
+
+	page crossing       dummy read of the last instruction byte (not the unfixed address)
+	read-modify-write   dummy read of the address (not a write of the unmodified value)
+	JMP (abs)           an extra clock (a dummy read), the pointer doesn't wrap in the page
+	ADC/SBC             an extra clock in decimal mode (a dummy read at the PC)
+	BRK and interrupts  D is cleared, an NMI doesn't take over the BRK vector
+
+http://www.6502.org/tutorials/interrupts.html
+https://www.nesdev.org/6502_cpu.txt
+https://www.nesdev.org/wiki/CPU_interrupts
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import "fmt"
+
+//-----------------------------------------------------------------------------
+
+// BusFunc is called for each bus cycle when the CPU is cycle stepped.
+// write is the inverse of the R/W pin, sync is set on opcode fetches.
+type BusFunc func(adr uint16, data uint8, write, sync bool)
+
+// BusCycle sets a callback for the bus cycles done by Tick.
+func BusCycle(fn BusFunc) Option {
+	return func(m *M6502) {
+		m.bus = fn
+	}
+}
+
+//-----------------------------------------------------------------------------
+
+// tickKind is the bus cycle sequence of an instruction.
+type tickKind int
+
+const (
+	tickHalt      tickKind = iota // illegal or JAM opcode
+	tickImpl                      // implied/accumulator
+	tickRead                      // read from memory
+	tickWrite                     // write to memory
+	tickRMW                       // read-modify-write
+	tickBranch                    // conditional branch
+	tickBrk                       // BRK and the interrupt sequence
+	tickJsr                       // JSR
+	tickRts                       // RTS
+	tickRti                       // RTI
+	tickPush                      // PHA, PHP
+	tickPull                      // PLA, PLP
+	tickJmp                       // JMP absolute
+	tickJmpInd                    // JMP indirect (NMOS)
+	tickJmpPtr                    // JMP indirect and JMP (abs,X) (CMOS)
+	tickBitBranch                 // BBR/BBS branch on a zero page bit (CMOS)
+	tickNop                       // 1 clock NOP (CMOS)
+)
+
+// tickOp is the cycle stepped form of an opcode.
+type tickOp struct {
+	kind     tickKind
+	mode     adrMode
+	impl     func(m *M6502)          // implied operation
+	read     func(m *M6502, v uint8) // operation on a value read from memory
+	store    func(m *M6502) uint8    // value to write to memory
+	rmw      func(m *M6502, v uint8) uint8
+	cond     func(m *M6502) bool // branch condition
+	unstable bool                // store ANDs with the base address high byte + 1
+	penalty  bool                // indexing adds a clock only on a page crossing
+	decimal  bool                // an extra clock in decimal mode (CMOS ADC/SBC)
+	extra    uint                // dummy read clocks at the end of the instruction
+}
+
+// tickState is the state of an instruction being cycle stepped.
+type tickState struct {
+	n       uint    // clock within the instruction (0 = the next clock is an opcode fetch)
+	code    uint8   // opcode
+	op      *tickOp // current operation
+	intr    bool    // interrupt sequence (forced BRK)
+	data    uint    // first clock of the data phase (0 = address not resolved)
+	adr     uint16  // effective address
+	base    uint16  // address before indexing
+	ptr     uint8   // zero page pointer
+	val     uint8   // data latch
+	poll    [2]bool // interrupt line samples at the end of the last two clocks
+	pending bool    // an interrupt is serviced at the next opcode fetch
+	start   uint    // cycle count at the opcode fetch
+	extra   uint    // dummy read clocks left at the end of the instruction
+}
+
+//-----------------------------------------------------------------------------
+// operations
+
+// The operations are generated from the instruction semantics (tickops.go).
+
+// tickInterrupt is the interrupt sequence.
+var tickInterrupt = tickOp{kind: tickBrk, mode: amImpl}
+
+var tickControlOps = map[string]tickKind{
+	"brk": tickBrk,
+	"jsr": tickJsr,
+	"rts": tickRts,
+	"rti": tickRti,
+	"pha": tickPush,
+	"php": tickPush,
+	"pla": tickPull,
+	"plp": tickPull,
+	"phx": tickPush,
+	"phy": tickPush,
+	"plx": tickPull,
+	"ply": tickPull,
+}
+
+// tickBitOp returns the operations of the Rockwell bit branches
+// (bbr/bbs with the bit number as a suffix).
+func tickBitOp(t *tickOp, ins string) bool {
+	n := len(ins) - 1
+	if n < 1 || ins[n] < '0' || ins[n] > '7' {
+		return false
+	}
+	mask := uint8(1) << (ins[n] - '0')
+	switch ins[:n] {
+	case "bbr":
+		t.kind = tickBitBranch
+		t.cond = func(m *M6502) bool { return m.tick.val&mask == 0 }
+	case "bbs":
+		t.kind = tickBitBranch
+		t.cond = func(m *M6502) bool { return m.tick.val&mask != 0 }
+	default:
+		return false
+	}
+	return true
+}
+
+// newTickTable returns the cycle stepped operations for an opcode table.
+func newTickTable(table *[256]opcode, cmos bool) *[256]tickOp {
+	var ticks [256]tickOp
+	for i := range table {
+		x := &table[i]
+		t := &ticks[i]
+		t.mode = x.mode
+		t.penalty = x.penalty
+		t.read = tickReadOps[x.ins]
+		t.store = tickStoreOps[x.ins]
+		switch x.ins {
+		case "sha", "shx", "shy", "tas":
+			t.unstable = true
+		case "adc", "sbc":
+			t.decimal = cmos
+		case "wai", "stp":
+			t.extra = 1
+		case "nop":
+			if x.mode == amAbs && x.cycles > 4 {
+				// the 8 clock CMOS nop
+				t.extra = x.cycles - 4
+			}
+		}
+		if k, ok := tickControlOps[x.ins]; ok {
+			t.kind = k
+			continue
+		}
+		if x.ins == "jmp" {
+			switch {
+			case x.mode == amAbs:
+				t.kind = tickJmp
+			case cmos:
+				t.kind = tickJmpPtr
+			default:
+				t.kind = tickJmpInd
+			}
+			continue
+		}
+		if tickBitOp(t, x.ins) {
+			continue
+		}
+		switch x.mode {
+		case amImpl, amAcc:
+			if x.ins == "nop" && x.cycles == 1 {
+				t.kind = tickNop
+			} else if fn, ok := tickImplOps[x.ins]; ok {
+				t.kind = tickImpl
+				t.impl = fn
+			}
+		case amRel:
+			t.kind = tickBranch
+			t.cond = tickBranchOps[x.ins]
+		default:
+			if fn, ok := tickRMWOps[x.ins]; ok {
+				t.kind = tickRMW
+				t.rmw = fn
+			} else if t.store != nil {
+				t.kind = tickWrite
+			} else if t.read != nil {
+				t.kind = tickRead
+				if fn, ok := tickImmOps[x.ins]; ok && x.mode == amImm {
+					t.read = fn
+				}
+			}
+		}
+	}
+	return &ticks
+}
+
+//-----------------------------------------------------------------------------
+// bus cycles
+
+func (m *M6502) busSync(adr uint16) uint8 {
+	v := m.load(AccessFetch, adr)
+	if m.bus != nil {
+		m.bus(adr, v, false, true)
+	}
+	return v
+}
+
+func (m *M6502) busRead(adr uint16) uint8 {
+	return m.busLoad(AccessRead, adr)
+}
+
+func (m *M6502) busLoad(kind AccessKind, adr uint16) uint8 {
+	v := m.load(kind, adr)
+	if m.bus != nil {
+		m.bus(adr, v, false, false)
+	}
+	return v
+}
+
+func (m *M6502) busWrite(adr uint16, val uint8) {
+	m.busStore(AccessWrite, adr, val)
+}
+
+func (m *M6502) busStore(kind AccessKind, adr uint16, val uint8) {
+	m.store(kind, adr, val)
+	if m.bus != nil {
+		m.bus(adr, val, true, false)
+	}
+}
+
+func (m *M6502) busPush(val uint8) {
+	m.busStore(AccessPush, stkAddress+uint16(m.S), val)
+	m.S--
+}
+
+func (m *M6502) busPull() uint8 {
+	m.S++
+	return m.busLoad(AccessPull, stkAddress+uint16(m.S))
+}
+
+// busStack is a dummy read of the stack.
+func (m *M6502) busStack() {
+	m.busLoad(AccessPull, stkAddress+uint16(m.S))
+}
+
+// busOperand reads (or dummy reads) an operand byte at the PC.
+func (m *M6502) busOperand() uint8 {
+	return m.busLoad(AccessOperand, m.PC)
+}
+
+// busFetch reads the next instruction byte.
+func (m *M6502) busFetch() uint8 {
+	v := m.busOperand()
+	m.PC++
+	return v
+}
+
+//-----------------------------------------------------------------------------
+
+// tickFetch is the opcode fetch clock.
+func (m *M6502) tickFetch() bool {
+	t := &m.tick
+	t.data = 0
+	if t.pending {
+		// the fetched opcode is discarded and a BRK is forced
+		m.busSync(m.PC)
+		t.pending = false
+		t.intr = true
+		t.code = 0
+		t.op = &tickInterrupt
+		return false
+	}
+	t.intr = false
+	t.code = m.busSync(m.PC)
+	t.op = &m.ticks[t.code]
+	switch t.op.kind {
+	case tickHalt:
+		m.table[t.code].fn(m)
+		return true
+	case tickNop:
+		m.PC++
+		return true
+	}
+	m.PC++
+	if t.op.mode == amImm {
+		t.adr = m.PC
+		m.PC++
+		t.data = 2
+	}
+	return false
+}
+
+// tickAddress does the address clocks of a memory operation.
+// It returns true when the effective address is resolved.
+func (m *M6502) tickAddress() bool {
+	t := &m.tick
+	short := t.op.kind == tickRead || t.op.penalty
+	switch t.op.mode {
+	case amZpg:
+		t.adr = uint16(m.busFetch())
+		return true
+	case amZpgX, amZpgY:
+		if t.n == 2 {
+			t.adr = uint16(m.busFetch())
+			return false
+		}
+		idx := m.X
+		if t.op.mode == amZpgY {
+			idx = m.Y
+		}
+		m.busRead(t.adr)
+		t.adr = uint16(uint8(t.adr) + idx)
+		return true
+	case amAbs:
+		if t.n == 2 {
+			t.adr = uint16(m.busFetch())
+			return false
+		}
+		t.adr |= uint16(m.busFetch()) << 8
+		return true
+	case amAbsX, amAbsY:
+		switch t.n {
+		case 2:
+			t.adr = uint16(m.busFetch())
+			return false
+		case 3:
+			idx := m.X
+			if t.op.mode == amAbsY {
+				idx = m.Y
+			}
+			t.base = t.adr | uint16(m.busFetch())<<8
+			t.adr = t.base + uint16(idx)
+			return short && !t.crossed()
+		}
+		m.tickFixup()
+		return true
+	case amXInd:
+		switch t.n {
+		case 2:
+			t.ptr = m.busFetch()
+		case 3:
+			m.busRead(uint16(t.ptr))
+			t.ptr += m.X
+		case 4:
+			t.adr = uint16(m.busRead(uint16(t.ptr)))
+		default:
+			t.adr |= uint16(m.busRead(uint16(t.ptr+1))) << 8
+			return true
+		}
+		return false
+	case amIndY:
+		switch t.n {
+		case 2:
+			t.ptr = m.busFetch()
+		case 3:
+			t.adr = uint16(m.busRead(uint16(t.ptr)))
+		case 4:
+			t.base = t.adr | uint16(m.busRead(uint16(t.ptr+1)))<<8
+			t.adr = t.base + uint16(m.Y)
+			return short && !t.crossed()
+		default:
+			m.tickFixup()
+			return true
+		}
+		return false
+	case amZpgInd:
+		switch t.n {
+		case 2:
+			t.ptr = m.busFetch()
+		case 3:
+			t.adr = uint16(m.busRead(uint16(t.ptr)))
+		default:
+			t.adr |= uint16(m.busRead(uint16(t.ptr+1))) << 8
+			return true
+		}
+		return false
+	}
+	panic(fmt.Sprintf("bad address mode %d", t.op.mode))
+}
+
+// tickFixup is the dummy read while the high byte of an indexed address is fixed up.
+func (m *M6502) tickFixup() {
+	t := &m.tick
+	if m.cmos {
+		// the last instruction byte
+		m.busRead(m.PC - 1)
+		return
+	}
+	// the address before the high byte is fixed up
+	m.busRead((t.base & 0xff00) | (t.adr & 0xff))
+}
+
+// crossed returns true if indexing crossed a page.
+func (t *tickState) crossed() bool {
+	return (t.adr & 0xff00) != (t.base & 0xff00)
+}
+
+// tickStore returns the value for a write and fixes up the address of the unstable stores.
+func (m *M6502) tickStore() uint8 {
+	t := &m.tick
+	v := t.op.store(m)
+	if t.op.unstable {
+		v &= uint8(t.base>>8) + 1
+		if t.crossed() {
+			t.adr = (uint16(v) << 8) | (t.adr & 0xff)
+		}
+	}
+	return v
+}
+
+// tickBranch does the clocks of a conditional branch.
+func (m *M6502) tickBranch() bool {
+	t := &m.tick
+	switch t.n {
+	case 2:
+		t.val = m.busFetch()
+		return !t.op.cond(m)
+	case 3:
+		m.busOperand()
+		t.adr = uint16(int(m.PC) + int(int8(t.val)))
+		if (t.adr & 0xff00) == (m.PC & 0xff00) {
+			m.PC = t.adr
+			return true
+		}
+		// the high byte is fixed up on the next clock
+		m.PC = (m.PC & 0xff00) | (t.adr & 0xff)
+		return false
+	}
+	m.busOperand()
+	m.PC = t.adr
+	return true
+}
+
+// tickBrk does the clocks of BRK and the interrupt sequence.
+func (m *M6502) tickBrk() bool {
+	t := &m.tick
+	switch t.n {
+	case 2:
+		if t.intr {
+			m.busOperand()
+		} else {
+			m.busFetch()
+		}
+	case 3:
+		m.busPush(uint8(m.PC >> 8))
+	case 4:
+		m.busPush(uint8(m.PC))
+	case 5:
+		if t.intr {
+			m.busPush((m.P & ^flagB) | flagU)
+		} else {
+			m.busPush(m.P | flagB | flagU)
+		}
+		if t.intr && m.nmi {
+			m.nmi = false
+			t.adr = NmiAddress
+		} else {
+			// an nmi at this point hijacks the vector (NMOS)
+			t.adr = m.hijack(IrqAddress)
+		}
+	case 6:
+		m.P |= flagI
+		if m.cmos {
+			m.P &= ^flagD
+		}
+		t.val = m.busLoad(AccessVector, t.adr)
+	default:
+		m.PC = uint16(m.busLoad(AccessVector, t.adr+1))<<8 | uint16(t.val)
+		return true
+	}
+	return false
+}
+
+// tickControl does the clocks of the stack and jump instructions.
+func (m *M6502) tickControl() bool {
+	t := &m.tick
+	switch t.op.kind {
+	case tickJsr:
+		switch t.n {
+		case 2:
+			t.val = m.busFetch()
+		case 3:
+			m.busStack()
+		case 4:
+			m.busPush(uint8(m.PC >> 8))
+		case 5:
+			m.busPush(uint8(m.PC))
+		default:
+			m.PC = uint16(m.busOperand())<<8 | uint16(t.val)
+			m.jsrVSR()
+			return true
+		}
+	case tickRts:
+		switch t.n {
+		case 2:
+			m.busOperand()
+		case 3:
+			m.busStack()
+		case 4:
+			t.val = m.busPull()
+		case 5:
+			m.PC = uint16(m.busPull())<<8 | uint16(t.val)
+		default:
+			m.busFetch()
+			return true
+		}
+	case tickRti:
+		switch t.n {
+		case 2:
+			m.busOperand()
+		case 3:
+			m.busStack()
+		case 4:
+			m.P = m.busPull() | flagB | flagU
+		case 5:
+			t.val = m.busPull()
+		default:
+			m.PC = uint16(m.busPull())<<8 | uint16(t.val)
+			return true
+		}
+	case tickPush:
+		if t.n == 2 {
+			m.busOperand()
+			return false
+		}
+		m.busPush(t.op.store(m))
+		return true
+	case tickPull:
+		switch t.n {
+		case 2:
+			m.busOperand()
+		case 3:
+			m.busStack()
+		default:
+			t.op.read(m, m.busPull())
+			return true
+		}
+	case tickJmp:
+		if t.n == 2 {
+			t.val = m.busFetch()
+			return false
+		}
+		m.PC = uint16(m.busOperand())<<8 | uint16(t.val)
+		m.jmpVSR()
+		return true
+	case tickJmpInd:
+		switch t.n {
+		case 2:
+			t.adr = uint16(m.busFetch())
+		case 3:
+			t.adr |= uint16(m.busFetch()) << 8
+		case 4:
+			t.val = m.busRead(t.adr)
+		default:
+			// the pointer high byte doesn't carry
+			m.PC = uint16(m.busRead((t.adr&0xff00)|uint16(uint8(t.adr)+1)))<<8 | uint16(t.val)
+			m.jmpVSR()
+			return true
+		}
+	case tickJmpPtr:
+		switch t.n {
+		case 2:
+			t.adr = uint16(m.busFetch())
+		case 3:
+			t.adr |= uint16(m.busFetch()) << 8
+			if t.op.mode == amAbsXInd {
+				t.adr += uint16(m.X)
+			}
+		case 4:
+			m.busRead(m.PC - 1)
+		case 5:
+			t.val = m.busRead(t.adr)
+		default:
+			m.PC = uint16(m.busRead(t.adr+1))<<8 | uint16(t.val)
+			m.jmpVSR()
+			return true
+		}
+	}
+	return false
+}
+
+// tickBitBranch does the clocks of BBR and BBS.
+func (m *M6502) tickBitBranch() bool {
+	t := &m.tick
+	switch t.n {
+	case 2:
+		t.ptr = m.busFetch()
+	case 3:
+		t.val = m.busRead(uint16(t.ptr))
+	case 4:
+		m.busRead(uint16(t.ptr))
+	case 5:
+		ofs := m.busFetch()
+		if !t.op.cond(m) {
+			return true
+		}
+		t.adr = uint16(int(m.PC) + int(int8(ofs)))
+	case 6:
+		m.busOperand()
+		if (t.adr & 0xff00) == (m.PC & 0xff00) {
+			m.PC = t.adr
+			return true
+		}
+	default:
+		m.busOperand()
+		m.PC = t.adr
+		return true
+	}
+	return false
+}
+
+// tickEnd ends the operation of an instruction.
+// It returns true if there are no extra clocks.
+func (m *M6502) tickEnd() bool {
+	t := &m.tick
+	t.extra = t.op.extra
+	if t.op.decimal && m.P&flagD != 0 && m.decimal {
+		t.extra++
+	}
+	return t.extra == 0
+}
+
+// tickExec does the clocks after the opcode fetch.
+// It returns true on the last clock of the instruction.
+func (m *M6502) tickExec() bool {
+	t := &m.tick
+	if t.extra != 0 {
+		// dummy read clocks
+		m.busOperand()
+		t.extra--
+		return t.extra == 0
+	}
+	switch t.op.kind {
+	case tickImpl:
+		m.busOperand()
+		t.op.impl(m)
+		return m.tickEnd()
+	case tickBranch:
+		return m.tickBranch()
+	case tickBitBranch:
+		return m.tickBitBranch()
+	case tickBrk:
+		return m.tickBrk()
+	case tickRead, tickWrite, tickRMW:
+		// memory operations
+	default:
+		return m.tickControl()
+	}
+	if t.data == 0 {
+		if m.tickAddress() {
+			t.data = t.n + 1
+		}
+		return false
+	}
+	switch t.op.kind {
+	case tickRead:
+		t.op.read(m, m.busRead(t.adr))
+		return m.tickEnd()
+	case tickWrite:
+		v := m.tickStore()
+		m.busWrite(t.adr, v)
+		return true
+	}
+	// read-modify-write: the unmodified value is written back first (NMOS)
+	// or read again (CMOS)
+	switch t.n - t.data {
+	case 0:
+		t.val = m.busRead(t.adr)
+	case 1:
+		if m.cmos {
+			m.busRead(t.adr)
+		} else {
+			m.busWrite(t.adr, t.val)
+		}
+		t.val = t.op.rmw(m, t.val)
+	default:
+		m.busWrite(t.adr, t.val)
+		return true
+	}
+	return false
+}
+
+// tickRetire ends a cycle stepped instruction.
+func (m *M6502) tickRetire() error {
+	t := &m.tick
+	switch {
+	case t.op.kind == tickBrk:
+		// the first instruction of a handler always runs
+		t.pending = false
+	case t.op.kind == tickBranch && t.n == 3:
+		// a taken branch without a page crossing doesn't poll on its last clock
+		t.pending = t.poll[1]
+	default:
+		t.pending = t.poll[0]
+	}
+	t.n = 0
+	if t.intr {
+		return nil
+	}
+	if err := m.retire(t.code); err != nil {
+		return err
+	}
+	if m.hooks != nil {
+		return m.hookAfter(m.cycles - t.start)
+	}
+	return nil
+}
+
+//-----------------------------------------------------------------------------
+
+// Tick advances the CPU by one clock cycle.
+// Ticking and Run may be mixed: Run completes an instruction started by Tick.
+func (m *M6502) Tick() error {
+	// a jammed cpu needs a reset
+	if m.jam {
+		return m.jamError()
+	}
+	t := &m.tick
+	if t.n == 0 {
+		// a stopped cpu needs a reset
+		if m.stop {
+			return nil
+		}
+		// wait for an interrupt
+		if m.wait {
+			if !m.nmi && !m.irq {
+				m.cycles++
+				return nil
+			}
+			// an irq with interrupts disabled continues with the next instruction
+			m.wait = false
+			t.pending = m.nmi || m.P&flagI == 0
+		}
+		m.halted = nil
+		if m.hooks != nil && !t.pending {
+			if err := m.hookBefore(m.Peek8(m.PC)); err != nil {
+				return err
+			}
+		}
+		t.start = m.cycles
+		if m.obs != nil {
+			m.opPC = m.PC
+		}
+	}
+	t.n++
+	var done bool
+	if t.n == 1 {
+		done = m.tickFetch()
+	} else {
+		done = m.tickExec()
+	}
+	m.cycles++
+	var err error
+	if done {
+		err = m.tickRetire()
+	}
+	// sample the interrupt lines
+	t.poll[1] = t.poll[0]
+	t.poll[0] = m.nmi || (m.irq && m.P&flagI == 0)
+	return err
+}
+
+// Sync returns true if the next clock is an opcode fetch (the SYNC pin).
+func (m *M6502) Sync() bool {
+	return m.tick.n == 0
+}
+
+// tickFinish completes an instruction started by Tick.
+func (m *M6502) tickFinish() error {
+	for m.tick.n != 0 {
+		if err := m.Tick(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//-----------------------------------------------------------------------------
@@ -0,0 +1,153 @@
+//-----------------------------------------------------------------------------
+/*
+
+Cycle Stepped Execution Tests
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+// tickRun runs random code with Run or Tick, logging the state after each instruction.
+func tickRun(v Variant, tick bool, seed int64, steps int) ([]string, *M6502, *testRAM) {
+	rnd := rand.New(rand.NewSource(seed))
+	r := &testRAM{}
+	rnd.Read(r[:])
+	m := newCPU(r, v, nil)
+	m.PC = uint16(rnd.Intn(1 << 16))
+	m.S = uint8(rnd.Intn(256))
+	m.P = uint8(rnd.Intn(256)) | flagB | flagU
+	m.A = uint8(rnd.Intn(256))
+	m.X = uint8(rnd.Intn(256))
+	m.Y = uint8(rnd.Intn(256))
+	var log []string
+	for i := 0; i < steps; i++ {
+		var err error
+		if tick {
+			for {
+				err = m.Tick()
+				if err != nil || m.Sync() {
+					break
+				}
+			}
+		} else {
+			err = m.Run()
+		}
+		if err != nil {
+			// Tick counts the opcode fetch of an illegal opcode
+			log = append(log, fmt.Sprintf("pc %04x %v", m.PC, err))
+			break
+		}
+		log = append(log, fmt.Sprintf("pc %04x s %02x p %02x a %02x x %02x y %02x cyc %d %t %t",
+			m.PC, m.S, m.P, m.A, m.X, m.Y, m.cycles, m.wait, m.stop))
+	}
+	return log, m, r
+}
+
+// TestTickRun runs the same random instruction streams with Run and Tick and
+// compares the state, cycle counts and memory.
+func TestTickRun(t *testing.T) {
+	for v := range variants {
+		v := Variant(v)
+		var usage [256]uint
+		for seed := int64(0); seed < 500; seed++ {
+			want, _, wantRAM := tickRun(v, false, seed, 100)
+			got, m, gotRAM := tickRun(v, true, seed, 100)
+			for i := range m.usage {
+				usage[i] += m.usage[i]
+			}
+			if len(got) != len(want) {
+				t.Fatalf("%s seed %d: %d steps, want %d", v, seed, len(got), len(want))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("%s seed %d step %d:\ngot  %s\nwant %s", v, seed, i, got[i], want[i])
+				}
+			}
+			if *gotRAM != *wantRAM {
+				t.Fatalf("%s seed %d: memory differs", v, seed)
+			}
+		}
+		// every opcode has been tested
+		for op := range usage {
+			if ins := variants[v].table[op].ins; usage[op] == 0 && ins != "ill" && ins != "jam" {
+				t.Errorf("%s: opcode %02x not run", v, op)
+			}
+		}
+	}
+}
+
+// TestTickBus checks the bus cycles where the NMOS and CMOS parts differ.
+func TestTickBus(t *testing.T) {
+	tests := []struct {
+		name string
+		code []uint8
+		p    uint8
+		nmos string
+		cmos string
+	}{
+		{"inc $10", []uint8{0xe6, 0x10}, 0,
+			"f 0400 e6, r 0401 10, r 0010 41, w 0010 41, w 0010 42",
+			"f 0400 e6, r 0401 10, r 0010 41, r 0010 41, w 0010 42"},
+		{"lda $12f0,x", []uint8{0xbd, 0xf0, 0x12}, 0,
+			"f 0400 bd, r 0401 f0, r 0402 12, r 1210 aa, r 1310 55",
+			"f 0400 bd, r 0401 f0, r 0402 12, r 0402 12, r 1310 55"},
+		{"jmp ($10ff)", []uint8{0x6c, 0xff, 0x10}, 0,
+			"f 0400 6c, r 0401 ff, r 0402 10, r 10ff 34, r 1000 12",
+			"f 0400 6c, r 0401 ff, r 0402 10, r 0402 10, r 10ff 34, r 1100 56"},
+		{"adc #$01", []uint8{0x69, 0x01}, flagD,
+			"f 0400 69, r 0401 01",
+			"f 0400 69, r 0401 01, r 0402 00"},
+	}
+	for _, tt := range tests {
+		for _, v := range []Variant{Variant6502, Variant65C02} {
+			var log []string
+			var r testRAM
+			m := newCPU(&r, v, []Option{BusCycle(func(adr uint16, data uint8, write, sync bool) {
+				kind := "r"
+				if write {
+					kind = "w"
+				} else if sync {
+					kind = "f"
+				}
+				log = append(log, fmt.Sprintf("%s %04x %02x", kind, adr, data))
+			})})
+			copy(r[0x0400:], tt.code)
+			r[0x0010] = 0x41
+			r[0x1210] = 0xaa
+			r[0x1310] = 0x55
+			r[0x10ff] = 0x34
+			r[0x1000] = 0x12
+			r[0x1100] = 0x56
+			m.PC = 0x0400
+			m.X = 0x20
+			m.P = tt.p | flagU | flagB
+			if err := m.Tick(); err != nil {
+				t.Fatal(err)
+			}
+			for !m.Sync() {
+				if err := m.Tick(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want := tt.nmos
+			if m.cmos {
+				want = tt.cmos
+			}
+			if got := strings.Join(log, ", "); got != want {
+				t.Errorf("%s: %s\ngot  %s\nwant %s", v, tt.name, got, want)
+			}
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------
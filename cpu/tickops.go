@@ -0,0 +1,419 @@
+// Code generated by cmd/gen -tick. DO NOT EDIT.
+
+package cpu
+
+// tickImplOps are the implied and accumulator operations.
+var tickImplOps = map[string]func(m *M6502){
+	"asl": func(m *M6502) {
+		v := m.A
+		v = m.opASL(v)
+		m.A = v
+	},
+	"clc": func(m *M6502) {
+		m.P &= ^flagC
+	},
+	"cld": func(m *M6502) {
+		m.P &= ^flagD
+	},
+	"cli": func(m *M6502) {
+		m.P &= ^flagI
+	},
+	"clv": func(m *M6502) {
+		m.P &= ^flagV
+	},
+	"dec": func(m *M6502) {
+		v := m.A
+		v--
+		m.setNZ(v)
+		m.A = v
+	},
+	"dex": func(m *M6502) {
+		m.X--
+		m.setNZ(m.X)
+	},
+	"dey": func(m *M6502) {
+		m.Y--
+		m.setNZ(m.Y)
+	},
+	"inc": func(m *M6502) {
+		v := m.A
+		v++
+		m.setNZ(v)
+		m.A = v
+	},
+	"inx": func(m *M6502) {
+		m.X++
+		m.setNZ(m.X)
+	},
+	"iny": func(m *M6502) {
+		m.Y++
+		m.setNZ(m.Y)
+	},
+	"lsr": func(m *M6502) {
+		v := m.A
+		v = m.opLSR(v)
+		m.A = v
+	},
+	"nop": func(m *M6502) {},
+	"rol": func(m *M6502) {
+		v := m.A
+		v = m.opROL(v)
+		m.A = v
+	},
+	"ror": func(m *M6502) {
+		v := m.A
+		v = m.opROR(v)
+		m.A = v
+	},
+	"sec": func(m *M6502) {
+		m.P |= flagC
+	},
+	"sed": func(m *M6502) {
+		m.P |= flagD
+	},
+	"sei": func(m *M6502) {
+		m.P |= flagI
+	},
+	"stp": func(m *M6502) {
+		m.stop = true
+	},
+	"tax": func(m *M6502) {
+		m.X = m.A
+		m.setNZ(m.X)
+	},
+	"tay": func(m *M6502) {
+		m.Y = m.A
+		m.setNZ(m.Y)
+	},
+	"tsx": func(m *M6502) {
+		m.X = m.S
+		m.setNZ(m.X)
+	},
+	"txa": func(m *M6502) {
+		m.A = m.X
+		m.setNZ(m.A)
+	},
+	"txs": func(m *M6502) {
+		m.S = m.X
+	},
+	"tya": func(m *M6502) {
+		m.A = m.Y
+		m.setNZ(m.A)
+	},
+	"wai": func(m *M6502) {
+		m.wait = true
+	},
+}
+
+// tickReadOps are the operations on a value read from memory.
+var tickReadOps = map[string]func(m *M6502, v uint8){
+	"adc": func(m *M6502, v uint8) {
+		m.opADC(v)
+	},
+	"alr": func(m *M6502, v uint8) {
+		m.A = m.opLSR(m.A & v)
+	},
+	"anc": func(m *M6502, v uint8) {
+		m.P &= ^flagC
+		m.A &= v
+		m.setNZ(m.A)
+		m.setC(m.A&0x80 != 0)
+	},
+	"and": func(m *M6502, v uint8) {
+		m.A &= v
+		m.setNZ(m.A)
+	},
+	"arr": func(m *M6502, v uint8) {
+		m.opARR(v)
+	},
+	"bit": func(m *M6502, v uint8) {
+		m.opBit(v)
+	},
+	"cmp": func(m *M6502, v uint8) {
+		m.opCompare(m.A, v)
+	},
+	"cpx": func(m *M6502, v uint8) {
+		m.opCompare(m.X, v)
+	},
+	"cpy": func(m *M6502, v uint8) {
+		m.opCompare(m.Y, v)
+	},
+	"eor": func(m *M6502, v uint8) {
+		m.A ^= v
+		m.setNZ(m.A)
+	},
+	"las": func(m *M6502, v uint8) {
+		v &= m.S
+		m.A = v
+		m.X = v
+		m.S = v
+		m.setNZ(v)
+	},
+	"lax": func(m *M6502, v uint8) {
+		m.A = v
+		m.X = v
+		m.setNZ(v)
+	},
+	"lda": func(m *M6502, v uint8) {
+		m.A = v
+		m.setNZ(m.A)
+	},
+	"ldx": func(m *M6502, v uint8) {
+		m.X = v
+		m.setNZ(m.X)
+	},
+	"ldy": func(m *M6502, v uint8) {
+		m.Y = v
+		m.setNZ(m.Y)
+	},
+	"nop": func(m *M6502, v uint8) {},
+	"ora": func(m *M6502, v uint8) {
+		m.A |= v
+		m.setNZ(m.A)
+	},
+	"pla": func(m *M6502, v uint8) {
+		m.A = v
+		m.setNZ(m.A)
+	},
+	"plp": func(m *M6502, v uint8) {
+		m.P = v | flagB | flagU
+	},
+	"plx": func(m *M6502, v uint8) {
+		m.X = v
+		m.setNZ(m.X)
+	},
+	"ply": func(m *M6502, v uint8) {
+		m.Y = v
+		m.setNZ(m.Y)
+	},
+	"sbc": func(m *M6502, v uint8) {
+		m.opSBC(v)
+	},
+	"sbx": func(m *M6502, v uint8) {
+		x := m.A & m.X
+		m.opCompare(x, v)
+		m.X = x - v
+	},
+	"xaa": func(m *M6502, v uint8) {
+		m.A = (m.A | unstableMagic) & m.X & v
+		m.setNZ(m.A)
+	},
+}
+
+// tickImmOps are the immediate mode operations that differ from tickReadOps.
+var tickImmOps = map[string]func(m *M6502, v uint8){
+	"bit": func(m *M6502, v uint8) {
+		m.P &= ^flagZ
+		m.setZ(v&m.A == 0)
+	},
+	"lax": func(m *M6502, v uint8) {
+		m.A = (m.A | unstableMagic) & v
+		m.X = m.A
+		m.setNZ(m.A)
+	},
+}
+
+// tickStoreOps return the value written to memory.
+var tickStoreOps = map[string]func(m *M6502) uint8{
+	"pha": func(m *M6502) uint8 {
+		return m.A
+	},
+	"php": func(m *M6502) uint8 {
+		return m.P | flagB | flagU
+	},
+	"phx": func(m *M6502) uint8 {
+		return m.X
+	},
+	"phy": func(m *M6502) uint8 {
+		return m.Y
+	},
+	"sax": func(m *M6502) uint8 {
+		return m.A & m.X
+	},
+	"sha": func(m *M6502) uint8 {
+		return m.A & m.X
+	},
+	"shx": func(m *M6502) uint8 {
+		return m.X
+	},
+	"shy": func(m *M6502) uint8 {
+		return m.Y
+	},
+	"sta": func(m *M6502) uint8 {
+		return m.A
+	},
+	"stx": func(m *M6502) uint8 {
+		return m.X
+	},
+	"sty": func(m *M6502) uint8 {
+		return m.Y
+	},
+	"stz": func(m *M6502) uint8 {
+		return 0
+	},
+	"tas": func(m *M6502) uint8 {
+		m.S = m.A & m.X
+		return m.S
+	},
+}
+
+// tickRMWOps are the read-modify-write operations.
+var tickRMWOps = map[string]func(m *M6502, v uint8) uint8{
+	"asl": func(m *M6502, v uint8) uint8 {
+		v = m.opASL(v)
+		return v
+	},
+	"dcp": func(m *M6502, v uint8) uint8 {
+		v = m.opDCP(v)
+		return v
+	},
+	"dec": func(m *M6502, v uint8) uint8 {
+		v--
+		m.setNZ(v)
+		return v
+	},
+	"inc": func(m *M6502, v uint8) uint8 {
+		v++
+		m.setNZ(v)
+		return v
+	},
+	"isc": func(m *M6502, v uint8) uint8 {
+		v = m.opISC(v)
+		return v
+	},
+	"lsr": func(m *M6502, v uint8) uint8 {
+		v = m.opLSR(v)
+		return v
+	},
+	"rla": func(m *M6502, v uint8) uint8 {
+		v = m.opRLA(v)
+		return v
+	},
+	"rmb0": func(m *M6502, v uint8) uint8 {
+		v &^= 1 << 0
+		return v
+	},
+	"rmb1": func(m *M6502, v uint8) uint8 {
+		v &^= 1 << 1
+		return v
+	},
+	"rmb2": func(m *M6502, v uint8) uint8 {
+		v &^= 1 << 2
+		return v
+	},
+	"rmb3": func(m *M6502, v uint8) uint8 {
+		v &^= 1 << 3
+		return v
+	},
+	"rmb4": func(m *M6502, v uint8) uint8 {
+		v &^= 1 << 4
+		return v
+	},
+	"rmb5": func(m *M6502, v uint8) uint8 {
+		v &^= 1 << 5
+		return v
+	},
+	"rmb6": func(m *M6502, v uint8) uint8 {
+		v &^= 1 << 6
+		return v
+	},
+	"rmb7": func(m *M6502, v uint8) uint8 {
+		v &^= 1 << 7
+		return v
+	},
+	"rol": func(m *M6502, v uint8) uint8 {
+		v = m.opROL(v)
+		return v
+	},
+	"ror": func(m *M6502, v uint8) uint8 {
+		v = m.opROR(v)
+		return v
+	},
+	"rra": func(m *M6502, v uint8) uint8 {
+		v = m.opRRA(v)
+		return v
+	},
+	"slo": func(m *M6502, v uint8) uint8 {
+		v = m.opSLO(v)
+		return v
+	},
+	"smb0": func(m *M6502, v uint8) uint8 {
+		v |= 1 << 0
+		return v
+	},
+	"smb1": func(m *M6502, v uint8) uint8 {
+		v |= 1 << 1
+		return v
+	},
+	"smb2": func(m *M6502, v uint8) uint8 {
+		v |= 1 << 2
+		return v
+	},
+	"smb3": func(m *M6502, v uint8) uint8 {
+		v |= 1 << 3
+		return v
+	},
+	"smb4": func(m *M6502, v uint8) uint8 {
+		v |= 1 << 4
+		return v
+	},
+	"smb5": func(m *M6502, v uint8) uint8 {
+		v |= 1 << 5
+		return v
+	},
+	"smb6": func(m *M6502, v uint8) uint8 {
+		v |= 1 << 6
+		return v
+	},
+	"smb7": func(m *M6502, v uint8) uint8 {
+		v |= 1 << 7
+		return v
+	},
+	"sre": func(m *M6502, v uint8) uint8 {
+		v = m.opSRE(v)
+		return v
+	},
+	"trb": func(m *M6502, v uint8) uint8 {
+		m.P &= ^flagZ
+		m.setZ(v&m.A == 0)
+		v &^= m.A
+		return v
+	},
+	"tsb": func(m *M6502, v uint8) uint8 {
+		m.P &= ^flagZ
+		m.setZ(v&m.A == 0)
+		v |= m.A
+		return v
+	},
+}
+
+// tickBranchOps are the branch conditions.
+var tickBranchOps = map[string]func(m *M6502) bool{
+	"bcc": func(m *M6502) bool {
+		return m.P&flagC == 0
+	},
+	"bcs": func(m *M6502) bool {
+		return m.P&flagC != 0
+	},
+	"beq": func(m *M6502) bool {
+		return m.P&flagZ != 0
+	},
+	"bmi": func(m *M6502) bool {
+		return m.P&flagN != 0
+	},
+	"bne": func(m *M6502) bool {
+		return m.P&flagZ == 0
+	},
+	"bpl": func(m *M6502) bool {
+		return m.P&flagN == 0
+	},
+	"bra": func(m *M6502) bool {
+		return true
+	},
+	"bvc": func(m *M6502) bool {
+		return m.P&flagV == 0
+	},
+	"bvs": func(m *M6502) bool {
+		return m.P&flagV != 0
+	},
+}
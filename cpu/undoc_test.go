@@ -0,0 +1,175 @@
+//-----------------------------------------------------------------------------
+/*
+
+Undocumented NMOS Opcode Tests
+
+Hand checked results of the undocumented opcodes on the NMOS parts.
+
+http://www.oxyron.de/html/opcodes02.html
+https://www.nesdev.org/undocumented_opcodes.txt
+
+*/
+//-----------------------------------------------------------------------------
+
+package cpu
+
+import (
+	"errors"
+	"testing"
+)
+
+//-----------------------------------------------------------------------------
+
+// undocTest is an undocumented opcode test vector.
+// The code runs at $0400 with $10 as a zero page operand and $12xx as an
+// absolute operand.
+type undocTest struct {
+	name       string
+	code       []uint8
+	a, x, y, s uint8 // registers before
+	p          uint8 // flags before (nvzc)
+	m          uint8 // operand value
+	adr        uint16
+	wantA      uint8
+	wantX      uint8
+	wantS      uint8
+	wantP      uint8  // flags after (nvzc)
+	wantM      uint8  // value at wantAdr after
+	wantAdr    uint16 // written address (0 for none)
+	cycles     uint
+}
+
+var undocTests = []undocTest{
+	// read-modify-write combinations
+	{name: "slo $10", code: []uint8{0x07, 0x10}, a: 0x10, m: 0xc1, adr: 0x10,
+		wantA: 0x92, wantP: flagN | flagC, wantM: 0x82, wantAdr: 0x10, cycles: 5},
+	{name: "rla $10", code: []uint8{0x27, 0x10}, a: 0xff, p: flagC, m: 0x40, adr: 0x10,
+		wantA: 0x81, wantP: flagN, wantM: 0x81, wantAdr: 0x10, cycles: 5},
+	{name: "sre $10", code: []uint8{0x47, 0x10}, a: 0x01, m: 0x03, adr: 0x10,
+		wantA: 0x00, wantP: flagZ | flagC, wantM: 0x01, wantAdr: 0x10, cycles: 5},
+	{name: "rra $10", code: []uint8{0x67, 0x10}, a: 0x10, p: flagC, m: 0x02, adr: 0x10,
+		wantA: 0x91, wantP: flagN, wantM: 0x81, wantAdr: 0x10, cycles: 5},
+	{name: "rra $10 carry", code: []uint8{0x67, 0x10}, a: 0xff, m: 0x01, adr: 0x10,
+		wantA: 0x00, wantP: flagZ | flagC, wantM: 0x00, wantAdr: 0x10, cycles: 5},
+	{name: "dcp $10", code: []uint8{0xc7, 0x10}, a: 0x10, m: 0x11, adr: 0x10,
+		wantA: 0x10, wantP: flagZ | flagC, wantM: 0x10, wantAdr: 0x10, cycles: 5},
+	{name: "isc $10", code: []uint8{0xe7, 0x10}, a: 0x20, p: flagC, m: 0x0f, adr: 0x10,
+		wantA: 0x10, wantP: flagC, wantM: 0x10, wantAdr: 0x10, cycles: 5},
+	{name: "slo $1200,x", code: []uint8{0x1f, 0xf0, 0x12}, a: 0x00, x: 0x20, m: 0x01, adr: 0x1310,
+		wantA: 0x02, wantX: 0x20, wantM: 0x02, wantAdr: 0x1310, cycles: 7},
+
+	// loads and stores
+	{name: "lax $10", code: []uint8{0xa7, 0x10}, m: 0x80, adr: 0x10,
+		wantA: 0x80, wantX: 0x80, wantP: flagN, cycles: 3},
+	{name: "lax $1200,y", code: []uint8{0xbf, 0x00, 0x12}, y: 0x10, m: 0x00, adr: 0x1210,
+		wantA: 0x00, wantX: 0x00, wantP: flagZ, cycles: 4},
+	{name: "lax $12f0,y", code: []uint8{0xbf, 0xf0, 0x12}, y: 0x20, m: 0x01, adr: 0x1310,
+		wantA: 0x01, wantX: 0x01, cycles: 5},
+	{name: "sax $10", code: []uint8{0x87, 0x10}, a: 0xf0, x: 0x3c, p: flagN | flagZ,
+		wantA: 0xf0, wantX: 0x3c, wantP: flagN | flagZ, wantM: 0x30, wantAdr: 0x10, cycles: 3},
+	{name: "las $1200,y", code: []uint8{0xbb, 0x00, 0x12}, y: 0x10, s: 0xf0, m: 0x3f, adr: 0x1210,
+		wantA: 0x30, wantX: 0x30, wantS: 0x30, cycles: 4},
+
+	// immediate
+	{name: "anc #$80", code: []uint8{0x0b, 0x80}, a: 0xff,
+		wantA: 0x80, wantP: flagN | flagC, cycles: 2},
+	{name: "anc #$01", code: []uint8{0x2b, 0x01}, a: 0x01, p: flagC,
+		wantA: 0x01, cycles: 2},
+	{name: "alr #$03", code: []uint8{0x4b, 0x03}, a: 0xff,
+		wantA: 0x01, wantP: flagC, cycles: 2},
+	{name: "arr #$ff", code: []uint8{0x6b, 0xff}, a: 0xc0, p: flagC,
+		wantA: 0xe0, wantP: flagN | flagC, cycles: 2},
+	{name: "arr #$ff overflow", code: []uint8{0x6b, 0xff}, a: 0x40,
+		wantA: 0x20, wantP: flagV, cycles: 2},
+	{name: "sbx #$02", code: []uint8{0xcb, 0x02}, a: 0x0f, x: 0xfc,
+		wantA: 0x0f, wantX: 0x0a, wantP: flagC, cycles: 2},
+	{name: "sbx #$01 borrow", code: []uint8{0xcb, 0x01}, a: 0xf0, x: 0x0f, p: flagC,
+		wantA: 0xf0, wantX: 0xff, wantP: flagN, cycles: 2},
+	{name: "xaa #$f0", code: []uint8{0x8b, 0xf0}, a: 0xff, x: 0x5a,
+		wantA: 0x50, wantX: 0x5a, cycles: 2},
+	{name: "lax #$5a", code: []uint8{0xab, 0x5a}, a: 0xff,
+		wantA: 0x5a, wantX: 0x5a, cycles: 2},
+	{name: "sbc #$01 (eb)", code: []uint8{0xeb, 0x01}, a: 0x00, p: flagC,
+		wantA: 0xff, wantP: flagN, cycles: 2},
+
+	// the stores AND with the high byte of the address + 1
+	{name: "shx $1200,y", code: []uint8{0x9e, 0x00, 0x12}, x: 0xff, y: 0x10,
+		wantX: 0xff, wantM: 0x13, wantAdr: 0x1210, cycles: 5},
+	{name: "shx $12f0,y crossed", code: []uint8{0x9e, 0xf0, 0x12}, x: 0x03, y: 0x20,
+		wantX: 0x03, wantM: 0x03, wantAdr: 0x0310, cycles: 5},
+	{name: "shy $12f0,x crossed", code: []uint8{0x9c, 0xf0, 0x12}, x: 0x20, y: 0x03,
+		wantX: 0x20, wantM: 0x03, wantAdr: 0x0310, cycles: 5},
+	{name: "sha $1200,y", code: []uint8{0x9f, 0x00, 0x12}, a: 0xf7, x: 0x7f, y: 0x10,
+		wantA: 0xf7, wantX: 0x7f, wantM: 0x13, wantAdr: 0x1210, cycles: 5},
+	{name: "tas $1200,y", code: []uint8{0x9b, 0x00, 0x12}, a: 0xf3, x: 0x3f, y: 0x10, s: 0xff,
+		wantA: 0xf3, wantX: 0x3f, wantS: 0x33, wantM: 0x13, wantAdr: 0x1210, cycles: 5},
+
+	// nops with page crossing penalties
+	{name: "nop $10,x", code: []uint8{0x14, 0x10}, x: 0x01, wantX: 0x01, cycles: 4},
+	{name: "nop $1200,x", code: []uint8{0x1c, 0x00, 0x12}, x: 0x10, wantX: 0x10, cycles: 4},
+	{name: "nop $12f0,x", code: []uint8{0x3c, 0xf0, 0x12}, x: 0x20, wantX: 0x20, cycles: 5},
+	{name: "nop #$ff", code: []uint8{0x80, 0xff}, cycles: 2},
+}
+
+func TestUndocumented(t *testing.T) {
+	const flags = flagN | flagV | flagZ | flagC
+	for _, tt := range undocTests {
+		testCPUs(t, []Variant{Variant6502, Variant6510}, func(m *M6502, r *testRAM, tick bool) {
+			m.PC = 0x0400
+			copy(r[0x0400:], tt.code)
+			m.A, m.X, m.Y = tt.a, tt.x, tt.y
+			m.S = tt.s
+			if tt.s == 0 {
+				m.S = 0xfd
+			}
+			m.P = tt.p | flagU | flagB
+			if tt.adr != 0 {
+				r[tt.adr] = tt.m
+			}
+			n := testCycles(t, m, tick)
+			wantS := tt.wantS
+			if wantS == 0 {
+				wantS = m.S
+				if tt.s != 0 {
+					wantS = tt.s
+				}
+			}
+			if m.A != tt.wantA || m.X != tt.wantX || m.S != wantS || m.P&flags != tt.wantP {
+				t.Errorf("%s: %s: a %02x x %02x s %02x p %02x, want a %02x x %02x s %02x p %02x",
+					m.variant, tt.name, m.A, m.X, m.S, m.P&flags, tt.wantA, tt.wantX, wantS, tt.wantP)
+			}
+			if tt.wantAdr != 0 && r[tt.wantAdr] != tt.wantM {
+				t.Errorf("%s: %s: wrote %02x to %04x, want %02x", m.variant, tt.name, r[tt.wantAdr], tt.wantAdr, tt.wantM)
+			}
+			if n != tt.cycles {
+				t.Errorf("%s: %s: %d cycles, want %d", m.variant, tt.name, n, tt.cycles)
+			}
+			if m.PC != 0x0400+uint16(len(tt.code)) {
+				t.Errorf("%s: %s: pc %04x", m.variant, tt.name, m.PC)
+			}
+		})
+	}
+}
+
+// TestStrict checks that Strict makes the undocumented opcodes illegal.
+func TestStrict(t *testing.T) {
+	for _, v := range nmosVariants {
+		for code := 0; code < 256; code++ {
+			var r testRAM
+			r[0x0400] = uint8(code)
+			m := newCPU(&r, v, []Option{Strict()})
+			m.PC = 0x0400
+			err := m.Run()
+			var illegal *IllegalOpcodeError
+			undoc := variants[v].table[code].undoc
+			if undoc && (!errors.As(err, &illegal) || illegal.Opcode != uint8(code)) {
+				t.Errorf("%s: strict undocumented opcode %02x: %v", v, code, err)
+			}
+			if !undoc && errors.Is(err, ErrIllegalOpcode) {
+				t.Errorf("%s: strict documented opcode %02x is illegal", v, code)
+			}
+		}
+	}
+}
+
+//-----------------------------------------------------------------------------